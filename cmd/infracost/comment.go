@@ -12,12 +12,14 @@ import (
 
 	"github.com/open-policy-agent/opa/ast"  //nolint:staticcheck // we need to use this deprecated package to support parsing of rego policies
 	"github.com/open-policy-agent/opa/rego" //nolint:staticcheck // we need to use this deprecated package to support parsing of rego policies
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/logging"
 
 	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/comment"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
 )
@@ -27,6 +29,7 @@ type CommentOutput struct {
 	HasDiff        bool
 	ValidAt        *time.Time
 	AddRunResponse apiclient.AddRunResponse
+	Combined       output.Root
 }
 
 var (
@@ -38,8 +41,8 @@ var (
 func commentCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "comment",
-		Short: "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
-		Long:  "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
+		Short: "Post an Infracost comment to GitHub, GitLab, Azure Repos, Bitbucket or Gitea",
+		Long:  "Post an Infracost comment to GitHub, GitLab, Azure Repos, Bitbucket or Gitea",
 		Example: `  Update the Infracost comment on a GitHub pull request:
 
       infracost comment github --repo my-org/my-repo --pull-request 3 --path infracost.json --behavior update --github-token $GITHUB_TOKEN
@@ -57,17 +60,21 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 		},
 	}
 
-	cmds := []*cobra.Command{commentGitHubCmd(ctx), commentGitLabCmd(ctx), commentAzureReposCmd(ctx), commentBitbucketCmd(ctx)}
+	cmds := []*cobra.Command{commentGitHubCmd(ctx), commentGitLabCmd(ctx), commentAzureReposCmd(ctx), commentBitbucketCmd(ctx), commentGiteaCmd(ctx)}
 	for _, subCmd := range cmds {
 		subCmd.RunE = checkAPIKeyIsValid(ctx, subCmd.RunE)
 
 		subCmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+		subCmd.Flags().String("config-file", "", "Path to Infracost config file with guardrails to check the costs against")
+		_ = subCmd.MarkFlagFilename("config-file", "yml")
 		subCmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 		subCmd.Flags().Bool("show-changed", false, "Show only projects in the table that have code changes")
 		subCmd.Flags().Bool("show-skipped", true, "List unsupported resources")
 		_ = subCmd.Flags().MarkHidden("show-changed")
 		subCmd.Flags().Bool("skip-no-diff", false, "Skip posting comment if there are no resource changes. Only applies to update, hide-and-new, and delete-and-new behaviors")
 		_ = subCmd.Flags().MarkHidden("skip-no-diff")
+		subCmd.Flags().String("template-path", "", "Path to a Go template that replaces the built-in comment template")
+		_ = subCmd.MarkFlagFilename("template-path")
 		subCmd.Flags().String("comment-path", "", "Path to comment content file (experimental)")
 		_ = subCmd.Flags().MarkHidden("comment-path")
 	}
@@ -78,6 +85,14 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 }
 
 func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []string, mdOpts output.MarkdownOptions) (*CommentOutput, error) {
+	templatePath, _ := cmd.Flags().GetString("template-path")
+	if templatePath != "" {
+		err := output.ValidateTemplate(templatePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	inputs, err := output.LoadPaths(paths)
 	if err != nil {
 		return nil, err
@@ -92,6 +107,17 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 
 	combined.IsCIRun = ctx.IsCIRun()
 
+	configFile, _ := cmd.Flags().GetString("config-file")
+	if configFile != "" {
+		cfgFile, err := config.LoadConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		combined.GuardrailViolations = output.EvaluateGuardrails(combined, cfgFile.Guardrails, cfgFile.Projects)
+		ctx.ContextValues.SetValue("guardrailViolationCount", len(combined.GuardrailViolations))
+	}
+
 	var commentData string
 	var governanceFailures output.GovernanceFailures
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
@@ -103,7 +129,10 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 			combined.Metadata.InfracostCommand = "comment"
 			result = shareCombinedRun(ctx, combined, inputs)
 			combined.RunID, combined.ShareURL, combined.CloudURL, governanceFailures = result.RunID, result.ShareURL, result.CloudURL, result.GovernanceFailures
-			commentData = result.CommentMarkdown
+			// a user template replaces the comment markdown from Infracost Cloud
+			if templatePath == "" {
+				commentData = result.CommentMarkdown
+			}
 		}
 	}
 
@@ -125,6 +154,7 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 			HasDiff:        combined.HasDiff(),
 			ValidAt:        &combined.TimeGenerated,
 			AddRunResponse: result,
+			Combined:       combined,
 		}
 	}
 
@@ -141,10 +171,14 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 	}
 
 	if out == nil {
+		policyOutput := output.NewPolicyOutput(policyChecks)
+		policyOutput.AddLocalPolicies(combined.Projects)
+
 		opts := output.Options{
 			DashboardEndpoint: ctx.Config.DashboardEndpoint,
 			NoColor:           ctx.Config.NoColor,
-			PolicyOutput:      output.NewPolicyOutput(policyChecks),
+			PolicyOutput:      policyOutput,
+			TemplatePath:      templatePath,
 		}
 		opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
 		opts.ShowOnlyChanges, _ = cmd.Flags().GetBool("show-changed")
@@ -164,6 +198,7 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 			HasDiff:        combined.HasDiff(),
 			ValidAt:        &combined.TimeGenerated,
 			AddRunResponse: result,
+			Combined:       combined,
 		}
 	}
 
@@ -173,10 +208,103 @@ func buildCommentOutput(cmd *cobra.Command, ctx *config.RunContext, paths []stri
 	if len(governanceFailures) > 0 {
 		return out, governanceFailures
 	}
+	if len(combined.GuardrailViolations) > 0 {
+		return out, combined.GuardrailViolations
+	}
 
 	return out, nil
 }
 
+// postReviewComments posts a review comment on the block of each resource
+// whose cost changed and resolves the review comments from previous runs that
+// are stale.
+func postReviewComments(ctx *config.RunContext, h *comment.ReviewHandler, combined output.Root, platform string) error {
+	var targets []comment.ReviewTarget
+	for _, r := range output.ToReviewResources(combined) {
+		targets = append(targets, comment.ReviewTarget{
+			Key:       r.Project + "/" + r.Address,
+			Path:      r.Filename,
+			StartLine: r.StartLine,
+			EndLine:   r.EndLine,
+			Body:      r.Body,
+		})
+	}
+
+	res, err := h.PostReview(ctx.Context(), targets)
+	if err != nil {
+		return fmt.Errorf("The review comments could not be posted to %s: %w", platform, err)
+	}
+
+	ctx.ContextValues.SetValue("reviewCommentCount", res.Created+res.Unchanged)
+
+	logging.Logger.Info().Msgf("Review comments posted to %s: %d created, %d unchanged, %d resolved", platform, res.Created, res.Unchanged, res.Resolved)
+	if res.Skipped > 0 {
+		logging.Logger.Info().Msgf("%d resources with cost changes were not commented on as their code was not changed by the pull request", res.Skipped)
+	}
+
+	return nil
+}
+
+// buildCheck returns the check that is posted by --as-check. It fails if the
+// monthly cost increase is above --check-threshold or if any policy, guardrail
+// or governance checks failed, and is neutral if there were only governance
+// warnings.
+func buildCheck(cmd *cobra.Command, commentOut *CommentOutput, commentErr error) comment.Check {
+	combined := commentOut.Combined
+
+	check := comment.Check{
+		Title:      output.CheckTitle(combined),
+		Summary:    commentOut.Body,
+		Conclusion: comment.CheckSuccess,
+		DetailsURL: combined.CloudURL,
+	}
+	if check.DetailsURL == "" {
+		check.DetailsURL = combined.ShareURL
+	}
+
+	for _, gr := range commentOut.AddRunResponse.GovernanceResults {
+		if len(gr.Failures) > 0 {
+			check.Conclusion = comment.CheckFailure
+		} else if len(gr.Warnings) > 0 && check.Conclusion == comment.CheckSuccess {
+			check.Conclusion = comment.CheckNeutral
+		}
+	}
+
+	if commentErr != nil {
+		check.Conclusion = comment.CheckFailure
+	}
+
+	if cmd.Flags().Changed("check-threshold") {
+		v, _ := cmd.Flags().GetFloat64("check-threshold")
+		threshold := decimal.NewFromFloat(v)
+		increase := output.MonthlyCostIncrease(combined)
+
+		if increase.GreaterThan(threshold) {
+			check.Conclusion = comment.CheckFailure
+			check.Summary = fmt.Sprintf("The monthly cost increase of %s is above the threshold of %s.\n\n%s",
+				output.FormatCost2DP(combined.Currency, &increase),
+				output.FormatCost2DP(combined.Currency, &threshold),
+				check.Summary,
+			)
+		}
+	}
+
+	return check
+}
+
+// postCheck posts the check to the commit and prints its conclusion.
+func postCheck(ctx *config.RunContext, h comment.CheckHandler, check comment.Check, platform string) error {
+	err := h.CallPostCheck(ctx.Context(), check)
+	if err != nil {
+		return fmt.Errorf("The check could not be posted to %s: %w", platform, err)
+	}
+
+	ctx.ContextValues.SetValue("checkConclusion", string(check.Conclusion))
+	logging.Logger.Info().Msgf("Check posted to %s with conclusion %s", platform, check.Conclusion)
+
+	return nil
+}
+
 type PRNumber int
 
 func (p *PRNumber) Set(value string) error {
@@ -278,7 +406,7 @@ func isErrorUnhandled(err error) bool {
 	}
 
 	switch err.(type) {
-	case output.PolicyCheckFailures, output.GovernanceFailures:
+	case output.PolicyCheckFailures, output.GovernanceFailures, output.GuardrailViolations:
 		return false
 	}
 
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/comment"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+var validCommentGiteaBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new"}
+
+func commentGiteaCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gitea",
+		Short: "Post an Infracost comment to Gitea or Forgejo",
+		Long:  "Post an Infracost comment to Gitea or Forgejo",
+		Example: `  Update comment on a pull request:
+
+      infracost comment gitea --gitea-server-url https://gitea.example.com --repo my-org/my-repo --pull-request 3 --path infracost.json --gitea-token $GITEA_TOKEN
+
+  Delete old Infracost comments and post a new comment:
+
+      infracost comment gitea --gitea-server-url https://gitea.example.com --repo my-org/my-repo --pull-request 3 --path infracost.json --behavior delete-and-new --gitea-token $GITEA_TOKEN`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx.ContextValues.SetValue("platform", "gitea")
+
+			var err error
+
+			format, _ := cmd.Flags().GetString("format")
+			format = strings.ToLower(format)
+			if format != "" && !contains(validCommentOutputFormats, format) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--format only supports %s", strings.Join(validCommentOutputFormats, ", "))
+			}
+
+			serverURL, _ := cmd.Flags().GetString("gitea-server-url")
+			token, _ := cmd.Flags().GetString("gitea-token")
+			tag, _ := cmd.Flags().GetString("tag")
+
+			tlsConfig, err := loadTLSConfigFromEnv(ctx)
+			if err != nil {
+				return err
+			}
+
+			extra := comment.GiteaExtra{
+				ServerURL: serverURL,
+				Token:     token,
+				Tag:       tag,
+				TLSConfig: tlsConfig,
+			}
+
+			prNumber, _ := cmd.Flags().GetInt("pull-request")
+			repo, _ := cmd.Flags().GetString("repo")
+
+			if prNumber == 0 {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--pull-request is required")
+			}
+
+			ctx.ContextValues.SetValue("targetType", "pull-request")
+
+			commentHandler, err := comment.NewGiteaPRHandler(ctx.Context(), repo, strconv.Itoa(prNumber), extra)
+			if err != nil {
+				return err
+			}
+
+			behavior, _ := cmd.Flags().GetString("behavior")
+			if behavior != "" && !contains(validCommentGiteaBehaviors, behavior) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--behavior only supports %s", strings.Join(validCommentGiteaBehaviors, ", "))
+			}
+			ctx.ContextValues.SetValue("behavior", behavior)
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			commentOut, commentErr := buildCommentOutput(cmd, ctx, paths, output.MarkdownOptions{
+				WillUpdate: behavior == "update",
+				// Gitea can't hide comments so hide-and-new deletes them instead.
+				WillReplace:         behavior == "delete-and-new" || behavior == "hide-and-new",
+				IncludeFeedbackLink: !ctx.Config.IsSelfHosted(),
+				MaxMessageSize:      output.GiteaMaxMessageSize,
+			})
+			if isErrorUnhandled(commentErr) {
+				return commentErr
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if !dryRun {
+				skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff")
+
+				res, err := commentHandler.CommentWithBehavior(ctx.Context(), behavior, commentOut.Body, &comment.CommentOpts{
+					ValidAt:    commentOut.ValidAt,
+					SkipNoDiff: !commentOut.HasDiff && skipNoDiff,
+				})
+				if err != nil {
+					return err
+				}
+
+				if res.Posted && ctx.IsCloudUploadExplicitlyEnabled() {
+					dashboardClient := apiclient.NewDashboardAPIClient(ctx)
+					if err := dashboardClient.SavePostedPrComment(ctx, commentOut.AddRunResponse.RunID, commentOut.Body); err != nil {
+						logging.Logger.Err(err).Msg("could not save posted PR comment")
+					}
+				}
+
+				pricingClient := apiclient.GetPricingAPIClient(ctx)
+				err = pricingClient.AddEvent("infracost-comment", ctx.EventEnv())
+				if err != nil {
+					logging.Logger.Err(err).Msg("could not report infracost-comment event")
+				}
+
+				if format == "json" {
+					b, err := jsoniter.MarshalIndent(commentOut.AddRunResponse, "", "  ")
+					if err != nil {
+						return fmt.Errorf("failed to marshal result: %w", err)
+					}
+					cmd.Print(string(b))
+				} else if res.Posted {
+					cmd.Println("Comment posted to Gitea")
+				} else {
+					msg := "Comment not posted to Gitea"
+					if res.SkipReason != "" {
+						msg += fmt.Sprintf(": %s", res.SkipReason)
+					}
+					cmd.Println(msg)
+				}
+			} else {
+				cmd.Println(commentOut.Body)
+				cmd.Println("Comment not posted to Gitea (--dry-run was specified)")
+			}
+
+			return commentErr
+		},
+	}
+
+	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
+  update (default)  Update latest comment
+  new               Create a new comment
+  hide-and-new      Delete previous matching comments and create a new comment, Gitea doesn't support hiding comments
+  delete-and-new    Delete previous matching comments and create a new comment`)
+	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validCommentGiteaBehaviors, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().String("gitea-server-url", "https://gitea.com", "Gitea or Forgejo server URL")
+	cmd.Flags().String("gitea-token", "", "Gitea access token")
+	_ = cmd.MarkFlagRequired("gitea-token")
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	var prNumber PRNumber
+	cmd.Flags().Var(&prNumber, "pull-request", "Pull request number to post comment on")
+	cmd.Flags().String("repo", "", "Repository in format owner/repo")
+	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().String("tag", "", "Customize hidden markdown tag used to detect comments posted by Infracost")
+	cmd.Flags().Bool("dry-run", false, "Generate comment without actually posting to Gitea")
+	cmd.Flags().String("format", "", "Output format: json")
+
+	return cmd
+}
@@ -31,7 +31,11 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 
   Post a new comment to a commit:
 
-      infracost comment github --repo my-org/my-repo --commit 2ca7182 --path infracost.json --behavior hide-and-new --github-token $GITHUB_TOKEN`,
+      infracost comment github --repo my-org/my-repo --commit 2ca7182 --path infracost.json --behavior hide-and-new --github-token $GITHUB_TOKEN
+
+  Also post a check run that fails if the monthly cost increases by more than $100:
+
+      infracost comment github --repo my-org/my-repo --pull-request 3 --path infracost.json --as-check --check-threshold 100 --github-token $GITHUB_TOKEN`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx.ContextValues.SetValue("platform", "github")
@@ -106,6 +110,12 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 			}
 			ctx.ContextValues.SetValue("behavior", behavior)
 
+			reviewComments, _ := cmd.Flags().GetBool("review-comments")
+			if reviewComments && prNumber == 0 {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--review-comments requires --pull-request")
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			commentOut, commentErr := buildCommentOutput(cmd, ctx, paths, output.MarkdownOptions{
@@ -137,6 +147,31 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 					}
 				}
 
+				if reviewComments {
+					reviewHandler, err := comment.NewGitHubReviewHandler(ctx.Context(), repo, strconv.Itoa(prNumber), extra)
+					if err != nil {
+						return err
+					}
+
+					err = postReviewComments(ctx, reviewHandler, commentOut.Combined, "GitHub")
+					if err != nil {
+						return err
+					}
+				}
+
+				asCheck, _ := cmd.Flags().GetBool("as-check")
+				if asCheck {
+					checkHandler, err := comment.NewGitHubCheckHandler(ctx.Context(), repo, commit, prNumber, extra)
+					if err != nil {
+						return err
+					}
+
+					err = postCheck(ctx, checkHandler, buildCheck(cmd, commentOut, commentErr), "GitHub")
+					if err != nil {
+						return err
+					}
+				}
+
 				pricingClient := apiclient.GetPricingAPIClient(ctx)
 				err = pricingClient.AddEvent("infracost-comment", ctx.EventEnv())
 				if err != nil {
@@ -173,6 +208,7 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().Bool("as-check", false, "Also post the result as a check run with a pass/fail conclusion (experimental)")
 	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
   update (default)  Update latest comment
   new               Create a new comment
@@ -181,6 +217,7 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validCommentGitHubBehaviors, cobra.ShellCompDirectiveDefault
 	})
+	cmd.Flags().Float64("check-threshold", 0, "Monthly cost increase above which the check fails, used with --as-check")
 	cmd.Flags().String("commit", "", "Commit SHA to post comment on, mutually exclusive with pull-request")
 	cmd.Flags().String("github-api-url", "https://api.github.com", "GitHub API URL")
 	cmd.Flags().String("github-token", "", "GitHub token")
@@ -195,6 +232,7 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().Var(&prNumber, "pull-request", "Pull request number to post comment on, mutually exclusive with commit")
 	cmd.Flags().String("repo", "", "Repository in format owner/repo")
 	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().Bool("review-comments", false, "Also post review comments on the resource blocks whose costs changed and resolve stale ones (experimental)")
 	cmd.Flags().String("tag", "", "Customize hidden markdown tag used to detect comments posted by Infracost")
 	cmd.Flags().Bool("dry-run", false, "Generate comment without actually posting to GitHub")
 	cmd.Flags().String("format", "", "Output format: json")
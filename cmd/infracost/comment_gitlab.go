@@ -90,6 +90,12 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 			}
 			ctx.ContextValues.SetValue("behavior", behavior)
 
+			reviewComments, _ := cmd.Flags().GetBool("review-comments")
+			if reviewComments && mrNumber == 0 {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--review-comments requires --merge-request")
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			commentOut, commentErr := buildCommentOutput(cmd, ctx, paths, output.MarkdownOptions{
@@ -121,6 +127,31 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 					}
 				}
 
+				if reviewComments {
+					reviewHandler, err := comment.NewGitLabReviewHandler(ctx.Context(), repo, strconv.Itoa(mrNumber), extra)
+					if err != nil {
+						return err
+					}
+
+					err = postReviewComments(ctx, reviewHandler, commentOut.Combined, "GitLab")
+					if err != nil {
+						return err
+					}
+				}
+
+				asCheck, _ := cmd.Flags().GetBool("as-check")
+				if asCheck {
+					checkHandler, err := comment.NewGitLabCheckHandler(ctx.Context(), repo, commit, mrNumber, extra)
+					if err != nil {
+						return err
+					}
+
+					err = postCheck(ctx, checkHandler, buildCheck(cmd, commentOut, commentErr), "GitLab")
+					if err != nil {
+						return err
+					}
+				}
+
 				pricingClient := apiclient.GetPricingAPIClient(ctx)
 				err = pricingClient.AddEvent("infracost-comment", ctx.EventEnv())
 				if err != nil {
@@ -151,6 +182,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().Bool("as-check", false, "Also post the result as a commit status with a pass/fail conclusion (experimental)")
 	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
   update (default)  Update latest comment
   new               Create a new comment
@@ -158,6 +190,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validCommentGitLabBehaviors, cobra.ShellCompDirectiveDefault
 	})
+	cmd.Flags().Float64("check-threshold", 0, "Monthly cost increase above which the check fails, used with --as-check")
 	cmd.Flags().String("commit", "", "Commit SHA to post comment on, mutually exclusive with merge-request")
 	cmd.Flags().String("gitlab-server-url", "https://gitlab.com", "GitLab Server URL")
 	cmd.Flags().String("gitlab-token", "", "GitLab token")
@@ -169,6 +202,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().Var(&mrNumber, "merge-request", "Merge request number to post comment on, mutually exclusive with commit")
 	cmd.Flags().String("repo", "", "Repository in format owner/repo")
 	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().Bool("review-comments", false, "Also post review comments on the resource blocks whose costs changed and resolve stale ones (experimental)")
 	cmd.Flags().String("tag", "", "Customize hidden markdown tag used to detect comments posted by Infracost")
 	cmd.Flags().Bool("dry-run", false, "Generate comment without actually posting to GitLab")
 	cmd.Flags().String("format", "", "Output format: json")
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/ui"
@@ -22,6 +23,7 @@ var supportedConfigureKeys = map[string]struct{}{
 	"disable_hcl":              {},
 	"tls_insecure_skip_verify": {},
 	"tls_ca_cert_file":         {},
+	"pricing_snapshot_path":    {},
 }
 
 func configureCmd(ctx *config.RunContext) *cobra.Command {
@@ -102,6 +104,15 @@ func configureSetCmd(ctx *config.RunContext) *cobra.Command {
 			case "tls_ca_cert_file":
 				ctx.Config.Configuration.TLSCACertFile = value
 				saveConfiguration = true
+			case "pricing_snapshot_path":
+				if value != "" {
+					if _, err := apiclient.LoadPricingSnapshot(value); err != nil {
+						return err
+					}
+				}
+
+				ctx.Config.Configuration.PricingSnapshotPath = value
+				saveConfiguration = true
 			case "currency":
 				ctx.Config.Configuration.Currency = value
 				saveConfiguration = true
@@ -253,6 +264,16 @@ func configureGetCmd(ctx *config.RunContext) *cobra.Command {
 					)
 					logging.Logger.Warn().Msg(msg)
 				}
+			case "pricing_snapshot_path":
+				value = ctx.Config.Configuration.PricingSnapshotPath
+
+				if value == "" {
+					msg := fmt.Sprintf("No pricing snapshot in your saved config (%s), prices are fetched from the Cloud Pricing API.\nImport a snapshot using %s.",
+						config.ConfigurationFilePath(),
+						ui.PrimaryString("infracost pricing import --path pricing-snapshot.json"),
+					)
+					logging.Logger.Warn().Msg(msg)
+				}
 			case "enable_dashboard":
 				if ctx.Config.Configuration.EnableDashboard == nil {
 					value = ""
@@ -292,6 +313,7 @@ Supported settings:
   - currency: convert output from USD to your preferred currency
   - tls_insecure_skip_verify: skip TLS certificate checks for a self-hosted Cloud Pricing API
   - tls_ca_cert_file: verify certificate of a self-hosted Cloud Pricing API using this CA certificate
+  - pricing_snapshot_path: resolve prices offline from a snapshot created by 'infracost pricing export'
 `
 
 	return fmt.Sprintf("%s.\n%s", description, settings)
@@ -33,8 +33,10 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
       infracost diff --path plan.json`,
 		ValidArgs: []string{"--", "-"},
 		RunE: checkAPIKeyIsValid(ctx, func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
-				return err
+			if !ctx.Config.IsOfflinePricing() {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
 			}
 
 			err := loadRunFlags(ctx.Config, cmd)
@@ -63,6 +65,7 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against")
 	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff"})
 	cmd.Flags().String("out-file", "", "Save output to a file")
+	addGroupByFlag(cmd)
 
 	return cmd
 }
@@ -96,6 +99,9 @@ func runCompare(cmd *cobra.Command, ctx *config.RunContext, current output.Root)
 		return err
 	}
 
+	combined.GuardrailViolations = output.EvaluateGuardrails(combined, ctx.Config.Guardrails, ctx.Config.Projects)
+	ctx.ContextValues.SetValue("guardrailViolationCount", len(combined.GuardrailViolations))
+
 	format, _ := cmd.Flags().GetString("format")
 	b, err := output.FormatOutput(strings.ToLower(format), combined, output.Options{
 		DashboardEndpoint: ctx.Config.DashboardEndpoint,
@@ -103,6 +109,7 @@ func runCompare(cmd *cobra.Command, ctx *config.RunContext, current output.Root)
 		NoColor:           ctx.Config.NoColor,
 		Fields:            ctx.Config.Fields,
 		CurrencyFormat:    ctx.Config.CurrencyFormat,
+		GroupBy:           ctx.Config.GroupBy,
 	})
 	if err != nil {
 		return err
@@ -115,10 +122,22 @@ func runCompare(cmd *cobra.Command, ctx *config.RunContext, current output.Root)
 	}
 
 	if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
-		return saveOutFile(ctx, cmd, outFile, b)
+		err = saveOutFile(ctx, cmd, outFile, b)
+		if err != nil {
+			return err
+		}
+	} else {
+		cmd.Println(string(b))
+	}
+
+	if len(combined.GuardrailViolations) > 0 {
+		return combined.GuardrailViolations
+	}
+
+	if failures := output.LocalPolicyFailures(combined); len(failures) > 0 {
+		return failures
 	}
 
-	cmd.Println(string(b))
 	return nil
 }
 
@@ -268,6 +268,20 @@ func TestDiffWithInfracostJSON(t *testing.T) {
 		})
 }
 
+func TestDiffWithGuardrails(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(
+		t,
+		testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"diff",
+			"--config-file",
+			path.Join(dir, "infracost.yml"),
+			"--compare-to",
+			path.Join(dir, "prior.json"),
+		}, nil)
+}
+
 func TestDiffWithConfigFileCompareTo(t *testing.T) {
 	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
 	configFile := fmt.Sprintf(`version: 0.1
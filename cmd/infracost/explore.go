@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/explore"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+)
+
+func exploreCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explore",
+		Short: "Explore Infracost JSON files in an interactive terminal UI",
+		Long: `Explore Infracost JSON files in an interactive terminal UI.
+
+Projects, resources, sub-resources and cost components are shown in a
+collapsible tree that can be sorted by cost or diff and filtered by
+resource name, type (type:aws_instance) or tag (tag:team=web). The
+details of the selected row are shown next to the tree. The price filters
+and usage values behind cost components are only shown for JSON files
+created with --explore-details.`,
+		Example: `  Explore a breakdown:
+
+      infracost breakdown --path /code --format json --explore-details --out-file infracost.json
+      infracost explore --path infracost.json
+
+  Explore multiple Infracost JSON files:
+
+      infracost explore --path "out*.json" # glob needs quotes`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := output.LoadPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs)
+			if errors.As(err, &clierror.WarningError{}) {
+				logging.Logger.Warn().Msg(err.Error())
+			} else if err != nil {
+				return err
+			}
+
+			pricingClient := apiclient.GetPricingAPIClient(ctx)
+			err = pricingClient.AddEvent("infracost-explore", ctx.EventEnv())
+			if err != nil {
+				logging.Logger.Err(err).Msg("could not report infracost-explore event")
+			}
+
+			return explore.Run(combined, os.Stdin, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	return cmd
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/output"
 )
 
 // enum implements the flag.Value interface to provide a custom flag that validates
@@ -51,3 +53,14 @@ func newEnumFlag(cmd *cobra.Command, name string, value string, usage string, al
 	cmd.Flags().Var(&e, name, fmt.Sprintf("%s: %s", usage, strings.Join(e.Allowed, ", ")))
 	_ = cmd.RegisterFlagCompletionFunc(name, e.completion)
 }
+
+// validGroupByFormats are the output formats that show the costs rolled up
+// by --group-by.
+var validGroupByFormats = []string{"json", "table", "diff"}
+
+func addGroupByFlag(cmd *cobra.Command) {
+	cmd.Flags().String("group-by", "", fmt.Sprintf("Roll up resource costs by: %s.\nSupported by json, table and diff output formats", strings.Join(output.ValidGroupBys, ", ")))
+	_ = cmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"tag:", "module", "resource-type", "provider", "region"}, cobra.ShellCompDirectiveNoSpace
+	})
+}
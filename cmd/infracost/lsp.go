@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/lsp"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func lspCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Start a language server to show costs in editors",
+		Long: `Start a Language Server Protocol server that communicates over stdio.
+
+Editors that support LSP, e.g. VS Code, Neovim and JetBrains IDEs, can run this
+command for Terraform files to show the monthly cost of each resource and module
+block as a code lens, the cost component breakdown when hovering over a block and
+diagnostics for missing prices and variables. Projects are evaluated when their
+files are opened and re-evaluated when they are saved.`,
+		Example: `  Configure your editor to run the language server for Terraform files:
+
+      infracost lsp
+
+  Use a usage file for all projects in the workspace:
+
+      infracost lsp --usage-file infracost-usage.yml`,
+		ValidArgs: []string{"--", "-"},
+		RunE: checkAPIKeyIsValid(ctx, func(cmd *cobra.Command, args []string) error {
+			usageData := schema.UsageMap{}
+			usageFilePath, _ := cmd.Flags().GetString("usage-file")
+			if usageFilePath != "" {
+				usageFile, err := usage.LoadUsageFile(usageFilePath)
+				if err != nil {
+					return err
+				}
+
+				usageData = usageFile.ToUsageDataMap()
+			}
+
+			pricingFetcher := prices.NewPriceFetcher(ctx, false)
+			if ctx.Config.IsOfflinePricing() {
+				var err error
+				pricingFetcher, err = prices.NewOfflinePriceFetcher(ctx, false)
+				if err != nil {
+					return fmt.Errorf("Error loading pricing snapshot. %w", err)
+				}
+			}
+
+			server := lsp.NewServer(ctx, pricingFetcher, usageData, logging.Logger)
+			server.Serve(cmd.Context(), stdio{Reader: os.Stdin, Writer: os.Stdout})
+
+			return nil
+		}),
+	}
+
+	cmd.Flags().String("usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+
+	return cmd
+}
+
+// stdio is the stream of the language server, stdin and stdout are left open
+// when the client disconnects.
+type stdio struct {
+	io.Reader
+	io.Writer
+}
+
+func (stdio) Close() error {
+	return nil
+}
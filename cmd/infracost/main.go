@@ -81,9 +81,13 @@ func Run(modifyCtx func(*config.RunContext), args *[]string) {
 
 		handleUpdateMessage(updateMessageChan)
 
-		if appErr != nil || unexpectedErr != nil {
+		if unexpectedErr != nil {
 			ctx.Exit(1)
 		}
+
+		if appErr != nil {
+			ctx.Exit(exitCode(appErr))
+		}
 	}()
 
 	startUpdateCheck(ctx, updateMessageChan)
@@ -193,9 +197,13 @@ func newRootCmd(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(outputCmd(ctx))
 	rootCmd.AddCommand(uploadCmd(ctx))
 	rootCmd.AddCommand(commentCmd(ctx))
+	rootCmd.AddCommand(notifyCmd(ctx))
+	rootCmd.AddCommand(exploreCmd(ctx))
+	rootCmd.AddCommand(lspCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 	rootCmd.AddCommand(figAutocompleteCmd())
 	rootCmd.AddCommand(newGenerateCommand())
+	rootCmd.AddCommand(pricingCmd(ctx))
 
 	rootCmd.SetUsageTemplate(fmt.Sprintf(`%s{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
@@ -249,7 +257,7 @@ func startUpdateCheck(ctx *config.RunContext, c chan *update.Info) {
 }
 
 func loadCloudSettings(ctx *config.RunContext) {
-	if ctx.Config.IsSelfHosted() || (ctx.Config.EnableCloud != nil && !*ctx.Config.EnableCloud) {
+	if ctx.Config.IsSelfHosted() || ctx.Config.IsOfflinePricing() || (ctx.Config.EnableCloud != nil && !*ctx.Config.EnableCloud) {
 		return
 	}
 
@@ -302,6 +310,19 @@ func checkAPIKey(apiKey string, apiEndpoint string, defaultEndpoint string) erro
 var ignoredErrors = []string{
 	"Policy check failed",
 	"Governance check failed",
+	"Guardrail check failed",
+}
+
+// exitCode returns the exit code that should be used for the error. Errors
+// can set a specific exit code by implementing an ExitCode method, otherwise
+// 1 is returned.
+func exitCode(err error) int {
+	var e interface{ ExitCode() int }
+	if errors.As(err, &e) {
+		return e.ExitCode()
+	}
+
+	return 1
 }
 
 func handleCLIError(ctx *config.RunContext, cliErr error) {
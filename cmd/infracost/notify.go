@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+var validNotifyFormats = []string{
+	"slack-message",
+	"teams-message",
+	"webhook-message",
+}
+
+func notifyCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Send an Infracost notification to Slack, Microsoft Teams or a webhook",
+		Long: `Send an Infracost notification to a Slack, Microsoft Teams or generic
+incoming webhook. The notification is only sent if the monthly cost of a
+project, or of all projects, changes by more than the threshold.
+
+The webhook-message format sends a JSON summary of the cost changes, or
+the output of --webhook-template which is executed with the same data,
+so it can be shaped into the payload of other chat tools.`,
+		Example: `  Send a Slack message if the monthly cost changes by more than $100:
+
+      infracost notify --path infracost.json --webhook-url $SLACK_WEBHOOK_URL --threshold 100
+
+  Send a Microsoft Teams message:
+
+      infracost notify --path infracost.json --format teams-message --webhook-url $TEAMS_WEBHOOK_URL
+
+  Send a Discord message using a template, e.g. {"content": {{ json .Text }}}:
+
+      infracost notify --path infracost.json --format webhook-message --webhook-template discord.tmpl --webhook-url $DISCORD_WEBHOOK_URL`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			format = strings.ToLower(format)
+			if !contains(validNotifyFormats, format) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--format only supports %s", strings.Join(validNotifyFormats, ", "))
+			}
+			ctx.ContextValues.SetValue("outputFormat", format)
+
+			webhookURL, _ := cmd.Flags().GetString("webhook-url")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if webhookURL == "" && !dryRun {
+				ui.PrintUsage(cmd)
+				return errors.New("--webhook-url is required")
+			}
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := output.LoadPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs)
+			if errors.As(err, &clierror.WarningError{}) {
+				logging.Logger.Warn().Msg(err.Error())
+			} else if err != nil {
+				return err
+			}
+			combined.IsCIRun = ctx.IsCIRun()
+			combined.Metadata.InfracostCommand = "notify"
+
+			thresholdValue, _ := cmd.Flags().GetFloat64("threshold")
+			threshold := decimal.NewFromFloat(thresholdValue)
+			if !output.ExceedsNotifyThreshold(combined, threshold) {
+				cmd.Printf("Notification not sent: the monthly cost did not change by more than %s\n", output.FormatCost2DP(combined.Currency, &threshold))
+				return nil
+			}
+
+			opts := output.Options{
+				DashboardEndpoint: ctx.Config.DashboardEndpoint,
+				NoColor:           ctx.Config.NoColor,
+				CurrencyFormat:    ctx.Config.CurrencyFormat,
+			}
+			opts.WebhookTemplatePath, _ = cmd.Flags().GetString("webhook-template")
+
+			b, err := output.FormatOutput(format, combined, opts)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				cmd.Println(string(b))
+				cmd.Println("Notification not sent (--dry-run was specified)")
+				return nil
+			}
+
+			err = sendNotification(ctx, webhookURL, b)
+			if err != nil {
+				return err
+			}
+
+			pricingClient := apiclient.GetPricingAPIClient(ctx)
+			err = pricingClient.AddEvent("infracost-notify", ctx.EventEnv())
+			if err != nil {
+				logging.Logger.Err(err).Msg("could not report infracost-notify event")
+			}
+
+			cmd.Println("Notification sent")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	cmd.Flags().String("format", "slack-message", "Notification format: slack-message, teams-message, webhook-message")
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validNotifyFormats, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().String("webhook-url", "", "Incoming webhook URL to POST the notification to")
+	cmd.Flags().String("webhook-template", "", "Path to a Go template used to build the webhook-message payload")
+	_ = cmd.MarkFlagFilename("webhook-template")
+	cmd.Flags().Float64("threshold", 0, "Only send if the monthly cost of a project or all projects changes by more than this amount")
+	cmd.Flags().Bool("dry-run", false, "Print the notification without sending it")
+
+	return cmd
+}
+
+// sendNotification POSTs the notification payload to the webhook URL.
+func sendNotification(ctx *config.RunContext, webhookURL string, payload []byte) error {
+	tlsConfig, err := loadTLSConfigFromEnv(ctx)
+	if err != nil {
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Transport: transport, Timeout: 30 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx.Context(), http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Error creating notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error sending notification: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Error sending notification: webhook returned %s", res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package main_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestNotifyHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--help"}, nil)
+}
+
+func TestNotifyBelowThreshold(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json", "--webhook-url", "http://localhost:1", "--threshold", "100000"}, nil)
+}
+
+func TestNotifyTeamsMessageDryRun(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--format", "teams-message", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json", "--dry-run"}, nil)
+}
+
+func TestNotifyWebhookMessageTemplateDryRun(t *testing.T) {
+	tmpl := filepath.Join(t.TempDir(), "discord.tmpl")
+	err := os.WriteFile(tmpl, []byte(`{"content": {{ json .Text }}}`), 0600)
+	require.NoError(t, err)
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--format", "webhook-message", "--webhook-template", tmpl, "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json", "--dry-run"}, nil)
+}
+
+func TestNotifySendsWebhookMessage(t *testing.T) {
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--format", "webhook-message", "--path", "./testdata/example_out.json", "--webhook-url", ts.URL}, nil)
+
+	assert.Contains(t, string(body), `"title":"Monthly estimate increased by`)
+}
+
+func TestOutputFormatTeamsMessage(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-message", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, &GoldenFileOptions{IsJSON: true})
+}
+
+func TestOutputFormatWebhookMessage(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "webhook-message", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, &GoldenFileOptions{IsJSON: true})
+}
@@ -21,12 +21,26 @@ var (
 		"diff",
 		"json",
 		"html",
+		"html-report",
+		"csv",
+		"xlsx",
+		"focus",
 		"github-comment",
 		"gitlab-comment",
 		"azure-repos-comment",
 		"bitbucket-comment",
 		"bitbucket-comment-summary",
 		"slack-message",
+		"teams-message",
+		"webhook-message",
+	}
+
+	validTemplatePathFormats = []string{
+		"github-comment",
+		"gitlab-comment",
+		"azure-repos-comment",
+		"bitbucket-comment",
+		"bitbucket-comment-summary",
 	}
 
 	validCompareToFormats = map[string]bool{
@@ -38,6 +52,8 @@ var (
 		"bitbucket-comment":         true,
 		"bitbucket-comment-summary": true,
 		"slack-message":             true,
+		"teams-message":             true,
+		"webhook-message":           true,
 	}
 )
 
@@ -50,10 +66,26 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
       infracost output --path out1.json --path out2.json --path out3.json
 
+  Create a spreadsheet with a sheet for each project from multiple Infracost JSON files:
+
+      infracost output --format xlsx --path "out*.json" --out-file infracost.xlsx # glob needs quotes
+
+  Export the estimates in the FinOps Open Cost and Usage Specification (FOCUS) format:
+
+      infracost output --format focus --path infracost.json --out-file infracost-focus.csv
+
+  Show the monthly cost of each team using the team tag:
+
+      infracost output --path infracost.json --group-by tag:team
+
   Create HTML report from multiple Infracost JSON files:
 
       infracost output --format html --path "out*.json" --out-file output.html # glob needs quotes
 
+  Create an interactive HTML report with charts that can be opened offline:
+
+      infracost output --format html-report --path infracost.json --out-file infracost-report.html
+
   Merge multiple Infracost JSON files:
 
       infracost output --format json --path "out*.json" # glob needs quotes
@@ -86,6 +118,19 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
 			}
 
+			templatePath, _ := cmd.Flags().GetString("template-path")
+			if templatePath != "" {
+				if !contains(validTemplatePathFormats, format) {
+					ui.PrintUsage(cmd)
+					return fmt.Errorf("--template-path only supports %s formats", strings.Join(validTemplatePathFormats, ", "))
+				}
+
+				err = output.ValidateTemplate(templatePath)
+				if err != nil {
+					return err
+				}
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			inputs, err := output.LoadPaths(paths)
@@ -135,6 +180,8 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
+			opts.WebhookTemplatePath, _ = cmd.Flags().GetString("webhook-template")
+			opts.TemplatePath = templatePath
 
 			validFieldsFormats := []string{"table", "html"}
 
@@ -142,6 +189,18 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				logging.Logger.Warn().Msg("fields is only supported for table and html output formats")
 			}
 
+			opts.GroupBy, _ = cmd.Flags().GetString("group-by")
+			if opts.GroupBy != "" {
+				if _, err := output.ParseGroupBy(opts.GroupBy); err != nil {
+					ui.PrintUsage(cmd)
+					return err
+				}
+
+				if !contains(validGroupByFormats, format) {
+					logging.Logger.Warn().Msg("group-by is only supported for json, table and diff output formats")
+				}
+			}
+
 			if ctx.IsCloudUploadExplicitlyEnabled() {
 				if ctx.Config.IsSelfHosted() {
 					logging.Logger.Warn().Msg("Infracost Cloud is part of Infracost's hosted services. Contact hello@infracost.io for help.")
@@ -167,6 +226,9 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				if err != nil {
 					return err
 				}
+			} else if format == "xlsx" {
+				// xlsx is binary so don't add a trailing newline
+				cmd.Print(string(b))
 			} else {
 				cmd.Println(string(b))
 			}
@@ -178,10 +240,15 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
 	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, html-report, csv, xlsx, focus, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message, teams-message, webhook-message")
+	cmd.Flags().String("template-path", "", "Path to a Go template that replaces the built-in template of the comment formats")
+	_ = cmd.MarkFlagFilename("template-path")
+	cmd.Flags().String("webhook-template", "", "Path to a Go template used to build the webhook-message payload")
+	_ = cmd.MarkFlagFilename("webhook-template")
 	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 	cmd.Flags().Bool("show-skipped", false, "List unsupported resources")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	addGroupByFlag(cmd)
 
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func pricingCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage local pricing snapshots for offline runs",
+		Long: `Manage local pricing snapshots for offline runs.
+
+A pricing snapshot contains the prices needed to estimate a set of projects. Snapshots
+can be exported on a machine with access to the Cloud Pricing API and imported on
+machines without it, e.g. air-gapped CI agents.`,
+		Example: `  Export the prices used by a Terraform directory:
+
+      infracost pricing export --path /code --out-file pricing-snapshot.json
+
+  Import the snapshot on an offline machine:
+
+      infracost pricing import --path pricing-snapshot.json
+      infracost breakdown --path /code
+
+  Serve a snapshot to other machines as a pricing API:
+
+      infracost pricing serve --path pricing-snapshot.json --port 4000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(pricingExportCmd(ctx), pricingImportCmd(ctx), pricingServeCmd(ctx))
+
+	return cmd
+}
+
+func pricingExportCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the prices used by projects to a pricing snapshot file",
+		Long:  "Export the prices used by projects to a pricing snapshot file",
+		Example: `  Use Terraform directory:
+
+      infracost pricing export --path /code --out-file pricing-snapshot.json
+
+  Use an Infracost config file:
+
+      infracost pricing export --config-file infracost.yml --out-file pricing-snapshot.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: checkAPIKeyIsValid(ctx, func(cmd *cobra.Command, args []string) error {
+			if ctx.Config.IsOfflinePricing() {
+				return fmt.Errorf("Cannot export prices while using the pricing snapshot %s. Unset %s to export prices from the Cloud Pricing API.",
+					ctx.Config.PricingSnapshotPath,
+					ui.PrimaryString("INFRACOST_PRICING_SNAPSHOT_PATH"),
+				)
+			}
+
+			outFile, _ := cmd.Flags().GetString("out-file")
+			if outFile == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--out-file flag is required")
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			snapshot := apiclient.NewPricingSnapshot(ctx.Config.Currency)
+			apiclient.GetPricingAPIClient(ctx).RecordTo(snapshot)
+
+			pr, err := newParallelRunner(cmd, ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = pr.run()
+			if err != nil {
+				return err
+			}
+
+			pr.pricingFetcher.LogWarnings()
+
+			err = snapshot.WriteToPath(outFile)
+			if err != nil {
+				return fmt.Errorf("Unable to save pricing snapshot %w", err)
+			}
+
+			logging.Logger.Info().Msgf("Exported %d prices to %s", snapshot.Len(), outFile)
+
+			return nil
+		}),
+	}
+
+	addRunFlags(cmd)
+	cmd.Flags().String("out-file", "", "Save the pricing snapshot to a file")
+
+	_ = cmd.MarkFlagFilename("out-file", "json")
+
+	return cmd
+}
+
+func pricingImportCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a pricing snapshot and use it for all future runs",
+		Long: fmt.Sprintf(`Import a pricing snapshot and use it for all future runs.
+
+The snapshot is merged into %s and the pricing_snapshot_path
+setting is saved to %s.`, config.PricingSnapshotFilePath(), config.ConfigurationFilePath()),
+		Example: `  Import a snapshot exported on another machine:
+
+      infracost pricing import --path pricing-snapshot.json
+
+  Replace any previously imported prices:
+
+      infracost pricing import --path pricing-snapshot.json --replace`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			if path == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--path flag is required")
+			}
+
+			imported, err := apiclient.LoadPricingSnapshot(path)
+			if err != nil {
+				return err
+			}
+
+			snapshot := imported
+			dest := config.PricingSnapshotFilePath()
+			replace, _ := cmd.Flags().GetBool("replace")
+
+			if !replace && config.FileExists(dest) {
+				existing, err := apiclient.LoadPricingSnapshot(dest)
+				if err != nil {
+					logging.Logger.Warn().Msgf("Replacing invalid pricing snapshot %s: %s", dest, err)
+				} else {
+					err = existing.Merge(imported)
+					if err != nil {
+						return fmt.Errorf("%w, use --replace to overwrite the existing snapshot", err)
+					}
+
+					snapshot = existing
+				}
+			}
+
+			err = snapshot.WriteToPath(dest)
+			if err != nil {
+				return fmt.Errorf("Unable to save pricing snapshot %w", err)
+			}
+
+			ctx.Config.Configuration.PricingSnapshotPath = dest
+			err = ctx.Config.Configuration.Save()
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Imported %d prices, %s now contains %d %s prices.\n", imported.Len(), dest, snapshot.Len(), snapshot.Currency)
+			cmd.Printf("Prices will be resolved from this snapshot. To use the Cloud Pricing API again run:\n\n  %s\n",
+				ui.PrimaryString(`infracost configure set pricing_snapshot_path ""`),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the pricing snapshot file")
+	cmd.Flags().Bool("replace", false, "Replace previously imported prices instead of merging them")
+
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	return cmd
+}
+
+func pricingServeCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a pricing snapshot as a self-hosted pricing API",
+		Long: `Serve a pricing snapshot as a self-hosted pricing API.
+
+The server answers the same GraphQL queries as the Cloud Pricing API from a local
+snapshot, so many machines can share one set of prices by setting their
+pricing_api_endpoint (or INFRACOST_PRICING_API_ENDPOINT) to the server's URL.
+Prices that aren't in the snapshot are reported as missing by the clients.
+
+The server does not check API keys, clients only need INFRACOST_API_KEY to be set.`,
+		Example: `  Serve a snapshot exported with infracost pricing export:
+
+      infracost pricing serve --path pricing-snapshot.json --port 4000
+
+  Use the server from another machine:
+
+      INFRACOST_PRICING_API_ENDPOINT=http://pricing.internal:4000 infracost breakdown --path /code`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			if path == "" {
+				path = ctx.Config.PricingSnapshotPath
+			}
+
+			if path == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--path flag is required")
+			}
+
+			snapshot, err := apiclient.LoadPricingSnapshot(path)
+			if err != nil {
+				return err
+			}
+
+			host, _ := cmd.Flags().GetString("host")
+			port, _ := cmd.Flags().GetInt("port")
+
+			server := &http.Server{
+				Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+				Handler:           apiclient.NewPricingSnapshotServer(snapshot),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			sigCtx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- server.ListenAndServe()
+			}()
+
+			cmd.Printf("Serving %d %s prices from %s on %s\n", snapshot.Len(), snapshot.Currency, path, server.Addr)
+
+			select {
+			case err := <-errCh:
+				return fmt.Errorf("Pricing server stopped %w", err)
+			case <-sigCtx.Done():
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			return server.Shutdown(shutdownCtx)
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the pricing snapshot file, defaults to the imported snapshot")
+	cmd.Flags().String("host", "0.0.0.0", "Host to listen on")
+	cmd.Flags().Int("port", 4000, "Port to listen on")
+
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	return cmd
+}
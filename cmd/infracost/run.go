@@ -26,6 +26,7 @@ import (
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/policy"
 	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/schema"
@@ -33,6 +34,9 @@ import (
 	"github.com/infracost/infracost/internal/usage"
 )
 
+// maxForecastMonths is the longest forecast that can be requested with --forecast-months.
+const maxForecastMonths = 120
+
 type projectJob struct {
 	index    int
 	provider schema.Provider
@@ -53,6 +57,8 @@ func addRunFlags(cmd *cobra.Command) {
 
 	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
 	cmd.Flags().String("usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	cmd.Flags().String("local-policy-file", "", "Path to a policy file that is evaluated locally against resources and their costs")
+	cmd.Flags().String("price-overrides-file", "", "Path to a file of rules that replace list prices with negotiated prices")
 
 	cmd.Flags().String("project-name", "", "Name of project in the output. Defaults to path or git repo name")
 
@@ -72,6 +78,8 @@ func addRunFlags(cmd *cobra.Command) {
 
 	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with missing resources, needs usage-file too (experimental)")
 
+	cmd.Flags().Int("forecast-months", 0, "Number of months to forecast costs for, using the growth rates in the forecast section of the usage file")
+
 	_ = cmd.MarkFlagFilename("path", "json", "tf", "tofu")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
 	_ = cmd.MarkFlagFilename("usage-file", "yml")
@@ -139,6 +147,8 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	r.IsCIRun = runCtx.IsCIRun()
 	r.Currency = runCtx.Config.Currency
 	r.Metadata = output.NewMetadata(runCtx)
+	r.GuardrailViolations = output.EvaluateGuardrails(r, runCtx.Config.Guardrails, runCtx.Config.Projects)
+	runCtx.ContextValues.SetValue("guardrailViolationCount", len(r.GuardrailViolations))
 
 	if runCtx.IsCloudUploadExplicitlyEnabled() {
 		dashboardClient := apiclient.NewDashboardAPIClient(runCtx)
@@ -164,6 +174,7 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		NoColor:           runCtx.Config.NoColor,
 		Fields:            runCtx.Config.Fields,
 		CurrencyFormat:    runCtx.Config.CurrencyFormat,
+		GroupBy:           runCtx.Config.GroupBy,
 	})
 	if err != nil {
 		return err
@@ -193,6 +204,14 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		cmd.Println(string(b))
 	}
 
+	if len(r.GuardrailViolations) > 0 {
+		return r.GuardrailViolations
+	}
+
+	if failures := output.LocalPolicyFailures(r); len(failures) > 0 {
+		return failures
+	}
+
 	return nil
 }
 
@@ -207,6 +226,8 @@ type parallelRunner struct {
 	prior          *output.Root
 	parallelism    int
 	pricingFetcher *prices.PriceFetcher
+	localPolicies  *policy.File
+	priceOverrides *prices.OverridesFile
 }
 
 func newParallelRunner(cmd *cobra.Command, runCtx *config.RunContext) (*parallelRunner, error) {
@@ -238,13 +259,42 @@ func newParallelRunner(cmd *cobra.Command, runCtx *config.RunContext) (*parallel
 
 	metrics.GetCounter("parallel_runner.parallelism", false).Add(parallelism)
 
+	pricingFetcher := prices.NewPriceFetcher(runCtx, false)
+	if runCtx.Config.IsOfflinePricing() {
+		pricingFetcher, err = prices.NewOfflinePriceFetcher(runCtx, false)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading pricing snapshot. %w", err)
+		}
+		runCtx.ContextValues.SetValue("offlinePricing", true)
+	}
+
+	var localPolicies *policy.File
+	if runCtx.Config.LocalPolicyFile != "" {
+		localPolicies, err = policy.LoadFile(runCtx.Config.LocalPolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading local policy file. %w", err)
+		}
+		runCtx.ContextValues.SetValue("localPolicyCount", len(localPolicies.Policies))
+	}
+
+	var priceOverrides *prices.OverridesFile
+	if runCtx.Config.PriceOverridesFile != "" {
+		priceOverrides, err = prices.LoadOverridesFile(runCtx.Config.PriceOverridesFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading price overrides file. %w", err)
+		}
+		runCtx.ContextValues.SetValue("priceOverrideCount", len(priceOverrides.Overrides))
+	}
+
 	return &parallelRunner{
 		parallelism:    parallelism,
 		runCtx:         runCtx,
 		cmd:            cmd,
 		pathMuxs:       pathMuxs,
 		prior:          prior,
-		pricingFetcher: prices.NewPriceFetcher(runCtx, false),
+		pricingFetcher: pricingFetcher,
+		localPolicies:  localPolicies,
+		priceOverrides: priceOverrides,
 	}, nil
 }
 
@@ -464,6 +514,12 @@ func (r *parallelRunner) runProvider(job projectJob) (out *projectOutput, err er
 		return nil, clierror.NewCLIError(errors.New(m), "Cannot use Terraform state JSON with the infracost diff command")
 	}
 
+	if r.cmd.Name() == "diff" && job.provider.Type() == "pulumi_state_json" {
+		m := "Cannot use a Pulumi stack export with the infracost diff command.\n\n"
+		m += fmt.Sprintf("Use the %s flag to specify the path to the output of %s instead.", ui.PrimaryString("--path"), ui.PrimaryString("pulumi preview --json"))
+		return nil, clierror.NewCLIError(errors.New(m), "Cannot use Pulumi stack export with the infracost diff command")
+	}
+
 	name := job.provider.ProjectName()
 	displayName := ui.ProjectDisplayName(r.runCtx, name)
 
@@ -549,13 +605,17 @@ func (r *parallelRunner) runProvider(job projectJob) (out *projectOutput, err er
 	_ = r.uploadCloudResourceIDs(projects)
 
 	buildResourcesTimer := metrics.GetTimer("parallel_runner.build_resources.duration", false, path).Start()
-	r.buildResources(projects)
+	projectPtrToUsageMap := r.buildResources(projects)
 	buildResourcesTimer.Stop()
 
 	costingTimer := metrics.GetTimer("parallel_runner.costing.duration", false, path).Start()
 	defer costingTimer.Stop()
 	logging.Logger.Debug().Msg("Retrieving cloud prices to calculate costs")
 
+	// Usage file commitments come first so they take precedence over the
+	// commitments in the config file.
+	commitments := append(append([]config.Commitment{}, usageFile.Commitments...), r.runCtx.Config.Commitments...)
+
 	for _, project := range projects {
 		if err = r.pricingFetcher.PopulatePrices(project); err != nil {
 			logging.Logger.Debug().Err(err).Msgf("failed to populate prices for project %s", project.Name)
@@ -587,11 +647,30 @@ func (r *parallelRunner) runProvider(job projectJob) (out *projectOutput, err er
 
 			return nil, err
 		}
+
+		r.priceOverrides.Apply(project)
+
+		if err = r.pricingFetcher.ApplyCommitments(project, commitments); err != nil {
+			logging.Logger.Debug().Err(err).Msgf("failed to apply commitments for project %s", project.Name)
+			return nil, err
+		}
+
 		schema.CalculateCosts(project)
 
+		if r.localPolicies != nil && project.Metadata != nil {
+			project.Metadata.Policies = append(project.Metadata.Policies, r.localPolicies.EvaluateProject(project)...)
+		}
+
 		project.CalculateDiff()
 	}
 
+	if r.runCtx.Config.ForecastMonths > 0 {
+		if err = r.forecastCosts(projects, projectPtrToUsageMap, usageFile.Forecast, commitments); err != nil {
+			logging.Logger.Debug().Err(err).Msg("failed to forecast costs")
+			return nil, err
+		}
+	}
+
 	t2 := time.Now()
 	taken := t2.Sub(t1).Milliseconds()
 	projectContext.ContextValues.SetValue("tfProjectRunTimeMs", taken)
@@ -636,13 +715,52 @@ func (r *parallelRunner) hasCloudResourceIDToUpload(projects []*schema.Project)
 	return false
 }
 
-func (r *parallelRunner) buildResources(projects []*schema.Project) {
+func (r *parallelRunner) buildResources(projects []*schema.Project) map[*schema.Project]schema.UsageMap {
 	var projectPtrToUsageMap map[*schema.Project]schema.UsageMap
 	if r.runCtx.Config.UsageAPIEndpoint != "" {
 		projectPtrToUsageMap = r.fetchProjectUsage(projects)
 	}
 
 	schema.BuildResources(projects, projectPtrToUsageMap)
+
+	return projectPtrToUsageMap
+}
+
+// forecastCosts sets the monthly cost of each resource for every month of the
+// forecast. The first month uses the current costs, the resources are then
+// rebuilt with their usage grown by the growth rates and priced for each
+// following month, with the same price overrides and commitments as the
+// current costs. A fork of the price fetcher is used so that prices that
+// can't be found are only reported once.
+func (r *parallelRunner) forecastCosts(projects []*schema.Project, projectPtrToUsageMap map[*schema.Project]schema.UsageMap, growth *schema.UsageGrowth, commitments []config.Commitment) error {
+	pricingFetcher := r.pricingFetcher.Fork()
+
+	for _, project := range projects {
+		project.AddForecastMonth(project)
+
+		for month := 2; month <= r.runCtx.Config.ForecastMonths; month++ {
+			if growth == nil {
+				project.AddForecastMonth(project)
+				continue
+			}
+
+			forecast := project.BuildForecastProject(projectPtrToUsageMap[project], growth, month)
+			if err := pricingFetcher.PopulatePrices(forecast); err != nil {
+				return err
+			}
+
+			r.priceOverrides.Apply(forecast)
+
+			if err := pricingFetcher.ApplyCommitments(forecast, commitments); err != nil {
+				return err
+			}
+
+			schema.CalculateCosts(forecast)
+			project.AddForecastMonth(forecast)
+		}
+	}
+
+	return nil
 }
 
 func (r *parallelRunner) fetchProjectUsage(projects []*schema.Project) map[*schema.Project]schema.UsageMap {
@@ -858,8 +976,35 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
+	cfg.ForecastMonths, _ = cmd.Flags().GetInt("forecast-months")
 	cfg.UsageFilePath, _ = cmd.Flags().GetString("usage-file")
 
+	if cmd.Flags().Changed("local-policy-file") {
+		cfg.LocalPolicyFile, _ = cmd.Flags().GetString("local-policy-file")
+	}
+
+	if cmd.Flags().Changed("price-overrides-file") {
+		cfg.PriceOverridesFile, _ = cmd.Flags().GetString("price-overrides-file")
+	}
+
+	if cmd.Flags().Changed("explore-details") {
+		cfg.ExploreDetails, _ = cmd.Flags().GetBool("explore-details")
+		if cfg.Format != "json" {
+			logging.Logger.Warn().Msg("explore-details is only supported for json output format")
+		}
+	}
+
+	if cmd.Flags().Changed("group-by") {
+		cfg.GroupBy, _ = cmd.Flags().GetString("group-by")
+		if _, err := output.ParseGroupBy(cfg.GroupBy); err != nil {
+			return err
+		}
+
+		if !contains(validGroupByFormats, cfg.Format) {
+			logging.Logger.Warn().Msg("group-by is only supported for json, table and diff output formats")
+		}
+	}
+
 	includeAllFields := "all"
 	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
 	validFieldsFormats := []string{"table", "html"}
@@ -935,6 +1080,10 @@ func checkRunConfig(warningWriter io.Writer, cfg *config.Config) error {
 		}
 	}
 
+	if cfg.ForecastMonths < 0 || cfg.ForecastMonths > maxForecastMonths {
+		return fmt.Errorf("--forecast-months must be between 0 and %d", maxForecastMonths)
+	}
+
 	if money.GetCurrency(cfg.Currency) == nil {
 		logging.Logger.Warn().Msgf("Ignoring unknown currency '%s', using USD.\n", cfg.Currency)
 		cfg.Currency = "USD"
@@ -1020,6 +1169,12 @@ type runCommandMiddleware func(ctx *config.RunContext, next runCommandFunc) runC
 // valid before running the command.
 func checkAPIKeyIsValid(ctx *config.RunContext, next runCommandFunc) runCommandFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		// Offline runs resolve prices from a local pricing snapshot so don't
+		// need an API key or access to the Cloud Pricing API.
+		if ctx.Config.IsOfflinePricing() {
+			return next(cmd, args)
+		}
+
 		if ctx.Config.APIKey == "" {
 			return fmt.Errorf("%s %s %s %s %s\n%s %s.\n%s %s %s",
 				ui.PrimaryString("INFRACOST_API_KEY"),
@@ -38,6 +38,10 @@ type PricingAPIClient struct {
 	cacheFile string
 
 	cache *lru.TwoQueueCache[uint64, cacheValue]
+
+	// snapshot, if set, records every pricing result returned so that it
+	// can be exported and used for offline runs.
+	snapshot *PricingSnapshot
 }
 
 type cacheValue struct {
@@ -135,7 +139,9 @@ func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
 			apiKey:     ctx.Config.APIKey,
 			uuid:       ctx.UUID(),
 		},
-		EventsDisabled: ctx.Config.EventsDisabled,
+		// Offline runs are expected to have no network access, so there is no
+		// point trying to send events.
+		EventsDisabled: ctx.Config.EventsDisabled || ctx.Config.IsOfflinePricing(),
 	}
 
 	return c
@@ -247,7 +253,13 @@ func (c *PricingAPIClient) AddEvent(name string, env map[string]interface{}) err
 	return err
 }
 
-func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *schema.PriceFilter, currency string) GraphQLQuery {
+// RecordTo sets a PricingSnapshot that all subsequent pricing results are
+// recorded in.
+func (c *PricingAPIClient) RecordTo(snapshot *PricingSnapshot) {
+	c.snapshot = snapshot
+}
+
+func buildQuery(product *schema.ProductFilter, price *schema.PriceFilter, currency string) GraphQLQuery {
 	if currency == "" {
 		currency = "USD"
 	}
@@ -259,6 +271,7 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	query := fmt.Sprintf(`
 		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
 			products(filter: $productFilter) {
+				sku
 				prices(filter: $priceFilter) {
 					priceHash
 					%s
@@ -273,6 +286,10 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 // BatchRequests batches all the queries for these resources so we can use less GraphQL requests
 // Use PriceQueryKeys to keep track of which query maps to which sub-resource and price component.
 func (c *PricingAPIClient) BatchRequests(resources []*schema.Resource, batchSize int, currency string) []BatchRequest {
+	return batchRequests(resources, batchSize, currency)
+}
+
+func batchRequests(resources []*schema.Resource, batchSize int, currency string) []BatchRequest {
 	reqs := make([]BatchRequest, 0)
 
 	keys := make([]PriceQueryKey, 0)
@@ -281,13 +298,13 @@ func (c *PricingAPIClient) BatchRequests(resources []*schema.Resource, batchSize
 	for _, r := range resources {
 		for _, component := range r.CostComponents {
 			keys = append(keys, PriceQueryKey{r, component})
-			queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter, currency))
+			queries = append(queries, buildQuery(component.ProductFilter, component.PriceFilter, currency))
 		}
 
 		for _, subresource := range r.FlattenedSubResources() {
 			for _, component := range subresource.CostComponents {
 				keys = append(keys, PriceQueryKey{subresource, component})
-				queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter, currency))
+				queries = append(queries, buildQuery(component.ProductFilter, component.PriceFilter, currency))
 			}
 		}
 	}
@@ -425,5 +442,11 @@ func (c *PricingAPIClient) PerformRequest(req BatchRequest) ([]PriceQueryResult,
 		}
 	}
 
+	if c.snapshot != nil {
+		for _, re := range res {
+			c.snapshot.Record(re.Query, re.Result)
+		}
+	}
+
 	return res, nil
 }
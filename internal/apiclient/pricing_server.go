@@ -0,0 +1,138 @@
+package apiclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// maxPricingServerRequestSize is the largest request body the pricing server
+// accepts. Batches sent by PricingAPIClient are well below this.
+const maxPricingServerRequestSize = 32 << 20
+
+var (
+	// queryCurrencyRegex matches the currency field requested by buildQuery.
+	queryCurrencyRegex = regexp.MustCompile(`priceHash\s+([A-Za-z]{3})\b`)
+
+	emptyProductsResult = json.RawMessage(`{"data":{"products":[]}}`)
+)
+
+// PricingSnapshotServer is an http.Handler that answers the GraphQL queries
+// sent by PricingAPIClient from a PricingSnapshot. It lets a single snapshot
+// be shared by many machines by pointing their pricing_api_endpoint at the
+// server. Queries that aren't in the snapshot return no products, so they are
+// reported as missing prices by the client.
+type PricingSnapshotServer struct {
+	snapshot *PricingSnapshot
+}
+
+// NewPricingSnapshotServer returns a PricingSnapshotServer backed by snapshot.
+func NewPricingSnapshotServer(snapshot *PricingSnapshot) *PricingSnapshotServer {
+	return &PricingSnapshotServer{snapshot: snapshot}
+}
+
+type pricingServerQuery struct {
+	Query     string `json:"query"`
+	Variables struct {
+		ProductFilter *schema.ProductFilter `json:"productFilter"`
+		PriceFilter   *schema.PriceFilter   `json:"priceFilter"`
+	} `json:"variables"`
+}
+
+func (s *PricingSnapshotServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/health":
+		writePricingServerResponse(w, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"currency": s.snapshot.Currency,
+			"prices":   s.snapshot.Len(),
+		})
+	case "/graphql":
+		if r.Method != http.MethodPost {
+			writePricingServerError(w, http.StatusMethodNotAllowed, "Only POST requests are supported")
+			return
+		}
+
+		s.serveGraphQL(w, r)
+	default:
+		writePricingServerError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// serveGraphQL answers a single query or a batch of queries. Batches are
+// answered with an array of results in the same order as the queries.
+func (s *PricingSnapshotServer) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPricingServerRequestSize))
+	if err != nil {
+		writePricingServerError(w, http.StatusBadRequest, "Could not read request body")
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	batch := bytes.HasPrefix(body, []byte("["))
+
+	var queries []pricingServerQuery
+	if batch {
+		err = json.Unmarshal(body, &queries)
+	} else {
+		queries = make([]pricingServerQuery, 1)
+		err = json.Unmarshal(body, &queries[0])
+	}
+	if err != nil {
+		writePricingServerError(w, http.StatusBadRequest, fmt.Sprintf("Invalid GraphQL request: %s", err))
+		return
+	}
+
+	results := make([]json.RawMessage, len(queries))
+	var hit int
+	for i, q := range queries {
+		if m := queryCurrencyRegex.FindStringSubmatch(q.Query); m != nil && !strings.EqualFold(m[1], s.snapshot.Currency) {
+			writePricingServerError(w, http.StatusBadRequest, fmt.Sprintf("Currency %s is not available, this server only has %s prices", m[1], s.snapshot.Currency))
+			return
+		}
+
+		results[i] = emptyProductsResult
+		if q.Variables.ProductFilter == nil {
+			continue
+		}
+
+		if result, ok := s.snapshot.Lookup(q.Variables.ProductFilter, q.Variables.PriceFilter); ok {
+			results[i] = json.RawMessage(result.Raw)
+			hit++
+		}
+	}
+
+	logging.Logger.Debug().Msgf("%d/%d queries were resolved from the pricing snapshot", hit, len(queries))
+
+	if batch {
+		writePricingServerResponse(w, http.StatusOK, results)
+		return
+	}
+
+	writePricingServerResponse(w, http.StatusOK, results[0])
+}
+
+func writePricingServerError(w http.ResponseWriter, status int, msg string) {
+	writePricingServerResponse(w, status, APIErrorResponse{Error: msg})
+}
+
+func writePricingServerResponse(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		logging.Logger.Error().Err(err).Msg("could not marshal pricing server response")
+		status = http.StatusInternalServerError
+		b = []byte(`{"error":"Internal server error"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
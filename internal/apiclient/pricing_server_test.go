@@ -0,0 +1,150 @@
+package apiclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPricingSnapshotServer_PerformRequest(t *testing.T) {
+	found := &schema.CostComponent{
+		Name: "Instance usage",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.micro")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+	}
+	missing := &schema.CostComponent{
+		Name: "Storage",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Sku:        strPtr("missing"),
+		},
+	}
+	resource := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{found, missing},
+	}
+
+	snapshot := NewPricingSnapshot("USD")
+	snapshot.Record(buildQuery(found.ProductFilter, found.PriceFilter, "USD"), gjson.Parse(`{"data":{"products":[{"sku":"ABC","prices":[{"priceHash":"abc","USD":"0.0104"}]}]}}`))
+
+	ts := httptest.NewServer(NewPricingSnapshotServer(snapshot))
+	defer ts.Close()
+
+	c := &PricingAPIClient{
+		APIClient: APIClient{
+			httpClient: ts.Client(),
+			endpoint:   ts.URL,
+			apiKey:     "any",
+		},
+	}
+
+	reqs := c.BatchRequests([]*schema.Resource{resource}, 10, "USD")
+	require.Len(t, reqs, 1)
+
+	res, err := c.PerformRequest(reqs[0])
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	assert.Equal(t, found, res[0].CostComponent)
+	assert.Equal(t, "0.0104", res[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, "ABC", res[0].Result.Get("data.products.0.sku").String())
+	assert.Equal(t, missing, res[1].CostComponent)
+	assert.True(t, res[1].Result.Get("data.products").Exists())
+	assert.Empty(t, res[1].Result.Get("data.products").Array())
+
+	// the server only has USD prices so other currencies are rejected.
+	reqs = c.BatchRequests([]*schema.Resource{resource}, 10, "EUR")
+	_, err = c.PerformRequest(reqs[0])
+	assert.ErrorContains(t, err, "Currency EUR is not available")
+}
+
+func TestPricingSnapshotServer_ServeHTTP(t *testing.T) {
+	snapshot := NewPricingSnapshot("USD")
+	ts := httptest.NewServer(NewPricingSnapshotServer(snapshot))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "health",
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ok","currency":"USD","prices":0}`,
+		},
+		{
+			name:       "single query",
+			method:     http.MethodPost,
+			path:       "/graphql",
+			body:       `{"query":"","variables":null}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"products":[]}}`,
+		},
+		{
+			name:       "batch of queries",
+			method:     http.MethodPost,
+			path:       "/graphql",
+			body:       `[{"query":"","variables":{"productFilter":{"vendorName":"aws"}}},{"query":"","variables":null}]`,
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"data":{"products":[]}},{"data":{"products":[]}}]`,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPost,
+			path:       "/graphql",
+			body:       `[{"query":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "get graphql",
+			method:     http.MethodGet,
+			path:       "/graphql",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unknown path",
+			method:     http.MethodGet,
+			path:       "/prices",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			resp, err := ts.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PricingSnapshotVersion is the current version of the pricing snapshot file
+// format. Snapshots with a different version are rejected when loaded so that
+// air-gapped environments don't silently use prices they can't interpret.
+const PricingSnapshotVersion = "0.1"
+
+// PricingSnapshot is a portable set of Cloud Pricing API results. Each entry is
+// keyed by the ProductFilter and PriceFilter variables that buildQuery sends
+// for a cost component, so a snapshot can answer exactly the same queries that
+// PricingAPIClient would send to the API.
+type PricingSnapshot struct {
+	Version   string                          `json:"version"`
+	Currency  string                          `json:"currency"`
+	CreatedAt time.Time                       `json:"createdAt"`
+	Prices    map[string]PricingSnapshotEntry `json:"prices"`
+
+	mu *sync.Mutex
+}
+
+// PricingSnapshotEntry is a single recorded pricing query result. The product
+// and price filters are stored alongside the raw result so that snapshots are
+// human-readable and can be audited before being imported.
+type PricingSnapshotEntry struct {
+	ProductFilter *schema.ProductFilter `json:"productFilter"`
+	PriceFilter   *schema.PriceFilter   `json:"priceFilter,omitempty"`
+	Result        json.RawMessage       `json:"result"`
+}
+
+// NewPricingSnapshot returns an empty PricingSnapshot for the given currency.
+func NewPricingSnapshot(currency string) *PricingSnapshot {
+	if currency == "" {
+		currency = "USD"
+	}
+
+	return &PricingSnapshot{
+		Version:   PricingSnapshotVersion,
+		Currency:  currency,
+		CreatedAt: time.Now().UTC(),
+		Prices:    map[string]PricingSnapshotEntry{},
+		mu:        &sync.Mutex{},
+	}
+}
+
+// LoadPricingSnapshot reads and validates a pricing snapshot from the given path.
+func LoadPricingSnapshot(path string) (*PricingSnapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pricing snapshot %s: %w", path, err)
+	}
+
+	var s PricingSnapshot
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse pricing snapshot %s: %w", path, err)
+	}
+
+	if s.Version != PricingSnapshotVersion {
+		return nil, fmt.Errorf("pricing snapshot %s has unsupported version '%s', expected '%s'", path, s.Version, PricingSnapshotVersion)
+	}
+
+	if s.Prices == nil {
+		s.Prices = map[string]PricingSnapshotEntry{}
+	}
+	s.mu = &sync.Mutex{}
+
+	return &s, nil
+}
+
+// WriteToPath writes the snapshot as JSON to the given path, creating any
+// parent directories that don't exist.
+func (s *PricingSnapshot) WriteToPath(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal pricing snapshot: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0600)
+}
+
+// Len returns the number of recorded prices in the snapshot.
+func (s *PricingSnapshot) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.Prices)
+}
+
+// Keys returns the sorted query keys of the snapshot.
+func (s *PricingSnapshot) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.Prices))
+	for k := range s.Prices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Merge copies all the entries of other into the snapshot. Entries in other
+// take precedence over existing entries with the same key.
+func (s *PricingSnapshot) Merge(other *PricingSnapshot) error {
+	if other.Currency != s.Currency {
+		return fmt.Errorf("cannot merge pricing snapshot with currency %s into snapshot with currency %s", other.Currency, s.Currency)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, v := range other.Prices {
+		s.Prices[k] = v
+	}
+	s.CreatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// Record stores the result of a pricing query in the snapshot. Queries without
+// a product filter are skipped as they can't be looked up in the API.
+func (s *PricingSnapshot) Record(query GraphQLQuery, result gjson.Result) {
+	product, price := queryFilters(query)
+	if product == nil || !result.Exists() {
+		return
+	}
+
+	key := PricingSnapshotKey(product, price)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Prices[key] = PricingSnapshotEntry{
+		ProductFilter: product,
+		PriceFilter:   price,
+		Result:        json.RawMessage(result.Raw),
+	}
+}
+
+// Lookup returns the recorded result for the filters and whether it was found.
+func (s *PricingSnapshot) Lookup(product *schema.ProductFilter, price *schema.PriceFilter) (gjson.Result, bool) {
+	key := PricingSnapshotKey(product, price)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.Prices[key]
+	if !ok {
+		return gjson.Result{}, false
+	}
+
+	return gjson.ParseBytes(entry.Result), true
+}
+
+// PricingSnapshotKey returns the key used to store a price lookup in a
+// snapshot. The key is the SHA256 of the JSON encoded filters, using the same
+// productFilter/priceFilter variables that buildQuery sends to the API. JSON is
+// used rather than a structural hash so that keys are stable across Infracost
+// versions. The currency is not part of the key as a snapshot only ever holds a
+// single currency.
+func PricingSnapshotKey(product *schema.ProductFilter, price *schema.PriceFilter) string {
+	b, err := json.ConfigCompatibleWithStandardLibrary.Marshal(map[string]interface{}{
+		"productFilter": product,
+		"priceFilter":   price,
+	})
+	if err != nil {
+		logging.Logger.Debug().Err(err).Msg("failed to marshal pricing filters for snapshot key")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func queryFilters(query GraphQLQuery) (*schema.ProductFilter, *schema.PriceFilter) {
+	product, _ := query.Variables["productFilter"].(*schema.ProductFilter)
+	price, _ := query.Variables["priceFilter"].(*schema.PriceFilter)
+
+	return product, price
+}
+
+// PricingSnapshotResolver resolves price queries from a local PricingSnapshot
+// instead of the Cloud Pricing API. It can be used by prices.PriceFetcher in
+// place of a PricingAPIClient for offline runs.
+type PricingSnapshotResolver struct {
+	snapshot *PricingSnapshot
+}
+
+// NewPricingSnapshotResolver returns a PricingSnapshotResolver backed by snapshot.
+func NewPricingSnapshotResolver(snapshot *PricingSnapshot) *PricingSnapshotResolver {
+	return &PricingSnapshotResolver{snapshot: snapshot}
+}
+
+// BatchRequests batches the price queries for the resources in the same way as
+// PricingAPIClient.BatchRequests so the snapshot keys line up.
+func (r *PricingSnapshotResolver) BatchRequests(resources []*schema.Resource, batchSize int, currency string) []BatchRequest {
+	return batchRequests(resources, batchSize, currency)
+}
+
+// PerformRequest resolves every query in the batch from the snapshot. Queries
+// that aren't in the snapshot are returned with an empty result so that they
+// are reported as missing prices by the caller.
+func (r *PricingSnapshotResolver) PerformRequest(req BatchRequest) ([]PriceQueryResult, error) {
+	logging.Logger.Debug().Msgf("Getting pricing details for %d cost components from local pricing snapshot", len(req.queries))
+
+	res := make([]PriceQueryResult, len(req.keys))
+	var hit int
+	for i, key := range req.keys {
+		res[i].PriceQueryKey = key
+		res[i].Query = req.queries[i]
+
+		if key.CostComponent.ProductFilter == nil {
+			continue
+		}
+
+		if result, ok := r.snapshot.Lookup(key.CostComponent.ProductFilter, key.CostComponent.PriceFilter); ok {
+			res[i].Result = result
+			hit++
+		}
+	}
+
+	logging.Logger.Debug().Msgf("%d/%d queries were resolved from the pricing snapshot", hit, len(req.queries))
+
+	return res, nil
+}
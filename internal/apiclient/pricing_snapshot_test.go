@@ -0,0 +1,110 @@
+package apiclient
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPricingSnapshot_RoundTrip(t *testing.T) {
+	product := &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Service:    strPtr("AmazonEC2"),
+		Region:     strPtr("us-east-1"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "instanceType", Value: strPtr("t3.micro")},
+		},
+	}
+	price := &schema.PriceFilter{PurchaseOption: strPtr("on_demand")}
+	result := gjson.Parse(`{"data":{"products":[{"prices":[{"priceHash":"abc","USD":"0.0104"}]}]}}`)
+
+	snapshot := NewPricingSnapshot("USD")
+	snapshot.Record(buildQuery(product, price, "USD"), result)
+	snapshot.Record(buildQuery(nil, nil, "USD"), result)
+	require.Equal(t, 1, snapshot.Len())
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, snapshot.WriteToPath(path))
+
+	loaded, err := LoadPricingSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", loaded.Currency)
+	assert.Equal(t, snapshot.Keys(), loaded.Keys())
+
+	// a filter built separately should resolve to the same entry.
+	lookupProduct := &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Service:    strPtr("AmazonEC2"),
+		Region:     strPtr("us-east-1"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "instanceType", Value: strPtr("t3.micro")},
+		},
+	}
+	got, ok := loaded.Lookup(lookupProduct, &schema.PriceFilter{PurchaseOption: strPtr("on_demand")})
+	require.True(t, ok)
+	assert.Equal(t, "0.0104", got.Get("data.products.0.prices.0.USD").String())
+
+	_, ok = loaded.Lookup(lookupProduct, &schema.PriceFilter{PurchaseOption: strPtr("reserved")})
+	assert.False(t, ok)
+}
+
+func TestLoadPricingSnapshot_InvalidVersion(t *testing.T) {
+	snapshot := NewPricingSnapshot("USD")
+	snapshot.Version = "99"
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, snapshot.WriteToPath(path))
+
+	_, err := LoadPricingSnapshot(path)
+	assert.ErrorContains(t, err, "unsupported version '99'")
+}
+
+func TestPricingSnapshot_MergeCurrencyMismatch(t *testing.T) {
+	err := NewPricingSnapshot("USD").Merge(NewPricingSnapshot("EUR"))
+	assert.Error(t, err)
+}
+
+func TestPricingSnapshotResolver_PerformRequest(t *testing.T) {
+	found := &schema.CostComponent{
+		Name: "Instance usage",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Sku:        strPtr("found"),
+		},
+	}
+	missing := &schema.CostComponent{
+		Name: "Storage",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Sku:        strPtr("missing"),
+		},
+	}
+	custom := &schema.CostComponent{Name: "Custom"}
+	resource := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{found, missing, custom},
+	}
+
+	snapshot := NewPricingSnapshot("USD")
+	snapshot.Record(buildQuery(found.ProductFilter, nil, "USD"), gjson.Parse(`{"data":{"products":[{"prices":[{"priceHash":"abc","USD":"1.5"}]}]}}`))
+
+	resolver := NewPricingSnapshotResolver(snapshot)
+	reqs := resolver.BatchRequests([]*schema.Resource{resource}, 10, "USD")
+	require.Len(t, reqs, 1)
+
+	res, err := resolver.PerformRequest(reqs[0])
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	assert.Equal(t, found, res[0].CostComponent)
+	assert.Equal(t, "1.5", res[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, missing, res[1].CostComponent)
+	assert.Empty(t, res[1].Result.Get("data.products").Array())
+	assert.Equal(t, custom, res[2].CostComponent)
+	assert.False(t, res[2].Result.Exists())
+}
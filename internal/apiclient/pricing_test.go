@@ -112,7 +112,7 @@ func TestPricingAPIClient_PerformRequest(t *testing.T) {
 			},
 		},
 	}
-	q := c.buildQuery(cachedProduct, nil, "USD")
+	q := buildQuery(cachedProduct, nil, "USD")
 	k, err := hashstructure.Hash(q, hashstructure.FormatV2, nil)
 	assert.NoError(t, err)
 	c.cache.Add(k, cacheValue{Result: gjson.Parse(`{"data":{"products":[{"prices":[{"priceHash":"cached-ee3dd7e4624338037ca6fea0933a662f","USD":"0.1250000000"}]}]}`), ExpiresAt: time.Now().Add(time.Hour)})
@@ -126,7 +126,7 @@ func TestPricingAPIClient_PerformRequest(t *testing.T) {
 		`
 [
   {
-    "query": "query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {products(filter: $productFilter) {prices(filter: $priceFilter) {priceHashUSD}}}",
+    "query": "query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {products(filter: $productFilter) {skuprices(filter: $priceFilter) {priceHashUSD}}}",
     "variables": {
       "priceFilter": {
         "purchaseOption": "something else"
@@ -138,7 +138,7 @@ func TestPricingAPIClient_PerformRequest(t *testing.T) {
     }
   },
   {
-    "query": "query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {products(filter: $productFilter) {prices(filter: $priceFilter) {priceHashUSD}}}",
+    "query": "query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {products(filter: $productFilter) {skuprices(filter: $priceFilter) {priceHashUSD}}}",
     "variables": {
       "priceFilter": null,
       "productFilter": {
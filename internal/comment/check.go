@@ -0,0 +1,50 @@
+package comment
+
+import (
+	"context"
+)
+
+// CheckConclusion is the pass/fail result of a check.
+type CheckConclusion string
+
+const (
+	CheckSuccess CheckConclusion = "success"
+	CheckNeutral CheckConclusion = "neutral"
+	CheckFailure CheckConclusion = "failure"
+)
+
+var defaultCheckName = "Infracost"
+
+// Check is the result of an Infracost run that is posted to a commit as a
+// check run or commit status, so that it shows alongside the other checks of
+// a pull request.
+type Check struct {
+	// Title is a one line summary of the result, e.g. the change in the
+	// monthly cost.
+	Title string
+	// Summary is the markdown shown on the check's page. Platforms that only
+	// support a description, such as GitLab commit statuses, ignore it.
+	Summary    string
+	Conclusion CheckConclusion
+	// DetailsURL is an optional link to the full details of the run.
+	DetailsURL string
+}
+
+// CheckHandler is an interface that represents a platform specific handler
+// for posting checks to a commit.
+type CheckHandler interface {
+	// CallPostCheck calls the platform-specific API to post the check to the
+	// commit.
+	CallPostCheck(ctx context.Context, check Check) error
+}
+
+// checkName returns the name the check is posted with. A custom tag is used
+// as the name so that multiple Infracost runs on the same commit don't
+// replace each other's checks.
+func checkName(tag string) string {
+	if tag == "" {
+		return defaultCheckName
+	}
+
+	return tag
+}
@@ -0,0 +1,113 @@
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubCheckHandler_CallPostCheck(t *testing.T) {
+	var got map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/infracost/infracost/pulls/3":
+			_, _ = w.Write([]byte(`{"number": 3, "head": {"sha": "abc123"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/infracost/infracost/check-runs":
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	h, err := NewGitHubCheckHandler(context.Background(), "infracost/infracost", "", 3, GitHubExtra{APIURL: ts.URL, Token: "abc"})
+	require.NoError(t, err)
+
+	err = h.CallPostCheck(context.Background(), Check{
+		Title:      "Monthly estimate increased by $30 ↑",
+		Summary:    strings.Repeat("a", githubCheckSummaryMaxSize+1),
+		Conclusion: CheckFailure,
+		DetailsURL: "https://dashboard.infracost.io/runs/1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Infracost", got["name"])
+	assert.Equal(t, "abc123", got["head_sha"])
+	assert.Equal(t, "completed", got["status"])
+	assert.Equal(t, "failure", got["conclusion"])
+	assert.Equal(t, "https://dashboard.infracost.io/runs/1", got["details_url"])
+
+	out := got["output"].(map[string]interface{})
+	assert.Equal(t, "Monthly estimate increased by $30 ↑", out["title"])
+	assert.Len(t, out["summary"], githubCheckSummaryMaxSize)
+	assert.True(t, strings.HasSuffix(out["summary"].(string), "...(truncated)"))
+}
+
+func TestGitLabCheckHandler_CallPostCheck(t *testing.T) {
+	var gotPath string
+	var got map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/infracost/infracost/merge_requests/3":
+			_, _ = w.Write([]byte(`{"iid": 3, "sha": "abc123"}`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v4/projects/infracost/infracost/statuses/"):
+			gotPath = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		commit     string
+		conclusion CheckConclusion
+		wantPath   string
+		wantState  string
+	}{
+		{name: "merge request failure", conclusion: CheckFailure, wantPath: "abc123", wantState: "failed"},
+		{name: "commit neutral", commit: "def456", conclusion: CheckNeutral, wantPath: "def456", wantState: "success"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewGitLabCheckHandler(context.Background(), "infracost/infracost", tt.commit, 3, GitLabExtra{ServerURL: ts.URL, Token: "abc", Tag: "infracost-dev"})
+			require.NoError(t, err)
+
+			err = h.CallPostCheck(context.Background(), Check{
+				Title:      "Monthly estimate generated",
+				Summary:    "ignored",
+				Conclusion: tt.conclusion,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "/api/v4/projects/infracost/infracost/statuses/"+tt.wantPath, gotPath)
+			assert.Equal(t, map[string]interface{}{
+				"state":       tt.wantState,
+				"name":        "infracost-dev",
+				"description": "Monthly estimate generated",
+			}, got)
+		})
+	}
+}
+
+func TestNewCheckHandler_NoTarget(t *testing.T) {
+	_, err := NewGitHubCheckHandler(context.Background(), "infracost/infracost", "", 0, GitHubExtra{})
+	assert.Error(t, err)
+
+	_, err = NewGitLabCheckHandler(context.Background(), "infracost/infracost", "", 0, GitLabExtra{})
+	assert.Error(t, err)
+}
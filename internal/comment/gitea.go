@@ -0,0 +1,299 @@
+package comment
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// Gitea Cloud URL
+var giteaDefaultServerURL = "https://gitea.com"
+
+// giteaComment represents a comment on a Gitea pull request. It implements
+// the Comment interface.
+type giteaComment struct {
+	id        int64
+	body      string
+	createdAt string
+	url       string
+}
+
+// Body returns the body of the comment
+func (c *giteaComment) Body() string {
+	return c.body
+}
+
+// Ref returns the reference to the comment. For Gitea this is a URL to the
+// HTML page of the comment.
+func (c *giteaComment) Ref() string {
+	return c.url
+}
+
+// Less compares the comment to another comment and returns true if this
+// comment should be sorted before the other comment.
+func (c *giteaComment) Less(other Comment) bool {
+	j := other.(*giteaComment)
+
+	if c.createdAt != j.createdAt {
+		return c.createdAt < j.createdAt
+	}
+
+	return c.id < j.id
+}
+
+// IsHidden always returns false for Gitea since Gitea doesn't have a
+// feature for hiding comments.
+func (c *giteaComment) IsHidden() bool {
+	return false
+}
+
+// ValidAt returns the time the comment was tagged as being valid at
+func (c *giteaComment) ValidAt() *time.Time {
+	return extractValidAt(c.Body())
+}
+
+// GiteaExtra contains any extra inputs that can be passed to the Gitea comment
+// handlers. The same handlers work with Forgejo, which has the same API.
+type GiteaExtra struct {
+	// ServerURL is the URL of the Gitea server. If not set, the default Gitea
+	// server URL will be used.
+	ServerURL string
+	// Token is the Gitea access token.
+	Token string
+	// Tag used to identify the Infracost comment
+	Tag string
+	// TLSConfig is the TLS configuration to use when connecting to the Gitea API.
+	TLSConfig *tls.Config
+}
+
+// giteaAPIComment represents API response structure of Gitea comment.
+type giteaAPIComment struct {
+	ID        int64  `json:"id"`
+	Body      string `json:"body"`
+	HTMLURL   string `json:"html_url"`
+	CreatedAt string `json:"created_at"`
+}
+
+// newGiteaAPIClient creates a HTTP client that authenticates with the token.
+func newGiteaAPIClient(ctx context.Context, token string, tlsConfig *tls.Config) *http.Client {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{
+			AccessToken: token,
+			TokenType:   "token",
+		},
+	)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Transport: transport}
+	httpCtx := context.WithValue(ctx, oauth2.HTTPClient, client)
+
+	return oauth2.NewClient(httpCtx, ts)
+}
+
+// giteaPRHandler is a PlatformHandler for Gitea pull requests. It
+// implements the PlatformHandler interface and contains the functions
+// for finding, creating, updating, deleting comments on Gitea pull requests.
+type giteaPRHandler struct {
+	httpClient *http.Client
+	apiURL     string
+	prNumber   int
+}
+
+// NewGiteaPRHandler creates a new PlatformHandler for Gitea pull requests.
+func NewGiteaPRHandler(ctx context.Context, repo string, targetRef string, extra GiteaExtra) (*CommentHandler, error) {
+	prNumber, err := strconv.Atoi(targetRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing targetRef as pull request number")
+	}
+
+	if len(strings.Split(repo, "/")) != 2 {
+		return nil, errors.New("Error parsing repo, must have format owner/repo")
+	}
+
+	serverURL := extra.ServerURL
+	if serverURL == "" {
+		serverURL = giteaDefaultServerURL
+	}
+
+	h := &giteaPRHandler{
+		httpClient: newGiteaAPIClient(ctx, extra.Token, extra.TLSConfig),
+		apiURL:     fmt.Sprintf("%s/api/v1/repos/%s/", strings.TrimSuffix(serverURL, "/"), repo),
+		prNumber:   prNumber,
+	}
+
+	return NewCommentHandler(ctx, h, extra.Tag), nil
+}
+
+// CallFindMatchingComments calls the Gitea API to find the pull request
+// comments that match the given tag, which has been embedded at the beginning
+// of the comment.
+func (h *giteaPRHandler) CallFindMatchingComments(ctx context.Context, tag string) ([]Comment, error) {
+	// Pull requests are issues in Gitea, so the comments are fetched from the
+	// issue comments API, which returns all the comments in a single page.
+	url := fmt.Sprintf("%sissues/%d/comments", h.apiURL, h.prNumber)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error creating request")
+	}
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error getting comments")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return []Comment{}, errors.Errorf("Error getting comments: %s", res.Status)
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error reading response body")
+	}
+
+	var resData []giteaAPIComment
+	err = json.Unmarshal(resBody, &resData)
+	if err != nil {
+		return []Comment{}, errors.Wrap(err, "Error unmarshaling response body")
+	}
+
+	var matchingComments []Comment
+	for _, c := range resData {
+		if hasTagKey(c.Body, tag) {
+			matchingComments = append(matchingComments, &giteaComment{
+				id:        c.ID,
+				body:      c.Body,
+				createdAt: c.CreatedAt,
+				url:       c.HTMLURL,
+			})
+		}
+	}
+
+	return matchingComments, nil
+}
+
+// CallCreateComment calls the Gitea API to create a new comment on the pull request.
+func (h *giteaPRHandler) CallCreateComment(ctx context.Context, body string) (Comment, error) {
+	reqData, err := json.Marshal(map[string]interface{}{
+		"body": body,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshaling comment body")
+	}
+
+	url := fmt.Sprintf("%sissues/%d/comments", h.apiURL, h.prNumber)
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqData))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating comment")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, errors.Errorf("Error creating comment: %s", res.Status)
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading response body")
+	}
+
+	resData := giteaAPIComment{}
+	err = json.Unmarshal(resBody, &resData)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error unmarshaling response body")
+	}
+
+	return &giteaComment{
+		id:        resData.ID,
+		body:      resData.Body,
+		createdAt: resData.CreatedAt,
+		url:       resData.HTMLURL,
+	}, nil
+}
+
+// CallUpdateComment calls the Gitea API to update the body of a comment on the pull request.
+func (h *giteaPRHandler) CallUpdateComment(ctx context.Context, comment Comment, body string) error {
+	reqData, err := json.Marshal(map[string]interface{}{
+		"body": body,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error marshaling comment body")
+	}
+
+	url := fmt.Sprintf("%sissues/comments/%d", h.apiURL, comment.(*giteaComment).id)
+
+	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(reqData))
+	if err != nil {
+		return errors.Wrap(err, "Error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error updating comment")
+	}
+	defer res.Body.Close()
+
+	// Older Gitea versions return no content when the comment is updated.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return errors.Errorf("Error updating comment: %s", res.Status)
+	}
+
+	return nil
+}
+
+// CallDeleteComment calls the Gitea API to delete the pull request comment.
+func (h *giteaPRHandler) CallDeleteComment(ctx context.Context, comment Comment) error {
+	url := fmt.Sprintf("%sissues/comments/%d", h.apiURL, comment.(*giteaComment).id)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating request")
+	}
+
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error deleting comment")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return errors.Errorf("Error deleting comment: %s", res.Status)
+	}
+
+	return nil
+}
+
+// CallHideComment deletes the pull request comment since Gitea doesn't have
+// a feature for hiding comments.
+func (h *giteaPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
+	logging.Logger.Debug().Msgf("Gitea does not support hiding comments, deleting comment %s instead", comment.Ref())
+
+	return h.CallDeleteComment(ctx, comment)
+}
+
+// AddMarkdownTags prepends tags as a markdown comment to the given string.
+func (h *giteaPRHandler) AddMarkdownTags(s string, tags []CommentTag) (string, error) {
+	return addMarkdownTags(s, tags)
+}
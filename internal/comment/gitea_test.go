@@ -0,0 +1,138 @@
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitea is an in-memory stand-in for the Gitea issue comments API.
+type fakeGitea struct {
+	mu       sync.Mutex
+	comments []giteaAPIComment
+	nextID   int64
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "token abc" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const listPath = "/api/v1/repos/infracost/infracost/issues/3/comments"
+	const commentPrefix = "/api/v1/repos/infracost/infracost/issues/comments/"
+
+	switch {
+	case r.URL.Path == listPath && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(f.comments)
+	case r.URL.Path == listPath && r.Method == http.MethodPost:
+		var body struct {
+			Body string `json:"body"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		f.nextID++
+		c := giteaAPIComment{
+			ID:        f.nextID,
+			Body:      body.Body,
+			HTMLURL:   fmt.Sprintf("https://gitea.example.com/infracost/infracost/pulls/3#issuecomment-%d", f.nextID),
+			CreatedAt: fmt.Sprintf("2024-01-01T00:00:%02dZ", f.nextID),
+		}
+		f.comments = append(f.comments, c)
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(c)
+	case strings.HasPrefix(r.URL.Path, commentPrefix):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, commentPrefix), 10, 64)
+		for i, c := range f.comments {
+			if c.ID != id {
+				continue
+			}
+
+			if r.Method == http.MethodDelete {
+				f.comments = append(f.comments[:i], f.comments[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			var body struct {
+				Body string `json:"body"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.comments[i].Body = body.Body
+			_ = json.NewEncoder(w).Encode(f.comments[i])
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeGitea) bodies() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bodies := make([]string, len(f.comments))
+	for i, c := range f.comments {
+		bodies[i] = c.Body
+	}
+
+	return bodies
+}
+
+func TestGiteaPRHandler_CommentWithBehavior(t *testing.T) {
+	tests := []struct {
+		behavior string
+		want     []string
+	}{
+		{behavior: "update", want: []string{"unrelated", "[//]: <> (infracost-comment)\nnew"}},
+		{behavior: "new", want: []string{"unrelated", "[//]: <> (infracost-comment)\nold", "[//]: <> (infracost-comment)\nnew"}},
+		{behavior: "delete-and-new", want: []string{"unrelated", "[//]: <> (infracost-comment)\nnew"}},
+		{behavior: "hide-and-new", want: []string{"unrelated", "[//]: <> (infracost-comment)\nnew"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			f := &fakeGitea{
+				comments: []giteaAPIComment{{ID: 1, Body: "unrelated", CreatedAt: "2024-01-01T00:00:00Z"}},
+				nextID:   1,
+			}
+			ts := httptest.NewServer(f)
+			defer ts.Close()
+
+			h, err := NewGiteaPRHandler(context.Background(), "infracost/infracost", "3", GiteaExtra{
+				ServerURL: ts.URL + "/",
+				Token:     "abc",
+			})
+			require.NoError(t, err)
+
+			_, err = h.CommentWithBehavior(context.Background(), "new", "old", nil)
+			require.NoError(t, err)
+
+			res, err := h.CommentWithBehavior(context.Background(), tt.behavior, "new", nil)
+			require.NoError(t, err)
+			assert.True(t, res.Posted)
+
+			assert.Equal(t, tt.want, f.bodies())
+		})
+	}
+}
+
+func TestNewGiteaPRHandler_InvalidRepo(t *testing.T) {
+	_, err := NewGiteaPRHandler(context.Background(), "infracost", "3", GiteaExtra{})
+	assert.ErrorContains(t, err, "must have format owner/repo")
+}
@@ -0,0 +1,94 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+)
+
+// githubCheckSummaryMaxSize is the maximum number of characters GitHub allows
+// in the summary of a check run.
+const githubCheckSummaryMaxSize = 65535
+
+// githubCheckHandler is a CheckHandler for GitHub. It posts checks as check
+// runs on a commit, or on the head commit of a pull request.
+type githubCheckHandler struct {
+	v3client *github.Client
+	owner    string
+	repo     string
+	commit   string
+	prNumber int
+	name     string
+}
+
+// NewGitHubCheckHandler creates a new CheckHandler for GitHub. The check is
+// posted on the given commit, or if commit is empty, on the head commit of
+// the pull request.
+func NewGitHubCheckHandler(ctx context.Context, project string, commit string, prNumber int, extra GitHubExtra) (CheckHandler, error) {
+	owner, repo, err := splitGitHubProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	if commit == "" && prNumber == 0 {
+		return nil, errors.New("Either a commit or pull request number is required to post a check")
+	}
+
+	v3client, _, err := newGitHubAPIClients(ctx, extra.Token, extra.APIURL, extra.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &githubCheckHandler{
+		v3client: v3client,
+		owner:    owner,
+		repo:     repo,
+		commit:   commit,
+		prNumber: prNumber,
+		name:     checkName(extra.Tag),
+	}, nil
+}
+
+// CallPostCheck calls the GitHub API to create a completed check run. GitHub
+// shows the latest check run with the same name, so checks from previous runs
+// are replaced.
+func (h *githubCheckHandler) CallPostCheck(ctx context.Context, check Check) error {
+	sha := h.commit
+	if sha == "" {
+		pr, _, err := h.v3client.PullRequests.Get(ctx, h.owner, h.repo, h.prNumber)
+		if err != nil {
+			return errors.Wrap(err, "Error getting pull request")
+		}
+		sha = pr.GetHead().GetSHA()
+	}
+
+	summary := check.Summary
+	if len([]rune(summary)) > githubCheckSummaryMaxSize {
+		suffix := "\n\n...(truncated)"
+		summary = string([]rune(summary)[:githubCheckSummaryMaxSize-len(suffix)]) + suffix
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:        h.name,
+		HeadSHA:     sha,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(string(check.Conclusion)),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:   github.String(check.Title),
+			Summary: github.String(summary),
+		},
+	}
+	if check.DetailsURL != "" {
+		opts.DetailsURL = github.String(check.DetailsURL)
+	}
+
+	_, _, err := h.v3client.Checks.CreateCheckRun(ctx, h.owner, h.repo, opts)
+	if err != nil {
+		return errors.Wrap(err, "Error creating check run")
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package comment
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+// githubReviewHandler is a ReviewPlatformHandler for GitHub pull requests. It
+// contains the functions for listing the changed files and finding, creating
+// and resolving review comment threads on GitHub pull requests.
+type githubReviewHandler struct {
+	v4client *githubv4.Client
+	v3client *github.Client
+	owner    string
+	repo     string
+	prNumber int
+
+	headSHA string
+}
+
+// NewGitHubReviewHandler creates a new ReviewHandler for GitHub pull requests.
+func NewGitHubReviewHandler(ctx context.Context, project, targetRef string, extra GitHubExtra) (*ReviewHandler, error) {
+	owner, repo, err := splitGitHubProject(project)
+	if err != nil {
+		return nil, err
+	}
+
+	prNumber, err := strconv.Atoi(targetRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing targetRef as pull request number")
+	}
+
+	v3client, v4client, err := newGitHubAPIClients(ctx, extra.Token, extra.APIURL, extra.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &githubReviewHandler{
+		v3client: v3client,
+		v4client: v4client,
+		owner:    owner,
+		repo:     repo,
+		prNumber: prNumber,
+	}
+
+	return NewReviewHandler(ctx, h, extra.Tag), nil
+}
+
+// CallListChangedFiles calls the GitHub API to list the files changed by the
+// pull request. GitHub doesn't return a patch for binary or very large files,
+// so no review comments are posted on them.
+func (h *githubReviewHandler) CallListChangedFiles(ctx context.Context) ([]ChangedFile, error) {
+	var files []ChangedFile
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, res, err := h.v3client.PullRequests.ListFiles(ctx, h.owner, h.repo, h.prNumber, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error listing pull request files")
+		}
+
+		for _, f := range page {
+			files = append(files, ChangedFile{
+				Path:  f.GetFilename(),
+				Patch: f.GetPatch(),
+			})
+		}
+
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+
+	return files, nil
+}
+
+// CallFindReviewThreads calls the GitHub API to find the pull request review
+// threads whose first comment matches the given tag.
+func (h *githubReviewHandler) CallFindReviewThreads(ctx context.Context, tag string) ([]ReviewThread, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID         githubv4.String
+						IsResolved githubv4.Boolean
+						IsOutdated githubv4.Boolean
+						Path       githubv4.String
+						Line       *githubv4.Int
+						Comments   struct {
+							Nodes []struct {
+								Body githubv4.String
+								URL  githubv4.String
+							}
+						} `graphql:"comments(first: 1)"`
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"reviewThreads(first: 100, after: $after)"`
+			} `graphql:"pullRequest(number: $prNumber)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	variables := map[string]interface{}{
+		"owner":    githubv4.String(h.owner),
+		"repo":     githubv4.String(h.repo),
+		"prNumber": githubv4.Int(h.prNumber), // nolint:gosec // ignore G115: integer overflow conversion int -> int32
+		"after":    (*githubv4.String)(nil),  // Null after argument to get first page.
+	}
+
+	var threads []ReviewThread
+	for {
+		err := h.v4client.Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Repository.PullRequest.ReviewThreads.Nodes {
+			if len(node.Comments.Nodes) == 0 || !hasTagKey(string(node.Comments.Nodes[0].Body), tag) {
+				continue
+			}
+
+			// Outdated threads are on lines that have since changed, so they
+			// never match a new comment and are resolved.
+			var line int
+			if node.Line != nil && !node.IsOutdated {
+				line = int(*node.Line)
+			}
+
+			threads = append(threads, ReviewThread{
+				ID:       string(node.ID),
+				Path:     string(node.Path),
+				Line:     line,
+				Body:     string(node.Comments.Nodes[0].Body),
+				URL:      string(node.Comments.Nodes[0].URL),
+				Resolved: bool(node.IsResolved),
+			})
+		}
+
+		if !q.Repository.PullRequest.ReviewThreads.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = githubv4.NewString(q.Repository.PullRequest.ReviewThreads.PageInfo.EndCursor)
+	}
+
+	return threads, nil
+}
+
+// CallCreateReviewComment calls the GitHub API to create a review comment on
+// a line of the head commit of the pull request.
+func (h *githubReviewHandler) CallCreateReviewComment(ctx context.Context, comment ReviewComment) error {
+	if h.headSHA == "" {
+		pr, _, err := h.v3client.PullRequests.Get(ctx, h.owner, h.repo, h.prNumber)
+		if err != nil {
+			return errors.Wrap(err, "Error getting pull request")
+		}
+		h.headSHA = pr.GetHead().GetSHA()
+	}
+
+	_, _, err := h.v3client.PullRequests.CreateComment(ctx, h.owner, h.repo, h.prNumber, &github.PullRequestComment{
+		Body:     github.String(comment.Body),
+		CommitID: github.String(h.headSHA),
+		Path:     github.String(comment.Path),
+		Line:     github.Int(comment.Line),
+		Side:     github.String("RIGHT"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error creating review comment")
+	}
+
+	return nil
+}
+
+// CallResolveReviewThread calls the GitHub API to resolve the review thread.
+func (h *githubReviewHandler) CallResolveReviewThread(ctx context.Context, thread ReviewThread) error {
+	var m struct {
+		ResolveReviewThread struct {
+			ClientMutationId githubv4.ID //nolint
+		} `graphql:"resolveReviewThread(input: $input)"`
+	}
+
+	input := githubv4.ResolveReviewThreadInput{
+		ThreadID: githubv4.ID(thread.ID),
+	}
+
+	return h.v4client.Mutate(ctx, &m, input, nil)
+}
@@ -0,0 +1,103 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// gitlabStatusDescriptionMaxSize is the maximum number of characters GitLab
+// allows in the description of a commit status.
+const gitlabStatusDescriptionMaxSize = 255
+
+// gitlabCheckHandler is a CheckHandler for GitLab. It posts checks as
+// external commit statuses on a commit, or on the head commit of a merge
+// request.
+type gitlabCheckHandler struct {
+	httpClient *http.Client
+	serverURL  string
+	project    string
+	commit     string
+	mrNumber   int
+	name       string
+}
+
+// NewGitLabCheckHandler creates a new CheckHandler for GitLab. The check is
+// posted on the given commit, or if commit is empty, on the head commit of
+// the merge request.
+func NewGitLabCheckHandler(ctx context.Context, project string, commit string, mrNumber int, extra GitLabExtra) (CheckHandler, error) {
+	if commit == "" && mrNumber == 0 {
+		return nil, errors.New("Either a commit or merge request number is required to post a check")
+	}
+
+	serverURL := extra.ServerURL
+
+	// Handle default GitLab API client
+	if serverURL == "" {
+		serverURL = "https://gitlab.com"
+	}
+
+	httpClient, _, err := newGitLabAPIClients(ctx, extra.Token, serverURL, extra.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitlabCheckHandler{
+		httpClient: httpClient,
+		serverURL:  serverURL,
+		project:    project,
+		commit:     commit,
+		mrNumber:   mrNumber,
+		name:       checkName(extra.Tag),
+	}, nil
+}
+
+// CallPostCheck calls the GitLab API to set the commit status. Commit
+// statuses only have a short description, so the title of the check is used
+// and the summary is ignored. Neutral checks are reported as a success since
+// GitLab has no neutral state.
+func (h *gitlabCheckHandler) CallPostCheck(ctx context.Context, check Check) error {
+	projectURL := fmt.Sprintf("%s/api/v4/projects/%s", h.serverURL, url.PathEscape(h.project))
+
+	sha := h.commit
+	if sha == "" {
+		var resData struct {
+			SHA string `json:"sha"`
+		}
+
+		_, err := doGitLabRequest(h.httpClient, "GET", fmt.Sprintf("%s/merge_requests/%d", projectURL, h.mrNumber), nil, http.StatusOK, &resData)
+		if err != nil {
+			return errors.Wrap(err, "Error getting merge request")
+		}
+		sha = resData.SHA
+	}
+
+	state := "success"
+	if check.Conclusion == CheckFailure {
+		state = "failed"
+	}
+
+	description := []rune(check.Title)
+	if len(description) > gitlabStatusDescriptionMaxSize {
+		description = append(description[:gitlabStatusDescriptionMaxSize-1], '…')
+	}
+
+	reqData := map[string]interface{}{
+		"state":       state,
+		"name":        h.name,
+		"description": string(description),
+	}
+	if check.DetailsURL != "" {
+		reqData["target_url"] = check.DetailsURL
+	}
+
+	_, err := doGitLabRequest(h.httpClient, "POST", fmt.Sprintf("%s/statuses/%s", projectURL, sha), reqData, http.StatusCreated, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating commit status")
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package comment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// gitlabReviewHandler is a ReviewPlatformHandler for GitLab merge requests.
+// It contains the functions for listing the changed files and finding,
+// creating and resolving diff discussions on GitLab merge requests.
+type gitlabReviewHandler struct {
+	httpClient *http.Client
+	serverURL  string
+	project    string
+	mrNumber   int
+
+	diffRefs *gitlabDiffRefs
+}
+
+// gitlabDiffRefs are the commit SHAs that the position of a diff discussion
+// is relative to.
+type gitlabDiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+// NewGitLabReviewHandler creates a new ReviewHandler for GitLab merge requests.
+func NewGitLabReviewHandler(ctx context.Context, project string, targetRef string, extra GitLabExtra) (*ReviewHandler, error) {
+	mrNumber, err := strconv.Atoi(targetRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing targetRef as merge request number")
+	}
+
+	serverURL := extra.ServerURL
+
+	// Handle default GitLab API client
+	if serverURL == "" {
+		serverURL = "https://gitlab.com"
+	}
+
+	httpClient, _, err := newGitLabAPIClients(ctx, extra.Token, serverURL, extra.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &gitlabReviewHandler{
+		httpClient: httpClient,
+		serverURL:  serverURL,
+		project:    project,
+		mrNumber:   mrNumber,
+	}
+
+	return NewReviewHandler(ctx, h, extra.Tag), nil
+}
+
+func (h *gitlabReviewHandler) mergeRequestURL() string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d", h.serverURL, url.PathEscape(h.project), h.mrNumber)
+}
+
+// doGitLabRequest sends a request to the GitLab API and decodes the JSON
+// response into v if the response has the wanted status code. It returns the
+// next page from the pagination headers.
+func doGitLabRequest(httpClient *http.Client, method, reqURL string, reqData interface{}, wantStatus int, v interface{}) (string, error) {
+	var body io.Reader
+	if reqData != nil {
+		b, err := json.Marshal(reqData)
+		if err != nil {
+			return "", errors.Wrap(err, "Error marshaling request body")
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != wantStatus {
+		return "", errors.New(res.Status)
+	}
+
+	if v != nil {
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			return "", errors.Wrap(err, "Error reading response body")
+		}
+
+		err = json.Unmarshal(resBody, v)
+		if err != nil {
+			return "", errors.Wrap(err, "Error unmarshaling response body")
+		}
+	}
+
+	return res.Header.Get("X-Next-Page"), nil
+}
+
+// CallListChangedFiles calls the GitLab API to list the files changed by the
+// merge request.
+func (h *gitlabReviewHandler) CallListChangedFiles(ctx context.Context) ([]ChangedFile, error) {
+	var files []ChangedFile
+
+	page := "1"
+	for page != "" {
+		var resData []struct {
+			NewPath     string `json:"new_path"`
+			Diff        string `json:"diff"`
+			DeletedFile bool   `json:"deleted_file"`
+		}
+
+		var err error
+		page, err = doGitLabRequest(h.httpClient, "GET", fmt.Sprintf("%s/diffs?per_page=100&page=%s", h.mergeRequestURL(), page), nil, http.StatusOK, &resData)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error listing merge request diffs")
+		}
+
+		for _, d := range resData {
+			if d.DeletedFile {
+				continue
+			}
+
+			files = append(files, ChangedFile{
+				Path:  d.NewPath,
+				Patch: d.Diff,
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// CallFindReviewThreads calls the GitLab API to find the merge request diff
+// discussions whose first note matches the given tag.
+func (h *gitlabReviewHandler) CallFindReviewThreads(ctx context.Context, tag string) ([]ReviewThread, error) {
+	var threads []ReviewThread
+
+	page := "1"
+	for page != "" {
+		var resData []struct {
+			ID    string `json:"id"`
+			Notes []struct {
+				ID       int    `json:"id"`
+				Body     string `json:"body"`
+				Resolved bool   `json:"resolved"`
+				Position *struct {
+					NewPath string `json:"new_path"`
+					NewLine int    `json:"new_line"`
+				} `json:"position"`
+			} `json:"notes"`
+		}
+
+		var err error
+		page, err = doGitLabRequest(h.httpClient, "GET", fmt.Sprintf("%s/discussions?per_page=100&page=%s", h.mergeRequestURL(), page), nil, http.StatusOK, &resData)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting discussions")
+		}
+
+		for _, d := range resData {
+			if len(d.Notes) == 0 || d.Notes[0].Position == nil || !hasTagKey(d.Notes[0].Body, tag) {
+				continue
+			}
+
+			note := d.Notes[0]
+			threads = append(threads, ReviewThread{
+				ID:       d.ID,
+				Path:     note.Position.NewPath,
+				Line:     note.Position.NewLine,
+				Body:     note.Body,
+				URL:      fmt.Sprintf("%s/%s/-/merge_requests/%d#note_%d", h.serverURL, h.project, h.mrNumber, note.ID),
+				Resolved: note.Resolved,
+			})
+		}
+	}
+
+	return threads, nil
+}
+
+// CallCreateReviewComment calls the GitLab API to start a diff discussion on
+// a line of the latest version of the merge request.
+func (h *gitlabReviewHandler) CallCreateReviewComment(ctx context.Context, comment ReviewComment) error {
+	if h.diffRefs == nil {
+		var resData struct {
+			DiffRefs *gitlabDiffRefs `json:"diff_refs"`
+		}
+
+		_, err := doGitLabRequest(h.httpClient, "GET", h.mergeRequestURL(), nil, http.StatusOK, &resData)
+		if err != nil {
+			return errors.Wrap(err, "Error getting merge request")
+		}
+
+		if resData.DiffRefs == nil {
+			return errors.New("Error getting merge request: no diff refs returned")
+		}
+		h.diffRefs = resData.DiffRefs
+	}
+
+	reqData := map[string]interface{}{
+		"body": comment.Body,
+		"position": map[string]interface{}{
+			"position_type": "text",
+			"base_sha":      h.diffRefs.BaseSHA,
+			"head_sha":      h.diffRefs.HeadSHA,
+			"start_sha":     h.diffRefs.StartSHA,
+			"new_path":      comment.Path,
+			"new_line":      comment.Line,
+		},
+	}
+
+	_, err := doGitLabRequest(h.httpClient, "POST", fmt.Sprintf("%s/discussions", h.mergeRequestURL()), reqData, http.StatusCreated, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating review comment")
+	}
+
+	return nil
+}
+
+// CallResolveReviewThread calls the GitLab API to resolve the diff discussion.
+func (h *gitlabReviewHandler) CallResolveReviewThread(ctx context.Context, thread ReviewThread) error {
+	reqData := map[string]interface{}{
+		"resolved": true,
+	}
+
+	_, err := doGitLabRequest(h.httpClient, "PUT", fmt.Sprintf("%s/discussions/%s", h.mergeRequestURL(), thread.ID), reqData, http.StatusOK, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error resolving review comment")
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package comment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+var (
+	reviewTagSuffix  = "-review"
+	reviewKeyTagKey  = "resource"
+	diffHunkRegex    = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+	parentPathsRegex = regexp.MustCompile(`^(\.\./)+`)
+)
+
+// ReviewTarget is a block of code that a review comment should be posted on,
+// e.g. the resource block of a resource whose cost changed.
+type ReviewTarget struct {
+	// Key uniquely identifies what the comment is about, e.g. the project and
+	// address of a resource. It is used to find the comment again on the next
+	// run.
+	Key string
+	// Path is the path of the file containing the block. Paths that aren't
+	// relative to the repository root are matched against the changed files
+	// by suffix.
+	Path      string
+	StartLine int
+	EndLine   int
+	Body      string
+}
+
+// ReviewComment is a review comment that is posted on a single line of a
+// changed file in a pull request.
+type ReviewComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+// ReviewThread is an existing review comment thread. ID is the platform
+// specific ID that is used to resolve the thread.
+type ReviewThread struct {
+	ID       string
+	Path     string
+	Line     int
+	Body     string
+	URL      string
+	Resolved bool
+}
+
+// ChangedFile is a file that was changed in a pull request, along with its
+// unified diff.
+type ChangedFile struct {
+	Path  string
+	Patch string
+}
+
+// ReviewPlatformHandler is an interface that represents a platform specific
+// handler for review comments. It is used to call the platform-specific APIs
+// for listing the changed files and finding, creating and resolving review
+// comment threads.
+type ReviewPlatformHandler interface {
+	// CallListChangedFiles calls the platform-specific API to list the files
+	// changed by the pull request.
+	CallListChangedFiles(ctx context.Context) ([]ChangedFile, error)
+
+	// CallFindReviewThreads calls the platform-specific API to find the review
+	// threads whose first comment contains the given tag.
+	CallFindReviewThreads(ctx context.Context, tag string) ([]ReviewThread, error)
+
+	// CallCreateReviewComment calls the platform-specific API to start a new
+	// review thread on a line of the pull request.
+	CallCreateReviewComment(ctx context.Context, comment ReviewComment) error
+
+	// CallResolveReviewThread calls the platform-specific API to resolve the
+	// review thread.
+	CallResolveReviewThread(ctx context.Context, thread ReviewThread) error
+}
+
+// ReviewResult contains the number of review threads that were created, left
+// unchanged and resolved.
+type ReviewResult struct {
+	Created   int
+	Unchanged int
+	Resolved  int
+	// Skipped is the number of targets that weren't posted because none of
+	// their lines were changed by the pull request.
+	Skipped int
+}
+
+// ReviewHandler contains the logic for posting review comments on the lines
+// of a pull request and resolving the ones from previous runs that are no
+// longer valid. It uses a ReviewPlatformHandler to call the platform-specific
+// APIs.
+type ReviewHandler struct {
+	PlatformHandler ReviewPlatformHandler
+	Tag             string
+}
+
+// NewReviewHandler creates a new ReviewHandler. The tag is suffixed so that
+// review comments are never mistaken for the summary comment with the same
+// tag.
+func NewReviewHandler(ctx context.Context, platformHandler ReviewPlatformHandler, tag string) *ReviewHandler {
+	if tag == "" {
+		tag = defaultTag
+	}
+
+	return &ReviewHandler{
+		PlatformHandler: platformHandler,
+		Tag:             tag + reviewTagSuffix,
+	}
+}
+
+// PostReview posts a review comment on the first changed line of each target.
+// Existing threads with the same body on the same line are left as they are,
+// all other unresolved threads from previous runs are resolved since the
+// cost change they describe is stale.
+func (h *ReviewHandler) PostReview(ctx context.Context, targets []ReviewTarget) (ReviewResult, error) {
+	var result ReviewResult
+
+	files, err := h.PlatformHandler.CallListChangedFiles(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	changed := changedLinesByPath(files)
+
+	threads, err := h.PlatformHandler.CallFindReviewThreads(ctx, h.Tag)
+	if err != nil {
+		return result, err
+	}
+
+	kept := make(map[string]bool, len(threads))
+	posted := make(map[string]bool, len(targets))
+
+	for _, target := range targets {
+		key := reviewKey(target.Key)
+		if posted[key] {
+			logging.Logger.Debug().Msgf("Skipping duplicate review comment for %s", target.Key)
+			continue
+		}
+
+		filePath, line := firstChangedLine(changed, target)
+		if line == 0 {
+			logging.Logger.Debug().Msgf("Skipping review comment for %s as lines %d-%d of %s were not changed", target.Key, target.StartLine, target.EndLine, target.Path)
+			result.Skipped++
+			continue
+		}
+
+		body, err := addMarkdownTags(target.Body, []CommentTag{
+			{Key: h.Tag},
+			{Key: reviewKeyTagKey, Value: key},
+		})
+		if err != nil {
+			return result, err
+		}
+
+		posted[key] = true
+
+		if existing := findReviewThread(threads, kept, key, filePath, line, body); existing != nil {
+			kept[existing.ID] = true
+			result.Unchanged++
+			continue
+		}
+
+		logging.Logger.Info().Msgf("Creating review comment on %s:%d", filePath, line)
+		err = h.PlatformHandler.CallCreateReviewComment(ctx, ReviewComment{
+			Path: filePath,
+			Line: line,
+			Body: body,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.Created++
+	}
+
+	for _, thread := range threads {
+		if thread.Resolved || kept[thread.ID] {
+			continue
+		}
+
+		logging.Logger.Info().Msgf("Resolving stale review comment %s", thread.URL)
+		err := h.PlatformHandler.CallResolveReviewThread(ctx, thread)
+		if err != nil {
+			return result, err
+		}
+
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+// reviewKey hashes the key of a review target so it can be safely stored in
+// a markdown tag, since resource addresses can contain characters that aren't
+// allowed in tag values.
+func reviewKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// findReviewThread returns the unresolved thread for the review key that is on
+// the given line and has the same body. Threads that have already been kept
+// are ignored, so duplicate threads for the same key are resolved.
+func findReviewThread(threads []ReviewThread, kept map[string]bool, key string, filePath string, line int, body string) *ReviewThread {
+	for i, thread := range threads {
+		if thread.Resolved || kept[thread.ID] || extractTagValue(thread.Body, reviewKeyTagKey) != key {
+			continue
+		}
+
+		if thread.Path == filePath && thread.Line == line && strings.TrimSpace(thread.Body) == strings.TrimSpace(body) {
+			return &threads[i]
+		}
+	}
+
+	return nil
+}
+
+// firstChangedLine returns the path of the changed file matching the target
+// and the first line of the target that was added or modified. It returns a
+// line of 0 if no lines of the target were changed.
+func firstChangedLine(changed map[string][]int, target ReviewTarget) (string, int) {
+	filePath := matchChangedPath(changed, target.Path)
+	if filePath == "" {
+		return "", 0
+	}
+
+	for _, line := range changed[filePath] {
+		if line >= target.StartLine && line <= target.EndLine {
+			return filePath, line
+		}
+	}
+
+	return filePath, 0
+}
+
+// matchChangedPath returns the path of the changed file that target refers to.
+// Targets are relative to the directory Infracost was run from, so if there's
+// no exact match the target is matched against the end of the changed paths.
+func matchChangedPath(changed map[string][]int, target string) string {
+	target = path.Clean(strings.ReplaceAll(target, "\\", "/"))
+	target = parentPathsRegex.ReplaceAllString(target, "")
+
+	if _, ok := changed[target]; ok {
+		return target
+	}
+
+	var match string
+	for p := range changed {
+		if strings.HasSuffix(p, "/"+target) {
+			if match != "" {
+				logging.Logger.Debug().Msgf("Multiple changed files match %s, not posting review comments on it", target)
+				return ""
+			}
+
+			match = p
+		}
+	}
+
+	return match
+}
+
+// changedLinesByPath returns the sorted line numbers of the added and
+// modified lines of each changed file, keyed by file path.
+func changedLinesByPath(files []ChangedFile) map[string][]int {
+	changed := make(map[string][]int, len(files))
+	for _, f := range files {
+		changed[f.Path] = parseChangedLines(f.Patch)
+	}
+
+	return changed
+}
+
+// parseChangedLines parses a unified diff and returns the line numbers in the
+// new version of the file of the lines that were added or modified.
+func parseChangedLines(patch string) []int {
+	var lines []int
+	var line int
+	inHunk := false
+
+	// A patch that ends with a newline leaves an empty element after the last
+	// line, which isn't a line of the diff.
+	diffLines := strings.Split(patch, "\n")
+	if diffLines[len(diffLines)-1] == "" {
+		diffLines = diffLines[:len(diffLines)-1]
+	}
+
+	for _, l := range diffLines {
+		if m := diffHunkRegex.FindStringSubmatch(l); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			inHunk = true
+			continue
+		}
+
+		if !inHunk {
+			continue
+		}
+
+		// Some diff producers write blank context lines without the leading space.
+		if l == "" {
+			line++
+			continue
+		}
+
+		switch l[0] {
+		case '+':
+			lines = append(lines, line)
+			line++
+		case ' ':
+			line++
+		case '-', '\\':
+			// Removed lines and "\ No newline at end of file" markers don't
+			// exist in the new version of the file.
+		default:
+			// Anything else is the start of the next file's headers.
+			inHunk = false
+		}
+	}
+
+	return lines
+}
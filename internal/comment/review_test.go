@@ -0,0 +1,147 @@
+package comment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReviewPlatform is an in-memory ReviewPlatformHandler.
+type fakeReviewPlatform struct {
+	files    []ChangedFile
+	threads  []ReviewThread
+	created  []ReviewComment
+	resolved []string
+}
+
+func (f *fakeReviewPlatform) CallListChangedFiles(ctx context.Context) ([]ChangedFile, error) {
+	return f.files, nil
+}
+
+func (f *fakeReviewPlatform) CallFindReviewThreads(ctx context.Context, tag string) ([]ReviewThread, error) {
+	var threads []ReviewThread
+	for _, t := range f.threads {
+		if hasTagKey(t.Body, tag) {
+			threads = append(threads, t)
+		}
+	}
+
+	return threads, nil
+}
+
+func (f *fakeReviewPlatform) CallCreateReviewComment(ctx context.Context, comment ReviewComment) error {
+	f.created = append(f.created, comment)
+	return nil
+}
+
+func (f *fakeReviewPlatform) CallResolveReviewThread(ctx context.Context, thread ReviewThread) error {
+	f.resolved = append(f.resolved, thread.ID)
+	return nil
+}
+
+func TestParseChangedLines(t *testing.T) {
+	patch := `@@ -1,4 +1,5 @@
+ resource "aws_instance" "web" {
+-  instance_type = "t3.micro"
++  instance_type = "m5.large"
++  ebs_optimized = true
+   ami           = "ami-123"
+ }
+@@ -20,2 +21,2 @@ resource "aws_db_instance" "db" {
+-  instance_class = "db.t3.micro"
++  instance_class = "db.m5.large"
+\ No newline at end of file`
+
+	assert.Equal(t, []int{2, 3, 21}, parseChangedLines(patch))
+	assert.Empty(t, parseChangedLines(""))
+
+	// The blank context line is written without its leading space.
+	blankContext := "@@ -1,4 +1,4 @@\n resource \"aws_instance\" \"web\" {\n\n-  instance_type = \"t3.micro\"\n+  instance_type = \"m5.large\"\n }\n"
+	assert.Equal(t, []int{3}, parseChangedLines(blankContext))
+}
+
+func TestMatchChangedPath(t *testing.T) {
+	changed := map[string][]int{
+		"infra/main.tf":            nil,
+		"infra/modules/db/main.tf": nil,
+		"other/modules/db/main.tf": nil,
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: "infra/main.tf", want: "infra/main.tf"},
+		{target: "./infra/main.tf", want: "infra/main.tf"},
+		{target: "../infra/main.tf", want: "infra/main.tf"},
+		{target: "main.tf", want: ""},
+		{target: "db/main.tf", want: ""},
+		{target: "../infra/modules/db/main.tf", want: "infra/modules/db/main.tf"},
+		{target: "infra/modules/db/main.tf", want: "infra/modules/db/main.tf"},
+		{target: "modules/db/main.tf", want: ""},
+		{target: "variables.tf", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchChangedPath(changed, tt.target))
+		})
+	}
+}
+
+func TestReviewHandler_PostReview(t *testing.T) {
+	ctx := context.Background()
+
+	f := &fakeReviewPlatform{
+		files: []ChangedFile{
+			{Path: "infra/main.tf", Patch: "@@ -10,3 +10,3 @@\n resource \"aws_instance\" \"web\" {\n-  instance_type = \"t3.micro\"\n+  instance_type = \"m5.large\"\n }"},
+		},
+	}
+
+	h := NewReviewHandler(ctx, f, "")
+	assert.Equal(t, "infracost-comment-review", h.Tag)
+
+	targets := []ReviewTarget{
+		{Key: "infra/aws_instance.web", Path: "main.tf", StartLine: 10, EndLine: 12, Body: "web costs more"},
+		{Key: "infra/aws_instance.other", Path: "main.tf", StartLine: 20, EndLine: 25, Body: "other costs more"},
+	}
+
+	res, err := h.PostReview(ctx, targets)
+	require.NoError(t, err)
+	assert.Equal(t, ReviewResult{Created: 1, Skipped: 1}, res)
+	require.Len(t, f.created, 1)
+	assert.Equal(t, "infra/main.tf", f.created[0].Path)
+	assert.Equal(t, 11, f.created[0].Line)
+	assert.Contains(t, f.created[0].Body, "web costs more")
+
+	assert.Contains(t, f.created[0].Body, "resource="+reviewKey("infra/aws_instance.web"))
+
+	// Rerunning with the same cost change leaves the existing thread and
+	// resolves any duplicates of it, and a new cost change resolves it and
+	// starts a new one.
+	f.threads = []ReviewThread{
+		{ID: "1", Path: "infra/main.tf", Line: 11, Body: f.created[0].Body},
+		{ID: "2", Path: "infra/main.tf", Line: 11, Body: f.created[0].Body, Resolved: true},
+		{ID: "3", Path: "infra/main.tf", Line: 11, Body: "[//]: <> (infracost-comment)\nsummary"},
+		{ID: "4", Path: "infra/main.tf", Line: 11, Body: f.created[0].Body},
+	}
+	f.created = nil
+
+	// Targets with the same key are only posted once.
+	res, err = h.PostReview(ctx, []ReviewTarget{targets[0], targets[0]})
+	require.NoError(t, err)
+	assert.Equal(t, ReviewResult{Unchanged: 1, Resolved: 1}, res)
+	assert.Empty(t, f.created)
+	assert.Equal(t, []string{"4"}, f.resolved)
+
+	f.threads = f.threads[:3]
+	f.resolved = nil
+
+	targets[0].Body = "web costs even more"
+	res, err = h.PostReview(ctx, targets[:1])
+	require.NoError(t, err)
+	assert.Equal(t, ReviewResult{Created: 1, Resolved: 1}, res)
+	assert.Equal(t, []string{"1"}, f.resolved)
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	CommitmentTypeReservedInstance     = "reserved_instance"
+	CommitmentTypeSavingsPlan          = "savings_plan"
+	CommitmentTypeCommittedUseDiscount = "committed_use_discount"
+
+	SavingsPlanTypeCompute     = "compute"
+	SavingsPlanTypeEC2Instance = "ec2_instance"
+
+	CommitmentServiceEC2         = "ec2"
+	CommitmentServiceRDS         = "rds"
+	CommitmentServiceElastiCache = "elasticache"
+
+	CommitmentTerm1Year = "1_year"
+	CommitmentTerm3Year = "3_year"
+
+	CommitmentPaymentNoUpfront      = "no_upfront"
+	CommitmentPaymentPartialUpfront = "partial_upfront"
+	CommitmentPaymentAllUpfront     = "all_upfront"
+)
+
+// Commitment describes a Reserved Instance, Savings Plan or Committed Use
+// Discount that covers part of the usage in a run. Cost components that match
+// the commitment are priced at a blend of the committed and on-demand rates
+// based on the coverage. Commitments can be defined at the top level of the
+// config file, where they apply to all projects, or in a usage file.
+type Commitment struct {
+	// Name is a user defined name that is shown next to the cost components the commitment covers.
+	Name string `yaml:"name,omitempty"`
+	// Type is the kind of commitment, one of reserved_instance, savings_plan or committed_use_discount.
+	Type string `yaml:"type"`
+	// PlanType is the savings plan type, one of compute (default) or ec2_instance. Compute savings plans
+	// cover EC2 instances, Fargate and Lambda duration, ec2_instance savings plans only cover EC2 instances.
+	PlanType string `yaml:"plan_type,omitempty"`
+	// Service is the service a reserved_instance commitment applies to, one of ec2 (default), rds or elasticache.
+	Service string `yaml:"service,omitempty"`
+	// OfferingClass is the reserved instance offering class, one of standard (default) or convertible.
+	OfferingClass string `yaml:"offering_class,omitempty"`
+	// Term is the length of the commitment, one of 1_year (default) or 3_year.
+	Term string `yaml:"term,omitempty"`
+	// PaymentOption is how an AWS commitment is paid for, one of no_upfront (default), partial_upfront or all_upfront.
+	PaymentOption string `yaml:"payment_option,omitempty"`
+	// CoveragePercent is the percentage of matching usage that the commitment covers. Defaults to 100.
+	CoveragePercent *float64 `yaml:"coverage_percent,omitempty"`
+	// DiscountPercent is the discount the commitment gives over the on-demand price. When it is not set the
+	// committed price is looked up from the Cloud Pricing API. This must be set for savings plans, whose rates
+	// aren't in the Cloud Pricing API, and for partial_upfront and all_upfront commitments since their upfront
+	// fees can't be spread over the hourly price.
+	DiscountPercent *float64 `yaml:"discount_percent,omitempty"`
+	// Region restricts the commitment to usage in a single region, e.g. us-east-1.
+	Region string `yaml:"region,omitempty"`
+	// InstanceFamily restricts the commitment to an instance or machine family, e.g. m5 or n2.
+	// It is required for ec2_instance savings plans.
+	InstanceFamily string `yaml:"instance_family,omitempty"`
+	// ResourceTypes restricts the commitment to the given resource types, e.g. aws_instance.
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+}
+
+// DisplayName returns the name of the commitment, falling back to a name
+// built from its type and term, e.g. "1 year savings plan".
+func (c Commitment) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	term := "1 year"
+	if c.TermOrDefault() == CommitmentTerm3Year {
+		term = "3 year"
+	}
+
+	switch c.Type {
+	case CommitmentTypeReservedInstance:
+		return fmt.Sprintf("%s reserved instance", term)
+	case CommitmentTypeSavingsPlan:
+		return fmt.Sprintf("%s savings plan", term)
+	default:
+		return fmt.Sprintf("%s committed use discount", term)
+	}
+}
+
+// PlanTypeOrDefault returns the savings plan type, defaulting to compute.
+func (c Commitment) PlanTypeOrDefault() string {
+	if c.PlanType == "" {
+		return SavingsPlanTypeCompute
+	}
+
+	return c.PlanType
+}
+
+// ServiceOrDefault returns the service of a reserved instance, defaulting to ec2.
+func (c Commitment) ServiceOrDefault() string {
+	if c.Service == "" {
+		return CommitmentServiceEC2
+	}
+
+	return c.Service
+}
+
+// OfferingClassOrDefault returns the offering class of a reserved instance,
+// defaulting to standard.
+func (c Commitment) OfferingClassOrDefault() string {
+	if c.OfferingClass == "" {
+		return "standard"
+	}
+
+	return c.OfferingClass
+}
+
+// TermOrDefault returns the term of the commitment, defaulting to 1_year.
+func (c Commitment) TermOrDefault() string {
+	if c.Term == "" {
+		return CommitmentTerm1Year
+	}
+
+	return c.Term
+}
+
+// PaymentOptionOrDefault returns the payment option of the commitment, defaulting to no_upfront.
+func (c Commitment) PaymentOptionOrDefault() string {
+	if c.PaymentOption == "" {
+		return CommitmentPaymentNoUpfront
+	}
+
+	return c.PaymentOption
+}
+
+// Coverage returns the fraction of matching usage covered by the commitment, between 0 and 1.
+func (c Commitment) Coverage() float64 {
+	if c.CoveragePercent == nil {
+		return 1
+	}
+
+	return *c.CoveragePercent / 100
+}
+
+// Validate checks the commitment options are valid.
+func (c Commitment) Validate() error {
+	switch c.Type {
+	case CommitmentTypeReservedInstance:
+		switch c.ServiceOrDefault() {
+		case CommitmentServiceEC2, CommitmentServiceRDS, CommitmentServiceElastiCache:
+		default:
+			return fmt.Errorf("service '%s' is not valid, must be one of ec2, rds or elasticache", c.Service)
+		}
+
+		switch c.OfferingClassOrDefault() {
+		case "standard", "convertible":
+		default:
+			return fmt.Errorf("offering_class '%s' is not valid, must be one of standard or convertible", c.OfferingClass)
+		}
+	case CommitmentTypeSavingsPlan:
+		// The Cloud Pricing API only has the on-demand and reserved instance
+		// rates, so the savings plan rate has to be given as a discount.
+		if c.DiscountPercent == nil {
+			return errors.New("discount_percent must be set for savings_plan commitments as savings plan rates are not available from the Cloud Pricing API")
+		}
+
+		switch c.PlanTypeOrDefault() {
+		case SavingsPlanTypeCompute:
+		case SavingsPlanTypeEC2Instance:
+			if c.InstanceFamily == "" {
+				return errors.New("instance_family must be set for ec2_instance savings plans")
+			}
+		default:
+			return fmt.Errorf("plan_type '%s' is not valid, must be one of compute or ec2_instance", c.PlanType)
+		}
+	case CommitmentTypeCommittedUseDiscount:
+		if c.PaymentOption != "" {
+			return errors.New("payment_option cannot be used with committed_use_discount commitments")
+		}
+	case "":
+		return errors.New("type must be set, must be one of reserved_instance, savings_plan or committed_use_discount")
+	default:
+		return fmt.Errorf("type '%s' is not valid, must be one of reserved_instance, savings_plan or committed_use_discount", c.Type)
+	}
+
+	if c.Type != CommitmentTypeSavingsPlan && c.PlanType != "" {
+		return fmt.Errorf("plan_type cannot be used with %s commitments", c.Type)
+	}
+
+	if c.Type != CommitmentTypeReservedInstance && (c.Service != "" || c.OfferingClass != "") {
+		return fmt.Errorf("service and offering_class cannot be used with %s commitments", c.Type)
+	}
+
+	switch c.TermOrDefault() {
+	case CommitmentTerm1Year, CommitmentTerm3Year:
+	default:
+		return fmt.Errorf("term '%s' is not valid, must be one of 1_year or 3_year", c.Term)
+	}
+
+	switch c.PaymentOptionOrDefault() {
+	case CommitmentPaymentNoUpfront:
+	case CommitmentPaymentPartialUpfront, CommitmentPaymentAllUpfront:
+		if c.DiscountPercent == nil {
+			return fmt.Errorf("discount_percent must be set for %s commitments", c.PaymentOption)
+		}
+	default:
+		return fmt.Errorf("payment_option '%s' is not valid, must be one of no_upfront, partial_upfront or all_upfront", c.PaymentOption)
+	}
+
+	if c.CoveragePercent != nil && (*c.CoveragePercent <= 0 || *c.CoveragePercent > 100) {
+		return errors.New("coverage_percent must be greater than 0 and at most 100")
+	}
+
+	if c.DiscountPercent != nil && (*c.DiscountPercent < 0 || *c.DiscountPercent > 100) {
+		return errors.New("discount_percent must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// ValidateCommitments validates a list of commitments, returning an error
+// for each invalid commitment.
+func ValidateCommitments(commitments []Commitment) error {
+	var errs []error
+	for i, c := range commitments {
+		if err := c.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("commitment at index %d is invalid: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -130,6 +130,19 @@ type Project struct {
 	// This is useful for storing flexible project information that needs to be accessed by other parts
 	// of the application.
 	Metadata map[string]string `yaml:"metadata,omitempty" ignored:"true"`
+	// Guardrails are cost thresholds that only apply to this project.
+	Guardrails []Guardrail `yaml:"guardrails,omitempty" ignored:"true"`
+	// CloudFormationParameters are the parameter values used when evaluating a CloudFormation
+	// template. Parameters that are not set use their default value from the template.
+	CloudFormationParameters map[string]string `yaml:"cloudformation_parameters,omitempty" ignored:"true"`
+	// CloudFormationRegion is the region the CloudFormation stack is deployed to, used for AWS::Region.
+	CloudFormationRegion string `yaml:"cloudformation_region,omitempty" ignored:"true"`
+	// DataSources supply the attribute values of data blocks when parsing HCL. They take
+	// precedence over the data sources in the data sources file and the top level data sources.
+	DataSources []DataSource `yaml:"data_sources,omitempty" ignored:"true"`
+	// DataSourcesFile is the path to a file with the data sources of the project, relative to
+	// the project path. Defaults to infracost-data-sources.yml in the project directory if it exists.
+	DataSourcesFile string `yaml:"data_sources_file,omitempty" ignored:"true"`
 }
 
 type Config struct {
@@ -163,6 +176,18 @@ type Config struct {
 	DisableHCLParsing         bool  `yaml:"disable_hcl_parsing,omitempty" envconfig:"DISABLE_HCL_PARSING"`
 	GraphEvaluator            bool  `yaml:"graph_evaluator,omitempty" envconfig:"GRAPH_EVALUATOR"`
 
+	// PricingSnapshotPath is the path to a pricing snapshot created by `infracost pricing export`.
+	// When set, prices are resolved from the snapshot and the Cloud Pricing API is never called.
+	PricingSnapshotPath string `yaml:"pricing_snapshot_path,omitempty" envconfig:"PRICING_SNAPSHOT_PATH"`
+
+	// LocalPolicyFile is the path to a policy file that is evaluated locally against
+	// the resources and their costs, without sending any data to Infracost Cloud.
+	LocalPolicyFile string `yaml:"local_policy_file,omitempty" envconfig:"LOCAL_POLICY_FILE"`
+
+	// PriceOverridesFile is the path to a file of rules that replace list prices with
+	// negotiated prices, e.g. enterprise discounts.
+	PriceOverridesFile string `yaml:"price_overrides_file,omitempty" envconfig:"PRICE_OVERRIDES_FILE"`
+
 	TLSInsecureSkipVerify *bool  `envconfig:"TLS_INSECURE_SKIP_VERIFY"`
 	TLSCACertFile         string `envconfig:"GIT_SSL_CAINFO"`
 
@@ -179,6 +204,17 @@ type Config struct {
 	// TerraformSourceMapRegex is a more flexible source mapping that supports regex patterns.
 	TerraformSourceMapRegex TerraformSourceMapRegex `yaml:"terraform_source_map,omitempty"`
 
+	// Guardrails are the top level cost thresholds defined in the config file.
+	Guardrails []Guardrail `yaml:"guardrails,omitempty" ignored:"true"`
+
+	// Commitments are the Reserved Instances, Savings Plans and Committed Use Discounts
+	// defined in the config file, they apply to all projects.
+	Commitments []Commitment `yaml:"commitments,omitempty" ignored:"true"`
+
+	// DataSources supply the attribute values of data blocks when parsing HCL, they apply
+	// to all projects.
+	DataSources []DataSource `yaml:"data_sources,omitempty" ignored:"true"`
+
 	S3ModuleCacheRegion  string `envconfig:"S3_MODULE_CACHE_REGION"`
 	S3ModuleCacheBucket  string `envconfig:"S3_MODULE_CACHE_BUCKET"`
 	S3ModuleCachePrefix  string `envconfig:"S3_MODULE_CACHE_PREFIX"`
@@ -195,7 +231,10 @@ type Config struct {
 	ShowAllProjects bool       `yaml:"show_all_projects,omitempty" ignored:"true"`
 	ShowSkipped     bool       `yaml:"show_skipped,omitempty" ignored:"true"`
 	SyncUsageFile   bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	ForecastMonths  int        `yaml:"forecast_months,omitempty" ignored:"true"`
 	Fields          []string   `yaml:"fields,omitempty" ignored:"true"`
+	GroupBy         string     `yaml:"group_by,omitempty" ignored:"true"`
+	ExploreDetails  bool       `yaml:"explore_details,omitempty" ignored:"true"`
 	CompareTo       string
 	GitDiffTarget   *string
 
@@ -306,6 +345,9 @@ func (c *Config) LoadFromConfigFile(path string, cmd *cobra.Command) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	c.Guardrails = cfgFile.Guardrails
+	c.Commitments = cfgFile.Commitments
+	c.DataSources = cfgFile.DataSources
 
 	if len(cfgFile.TerraformSourceMapRegex) > 0 {
 		c.TerraformSourceMapRegex = cfgFile.TerraformSourceMapRegex
@@ -489,6 +531,12 @@ func (c *Config) LoadGlobalFlags(cmd *cobra.Command) error {
 	return nil
 }
 
+// IsOfflinePricing returns true if prices should be resolved from a local
+// pricing snapshot rather than the Cloud Pricing API.
+func (c *Config) IsOfflinePricing() bool {
+	return c.PricingSnapshotPath != ""
+}
+
 func (c *Config) IsSelfHosted() bool {
 	return c.PricingAPIEndpoint != "" && c.PricingAPIEndpoint != c.DefaultPricingAPIEndpoint
 }
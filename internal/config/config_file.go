@@ -109,6 +109,9 @@ type ConfigFileSpec struct {
 	Version                 string                  `yaml:"version"`
 	Projects                []*Project              `yaml:"projects" ignored:"true"`
 	TerraformSourceMapRegex TerraformSourceMapRegex `yaml:"terraform_source_map,omitempty"`
+	Guardrails              []Guardrail             `yaml:"guardrails,omitempty"`
+	Commitments             []Commitment            `yaml:"commitments,omitempty"`
+	DataSources             []DataSource            `yaml:"data_sources,omitempty"`
 }
 
 // UnmarshalYAML implements the yaml.v2.Unmarshaller interface. Marshalls the
@@ -120,6 +123,9 @@ func (f *ConfigFileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error
 		Version                 string                   `yaml:"version"`
 		Projects                []map[string]interface{} `yaml:"projects"`
 		TerraformSourceMapRegex TerraformSourceMapRegex  `yaml:"terraform_source_map,omitempty"`
+		Guardrails              []Guardrail              `yaml:"guardrails,omitempty"`
+		Commitments             []Commitment             `yaml:"commitments,omitempty"`
+		DataSources             []DataSource             `yaml:"data_sources,omitempty"`
 	}
 
 	var r roughFile
@@ -196,9 +202,54 @@ func (f *ConfigFileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error
 		return &YamlError{raw: ErrorInvalidConfigFile}
 	}
 
+	validationErr := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	for i, g := range c.Guardrails {
+		if err := g.validate(false); err != nil {
+			validationErr.add(fmt.Errorf("guardrail at index %d is invalid: %w", i, err))
+		}
+	}
+
+	for _, p := range c.Projects {
+		for i, g := range p.Guardrails {
+			if err := g.validate(true); err != nil {
+				validationErr.add(fmt.Errorf("guardrail at index %d for project path: [%s] is invalid: %w", i, p.Path, err))
+			}
+		}
+	}
+
+	for i, commitment := range c.Commitments {
+		if err := commitment.Validate(); err != nil {
+			validationErr.add(fmt.Errorf("commitment at index %d is invalid: %w", i, err))
+		}
+	}
+
+	for i, d := range c.DataSources {
+		if err := d.Validate(); err != nil {
+			validationErr.add(fmt.Errorf("data source at index %d is invalid: %w", i, err))
+		}
+	}
+
+	for _, p := range c.Projects {
+		for i, d := range p.DataSources {
+			if err := d.Validate(); err != nil {
+				validationErr.add(fmt.Errorf("data source at index %d for project path: [%s] is invalid: %w", i, p.Path, err))
+			}
+		}
+	}
+
+	if validationErr.isValid() {
+		return validationErr
+	}
+
 	f.Version = c.Version
 	f.Projects = c.Projects
 	f.TerraformSourceMapRegex = c.TerraformSourceMapRegex
+	f.Guardrails = c.Guardrails
+	f.Commitments = c.Commitments
+	f.DataSources = c.DataSources
 	return nil
 }
 
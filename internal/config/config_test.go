@@ -88,6 +88,123 @@ projects:
 				},
 			},
 		},
+		{
+			name: "should parse project guardrails",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    guardrails:
+      - name: Instances
+        resource_type: aws_instance
+        max_monthly_cost: 1000
+`),
+			expected: []*Project{
+				{
+					Path: "path/to/my_terraform",
+					Guardrails: []Guardrail{
+						{
+							Name:           "Instances",
+							ResourceType:   "aws_instance",
+							MaxMonthlyCost: floatPtr(1000),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should error invalid guardrails given",
+			contents: []byte(`version: 0.1
+
+guardrails:
+  - cost_type: other
+    max_monthly_cost: 1000
+
+projects:
+  - path: path/to/my_terraform
+    guardrails:
+      - scope: total
+        max_monthly_increase: 100
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					fmt.Errorf("guardrail at index 0 is invalid: %w", errors.New("cost_type 'other' is not valid, must be one of total, baseline or usage")),
+					fmt.Errorf("guardrail at index 0 for project path: [path/to/my_terraform] is invalid: %w", errors.New("scope total cannot be used for project guardrails, use project or resource_type")),
+				},
+			},
+		},
+		{
+			name: "should error invalid commitments given",
+			contents: []byte(`version: 0.1
+
+commitments:
+  - type: savings_plan
+    plan_type: ec2_instance
+    discount_percent: 30
+  - type: reserved_instance
+    payment_option: all_upfront
+  - type: savings_plan
+
+projects:
+  - path: path/to/my_terraform
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					fmt.Errorf("commitment at index 0 is invalid: %w", errors.New("instance_family must be set for ec2_instance savings plans")),
+					fmt.Errorf("commitment at index 1 is invalid: %w", errors.New("discount_percent must be set for all_upfront commitments")),
+					fmt.Errorf("commitment at index 2 is invalid: %w", errors.New("discount_percent must be set for savings_plan commitments as savings plan rates are not available from the Cloud Pricing API")),
+				},
+			},
+		},
+		{
+			name: "should parse project data sources",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    data_sources_file: data-sources.yml
+    data_sources:
+      - address: data.aws_ssm_parameter.instance_type
+        values:
+          value: m5.large
+`),
+			expected: []*Project{
+				{
+					Path:            "path/to/my_terraform",
+					DataSourcesFile: "data-sources.yml",
+					DataSources: []DataSource{
+						{
+							Address: "data.aws_ssm_parameter.instance_type",
+							Values:  map[string]interface{}{"value": "m5.large"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should error invalid data sources given",
+			contents: []byte(`version: 0.1
+
+data_sources:
+  - address: aws_ami.ubuntu
+    values:
+      id: ami-123
+
+projects:
+  - path: path/to/my_terraform
+    data_sources:
+      - type: aws_ami
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					fmt.Errorf("data source at index 0 is invalid: %w", errors.New(`address "aws_ami.ubuntu" must be a data block address, e.g. data.aws_ami.ubuntu`)),
+					fmt.Errorf("data source at index 0 for project path: [path/to/my_terraform] is invalid: %w", errors.New("values are required")),
+				},
+			},
+		},
 		{
 			name: "should error invalid version given",
 			contents: []byte(`version: 81923.1
@@ -170,3 +287,7 @@ func TestConfig_CachePath(t *testing.T) {
 		})
 	}
 }
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
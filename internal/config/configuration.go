@@ -23,6 +23,7 @@ type Configuration struct {
 	TLSCACertFile         string `yaml:"tls_ca_cert_file,omitempty"`
 	EnableCloud           *bool  `yaml:"enable_cloud"`
 	EnableCloudUpload     *bool  `yaml:"enable_cloud_upload"`
+	PricingSnapshotPath   string `yaml:"pricing_snapshot_path,omitempty"`
 
 	ProductionFilters []ProductionFilter `yaml:"production_filters,omitempty"`
 }
@@ -79,6 +80,10 @@ func loadConfiguration(cfg *Config) error {
 		cfg.TLSCACertFile = cfg.Configuration.TLSCACertFile
 	}
 
+	if cfg.PricingSnapshotPath == "" {
+		cfg.PricingSnapshotPath = cfg.Configuration.PricingSnapshotPath
+	}
+
 	if cfg.Configuration.ProductionFilters != nil {
 		for i := range cfg.Projects {
 			if cfg.Projects[i].Metadata == nil {
@@ -138,6 +143,12 @@ func ConfigurationFilePath() string {
 	return path.Join(userConfigDir(), "configuration.yml")
 }
 
+// PricingSnapshotFilePath returns the default location that pricing snapshots
+// are imported to.
+func PricingSnapshotFilePath() string {
+	return path.Join(userConfigDir(), "pricing_snapshot.json")
+}
+
 // IsProduction returns true if the project is production.
 func (c *Config) IsProduction(value string) bool {
 	matchesProduction := false
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DataSourcesFileName is the sidecar file that is loaded from a project
+// directory when the project doesn't set data_sources_file.
+const DataSourcesFileName = "infracost-data-sources.yml"
+
+// DataSource supplies the attribute values of Terraform data blocks when
+// parsing HCL. Data sources are normally read from the cloud provider, so
+// without a fixture their attributes evaluate to mocked values. Data sources
+// can be defined at the top level of the config file, where they apply to all
+// projects, in a project or in a project's data sources file.
+type DataSource struct {
+	// Address matches data blocks by their address, e.g. data.aws_ami.ubuntu or
+	// module.web.data.aws_ssm_parameter.instance_type. Indexes can be left out to
+	// match every instance of a data block with count or for_each. Supports * wildcards.
+	Address string `yaml:"address,omitempty"`
+	// Type matches data blocks by their type, e.g. aws_ami. Supports * wildcards.
+	Type string `yaml:"type,omitempty"`
+	// Values are the attribute values of the matching data blocks, e.g. id or value.
+	// They override any attributes that are set in the data block.
+	Values map[string]interface{} `yaml:"values"`
+}
+
+// Validate checks that the data source matches either an address or a type
+// and has values.
+func (d DataSource) Validate() error {
+	if d.Address == "" && d.Type == "" {
+		return errors.New("address or type is required")
+	}
+
+	if d.Address != "" && d.Type != "" {
+		return errors.New("only one of address or type can be set")
+	}
+
+	if d.Address != "" && !strings.HasPrefix(d.Address, "data.") && !strings.HasPrefix(d.Address, "module.") && !strings.HasPrefix(d.Address, "*") {
+		return fmt.Errorf("address %q must be a data block address, e.g. data.aws_ami.ubuntu", d.Address)
+	}
+
+	if len(d.Values) == 0 {
+		return errors.New("values are required")
+	}
+
+	return nil
+}
+
+// ValidateDataSources validates a list of data sources, returning an error
+// for the first one that is invalid.
+func ValidateDataSources(dataSources []DataSource) error {
+	for i, d := range dataSources {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("data source at index %d is invalid: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// DataSourcesFile is the sidecar file that contains the data sources of a
+// project.
+type DataSourcesFile struct {
+	DataSources []DataSource `yaml:"data_sources"`
+}
+
+// LoadDataSourcesFile loads and validates the data sources from the file at path.
+func LoadDataSourcesFile(path string) ([]DataSource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data sources file: %w", err)
+	}
+
+	var f DataSourcesFile
+	err = yaml.Unmarshal([]byte(os.ExpandEnv(string(content))), &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing data sources file %s: %w", path, err)
+	}
+
+	err = ValidateDataSources(f.DataSources)
+	if err != nil {
+		return nil, fmt.Errorf("data sources file %s is invalid: %w", path, err)
+	}
+
+	return f.DataSources, nil
+}
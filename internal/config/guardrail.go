@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	GuardrailScopeTotal        = "total"
+	GuardrailScopeProject      = "project"
+	GuardrailScopeResourceType = "resource_type"
+
+	GuardrailCostTypeTotal    = "total"
+	GuardrailCostTypeBaseline = "baseline"
+	GuardrailCostTypeUsage    = "usage"
+)
+
+// Guardrail defines a monthly cost threshold that is evaluated against the
+// output of a run. Guardrails can be defined at the top level of the config
+// file, where they apply to all projects, or under a single project.
+type Guardrail struct {
+	// Name is a user defined name that is shown when the guardrail is breached.
+	Name string `yaml:"name,omitempty"`
+	// Scope is the cost the guardrail is evaluated against, one of total, project or resource_type.
+	// Top level guardrails default to total, project guardrails default to project. A total scope
+	// sums all projects, a project scope checks each project separately.
+	Scope string `yaml:"scope,omitempty"`
+	// ResourceType is the resource type, e.g. aws_instance, that a resource_type guardrail sums the costs of.
+	ResourceType string `yaml:"resource_type,omitempty"`
+	// CostType is the part of the monthly cost that is checked, one of total (default), baseline or usage.
+	// Baseline costs exclude any costs that come from usage based cost components.
+	CostType string `yaml:"cost_type,omitempty"`
+	// MaxMonthlyCost is the absolute monthly cost that must not be exceeded.
+	MaxMonthlyCost *float64 `yaml:"max_monthly_cost,omitempty"`
+	// MaxMonthlyIncrease is the absolute monthly cost increase that must not be exceeded.
+	// This is only evaluated for runs that have a past cost, e.g. infracost diff.
+	MaxMonthlyIncrease *float64 `yaml:"max_monthly_increase,omitempty"`
+	// MaxMonthlyIncreasePercent is the percentage monthly cost increase that must not be exceeded.
+	// This is only evaluated when the past cost is greater than zero.
+	MaxMonthlyIncreasePercent *float64 `yaml:"max_monthly_increase_percent,omitempty"`
+}
+
+// ScopeOrDefault returns the scope of the guardrail, falling back to
+// the default scope if none has been set.
+func (g Guardrail) ScopeOrDefault(defaultScope string) string {
+	if g.Scope != "" {
+		return g.Scope
+	}
+
+	if g.ResourceType != "" {
+		return GuardrailScopeResourceType
+	}
+
+	return defaultScope
+}
+
+// CostTypeOrDefault returns the cost type of the guardrail, defaulting to total.
+func (g Guardrail) CostTypeOrDefault() string {
+	if g.CostType == "" {
+		return GuardrailCostTypeTotal
+	}
+
+	return g.CostType
+}
+
+// validate checks the guardrail options are valid. projectLevel should be true
+// if the guardrail is defined under a project.
+func (g Guardrail) validate(projectLevel bool) error {
+	defaultScope := GuardrailScopeTotal
+	if projectLevel {
+		defaultScope = GuardrailScopeProject
+	}
+
+	scope := g.ScopeOrDefault(defaultScope)
+	switch scope {
+	case GuardrailScopeTotal:
+		if projectLevel {
+			return errors.New("scope total cannot be used for project guardrails, use project or resource_type")
+		}
+	case GuardrailScopeProject:
+	case GuardrailScopeResourceType:
+		if g.ResourceType == "" {
+			return errors.New("resource_type must be set when scope is resource_type")
+		}
+	default:
+		return fmt.Errorf("scope '%s' is not valid, must be one of total, project or resource_type", g.Scope)
+	}
+
+	if g.ResourceType != "" && scope != GuardrailScopeResourceType {
+		return fmt.Errorf("resource_type cannot be used with scope %s", scope)
+	}
+
+	switch g.CostTypeOrDefault() {
+	case GuardrailCostTypeTotal, GuardrailCostTypeBaseline, GuardrailCostTypeUsage:
+	default:
+		return fmt.Errorf("cost_type '%s' is not valid, must be one of total, baseline or usage", g.CostType)
+	}
+
+	if g.MaxMonthlyCost == nil && g.MaxMonthlyIncrease == nil && g.MaxMonthlyIncreasePercent == nil {
+		return errors.New("at least one of max_monthly_cost, max_monthly_increase or max_monthly_increase_percent must be set")
+	}
+
+	thresholds := []struct {
+		key   string
+		value *float64
+	}{
+		{"max_monthly_cost", g.MaxMonthlyCost},
+		{"max_monthly_increase", g.MaxMonthlyIncrease},
+		{"max_monthly_increase_percent", g.MaxMonthlyIncreasePercent},
+	}
+	for _, t := range thresholds {
+		if t.value != nil && *t.value < 0 {
+			return fmt.Errorf("%s must not be negative", t.key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package explore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func d(v float64) *decimal.Decimal {
+	x := decimal.NewFromFloat(v)
+	return &x
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func exploreTestRoot() output.Root {
+	web := output.Resource{
+		Name:         "aws_instance.web",
+		ResourceType: "aws_instance",
+		Tags:         &map[string]string{"team": "web"},
+		MonthlyCost:  d(20),
+		Usage:        map[string]interface{}{"monthly_cpu_credit_hrs": float64(100)},
+		CostComponents: []output.CostComponent{
+			{
+				Name:            "Instance usage",
+				Unit:            "hours",
+				Price:           decimal.NewFromFloat(0.02),
+				MonthlyQuantity: d(730),
+				MonthlyCost:     d(14.6),
+				VendorName:      "aws",
+				Service:         "AmazonEC2",
+				Region:          "us-east-1",
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "instanceType", Value: strPtr("t3.small")},
+				},
+				PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+			},
+		},
+		SubResources: []output.Resource{
+			{
+				Name:        "root_block_device",
+				MonthlyCost: d(5.4),
+				CostComponents: []output.CostComponent{
+					{Name: "Storage", Unit: "GB", Price: decimal.NewFromFloat(0.1), MonthlyQuantity: d(54), MonthlyCost: d(5.4)},
+				},
+			},
+		},
+	}
+
+	db := output.Resource{
+		Name:         "aws_db_instance.db",
+		ResourceType: "aws_db_instance",
+		DefaultTags:  &map[string]string{"team": "data"},
+		MonthlyCost:  d(50),
+		CostComponents: []output.CostComponent{
+			{Name: "Database instance", Unit: "hours", MonthlyCost: d(50)},
+		},
+	}
+
+	old := output.Resource{
+		Name:         "aws_eip.old",
+		ResourceType: "aws_eip",
+		MonthlyCost:  d(3.65),
+		CostComponents: []output.CostComponent{
+			{Name: "IP address", Unit: "hours", MonthlyCost: d(3.65)},
+		},
+	}
+
+	return output.Root{
+		Currency:             "USD",
+		TotalMonthlyCost:     d(70),
+		DiffTotalMonthlyCost: d(46.35),
+		Projects: output.Projects{
+			{
+				Name:          "infracost/infracost/dev",
+				Breakdown:     &output.Breakdown{TotalMonthlyCost: d(70), Resources: []output.Resource{web, db}},
+				PastBreakdown: &output.Breakdown{TotalMonthlyCost: d(23.65), Resources: []output.Resource{web, old}},
+				Diff: &output.Breakdown{
+					TotalMonthlyCost: d(46.35),
+					Resources: []output.Resource{
+						{Name: "aws_db_instance.db", MonthlyCost: d(50)},
+						{Name: "aws_eip.old", MonthlyCost: d(-3.65)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func visibleNames(m *Model) []string {
+	names := make([]string, 0, len(m.visible))
+	for _, n := range m.visible {
+		names = append(names, strings.Repeat("  ", n.depth())+n.name)
+	}
+
+	return names
+}
+
+func TestModelTree(t *testing.T) {
+	m := NewModel(exploreTestRoot())
+
+	assert.Equal(t, []string{
+		"infracost/infracost/dev",
+		"  aws_db_instance.db",
+		"  aws_instance.web",
+		"  aws_eip.old",
+	}, visibleNames(m), "the only project is expanded and resources are sorted by cost")
+
+	m.Update("down")
+	m.Update("down")
+	m.Update("right")
+	assert.Equal(t, []string{
+		"infracost/infracost/dev",
+		"  aws_db_instance.db",
+		"  aws_instance.web",
+		"    Instance usage",
+		"    root_block_device",
+		"  aws_eip.old",
+	}, visibleNames(m))
+
+	m.Update("s")
+	assert.Equal(t, []string{
+		"infracost/infracost/dev",
+		"  aws_db_instance.db",
+		"  aws_eip.old",
+		"  aws_instance.web",
+		"    Instance usage",
+		"    root_block_device",
+	}, visibleNames(m), "sorted by the size of the diff")
+	assert.Equal(t, "aws_instance.web", m.selected().name, "the selection follows the node when sorting")
+
+	m.Update("left")
+	m.Update("left")
+	assert.Equal(t, []string{"infracost/infracost/dev"}, visibleNames(m))
+
+	m.Update("q")
+	assert.True(t, m.Quit())
+}
+
+func TestModelFilter(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected []string
+	}{
+		{"WEB", []string{"aws_instance.web"}},
+		{"type:aws_db", []string{"aws_db_instance.db"}},
+		{"tag:team=data", []string{"aws_db_instance.db"}},
+		{"tag:team", []string{"aws_db_instance.db", "aws_instance.web"}},
+		{"tag:owner", nil},
+	}
+
+	for _, tt := range tests {
+		m := NewModel(exploreTestRoot())
+		setExpanded(m.projects, false)
+
+		m.Update("/")
+		for _, r := range tt.filter {
+			m.Update(string(r))
+		}
+		m.Update("enter")
+
+		var names []string
+		for _, n := range m.visible {
+			if n.kind == resourceNode {
+				names = append(names, n.name)
+			}
+		}
+		assert.Equal(t, tt.expected, names, tt.filter)
+
+		m.Update("esc")
+		assert.True(t, m.filter.empty())
+	}
+}
+
+func TestModelDetails(t *testing.T) {
+	m := NewModel(exploreTestRoot())
+	m.Update("down")
+	m.Update("down")
+	m.Update("right")
+	m.Update("down")
+
+	n := m.selected()
+	require.Equal(t, costComponentNode, n.kind)
+
+	details := strings.Join(m.details(n), "\n")
+	assert.Contains(t, details, " Region: us-east-1")
+	assert.Contains(t, details, " instanceType: t3.small")
+	assert.Contains(t, details, " Purchase option: on_demand")
+	assert.Contains(t, details, " monthly_cpu_credit_hrs: 100")
+	assert.Contains(t, details, " Past monthly cost: $14.60")
+
+	view := m.View(120, 20)
+	lines := strings.Split(view, "\r\n")
+	assert.Len(t, lines, 20)
+	assert.Contains(t, lines[0], "Total monthly cost: $70.00 (+$46.35)")
+	assert.Contains(t, view, "aws_eip.old (removed)")
+}
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []string{"up", "down", "enter", "q", "esc", "backspace", "pgdown", "ctrl+c"}, parseKeys([]byte("\x1b[A\x1b[B\rq\x1b\x7f\x1b[6~\x03")))
+}
@@ -0,0 +1,514 @@
+package explore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Model is the state of the explore UI. It is updated with key presses and
+// rendered to a string, so it can be tested without a terminal.
+type Model struct {
+	root     output.Root
+	projects []*node
+	visible  []*node
+
+	sortBy sortBy
+	filter filter
+
+	cursor int
+	offset int
+
+	// filtering is true while the filter is being typed into input.
+	filtering bool
+	input     string
+
+	quit bool
+}
+
+// NewModel returns a model of the tree of the projects in root, sorted by
+// cost.
+func NewModel(root output.Root) *Model {
+	m := &Model{
+		root:     root,
+		projects: buildTree(root),
+	}
+
+	sortNodes(m.projects, m.sortBy)
+	m.refresh()
+
+	return m
+}
+
+// Quit returns true once the user has asked to exit.
+func (m *Model) Quit() bool {
+	return m.quit
+}
+
+func (m *Model) refresh() {
+	var selected *node
+	if m.cursor < len(m.visible) {
+		selected = m.visible[m.cursor]
+	}
+
+	m.visible = visibleNodes(m.projects, m.filter)
+
+	m.cursor = 0
+	for i, n := range m.visible {
+		if n == selected {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+func (m *Model) selected() *node {
+	if m.cursor >= len(m.visible) {
+		return nil
+	}
+
+	return m.visible[m.cursor]
+}
+
+func (m *Model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// Update handles a key press. Keys are either a single character or one of
+// the names returned by parseKeys, e.g. "up" or "enter".
+func (m *Model) Update(key string) {
+	if m.filtering {
+		m.updateFilter(key)
+		return
+	}
+
+	n := m.selected()
+
+	switch key {
+	case "q", "ctrl+c":
+		m.quit = true
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-10)
+	case "pgdown":
+		m.move(10)
+	case "home", "g":
+		m.move(-len(m.visible))
+	case "end", "G":
+		m.move(len(m.visible))
+	case "enter", " ":
+		if n != nil && len(n.children) > 0 {
+			n.expanded = !n.expanded
+			m.refresh()
+		}
+	case "right", "l":
+		if n != nil && len(n.children) > 0 {
+			n.expanded = true
+			m.refresh()
+		}
+	case "left", "h":
+		if n == nil {
+			break
+		}
+		if n.expanded {
+			n.expanded = false
+		} else if n.parent != nil {
+			n.parent.expanded = false
+			m.cursor = m.indexOf(n.parent)
+		}
+		m.refresh()
+	case "e":
+		setExpanded(m.projects, true)
+		m.refresh()
+	case "c":
+		setExpanded(m.projects, false)
+		m.refresh()
+	case "s":
+		m.sortBy = m.sortBy.next()
+		sortNodes(m.projects, m.sortBy)
+		m.refresh()
+	case "/":
+		m.filtering = true
+		m.input = m.filter.raw
+	case "esc":
+		m.setFilter("")
+	}
+}
+
+func (m *Model) updateFilter(key string) {
+	switch key {
+	case "enter":
+		m.filtering = false
+		m.setFilter(m.input)
+	case "esc", "ctrl+c":
+		m.filtering = false
+	case "backspace":
+		if m.input != "" {
+			r := []rune(m.input)
+			m.input = string(r[:len(r)-1])
+		}
+	default:
+		if len([]rune(key)) == 1 {
+			m.input += key
+		}
+	}
+}
+
+// setFilter filters the resources and expands the projects that have
+// matching resources so that they are shown.
+func (m *Model) setFilter(s string) {
+	m.filter = parseFilter(s)
+
+	if !m.filter.empty() {
+		for _, p := range m.projects {
+			if projectMatches(p, m.filter) {
+				p.expanded = true
+			}
+		}
+	}
+
+	m.refresh()
+}
+
+func (m *Model) indexOf(n *node) int {
+	for i, v := range m.visible {
+		if v == n {
+			return i
+		}
+	}
+
+	return m.cursor
+}
+
+// View renders the model to fill a terminal of the given size. The tree is
+// on the left and the details of the selected node are on the right.
+func (m *Model) View(width, height int) string {
+	if width < 20 || height < 5 {
+		return "Terminal too small"
+	}
+
+	bodyHeight := height - 2
+	treeWidth := width
+	detailWidth := 0
+	if width >= 80 {
+		treeWidth = width * 3 / 5
+		detailWidth = width - treeWidth - 1
+	}
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+bodyHeight {
+		m.offset = m.cursor - bodyHeight + 1
+	}
+
+	var detail []string
+	if detailWidth > 0 {
+		detail = m.details(m.selected())
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, reverse(fit(m.header(), width)))
+
+	for i := 0; i < bodyHeight; i++ {
+		var left string
+		idx := m.offset + i
+		if idx < len(m.visible) {
+			left = m.treeLine(m.visible[idx], treeWidth)
+			if idx == m.cursor {
+				left = reverse(left)
+			}
+		} else {
+			left = fit("", treeWidth)
+		}
+
+		if detailWidth == 0 {
+			lines = append(lines, left)
+			continue
+		}
+
+		var right string
+		if i < len(detail) {
+			right = detail[i]
+		}
+		lines = append(lines, left+"│"+fit(right, detailWidth))
+	}
+
+	lines = append(lines, fit(m.footer(), width))
+
+	return strings.Join(lines, "\r\n")
+}
+
+func (m *Model) header() string {
+	s := fmt.Sprintf(" Infracost explore │ Total monthly cost: %s", output.FormatCost2DP(m.root.Currency, m.root.TotalMonthlyCost))
+	if m.root.DiffTotalMonthlyCost != nil {
+		s += fmt.Sprintf(" (%s)", formatDiff(m.root.Currency, m.root.DiffTotalMonthlyCost))
+	}
+
+	s += fmt.Sprintf(" │ Sort: %s", m.sortBy)
+	if !m.filter.empty() {
+		s += fmt.Sprintf(" │ Filter: %s", m.filter.raw)
+	}
+
+	return s
+}
+
+func (m *Model) footer() string {
+	if m.filtering {
+		return fmt.Sprintf(" Filter (name, type:<type>, tag:<key>[=<value>]): %s█", m.input)
+	}
+
+	return " ↑↓ move  ←→ collapse/expand  enter toggle  e/c expand/collapse all  s sort  / filter  esc clear filter  q quit"
+}
+
+func (m *Model) treeLine(n *node, width int) string {
+	marker := "  "
+	if len(n.children) > 0 {
+		marker = "▸ "
+		if n.expanded {
+			marker = "▾ "
+		}
+	}
+
+	name := n.name
+	if n.removed {
+		name += " (removed)"
+	}
+
+	cost := output.FormatCost2DP(m.root.Currency, n.cost)
+	if n.diffCost != nil {
+		cost = fmt.Sprintf("%s %10s", cost, formatDiff(m.root.Currency, n.diffCost))
+	}
+
+	left := strings.Repeat("  ", n.depth()) + marker + name
+	// Leave a space between the name and the cost
+	nameWidth := width - text.RuneCount(cost) - 2
+	if nameWidth < 1 {
+		return fit(left, width)
+	}
+
+	return " " + fit(left, nameWidth) + " " + cost
+}
+
+// details returns the lines of the detail pane of the node.
+func (m *Model) details(n *node) []string {
+	if n == nil {
+		return nil
+	}
+
+	currency := m.root.Currency
+	var lines []string
+	add := func(label string, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf(" %s: %s", label, value))
+		}
+	}
+	section := func(title string) {
+		lines = append(lines, "", " "+title)
+	}
+	costs := func() {
+		add("Monthly cost", output.FormatCost2DP(currency, n.cost))
+		if n.pastCost != nil || n.diffCost != nil {
+			add("Past monthly cost", output.FormatCost2DP(currency, n.pastCost))
+			add("Monthly cost change", formatDiff(currency, n.diffCost))
+		}
+	}
+
+	switch n.kind {
+	case projectNode:
+		lines = append(lines, " Project")
+		add("Name", n.name)
+		if md := n.project.Metadata; md != nil {
+			add("Path", md.Path)
+			add("Module path", md.TerraformModulePath)
+			add("Workspace", md.WorkspaceLabel())
+		}
+		add("Resources", fmt.Sprint(len(n.children)))
+		costs()
+	case resourceNode, subResourceNode:
+		lines = append(lines, " Resource")
+		add("Name", n.name)
+		if n.kind == subResourceNode {
+			add("Parent", n.resource.Name)
+		}
+		add("Type", n.resource.ResourceType)
+		costs()
+		lines = append(lines, m.tagLines(n.resource)...)
+		lines = append(lines, usageLines(n.resource)...)
+	case costComponentNode:
+		c := n.component
+		lines = append(lines, " Cost component")
+		add("Name", c.Name)
+		add("Resource", n.resource.Name)
+		if n.subResource != nil {
+			add("Sub-resource", n.subResource.Name)
+		}
+		add("Unit", c.Unit)
+		add("Price", formatDecimal(&c.Price))
+		add("Monthly quantity", formatDecimal(c.MonthlyQuantity))
+		add("Hourly cost", formatDecimal(c.HourlyCost))
+		costs()
+		if c.UsageBased {
+			add("Usage-based", "yes")
+		}
+		if c.PriceNotFound {
+			add("Price not found", "yes")
+		}
+		add("Commitment", c.Commitment)
+		if c.Commitment != "" {
+			add("Monthly cost with commitment", output.FormatCost2DP(currency, c.CommitmentMonthlyCost))
+		}
+		add("Price override", c.PriceOverride)
+
+		section("Price filters")
+		add("Vendor", c.VendorName)
+		add("Service", c.Service)
+		add("Region", c.Region)
+		for _, a := range c.AttributeFilters {
+			switch {
+			case a.Value != nil:
+				add(a.Key, *a.Value)
+			case a.ValueRegex != nil:
+				add(a.Key, fmt.Sprintf("/%s/", *a.ValueRegex))
+			}
+		}
+		lines = append(lines, priceFilterLines(c.PriceFilter)...)
+
+		lines = append(lines, usageLines(n.resource)...)
+	}
+
+	return lines
+}
+
+func (m *Model) tagLines(r *output.Resource) []string {
+	tags := output.ResourceTags(*r)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	lines := []string{"", " Tags"}
+	for _, k := range sortedKeys(tags) {
+		lines = append(lines, fmt.Sprintf(" %s: %s", k, tags[k]))
+	}
+
+	return lines
+}
+
+func usageLines(r *output.Resource) []string {
+	if len(r.Usage) == 0 {
+		return nil
+	}
+
+	lines := []string{"", " Usage"}
+	keys := make([]string, 0, len(r.Usage))
+	for k := range r.Usage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := r.Usage[k]
+		s := fmt.Sprint(v)
+		if _, ok := v.(map[string]interface{}); ok {
+			b, err := json.Marshal(v)
+			if err == nil {
+				s = string(b)
+			}
+		}
+		lines = append(lines, fmt.Sprintf(" %s: %s", k, s))
+	}
+
+	return lines
+}
+
+func priceFilterLines(f *schema.PriceFilter) []string {
+	if f == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, v := range []struct {
+		label string
+		value *string
+	}{
+		{"Purchase option", f.PurchaseOption},
+		{"Unit", f.Unit},
+		{"Description", f.Description},
+		{"Description regex", f.DescriptionRegex},
+		{"Start usage amount", f.StartUsageAmount},
+		{"End usage amount", f.EndUsageAmount},
+		{"Term length", f.TermLength},
+		{"Term purchase option", f.TermPurchaseOption},
+		{"Term offering class", f.TermOfferingClass},
+	} {
+		if v.value != nil {
+			lines = append(lines, fmt.Sprintf(" %s: %s", v.label, *v.value))
+		}
+	}
+
+	return lines
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func formatDiff(currency string, d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	s := output.FormatCost2DP(currency, d)
+	if d.IsPositive() {
+		return "+" + s
+	}
+
+	return s
+}
+
+func formatDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
+
+// fit pads or truncates s to exactly width columns.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	if text.RuneCount(s) > width {
+		return text.Snip(s, width, "…")
+	}
+
+	return text.Pad(s, width, ' ')
+}
+
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[0m"
+}
@@ -0,0 +1,165 @@
+package explore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// resizePollInterval is how often the terminal size is checked so the UI can
+// be redrawn when the terminal is resized.
+var resizePollInterval = 250 * time.Millisecond
+
+// Run opens the explore UI full screen in the terminal until the user quits.
+func Run(root output.Root, in *os.File, out io.Writer) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("explore needs an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to put the terminal into raw mode: %w", err)
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+
+	fmt.Fprint(out, enterAltScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+exitAltScreen)
+
+	m := NewModel(root)
+
+	keys := make(chan []string)
+	errs := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				errs <- err
+				return
+			}
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+
+	width, height := terminalSize(fd)
+	draw := func() {
+		fmt.Fprint(out, clearScreen+m.View(width, height))
+	}
+	draw()
+
+	for {
+		select {
+		case ks := <-keys:
+			for _, k := range ks {
+				m.Update(k)
+			}
+			if m.Quit() {
+				return nil
+			}
+			draw()
+		case <-ticker.C:
+			w, h := terminalSize(fd)
+			if w != width || h != height {
+				width, height = w, h
+				draw()
+			}
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func terminalSize(fd int) (int, int) {
+	w, h, err := term.GetSize(fd)
+	if err != nil {
+		return 80, 24
+	}
+
+	return w, h
+}
+
+var escapeSequences = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1b[C":  "right",
+	"\x1b[D":  "left",
+	"\x1bOA":  "up",
+	"\x1bOB":  "down",
+	"\x1bOC":  "right",
+	"\x1bOD":  "left",
+	"\x1b[H":  "home",
+	"\x1b[F":  "end",
+	"\x1b[1~": "home",
+	"\x1b[4~": "end",
+	"\x1b[5~": "pgup",
+	"\x1b[6~": "pgdown",
+}
+
+// parseKeys returns the keys in the bytes read from the terminal in raw mode.
+// Special keys are returned by name, e.g. "up" or "enter", and other keys as
+// the character typed.
+func parseKeys(b []byte) []string {
+	var keys []string
+
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			matched := false
+			for seq, name := range escapeSequences {
+				if len(s) >= len(seq) && s[:len(seq)] == seq {
+					keys = append(keys, name)
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+
+			keys = append(keys, "esc")
+			s = s[1:]
+			continue
+		}
+
+		r := []rune(s)[0]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		default:
+			if r >= 0x20 {
+				keys = append(keys, string(r))
+			}
+		}
+		s = s[len(string(r)):]
+	}
+
+	return keys
+}
@@ -0,0 +1,355 @@
+package explore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+type nodeKind int
+
+const (
+	projectNode nodeKind = iota
+	resourceNode
+	subResourceNode
+	costComponentNode
+)
+
+// node is a row of the tree. Projects contain resources, resources contain
+// sub-resources and cost components, and sub-resources can be nested.
+type node struct {
+	kind     nodeKind
+	name     string
+	parent   *node
+	children []*node
+	expanded bool
+
+	cost     *decimal.Decimal
+	pastCost *decimal.Decimal
+	diffCost *decimal.Decimal
+	// removed is true for resources and cost components that only exist in the
+	// past breakdown.
+	removed bool
+
+	project *output.Project
+	// resource is the top-level resource of resource, sub-resource and cost
+	// component nodes, which is what is filtered on and has the tags and usage.
+	resource *output.Resource
+	// subResource is set for sub-resource nodes and for the cost components of
+	// sub-resources.
+	subResource *output.Resource
+	component   *output.CostComponent
+}
+
+func (n *node) depth() int {
+	d := 0
+	for p := n.parent; p != nil; p = p.parent {
+		d++
+	}
+
+	return d
+}
+
+// buildTree returns a project node for each project with its resources.
+// Projects with a diff include the resources that were removed.
+func buildTree(root output.Root) []*node {
+	projects := make([]*node, 0, len(root.Projects))
+
+	for i := range root.Projects {
+		p := &root.Projects[i]
+		pn := &node{kind: projectNode, name: p.Label(), project: p, expanded: len(root.Projects) == 1}
+
+		if p.Breakdown != nil {
+			pn.cost = p.Breakdown.TotalMonthlyCost
+		}
+		if p.PastBreakdown != nil {
+			pn.pastCost = p.PastBreakdown.TotalMonthlyCost
+		}
+		if p.Diff != nil {
+			pn.diffCost = p.Diff.TotalMonthlyCost
+		}
+
+		var current, past, diff []output.Resource
+		if p.Breakdown != nil {
+			current = p.Breakdown.Resources
+		}
+		if p.PastBreakdown != nil {
+			past = p.PastBreakdown.Resources
+		}
+		if p.Diff != nil {
+			diff = p.Diff.Resources
+		}
+
+		for j := range current {
+			r := &current[j]
+			pn.children = append(pn.children, buildResourceNode(pn, p, r, r, findResource(past, r.Name), findResource(diff, r.Name), false))
+		}
+
+		for j := range past {
+			r := &past[j]
+			if findResource(current, r.Name) != nil {
+				continue
+			}
+
+			rn := buildResourceNode(pn, p, r, r, r, findResource(diff, r.Name), true)
+			pn.children = append(pn.children, rn)
+		}
+
+		projects = append(projects, pn)
+	}
+
+	return projects
+}
+
+// buildResourceNode returns the node of a resource or sub-resource. For
+// removed resources r is the past resource.
+func buildResourceNode(parent *node, project *output.Project, topLevel *output.Resource, r *output.Resource, past *output.Resource, diff *output.Resource, removed bool) *node {
+	kind := resourceNode
+	var subResource *output.Resource
+	if parent.kind != projectNode {
+		kind = subResourceNode
+		subResource = r
+	}
+
+	n := &node{
+		kind:        kind,
+		name:        r.Name,
+		parent:      parent,
+		removed:     removed,
+		project:     project,
+		resource:    topLevel,
+		subResource: subResource,
+	}
+
+	if !removed {
+		n.cost = r.MonthlyCost
+	}
+	if past != nil {
+		n.pastCost = past.MonthlyCost
+	}
+	if diff != nil {
+		n.diffCost = diff.MonthlyCost
+	}
+
+	for i := range r.CostComponents {
+		c := &r.CostComponents[i]
+		cn := &node{
+			kind:        costComponentNode,
+			name:        c.Name,
+			parent:      n,
+			removed:     removed,
+			project:     project,
+			resource:    topLevel,
+			subResource: subResource,
+			component:   c,
+		}
+
+		if !removed {
+			cn.cost = c.MonthlyCost
+		}
+		if pc := findCostComponent(past, c.Name); pc != nil {
+			cn.pastCost = pc.MonthlyCost
+		}
+		if dc := findCostComponent(diff, c.Name); dc != nil {
+			cn.diffCost = dc.MonthlyCost
+		}
+
+		n.children = append(n.children, cn)
+	}
+
+	for i := range r.SubResources {
+		s := &r.SubResources[i]
+		var pastSub, diffSub *output.Resource
+		if past != nil {
+			pastSub = findResource(past.SubResources, s.Name)
+		}
+		if diff != nil {
+			diffSub = findResource(diff.SubResources, s.Name)
+		}
+
+		n.children = append(n.children, buildResourceNode(n, project, topLevel, s, pastSub, diffSub, removed))
+	}
+
+	return n
+}
+
+func findResource(resources []output.Resource, name string) *output.Resource {
+	for i := range resources {
+		if resources[i].Name == name {
+			return &resources[i]
+		}
+	}
+
+	return nil
+}
+
+func findCostComponent(r *output.Resource, name string) *output.CostComponent {
+	if r == nil {
+		return nil
+	}
+
+	for i := range r.CostComponents {
+		if r.CostComponents[i].Name == name {
+			return &r.CostComponents[i]
+		}
+	}
+
+	return nil
+}
+
+// sortBy is the order of the nodes at each level of the tree.
+type sortBy int
+
+const (
+	sortByCost sortBy = iota
+	sortByDiff
+	sortByName
+)
+
+var sortByLabels = map[sortBy]string{
+	sortByCost: "cost",
+	sortByDiff: "diff",
+	sortByName: "name",
+}
+
+func (s sortBy) next() sortBy {
+	return (s + 1) % 3
+}
+
+func (s sortBy) String() string {
+	return sortByLabels[s]
+}
+
+// sortNodes sorts the nodes and their children. Costs are sorted highest
+// first and diffs by the size of the change, so the biggest changes in either
+// direction are at the top.
+func sortNodes(nodes []*node, by sortBy) {
+	value := func(n *node) decimal.Decimal {
+		switch by {
+		case sortByDiff:
+			if n.diffCost == nil {
+				return decimal.Zero
+			}
+			return n.diffCost.Abs()
+		default:
+			if n.cost == nil {
+				return decimal.Zero
+			}
+			return *n.cost
+		}
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if by != sortByName {
+			a, b := value(nodes[i]), value(nodes[j])
+			if !a.Equal(b) {
+				return a.GreaterThan(b)
+			}
+		}
+
+		return nodes[i].name < nodes[j].name
+	})
+
+	for _, n := range nodes {
+		sortNodes(n.children, by)
+	}
+}
+
+// filter matches resources by their name, or by their type or tags with the
+// type: and tag: prefixes, e.g. type:aws_instance, tag:team or tag:team=web.
+type filter struct {
+	raw          string
+	resourceType string
+	tagKey       string
+	tagValue     string
+	hasTagValue  bool
+	name         string
+}
+
+func parseFilter(s string) filter {
+	s = strings.TrimSpace(s)
+	f := filter{raw: s}
+
+	switch {
+	case strings.HasPrefix(s, "type:"):
+		f.resourceType = strings.ToLower(strings.TrimPrefix(s, "type:"))
+	case strings.HasPrefix(s, "tag:"):
+		f.tagKey, f.tagValue, f.hasTagValue = strings.Cut(strings.TrimPrefix(s, "tag:"), "=")
+	default:
+		f.name = strings.ToLower(s)
+	}
+
+	return f
+}
+
+func (f filter) empty() bool {
+	return f.raw == ""
+}
+
+func (f filter) matches(r *output.Resource) bool {
+	switch {
+	case f.resourceType != "":
+		return strings.Contains(strings.ToLower(r.ResourceType), f.resourceType)
+	case f.tagKey != "":
+		v, ok := output.ResourceTags(*r)[f.tagKey]
+		if !ok {
+			return false
+		}
+		return !f.hasTagValue || v == f.tagValue
+	default:
+		return strings.Contains(strings.ToLower(r.Name), f.name)
+	}
+}
+
+// visibleNodes returns the nodes that are shown in order, skipping the
+// children of collapsed nodes. When filtering, only the resources that match
+// and the projects that contain them are shown.
+func visibleNodes(nodes []*node, f filter) []*node {
+	var visible []*node
+
+	var walk func(n *node)
+	walk = func(n *node) {
+		visible = append(visible, n)
+		if !n.expanded {
+			return
+		}
+
+		for _, c := range n.children {
+			if c.kind == resourceNode && !f.empty() && !f.matches(c.resource) {
+				continue
+			}
+			walk(c)
+		}
+	}
+
+	for _, n := range nodes {
+		if !f.empty() && !projectMatches(n, f) {
+			continue
+		}
+		walk(n)
+	}
+
+	return visible
+}
+
+func projectMatches(n *node, f filter) bool {
+	for _, c := range n.children {
+		if f.matches(c.resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setExpanded(nodes []*node, expanded bool) {
+	for _, n := range nodes {
+		if len(n.children) > 0 {
+			n.expanded = expanded
+		}
+		setExpanded(n.children, expanded)
+	}
+}
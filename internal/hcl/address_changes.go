@@ -0,0 +1,130 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// AddressChanges returns the changes to resource addresses from the moved,
+// import and removed blocks in the module and its child modules. Addresses in
+// child modules are prefixed with the module address so that they are relative
+// to the root module.
+func (m *Module) AddressChanges() *schema.AddressChanges {
+	changes := &schema.AddressChanges{}
+	m.addAddressChanges(changes)
+
+	return changes
+}
+
+func (m *Module) addAddressChanges(changes *schema.AddressChanges) {
+	prefix := ""
+	if m.Name != "" {
+		prefix = m.Name + "."
+	}
+
+	for _, b := range m.Blocks.OfType("moved") {
+		from := blockAddress(b, "from")
+		to := blockAddress(b, "to")
+		if from == "" || to == "" {
+			continue
+		}
+
+		changes.Moved = append(changes.Moved, schema.MovedAddress{From: prefix + from, To: prefix + to})
+	}
+
+	// import blocks are only allowed in the root module.
+	if m.Parent == nil {
+		for _, b := range m.Blocks.OfType("import") {
+			// import blocks with for_each can't be resolved to a single address,
+			// so their target is left as a new resource.
+			if b.GetAttribute("for_each") != nil {
+				continue
+			}
+
+			to := blockAddress(b, "to")
+			if to == "" {
+				continue
+			}
+
+			changes.Imported = append(changes.Imported, to)
+		}
+	}
+
+	for _, b := range m.Blocks.OfType("removed") {
+		lifecycle := b.GetChildBlock("lifecycle")
+		if lifecycle == nil {
+			continue
+		}
+
+		destroy := lifecycle.GetAttribute("destroy")
+		if destroy == nil {
+			continue
+		}
+
+		v := destroy.Value()
+		if !v.IsKnown() || v.IsNull() || v.Type() != cty.Bool || v.True() {
+			continue
+		}
+
+		from := blockAddress(b, "from")
+		if from == "" {
+			continue
+		}
+
+		changes.Removed = append(changes.Removed, prefix+from)
+	}
+
+	for _, child := range m.Modules {
+		child.addAddressChanges(changes)
+	}
+}
+
+// blockAddress returns the address that the attribute of the block refers to,
+// e.g. the from attribute of a moved block, or an empty string if the
+// attribute isn't a static address.
+func blockAddress(b *Block, name string) string {
+	attr := b.GetAttribute(name)
+	if attr == nil || attr.HCLAttr == nil {
+		return ""
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(attr.HCLAttr.Expr)
+	if diags.HasErrors() {
+		return ""
+	}
+
+	return traversalAddress(traversal)
+}
+
+// traversalAddress formats a traversal like module.web["a"].aws_instance.this[0].
+func traversalAddress(traversal hcl.Traversal) string {
+	var sb strings.Builder
+
+	for _, t := range traversal {
+		switch step := t.(type) {
+		case hcl.TraverseRoot:
+			sb.WriteString(step.Name)
+		case hcl.TraverseAttr:
+			sb.WriteString(".")
+			sb.WriteString(step.Name)
+		case hcl.TraverseIndex:
+			switch {
+			case step.Key.Type() == cty.String:
+				sb.WriteString(fmt.Sprintf("[%q]", step.Key.AsString()))
+			case step.Key.Type() == cty.Number:
+				sb.WriteString(fmt.Sprintf("[%s]", step.Key.AsBigFloat().Text('f', -1)))
+			default:
+				return ""
+			}
+		default:
+			return ""
+		}
+	}
+
+	return sb.String()
+}
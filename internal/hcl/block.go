@@ -56,6 +56,15 @@ var (
 				Type:       "data",
 				LabelNames: []string{"type", "name"},
 			},
+			{
+				Type: "moved",
+			},
+			{
+				Type: "import",
+			},
+			{
+				Type: "removed",
+			},
 		},
 	}
 	terraformAndProviderBlocks = &hcl.BodySchema{
@@ -287,10 +296,17 @@ type Block struct {
 	verbose bool
 	logger  zerolog.Logger
 	// isGraph is a flag that indicates if the attribute should be evaluated with the graph evaluation
-	isGraph    bool
-	newMock    func(attr *Attribute) cty.Value
-	attributes []*Attribute
-	reference  *Reference
+	isGraph bool
+	newMock func(attr *Attribute) cty.Value
+	// dataSources are the user supplied data sources that replace the mocked
+	// values of data blocks.
+	dataSources dataSourceFixtures
+	// providerKey is the instance key of a provider block that has been expanded
+	// from an OpenTofu provider for_each, e.g. "us-east-1" for
+	// aws.by_region["us-east-1"].
+	providerKey string
+	attributes  []*Attribute
+	reference   *Reference
 
 	Filename  string
 	StartLine int
@@ -304,6 +320,8 @@ type BlockBuilder struct {
 	Logger        zerolog.Logger
 	HCLParser     *modules.SharedHCLParser
 	isGraph       bool
+	isOpenTofu    bool
+	dataSources   dataSourceFixtures
 }
 
 // NewBlock returns a Block with Context and child Blocks initialised.
@@ -337,6 +355,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 			verbose:     isLoggingVerbose,
 			isGraph:     b.isGraph,
 			newMock:     b.MockFunc,
+			dataSources: b.dataSources,
 			parent:      parent,
 		}
 
@@ -371,6 +390,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 			verbose:     isLoggingVerbose,
 			isGraph:     b.isGraph,
 			newMock:     b.MockFunc,
+			dataSources: b.dataSources,
 		}
 		block.setLogger(b.Logger)
 
@@ -390,6 +410,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 		verbose:     isLoggingVerbose,
 		isGraph:     b.isGraph,
 		newMock:     b.MockFunc,
+		dataSources: b.dataSources,
 	}
 
 	for i, hb := range content.Blocks {
@@ -1059,6 +1080,12 @@ func (b *Block) getHCLAttributes() hcl.Attributes {
 //
 // Would evaluate to a cty.Value of type Object with the instance_type Attribute holding the value "t3.medium".
 func (b *Block) Values() cty.Value {
+	if b.Type() == "data" {
+		if v, ok := b.dataSourceValues(); ok {
+			return v
+		}
+	}
+
 	if f, ok := blockValueFuncs[fmt.Sprintf("%s.%s", b.Type(), b.TypeLabel())]; ok {
 		return f(b)
 	}
@@ -1127,6 +1154,10 @@ func (b *Block) values() cty.Value {
 			configKey = configKey + "." + alias
 		}
 
+		if b.providerKey != "" {
+			configKey = fmt.Sprintf("%s[%q]", configKey, b.providerKey)
+		}
+
 		if b.ModuleAddress() != "" {
 			configKey = b.ModuleAddress() + ":" + configKey
 		}
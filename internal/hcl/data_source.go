@@ -0,0 +1,115 @@
+package hcl
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// dataSourceFixture is a user supplied data source from the config file that
+// replaces the mocked values of the data blocks that it matches.
+type dataSourceFixture struct {
+	address *regexp.Regexp
+	typ     *regexp.Regexp
+	values  map[string]cty.Value
+}
+
+// dataSourceFixtures are the data sources used by a Parser. Fixtures that
+// match a block by address take precedence over fixtures that match by type,
+// otherwise the first fixture that matches is used.
+type dataSourceFixtures []dataSourceFixture
+
+func newDataSourceFixtures(dataSources []config.DataSource, logger zerolog.Logger) dataSourceFixtures {
+	fixtures := make(dataSourceFixtures, 0, len(dataSources))
+
+	for _, d := range dataSources {
+		f := dataSourceFixture{values: make(map[string]cty.Value, len(d.Values))}
+		if d.Address != "" {
+			f.address = wildcardRegex(d.Address)
+		} else {
+			f.typ = wildcardRegex(d.Type)
+		}
+
+		for k, val := range d.Values {
+			v, err := dataSourceValue(val)
+			if err != nil {
+				logger.Debug().Err(err).Msgf("could not parse data source value %s", k)
+				continue
+			}
+
+			f.values[k] = v
+		}
+
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures
+}
+
+// dataSourceValue converts a value from the config file to a cty.Value.
+// Unlike input vars, strings are not parsed as HCL expressions since data
+// source values are often IDs like ami-123 that would be parsed as one.
+func dataSourceValue(val interface{}) (cty.Value, error) {
+	if s, ok := val.(string); ok {
+		return cty.StringVal(s), nil
+	}
+
+	return ParseVariable(val)
+}
+
+// wildcardRegex returns a regex that matches the whole pattern where * matches
+// any characters.
+func wildcardRegex(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
+
+// match returns the fixture values for the data block, or false if no fixture
+// matches it.
+func (f dataSourceFixtures) match(b *Block) (map[string]cty.Value, bool) {
+	if len(f) == 0 {
+		return nil, false
+	}
+
+	fullName := b.FullName()
+	withoutIndex := stripCount(fullName)
+
+	for _, fixture := range f {
+		if fixture.address != nil && (fixture.address.MatchString(fullName) || fixture.address.MatchString(withoutIndex)) {
+			return fixture.values, true
+		}
+	}
+
+	for _, fixture := range f {
+		if fixture.typ != nil && fixture.typ.MatchString(b.TypeLabel()) {
+			return fixture.values, true
+		}
+	}
+
+	return nil, false
+}
+
+// dataSourceValues returns the values of a data block with the attributes of
+// the fixture that matches it set, or false if no fixture matches it.
+func (b *Block) dataSourceValues() (cty.Value, bool) {
+	fixtureValues, ok := b.dataSources.match(b)
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	values := map[string]cty.Value{}
+	if v := b.values(); !v.IsNull() && v.IsKnown() && v.CanIterateElements() {
+		for k, attr := range v.AsValueMap() {
+			values[k] = attr
+		}
+	}
+
+	for k, v := range fixtureValues {
+		values[k] = v
+	}
+
+	return cty.ObjectVal(values), true
+}
@@ -244,6 +244,13 @@ func (e *Evaluator) Run() (*Module, error) {
 	e.logger.Debug().Msg("evaluating top level context")
 	e.evaluate(lastContext)
 
+	// OpenTofu supports for_each on provider blocks, which needs to be expanded
+	// before the modules are loaded so that provider instances can be passed to
+	// modules.
+	if e.blockBuilder.isOpenTofu {
+		e.expandProviderForEaches()
+	}
+
 	// let's load the modules now we have our top level context.
 	e.loadModules(lastContext)
 	e.logger.Debug().Msg("evaluating context after loading modules")
@@ -488,6 +495,7 @@ func (e *Evaluator) expandDynamicBlock(b *Block) *Block {
 		logger:      b.logger,
 		isGraph:     b.isGraph,
 		newMock:     b.newMock,
+		dataSources: b.dataSources,
 		attributes:  b.attributes,
 		reference:   b.reference,
 		Filename:    b.Filename,
@@ -913,20 +921,151 @@ func (e *Evaluator) evaluateProvider(b *Block, values map[string]cty.Value) cty.
 		return cty.ObjectVal(values)
 	}
 
-	if !exists {
-		return cty.ObjectVal(map[string]cty.Value{
-			str: b.Values(),
-		})
+	var ob map[string]cty.Value
+	if exists {
+		ob = v.AsValueMap()
 	}
-
-	ob := v.AsValueMap()
 	if ob == nil {
 		ob = make(map[string]cty.Value)
 	}
-	ob[str] = b.Values()
+
+	if b.providerKey == "" {
+		ob[str] = b.Values()
+		return cty.ObjectVal(ob)
+	}
+
+	// instances of a provider with a for_each are referenced by their key,
+	// e.g. aws.by_region["us-east-1"].
+	var instances map[string]cty.Value
+	if existing, ok := ob[str]; ok && existing.Type().IsObjectType() {
+		instances = existing.AsValueMap()
+	}
+	if instances == nil {
+		instances = make(map[string]cty.Value)
+	}
+	instances[b.providerKey] = b.Values()
+	ob[str] = cty.ObjectVal(instances)
+
 	return cty.ObjectVal(ob)
 }
 
+// expandProviderForEaches replaces the provider blocks that have a for_each,
+// which OpenTofu supports for aliased providers, with a block for each instance.
+// Resources and module calls then reference an instance with its key, e.g.
+// aws.by_region["us-east-1"].
+func (e *Evaluator) expandProviderForEaches() {
+	var blocks Blocks
+	for _, block := range e.module.Blocks {
+		if block.Type() != "provider" {
+			blocks = append(blocks, block)
+			continue
+		}
+
+		instances := e.expandProviderForEach(block)
+		if instances == nil {
+			blocks = append(blocks, block)
+			continue
+		}
+
+		blocks = append(blocks, instances...)
+	}
+
+	e.module.Blocks = blocks
+
+	providers := e.getValuesByBlockType("provider")
+	for key, provider := range providers.AsValueMap() {
+		e.ctx.Set(provider, key)
+	}
+}
+
+// expandProviderForEach returns a block for each instance of the aliased
+// provider block with a for_each and adds the instances to the provider
+// references of the module. It returns nil if the block has no for_each or
+// it can't be expanded.
+func (e *Evaluator) expandProviderForEach(block *Block) Blocks {
+	forEachAttr := block.GetAttribute("for_each")
+	if forEachAttr == nil || block.providerKey != "" || block.GetAttribute("alias") == nil {
+		return nil
+	}
+
+	value := forEachAttr.Value()
+	if value.IsNull() || !value.IsWhollyKnown() || !forEachAttr.IsIterable() {
+		e.logger.Debug().Msgf("could not expand provider %s as its for_each is unknown", block.LocalName())
+		return nil
+	}
+
+	name := block.Label() + "." + block.GetAttribute("alias").AsString()
+	e.logger.Debug().Msgf("expanding provider %s because a for_each attribute was found", name)
+	delete(e.module.ProviderReferences, name)
+
+	instances := Blocks{}
+	value.ForEachElement(func(key cty.Value, val cty.Value) bool {
+		var keyStr string
+		err := gocty.FromCtyValue(key, &keyStr)
+		if err != nil {
+			e.logger.Debug().Err(err).Msgf("could not marshal gocty key %s to string", key)
+			return false
+		}
+
+		instance := e.blockBuilder.NewBlock(block.Filename, block.rootPath, block.HCLBlock, block.context.NewChild(), block.parent, block.moduleBlock)
+		instance.providerKey = keyStr
+		instance.context.SetByDot(key, "each.key")
+		instance.context.SetByDot(val, "each.value")
+
+		instances = append(instances, instance)
+		e.module.ProviderReferences[fmt.Sprintf("%s[%q]", name, keyStr)] = instance
+
+		return false
+	})
+
+	return instances
+}
+
+// providerInstanceReferences returns the provider instances passed to a module
+// in the providers attribute, e.g. { aws = aws.by_region[each.key] }. These
+// can't be decoded statically with DecodeProviders so are found by evaluating
+// the attribute and matching the provider config keys.
+func (e *Evaluator) providerInstanceReferences(providerAttr *Attribute) map[string]*Block {
+	instances := make(map[string]*Block)
+	for _, block := range e.module.ProviderReferences {
+		if block == nil || block.providerKey == "" {
+			continue
+		}
+
+		var configKey string
+		if err := gocty.FromCtyValue(block.Values().GetAttr("config_key"), &configKey); err == nil {
+			instances[configKey] = block
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil
+	}
+
+	val := providerAttr.Value()
+	if val.IsNull() || !val.IsWhollyKnown() || !val.Type().IsObjectType() {
+		return nil
+	}
+
+	refs := make(map[string]*Block)
+	for key, provider := range val.AsValueMap() {
+		if provider.IsNull() || !provider.Type().IsObjectType() || !provider.Type().HasAttribute("config_key") {
+			continue
+		}
+
+		var configKey string
+		if err := gocty.FromCtyValue(provider.GetAttr("config_key"), &configKey); err != nil {
+			continue
+		}
+
+		if block, ok := instances[configKey]; ok {
+			refs[key] = block
+		}
+	}
+
+	return refs
+}
+
 // evaluateResourceOrData evaluates a resource or data block.
 // The values map is used to pass in the current context values. This is only needed
 // for the legacy evaluator and is not used for the graph evaluator.
@@ -1040,6 +1179,12 @@ func (e *Evaluator) loadModuleWithProviders(b *Block) (*ModuleCall, error) {
 		for key, val := range decodedProviders {
 			providerRefs[key] = providerRefs[val]
 		}
+
+		if e.blockBuilder.isOpenTofu {
+			for key, block := range e.providerInstanceReferences(providerAttr) {
+				providerRefs[key] = block
+			}
+		}
 	}
 
 	modCall.Module.ProviderReferences = providerRefs
@@ -1062,6 +1207,12 @@ func (e *Evaluator) loadModule(b *Block) (*ModuleCall, error) {
 		}
 	}
 
+	return e.loadModuleFromSource(b, source)
+}
+
+// loadModuleFromSource loads the module called by the module block from the
+// given source, which has already been read from the block.
+func (e *Evaluator) loadModuleFromSource(b *Block, source string) (*ModuleCall, error) {
 	if source == "" {
 		return nil, fmt.Errorf("could not read module source attribute at %s", b.FullName())
 	}
@@ -1265,6 +1416,10 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"zipmap":           stdlib.ZipmapFunc,
 	}
 
+	for name, fn := range funcs.ProviderFunctions() {
+		fns[name] = fn
+	}
+
 	fns["templatefile"] = funcs.MakeTemplateFileFunc(baseDir, func() map[string]function.Function {
 		return fns
 	})
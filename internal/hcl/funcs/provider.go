@@ -0,0 +1,292 @@
+package funcs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// ProviderFunctions returns the provider-defined functions that can be called
+// with the provider::<provider>::<function> syntax supported by OpenTofu and
+// Terraform 1.8+. Only functions that are useful for evaluating cost related
+// attributes are included, along with the built-in terraform provider functions.
+func ProviderFunctions() map[string]function.Function {
+	return map[string]function.Function{
+		"provider::aws::arn_build":           AWSARNBuildFunc,
+		"provider::aws::arn_parse":           AWSARNParseFunc,
+		"provider::aws::trim_iam_role_path":  AWSTrimIAMRolePathFunc,
+		"provider::google::location_from_id": googleFromIDFunc("location", "locations", "regions", "zones"),
+		"provider::google::name_from_id":     GoogleNameFromIDFunc,
+		"provider::google::project_from_id":  googleFromIDFunc("project", "projects"),
+		"provider::google::region_from_id":   googleFromIDFunc("region", "regions"),
+		"provider::google::region_from_zone": GoogleRegionFromZoneFunc,
+		"provider::google::zone_from_id":     googleFromIDFunc("zone", "zones"),
+		"provider::terraform::decode_tfvars": TerraformDecodeTFVarsFunc,
+		"provider::terraform::encode_expr":   TerraformEncodeExprFunc,
+		"provider::terraform::encode_tfvars": TerraformEncodeTFVarsFunc,
+	}
+}
+
+var awsARNType = cty.Object(map[string]cty.Type{
+	"partition":  cty.String,
+	"service":    cty.String,
+	"region":     cty.String,
+	"account_id": cty.String,
+	"resource":   cty.String,
+})
+
+// AWSARNParseFunc parses an ARN into its components, see
+// https://registry.terraform.io/providers/hashicorp/aws/latest/docs/functions/arn_parse.
+var AWSARNParseFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "arn",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(awsARNType),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		parsed, err := arn.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"partition":  cty.StringVal(parsed.Partition),
+			"service":    cty.StringVal(parsed.Service),
+			"region":     cty.StringVal(parsed.Region),
+			"account_id": cty.StringVal(parsed.AccountID),
+			"resource":   cty.StringVal(parsed.Resource),
+		}), nil
+	},
+})
+
+// AWSARNBuildFunc builds an ARN from its components, see
+// https://registry.terraform.io/providers/hashicorp/aws/latest/docs/functions/arn_build.
+var AWSARNBuildFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "partition", Type: cty.String},
+		{Name: "service", Type: cty.String},
+		{Name: "region", Type: cty.String},
+		{Name: "account_id", Type: cty.String},
+		{Name: "resource", Type: cty.String},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.StringVal(arn.ARN{
+			Partition: args[0].AsString(),
+			Service:   args[1].AsString(),
+			Region:    args[2].AsString(),
+			AccountID: args[3].AsString(),
+			Resource:  args[4].AsString(),
+		}.String()), nil
+	},
+})
+
+// AWSTrimIAMRolePathFunc removes the path from an IAM role ARN, see
+// https://registry.terraform.io/providers/hashicorp/aws/latest/docs/functions/trim_iam_role_path.
+var AWSTrimIAMRolePathFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "arn",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		parsed, err := arn.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+
+		if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "%q is not an IAM role ARN", args[0].AsString())
+		}
+
+		parts := strings.Split(parsed.Resource, "/")
+		parsed.Resource = "role/" + parts[len(parts)-1]
+
+		return cty.StringVal(parsed.String()), nil
+	},
+})
+
+// GoogleRegionFromZoneFunc returns the region of a zone, e.g. us-central1 for
+// us-central1-a, see
+// https://registry.terraform.io/providers/hashicorp/google/latest/docs/functions/region_from_zone.
+var GoogleRegionFromZoneFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "zone",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		zone := args[0].AsString()
+		i := strings.LastIndex(zone, "-")
+		if i <= 0 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "%q is not a valid zone", zone)
+		}
+
+		return cty.StringVal(zone[:i]), nil
+	},
+})
+
+// GoogleNameFromIDFunc returns the name of the resource from a resource id or
+// self link, i.e. the last segment of the path.
+var GoogleNameFromIDFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "id",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		id := strings.TrimSuffix(args[0].AsString(), "/")
+		i := strings.LastIndex(id, "/")
+		if i < 0 || i == len(id)-1 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "%q is not a valid resource id", id)
+		}
+
+		return cty.StringVal(id[i+1:]), nil
+	},
+})
+
+// googleFromIDFunc returns a function that extracts the segment following any of
+// the collections, e.g. "projects", from a Google resource id or self link.
+func googleFromIDFunc(name string, collections ...string) function.Function {
+	re := regexp.MustCompile(fmt.Sprintf(`(?:^|/)(?:%s)/([^/]+)`, strings.Join(collections, "|")))
+
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "id",
+				Type: cty.String,
+			},
+		},
+		Type:         function.StaticReturnType(cty.String),
+		RefineResult: refineNonNull,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			m := re.FindStringSubmatch(args[0].AsString())
+			if len(m) < 2 {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "could not find a %s in %q", name, args[0].AsString())
+			}
+
+			return cty.StringVal(m[1]), nil
+		},
+	})
+}
+
+// TerraformDecodeTFVarsFunc decodes a string in the .tfvars format into an
+// object.
+var TerraformDecodeTFVarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		f, diags := hclsyntax.ParseConfig([]byte(args[0].AsString()), "<decode_tfvars argument>", hcl.InitialPos)
+		if diags.HasErrors() {
+			return cty.DynamicVal, function.NewArgErrorf(0, "invalid tfvars syntax: %s", diags.Error())
+		}
+
+		attrs, diags := f.Body.JustAttributes()
+		if diags.HasErrors() {
+			return cty.DynamicVal, function.NewArgErrorf(0, "invalid tfvars content: %s", diags.Error())
+		}
+
+		vals := make(map[string]cty.Value, len(attrs))
+		for name, attr := range attrs {
+			v, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return cty.DynamicVal, function.NewArgErrorf(0, "invalid expression for variable %q: %s", name, diags.Error())
+			}
+
+			vals[name] = v
+		}
+
+		return cty.ObjectVal(vals), nil
+	},
+})
+
+// TerraformEncodeTFVarsFunc encodes an object into a string in the .tfvars
+// format.
+var TerraformEncodeTFVarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		if !v.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		ty := v.Type()
+		if v.IsNull() || !(ty.IsObjectType() || ty.IsMapType()) {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid value to encode: must be an object whose attribute names will become the encoded variable names")
+		}
+
+		vals := v.AsValueMap()
+		names := make([]string, 0, len(vals))
+		for name := range vals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		f := hclwrite.NewEmptyFile()
+		for _, name := range names {
+			if !hclsyntax.ValidIdentifier(name) {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid variable name %q", name)
+			}
+
+			f.Body().SetAttributeValue(name, vals[name])
+		}
+
+		return cty.StringVal(string(f.Bytes())), nil
+	},
+})
+
+// TerraformEncodeExprFunc encodes a value into a string with the HCL expression
+// that would produce it.
+var TerraformEncodeExprFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+			AllowNull:        true,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNonNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		if !args[0].IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		return cty.StringVal(string(hclwrite.TokensForValue(args[0]).Bytes())), nil
+	},
+})
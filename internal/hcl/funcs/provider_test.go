@@ -0,0 +1,140 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestProviderFunctions(t *testing.T) {
+	fns := ProviderFunctions()
+
+	tests := []struct {
+		Name string
+		Args []cty.Value
+		Want cty.Value
+		Err  bool
+	}{
+		{
+			"provider::aws::arn_parse",
+			[]cty.Value{cty.StringVal("arn:aws:iam::123456789012:role/path/web")},
+			cty.ObjectVal(map[string]cty.Value{
+				"partition":  cty.StringVal("aws"),
+				"service":    cty.StringVal("iam"),
+				"region":     cty.StringVal(""),
+				"account_id": cty.StringVal("123456789012"),
+				"resource":   cty.StringVal("role/path/web"),
+			}),
+			false,
+		},
+		{
+			"provider::aws::arn_parse",
+			[]cty.Value{cty.StringVal("not-an-arn")},
+			cty.UnknownVal(awsARNType),
+			true,
+		},
+		{
+			"provider::aws::arn_build",
+			[]cty.Value{cty.StringVal("aws"), cty.StringVal("ec2"), cty.StringVal("us-east-1"), cty.StringVal("123456789012"), cty.StringVal("instance/i-1234")},
+			cty.StringVal("arn:aws:ec2:us-east-1:123456789012:instance/i-1234"),
+			false,
+		},
+		{
+			"provider::aws::trim_iam_role_path",
+			[]cty.Value{cty.StringVal("arn:aws:iam::123456789012:role/path/web")},
+			cty.StringVal("arn:aws:iam::123456789012:role/web"),
+			false,
+		},
+		{
+			"provider::google::region_from_zone",
+			[]cty.Value{cty.StringVal("us-central1-a")},
+			cty.StringVal("us-central1"),
+			false,
+		},
+		{
+			"provider::google::region_from_id",
+			[]cty.Value{cty.StringVal("https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1/subnetworks/default")},
+			cty.StringVal("europe-west1"),
+			false,
+		},
+		{
+			"provider::google::zone_from_id",
+			[]cty.Value{cty.StringVal("projects/my-project/zones/us-central1-a/instances/web")},
+			cty.StringVal("us-central1-a"),
+			false,
+		},
+		{
+			"provider::google::location_from_id",
+			[]cty.Value{cty.StringVal("projects/my-project/locations/us-central1/clusters/main")},
+			cty.StringVal("us-central1"),
+			false,
+		},
+		{
+			"provider::google::project_from_id",
+			[]cty.Value{cty.StringVal("projects/my-project/zones/us-central1-a/instances/web")},
+			cty.StringVal("my-project"),
+			false,
+		},
+		{
+			"provider::google::name_from_id",
+			[]cty.Value{cty.StringVal("projects/my-project/zones/us-central1-a/instances/web")},
+			cty.StringVal("web"),
+			false,
+		},
+		{
+			"provider::google::project_from_id",
+			[]cty.Value{cty.StringVal("web")},
+			cty.UnknownVal(cty.String),
+			true,
+		},
+		{
+			"provider::terraform::decode_tfvars",
+			[]cty.Value{cty.StringVal("instance_type = \"t3.micro\"\ncount = 2\n")},
+			cty.ObjectVal(map[string]cty.Value{
+				"instance_type": cty.StringVal("t3.micro"),
+				"count":         cty.NumberIntVal(2),
+			}),
+			false,
+		},
+		{
+			"provider::terraform::encode_tfvars",
+			[]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"region":        cty.StringVal("us-east-1"),
+				"instance_type": cty.StringVal("t3.micro"),
+			})},
+			cty.StringVal("instance_type = \"t3.micro\"\nregion        = \"us-east-1\"\n"),
+			false,
+		},
+		{
+			"provider::terraform::encode_expr",
+			[]cty.Value{cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})},
+			cty.StringVal(`["a", "b"]`),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s(%#v)", test.Name, test.Args), func(t *testing.T) {
+			fn, ok := fns[test.Name]
+			if !ok {
+				t.Fatalf("function %s not found", test.Name)
+			}
+
+			got, err := fn.Call(test.Args)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/heimdalr/dag"
 	"github.com/rs/zerolog"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
 )
 
 var (
@@ -451,17 +454,49 @@ func (v *GraphVisitor) Visit(id string, vertex Vertex) {
 }
 
 func (g *Graph) loadAllBlocks(evaluator *Evaluator) ([]*Block, error) {
-	return g.loadBlocksForModule(evaluator)
+	var static *modules.StaticContext
+	if evaluator.blockBuilder.isOpenTofu {
+		// OpenTofu module sources can reference variables and locals, which
+		// haven't been evaluated when the blocks are loaded, so we evaluate
+		// them statically in the same way as the module loader.
+		static = modules.NewStaticContext(evaluator.inputVars, ExpFunctions(evaluator.module.RootPath, evaluator.logger))
+	}
+
+	return g.loadBlocksForModule(evaluator, static)
 }
 
-func (g *Graph) loadBlocksForModule(evaluator *Evaluator) ([]*Block, error) {
+func (g *Graph) loadBlocksForModule(evaluator *Evaluator, static *modules.StaticContext) ([]*Block, error) {
 	blocks := make([]*Block, len(evaluator.module.Blocks))
 	copy(blocks, evaluator.module.Blocks)
 
+	var staticCtx *hcl.EvalContext
+	if static != nil {
+		hclBlocks := make([]*hcl.Block, 0, len(evaluator.module.Blocks))
+		for _, block := range evaluator.module.Blocks {
+			hclBlocks = append(hclBlocks, block.HCLBlock)
+		}
+
+		staticCtx = static.EvalContext(hclBlocks)
+	}
+
 	for _, block := range evaluator.module.Blocks.OfType("module") {
-		modCall, err := evaluator.loadModule(block)
+		var moduleStatic *modules.StaticContext
+		var source string
+		var isStatic bool
+		if static != nil {
+			moduleStatic = static.ModuleCall(block.HCLBlock, staticCtx)
+			source, isStatic = static.ModuleSource(block.HCLBlock, staticCtx)
+		}
+
+		var modCall *ModuleCall
+		var err error
+		if isStatic {
+			modCall, err = evaluator.loadModuleFromSource(block, source)
+		} else {
+			modCall, err = evaluator.loadModule(block)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("could not load module %q", block.FullName())
+			return nil, fmt.Errorf("could not load module %q: %w", block.FullName(), err)
 		}
 
 		moduleEvaluator := NewEvaluator(
@@ -476,7 +511,7 @@ func (g *Graph) loadBlocksForModule(evaluator *Evaluator) ([]*Block, error) {
 			evaluator.isGraph,
 		)
 
-		modBlocks, err := g.loadBlocksForModule(moduleEvaluator)
+		modBlocks, err := g.loadBlocksForModule(moduleEvaluator, moduleStatic)
 		if err != nil {
 			return nil, fmt.Errorf("could not load blocks for module %q", block.FullName())
 		}
@@ -61,13 +61,30 @@ func (v *VertexProvider) Visit(mutex *sync.Mutex) error {
 			return fmt.Errorf("could not find block %q in module %q", v.ID(), moduleInstance.name)
 		}
 
-		// We don't care about the existing values, this is only needed by the legacy evaluator
-		val := e.evaluateProvider(blockInstance, map[string]cty.Value{})
+		instances := Blocks{blockInstance}
+		if e.blockBuilder.isOpenTofu {
+			mutex.Lock()
+			expanded := e.expandProviderForEach(blockInstance)
+			mutex.Unlock()
+
+			if expanded != nil {
+				instances = expanded
+			}
+		}
+
+		// We don't care about the existing values, this is only needed by the legacy
+		// evaluator or to merge the instances of a provider with a for_each.
+		values := map[string]cty.Value{}
+		for _, instance := range instances {
+			values[provider] = e.evaluateProvider(instance, values)
+		}
 
 		v.logger.Debug().Msgf("adding %s to the evaluation context", v.ID())
-		e.ctx.SetByDot(val, blockInstance.Label())
+		if val, ok := values[provider]; ok {
+			e.ctx.SetByDot(val, blockInstance.Label())
+		}
 
-		e.AddFilteredBlocks(blockInstance)
+		e.AddFilteredBlocks(instances...)
 	}
 
 	return nil
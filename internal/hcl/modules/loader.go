@@ -124,6 +124,12 @@ func NewModuleLoader(opts ModuleLoaderOptions) *ModuleLoader {
 	return m
 }
 
+// ClearParsedFiles clears the files that the loader has parsed, so that module
+// calls that have changed since are loaded again.
+func (m *ModuleLoader) ClearParsedFiles() {
+	m.hclParser.Reset()
+}
+
 // downloadDir returns the path to the directory where remote modules are downloaded relative to the current working directory
 func (m *ModuleLoader) downloadDir() string {
 	return filepath.Join(m.cachePath, downloadDir)
@@ -148,7 +154,18 @@ func (m *ModuleLoader) tfManifestFilePath(path string) string {
 // Load loads the modules from the given path.
 // For each module it checks if the module has already been downloaded, by checking if iut exists in the manifest
 // If not then it downloads the module from the registry or from a remote source and updates the module manifest with the latest metadata.
-func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
+func (m *ModuleLoader) Load(path string) (*Manifest, error) {
+	return m.load(path, nil)
+}
+
+// LoadWithStaticContext loads the modules from the given path in the same way
+// as Load, but evaluates module sources and versions that reference variables
+// and locals with the StaticContext, as supported by OpenTofu.
+func (m *ModuleLoader) LoadWithStaticContext(path string, static *StaticContext) (*Manifest, error) {
+	return m.load(path, static)
+}
+
+func (m *ModuleLoader) load(path string, static *StaticContext) (man *Manifest, err error) {
 	defer func() {
 		if man != nil {
 			man.cachePath = m.cachePath
@@ -197,7 +214,7 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 	}
 	m.cache.loadFromManifest(manifest)
 
-	metadatas, err := m.loadModules(path, "")
+	metadatas, err := m.loadModules(path, "", static)
 	if err != nil {
 		return nil, err
 	}
@@ -214,10 +231,27 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 	return manifest, nil
 }
 
-// loadModules recursively loads the modules from the given path.
-func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModule, error) {
+// LoadSource loads a single module source that is called from parentPath, e.g.
+// the source of a Terraform Stacks component, and returns the directory that
+// the module is in. Remote sources are downloaded in the same way as module calls.
+func (m *ModuleLoader) LoadSource(name string, source string, version string, parentPath string) (string, error) {
+	manifestModule, err := m.loadModule(&tfconfig.ModuleCall{
+		Name:    name,
+		Source:  source,
+		Version: version,
+	}, parentPath, "", nil)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(m.cachePath, manifestModule.Dir), nil
+}
+
+// loadModules recursively loads the modules from the given path. If static is
+// not nil it is used to evaluate the module sources and versions.
+func (m *ModuleLoader) loadModules(path string, prefix string, static *StaticContext) ([]*ManifestModule, error) {
 
-	module, err := m.loadModuleFromPath(path)
+	module, calls, err := m.loadModuleFromPath(path, static)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +272,12 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 	for i := 0; i < getProcessCount(); i++ {
 		errGroup.Go(func() error {
 			for moduleCall := range jobs {
-				metadata, err := m.loadModule(moduleCall, path, prefix)
+				var nestedStatic *StaticContext
+				if call, ok := calls[moduleCall.Name]; ok {
+					nestedStatic = call.static
+				}
+
+				metadata, err := m.loadModule(moduleCall, path, prefix, nestedStatic)
 				if err != nil {
 					return err
 				}
@@ -258,7 +297,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 				}
 
 				moduleDir := filepath.Join(m.cachePath, metadata.Dir)
-				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".")
+				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".", nestedStatic)
 				if err != nil {
 					return err
 				}
@@ -286,7 +325,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 // 2. Checks if the module is a local module.
 // 3. Checks if the module is a registry module and downloads it.
 // 4. Checks if the module is a remote module and downloads it.
-func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath string, prefix string) (*ManifestModule, error) {
+func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath string, prefix string, static *StaticContext) (*ManifestModule, error) {
 	key := prefix + moduleCall.Name
 	source := moduleCall.Source
 	version := moduleCall.Version
@@ -316,7 +355,7 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 		// Test if we can actually load the module. If not, then we should try re-loading it.
 		// This can happen if the directory the module was downloaded to has been deleted and moved
 		// so the existing manifest.json is out-of-date.
-		_, loadModErr := m.loadModuleFromPath(path.Join(m.cachePath, manifestModule.Dir))
+		_, _, loadModErr := m.loadModuleFromPath(path.Join(m.cachePath, manifestModule.Dir), static)
 		if loadModErr == nil {
 			return manifestModule, nil
 		}
@@ -699,14 +738,19 @@ func HasOpenTofuExtension(name string) bool {
 	return filepath.Ext(name) == ".tofu" || strings.HasSuffix(name, ".tofu.json")
 }
 
-func (m *ModuleLoader) loadModuleFromPath(fullPath string) (*tfconfig.Module, error) {
+// loadModuleFromPath loads the module at fullPath with tfconfig. If static is
+// not nil the module calls are also statically evaluated, and the evaluated
+// module calls are returned by name.
+func (m *ModuleLoader) loadModuleFromPath(fullPath string, static *StaticContext) (*tfconfig.Module, map[string]*moduleCallSource, error) {
 	mod := tfconfig.NewModule(fullPath)
 
 	fileInfos, err := os.ReadDir(fullPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	var paths []string
+	var files []*hcl.File
 	opentofuOverrides := make(map[string]struct{})
 
 	for _, info := range fileInfos {
@@ -754,20 +798,51 @@ func (m *ModuleLoader) loadModuleFromPath(fullPath string) (*tfconfig.Module, er
 		path := filepath.Join(fullPath, info.Name())
 		f, fileDiag := parseFunc(path)
 		if fileDiag != nil && fileDiag.HasErrors() {
-			return nil, fmt.Errorf("failed to parse file %s diag: %w", path, fileDiag)
+			return nil, nil, fmt.Errorf("failed to parse file %s diag: %w", path, fileDiag)
 		}
 
 		if f == nil {
 			continue
 		}
 
+		paths = append(paths, path)
+		files = append(files, f)
+	}
+
+	var calls map[string]*moduleCallSource
+	var ctx *hcl.EvalContext
+	if static != nil {
+		calls = make(map[string]*moduleCallSource)
+		ctx = static.EvalContext(staticBlocks(files))
+	}
+
+	for i, f := range files {
 		contentDiag := tfconfig.LoadModuleFromFile(f, mod)
+		if static != nil {
+			contentDiag = static.evaluateLoadedModuleCalls(f, calls, ctx, contentDiag)
+		}
+
 		if contentDiag != nil && contentDiag.HasErrors() {
-			return nil, fmt.Errorf("failed to load module from file %s diag: %w", path, contentDiag)
+			return nil, nil, fmt.Errorf("failed to load module from file %s diag: %w", paths[i], contentDiag)
+		}
+	}
+
+	for name, call := range calls {
+		mc, ok := mod.ModuleCalls[name]
+		if !ok {
+			continue
+		}
+
+		if call.source != "" {
+			mc.Source = call.source
+		}
+
+		if call.version != "" {
+			mc.Version = call.version
 		}
 	}
 
-	return mod, nil
+	return mod, calls, nil
 }
 
 func (m *ModuleLoader) loadRegistryModule(key string, source string, version string) (*ManifestModule, error) {
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/rs/zerolog"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/infracost/infracost/internal/config"
@@ -975,3 +976,63 @@ func TestSourceMapRegexModule(t *testing.T) {
 	assert.Equal(t, "git::https://github.com/org/foo//modules/virtual_private_cloud", manifestModule.Source)
 	assert.Equal(t, "ref=virtual_private_cloud_v1.7.0", manifestModule.RawQuery)
 }
+
+func TestLoadWithStaticContext(t *testing.T) {
+	ResetGlobalModuleCache()
+
+	path := t.TempDir()
+	files := map[string]string{
+		"main.tofu": `
+variable "env" {
+  default = "dev"
+}
+
+locals {
+  module_dir = "./modules/${var.env}"
+}
+
+module "app" {
+  source = local.module_dir
+  shared = "../shared"
+}
+`,
+		"modules/prod/main.tf": `
+variable "shared" {}
+
+module "shared" {
+  source = var.shared
+}
+`,
+		"modules/shared/main.tf": `
+variable "name" {
+  default = "shared"
+}
+`,
+	}
+	for name, contents := range files {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(path, name)), os.ModePerm)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(path, name), []byte(contents), os.ModePerm)
+		require.NoError(t, err)
+	}
+
+	moduleLoader := NewModuleLoader(ModuleLoaderOptions{
+		CachePath:         path,
+		HCLParser:         NewSharedHCLParser(),
+		CredentialsSource: &CredentialsSource{FetchToken: credentials.FindTerraformCloudToken},
+		Logger:            zerolog.New(io.Discard),
+		ModuleSync:        &sync2.KeyMutex{},
+	})
+
+	_, err := moduleLoader.Load(path)
+	assert.Error(t, err, "module sources with variables should only be loaded with a static context")
+
+	static := NewStaticContext(map[string]cty.Value{"env": cty.StringVal("prod")}, nil)
+	manifest, err := moduleLoader.LoadWithStaticContext(path, static)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Modules)
+
+	static = NewStaticContext(map[string]cty.Value{"env": cty.StringVal("missing")}, nil)
+	_, err = moduleLoader.LoadWithStaticContext(path, static)
+	assert.Error(t, err, "the module source should be evaluated with the input vars")
+}
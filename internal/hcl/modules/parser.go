@@ -19,6 +19,15 @@ func NewSharedHCLParser() *SharedHCLParser {
 	}
 }
 
+// Reset clears the files that have been parsed, so that files that have changed
+// since are parsed again.
+func (p *SharedHCLParser) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.parser = hclparse.NewParser()
+}
+
 func (p *SharedHCLParser) ParseHCLFile(filename string) (*hcl.File, hcl.Diagnostics) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -0,0 +1,267 @@
+package modules
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+var (
+	staticModuleSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "variable", LabelNames: []string{"name"}},
+			{Type: "locals"},
+			{Type: "module", LabelNames: []string{"name"}},
+		},
+	}
+	staticVariableSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "default"},
+		},
+	}
+	staticModuleCallSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source"},
+			{Name: "version"},
+		},
+	}
+
+	// moduleMetaArguments are the arguments of a module block that aren't input
+	// vars of the module.
+	moduleMetaArguments = map[string]struct{}{
+		"source":     {},
+		"version":    {},
+		"providers":  {},
+		"count":      {},
+		"for_each":   {},
+		"depends_on": {},
+	}
+)
+
+// StaticContext holds the values that can be used in OpenTofu's early
+// evaluation of module sources and versions and backend configuration. These
+// can reference variables and locals, but only if they are known without
+// evaluating any resources, data sources or module outputs.
+type StaticContext struct {
+	vars      map[string]cty.Value
+	functions map[string]function.Function
+}
+
+// NewStaticContext returns a StaticContext for a root module with the input
+// vars and the functions that can be called in static expressions.
+func NewStaticContext(vars map[string]cty.Value, functions map[string]function.Function) *StaticContext {
+	return &StaticContext{
+		vars:      vars,
+		functions: functions,
+	}
+}
+
+// EvalContext returns the context for evaluating static expressions in the
+// module with the top level blocks. Variables are set to the input vars of the
+// StaticContext or their default values, and any locals that only reference
+// these are evaluated.
+func (s *StaticContext) EvalContext(blocks []*hcl.Block) *hcl.EvalContext {
+	vars := make(map[string]cty.Value)
+	var locals []*hcl.Attribute
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "variable":
+			if len(block.Labels) == 0 {
+				continue
+			}
+
+			name := block.Labels[0]
+			if v, ok := s.vars[name]; ok {
+				vars[name] = v
+				continue
+			}
+
+			content, _, _ := block.Body.PartialContent(staticVariableSchema)
+			if attr, ok := content.Attributes["default"]; ok {
+				v, diags := attr.Expr.Value(nil)
+				if !diags.HasErrors() {
+					vars[name] = v
+				}
+			}
+		case "locals":
+			attrs, _ := block.Body.JustAttributes()
+			for _, attr := range attrs {
+				locals = append(locals, attr)
+			}
+		}
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(vars),
+			"local": cty.EmptyObjectVal,
+		},
+		Functions: s.functions,
+	}
+	ctx.Variables["local"] = evaluateStaticLocals(ctx, locals)
+
+	return ctx
+}
+
+// ModuleCall returns the StaticContext for the module called by the module
+// block. The input vars of the module are the arguments of the block that can
+// be statically evaluated with ctx.
+func (s *StaticContext) ModuleCall(block *hcl.Block, ctx *hcl.EvalContext) *StaticContext {
+	vars := make(map[string]cty.Value)
+
+	attrs, _ := block.Body.JustAttributes()
+	for name, attr := range attrs {
+		if _, ok := moduleMetaArguments[name]; ok {
+			continue
+		}
+
+		v, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() || !v.IsWhollyKnown() {
+			continue
+		}
+
+		vars[name] = v
+	}
+
+	return &StaticContext{
+		vars:      vars,
+		functions: s.functions,
+	}
+}
+
+// ModuleSource returns the source of the module called by the module block if
+// it can be statically evaluated with ctx.
+func (s *StaticContext) ModuleSource(block *hcl.Block, ctx *hcl.EvalContext) (string, bool) {
+	content, _, _ := block.Body.PartialContent(staticModuleCallSchema)
+	attr, ok := content.Attributes["source"]
+	if !ok {
+		return "", false
+	}
+
+	return staticString(attr.Expr, ctx)
+}
+
+// evaluateLoadedModuleCalls statically evaluates the source and version of the
+// module calls in the file, which tfconfig can't decode if they reference
+// variables or locals, and adds them to calls. It returns diags without the
+// errors for the expressions that were evaluated.
+func (s *StaticContext) evaluateLoadedModuleCalls(file *hcl.File, calls map[string]*moduleCallSource, ctx *hcl.EvalContext, diags hcl.Diagnostics) hcl.Diagnostics {
+	content, _, _ := file.Body.PartialContent(staticModuleSchema)
+
+	var evaluated []hcl.Range
+	for _, block := range content.Blocks.OfType("module") {
+		if len(block.Labels) == 0 {
+			continue
+		}
+
+		name := block.Labels[0]
+		call := &moduleCallSource{static: s.ModuleCall(block, ctx)}
+
+		callContent, _, _ := block.Body.PartialContent(staticModuleCallSchema)
+		if attr, ok := callContent.Attributes["source"]; ok {
+			if v, ok := staticString(attr.Expr, ctx); ok {
+				call.source = v
+				evaluated = append(evaluated, attr.Expr.Range())
+			}
+		}
+
+		if attr, ok := callContent.Attributes["version"]; ok {
+			if v, ok := staticString(attr.Expr, ctx); ok {
+				call.version = v
+				evaluated = append(evaluated, attr.Expr.Range())
+			}
+		}
+
+		calls[name] = call
+	}
+
+	var filtered hcl.Diagnostics
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError && diag.Subject != nil && overlapsAny(*diag.Subject, evaluated) {
+			continue
+		}
+
+		filtered = append(filtered, diag)
+	}
+
+	return filtered
+}
+
+// moduleCallSource is the statically evaluated source and version of a module
+// call along with the StaticContext of the called module.
+type moduleCallSource struct {
+	source  string
+	version string
+	static  *StaticContext
+}
+
+// staticBlocks returns the top level blocks of the files that are used to
+// build the static EvalContext of a module.
+func staticBlocks(files []*hcl.File) []*hcl.Block {
+	var blocks []*hcl.Block
+	for _, file := range files {
+		content, _, _ := file.Body.PartialContent(staticModuleSchema)
+		blocks = append(blocks, content.Blocks...)
+	}
+
+	return blocks
+}
+
+// evaluateStaticLocals evaluates the locals in ctx until no more locals can be
+// evaluated, so that locals can reference other locals in any order. Locals
+// that can't be evaluated to a known value are left out.
+func evaluateStaticLocals(ctx *hcl.EvalContext, attrs []*hcl.Attribute) cty.Value {
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Name < attrs[j].Name
+	})
+
+	locals := make(map[string]cty.Value, len(attrs))
+	for progress := true; progress; {
+		progress = false
+
+		for _, attr := range attrs {
+			if _, ok := locals[attr.Name]; ok {
+				continue
+			}
+
+			ctx.Variables["local"] = cty.ObjectVal(locals)
+			v, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() || !v.IsWhollyKnown() {
+				continue
+			}
+
+			locals[attr.Name] = v
+			progress = true
+		}
+	}
+
+	return cty.ObjectVal(locals)
+}
+
+func staticString(expr hcl.Expression, ctx *hcl.EvalContext) (string, bool) {
+	v, diags := expr.Value(ctx)
+	if diags.HasErrors() || v.IsNull() || !v.IsWhollyKnown() {
+		return "", false
+	}
+
+	v, err := convert.Convert(v, cty.String)
+	if err != nil {
+		return "", false
+	}
+
+	return v.AsString(), true
+}
+
+func overlapsAny(rng hcl.Range, ranges []hcl.Range) bool {
+	for _, r := range ranges {
+		if rng.Overlaps(r) {
+			return true
+		}
+	}
+
+	return false
+}
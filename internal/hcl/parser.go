@@ -263,6 +263,18 @@ func OptionWithBlockBuilder(blockBuilder BlockBuilder) Option {
 	}
 }
 
+// OptionWithDataSources sets the user supplied data sources that are used for
+// the values of the data blocks they match, instead of mocked values.
+func OptionWithDataSources(dataSources []config.DataSource) Option {
+	return func(p *Parser) {
+		if len(dataSources) == 0 {
+			return
+		}
+
+		p.blockBuilder.dataSources = newDataSourceFixtures(dataSources, p.logger)
+	}
+}
+
 // OptionWithTerraformWorkspace informs the Parser to use the provided name as the workspace for context evaluation.
 // The Parser exposes this workspace in the evaluation context under the variable named `terraform.workspace`.
 // This is commonly used by users to specify different capacity/configuration in their Terraform, e.g:
@@ -319,6 +331,7 @@ type Parser struct {
 	remoteVariableLoaders []RemoteVariableLoader
 	logger                zerolog.Logger
 	isGraph               bool
+	isOpenTofu            bool
 	hasChanges            bool
 	moduleSuffix          string
 	envMatcher            *EnvFileMatcher
@@ -337,6 +350,7 @@ func NewParser(projectRoot RootPath, envMatcher *EnvFileMatcher, moduleLoader *m
 		startingPath:        projectRoot.StartingPath,
 		detectedProjectPath: projectRoot.DetectedPath,
 		hasChanges:          projectRoot.HasChanges,
+		isOpenTofu:          projectRoot.IsOpenTofu,
 		moduleCalls:         projectRoot.ModuleCalls,
 		hclParser:           hclParser,
 		blockBuilder:        BlockBuilder{SetAttributes: []SetAttributesFunc{SetUUIDAttributes}, Logger: logger, HCLParser: hclParser},
@@ -349,6 +363,8 @@ func NewParser(projectRoot RootPath, envMatcher *EnvFileMatcher, moduleLoader *m
 		option(p)
 	}
 
+	p.blockBuilder.isOpenTofu = p.isOpenTofu
+
 	// if the project name is not set by the user, we will use the detected env name
 	// as the project name.
 	if p.workspaceName == "" && p.ProjectName() != p.EnvName() {
@@ -510,7 +526,13 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 	}
 
 	// load the modules. This downloads any remote modules to the local file system
-	modulesManifest, err := p.moduleLoader.Load(p.detectedProjectPath)
+	var modulesManifest *modules.Manifest
+	if p.isOpenTofu {
+		// OpenTofu allows module sources and versions to reference variables and locals.
+		modulesManifest, err = p.moduleLoader.LoadWithStaticContext(p.detectedProjectPath, p.staticContext(inputVars))
+	} else {
+		modulesManifest, err = p.moduleLoader.Load(p.detectedProjectPath)
+	}
 	if err != nil {
 		return m, fmt.Errorf("Error loading Terraform modules: %w", err)
 	}
@@ -580,6 +602,15 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 	return root, nil
 }
 
+// ClearParsedFiles clears the files that the parser and its module loader have
+// parsed, so that ParseDirectory reads any files that have changed since.
+func (p *Parser) ClearParsedFiles() {
+	p.hclParser.Reset()
+	if p.moduleLoader != nil {
+		p.moduleLoader.ClearParsedFiles()
+	}
+}
+
 // Path returns the full path that the parser runs within.
 func (p *Parser) Path() string {
 	return p.detectedProjectPath
@@ -671,6 +702,15 @@ func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Val
 	}
 
 	if p.remoteVariableLoaders != nil {
+		if p.isOpenTofu {
+			staticVars, err := p.loadStaticVars(filenames)
+			if err != nil {
+				return combinedVars, err
+			}
+
+			p.setStaticContext(blocks, staticVars)
+		}
+
 		for _, loader := range p.remoteVariableLoaders {
 			remoteVars, err := loader.Load(RemoteVarLoaderOptions{
 				Blocks:      blocks,
@@ -724,6 +764,49 @@ func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Val
 	return combinedVars, nil
 }
 
+// loadStaticVars returns the vars that are known before any remote variables
+// are loaded, i.e. from the environment, var files and input vars.
+func (p *Parser) loadStaticVars(filenames []string) (map[string]cty.Value, error) {
+	vars := make(map[string]cty.Value, len(p.tfEnvVars)+len(p.inputVars))
+	for k, v := range p.tfEnvVars {
+		vars[k] = v
+	}
+
+	for _, filename := range filenames {
+		err := p.loadAndCombineVars(filename, vars)
+		if err != nil {
+			return vars, err
+		}
+	}
+
+	for k, v := range p.inputVars {
+		vars[k] = v
+	}
+
+	return vars, nil
+}
+
+// setStaticContext sets the context of the terraform blocks so that their
+// backend and cloud configuration can reference variables and locals, as
+// supported by OpenTofu.
+func (p *Parser) setStaticContext(blocks Blocks, vars map[string]cty.Value) {
+	hclBlocks := make([]*hcl.Block, 0, len(blocks))
+	for _, block := range blocks {
+		hclBlocks = append(hclBlocks, block.HCLBlock)
+	}
+
+	ctx := NewContext(p.staticContext(vars).EvalContext(hclBlocks), nil, p.logger)
+	for _, block := range blocks.OfType("terraform") {
+		block.SetContext(ctx)
+	}
+}
+
+// staticContext returns the StaticContext used to evaluate the expressions that
+// OpenTofu evaluates before the rest of the config, e.g. module sources.
+func (p *Parser) staticContext(vars map[string]cty.Value) *modules.StaticContext {
+	return modules.NewStaticContext(vars, ExpFunctions(p.detectedProjectPath, p.logger))
+}
+
 func (p *Parser) loadAndCombineVars(filename string, combinedVars map[string]cty.Value) error {
 	vars, err := p.loadVarFile(filename)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/sync"
 )
 
@@ -1728,6 +1729,73 @@ data "aws_subnets" "example" {
 	)
 }
 
+func Test_DataSourceFixtures(t *testing.T) {
+	path := createTestFile("test.tf", `
+provider "aws" {
+	region = "us-east-1"
+}
+
+data "aws_ssm_parameter" "instance_type" {
+	name = "/web/instance_type"
+}
+
+data "aws_ami" "ubuntu" {
+	count       = 2
+	most_recent = true
+}
+
+data "aws_region" "current" {}
+
+data "aws_vpc" "main" {}
+
+resource "aws_instance" "web" {
+	ami           = data.aws_ami.ubuntu[1].id
+	instance_type = data.aws_ssm_parameter.instance_type.value
+	tags = {
+		Region = data.aws_region.current.name
+		Vpc    = data.aws_vpc.main.cidr_block
+	}
+}`)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(modules.ModuleLoaderOptions{
+		CachePath:         filepath.Dir(path),
+		HCLParser:         modules.NewSharedHCLParser(),
+		CredentialsSource: nil,
+		SourceMap:         config.TerraformSourceMap{},
+		Logger:            logger,
+		ModuleSync:        &sync.KeyMutex{},
+	})
+	parser := NewParser(
+		RootPath{DetectedPath: filepath.Dir(path)},
+		CreateEnvFileMatcher([]string{}, nil),
+		loader,
+		logger,
+		OptionWithDataSources([]config.DataSource{
+			{Address: "data.aws_ssm_parameter.instance_type", Values: map[string]interface{}{"value": "m5.large"}},
+			{Address: "data.aws_ami.ubuntu", Values: map[string]interface{}{"id": "ami-0123456789"}},
+			{Type: "aws_ami", Values: map[string]interface{}{"id": "ami-type"}},
+			{Type: "aws_re*", Values: map[string]interface{}{"name": "eu-west-2"}},
+		}),
+	)
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	blocks := module.Blocks
+
+	ssm := blocks.Matching(BlockMatcher{Label: "aws_ssm_parameter.instance_type", Type: "data"}).Values().AsValueMap()
+	assert.Equal(t, "/web/instance_type", ssm["name"].AsString())
+	assert.Equal(t, "m5.large", ssm["value"].AsString())
+
+	instance := blocks.Matching(BlockMatcher{Label: "aws_instance.web", Type: "resource"})
+	assert.Equal(t, "ami-0123456789", instance.GetAttribute("ami").Value().AsString())
+	assert.Equal(t, "m5.large", instance.GetAttribute("instance_type").Value().AsString())
+
+	tags := instance.GetAttribute("tags").Value().AsValueMap()
+	assert.Equal(t, "eu-west-2", tags["Region"].AsString())
+	assert.Contains(t, tags["Vpc"].AsString(), "infracost-mock")
+}
+
 func Test_LocalsMergeWithDataTags(t *testing.T) {
 	path := createTestFile("test.tf", `
 provider "aws" {
@@ -2601,3 +2669,237 @@ func Test_uniqueDependencyPaths(t *testing.T) {
 		})
 	}
 }
+
+func Test_AddressChanges(t *testing.T) {
+	path := createTestFileWithModule(`
+module "web" {
+	source = "../module"
+}
+
+resource "aws_instance" "new" {
+	instance_type = "t3.micro"
+}
+
+moved {
+	from = aws_instance.old
+	to   = aws_instance.new
+}
+
+moved {
+	from = module.app
+	to   = module.web
+}
+
+import {
+	to = aws_instance.new
+	id = "i-0123456789"
+}
+
+import {
+	for_each = toset(["a", "b"])
+	to       = aws_instance.each[each.key]
+	id       = each.value
+}
+
+removed {
+	from = aws_instance.kept
+
+	lifecycle {
+		destroy = false
+	}
+}
+
+removed {
+	from = aws_instance.destroyed
+
+	lifecycle {
+		destroy = true
+	}
+}
+`,
+		`
+resource "aws_instance" "this" {
+	instance_type = "t3.micro"
+}
+
+moved {
+	from = aws_instance.main["a"]
+	to   = aws_instance.this
+}
+
+import {
+	to = aws_instance.this
+	id = "i-ignored"
+}
+
+removed {
+	from = module.child
+
+	lifecycle {
+		destroy = false
+	}
+}
+`,
+		"module",
+	)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(modules.ModuleLoaderOptions{
+		CachePath:         filepath.Dir(path),
+		HCLParser:         modules.NewSharedHCLParser(),
+		CredentialsSource: nil,
+		SourceMap:         config.TerraformSourceMap{},
+		Logger:            logger,
+		ModuleSync:        &sync.KeyMutex{},
+	})
+	parser := NewParser(
+		RootPath{DetectedPath: path},
+		CreateEnvFileMatcher([]string{}, nil),
+		loader,
+		logger,
+	)
+
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	changes := module.AddressChanges()
+	assert.Equal(t, []schema.MovedAddress{
+		{From: "aws_instance.old", To: "aws_instance.new"},
+		{From: "module.app", To: "module.web"},
+		{From: `module.web.aws_instance.main["a"]`, To: "module.web.aws_instance.this"},
+	}, changes.Moved)
+	assert.Equal(t, []string{"aws_instance.new"}, changes.Imported)
+	assert.Equal(t, []string{"aws_instance.kept", "module.web.module.child"}, changes.Removed)
+}
+
+func Test_OpenTofu(t *testing.T) {
+	variations := []struct {
+		name       string
+		parserOpts []Option
+	}{
+		{
+			name:       "default",
+			parserOpts: nil,
+		},
+		{
+			name:       "with graph evaluator",
+			parserOpts: []Option{OptionGraphEvaluator()},
+		},
+	}
+
+	for _, variation := range variations {
+		t.Run(variation.name, func(t *testing.T) {
+			testOpenTofu(t, variation.parserOpts...)
+		})
+	}
+}
+
+func testOpenTofu(t *testing.T, opts ...Option) {
+	path := createTestFileWithModule(`
+variable "module_dir" {
+	default = "../module"
+}
+
+variable "regions" {
+	type    = set(string)
+	default = ["us-east-1", "eu-west-1"]
+}
+
+locals {
+	module_source = var.module_dir
+}
+
+provider "aws" {
+	region = "us-west-2"
+}
+
+provider "aws" {
+	alias    = "by_region"
+	for_each = var.regions
+	region   = each.value
+}
+
+module "web" {
+	source   = local.module_source
+	for_each = var.regions
+
+	providers = {
+		aws = aws.by_region[each.key]
+	}
+
+	account_id = provider::aws::arn_parse("arn:aws:iam::123456789012:role/web").account_id
+}
+
+resource "aws_instance" "east" {
+	provider      = aws.by_region["us-east-1"]
+	instance_type = "t3.micro"
+}
+
+resource "aws_instance" "default" {
+	instance_type = "t3.micro"
+}
+`,
+		`
+variable "account_id" {}
+
+resource "aws_instance" "this" {
+	instance_type = "t3.micro"
+	tags = {
+		Account = var.account_id
+	}
+}
+`,
+		"module",
+	)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(modules.ModuleLoaderOptions{
+		CachePath:         filepath.Dir(path),
+		HCLParser:         modules.NewSharedHCLParser(),
+		CredentialsSource: nil,
+		SourceMap:         config.TerraformSourceMap{},
+		Logger:            logger,
+		ModuleSync:        &sync.KeyMutex{},
+	})
+	parser := NewParser(
+		RootPath{DetectedPath: path, IsOpenTofu: true},
+		CreateEnvFileMatcher([]string{}, nil),
+		loader,
+		logger,
+		opts...,
+	)
+
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	configKeys := map[string]string{}
+	for _, block := range module.Blocks.OfType("provider") {
+		configKeys[block.Values().GetAttr("config_key").AsString()] = block.GetAttribute("region").AsString()
+	}
+	assert.Equal(t, map[string]string{
+		"aws":                        "us-west-2",
+		`aws.by_region["us-east-1"]`: "us-east-1",
+		`aws.by_region["eu-west-1"]`: "eu-west-1",
+	}, configKeys)
+
+	resources := map[string]string{}
+	tags := map[string]string{}
+	for _, m := range append([]*Module{module}, module.Modules...) {
+		for _, block := range m.Blocks.OfType("resource") {
+			resources[block.FullName()] = block.ProviderConfigKey()
+			if attr := block.GetAttribute("tags"); attr != nil {
+				tags[block.FullName()] = attr.Value().GetAttr("Account").AsString()
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"aws_instance.default":                      "aws",
+		"aws_instance.east":                         `aws.by_region["us-east-1"]`,
+		`module.web["us-east-1"].aws_instance.this`: `aws.by_region["us-east-1"]`,
+		`module.web["eu-west-1"].aws_instance.this`: `aws.by_region["eu-west-1"]`,
+	}, resources)
+	assert.Equal(t, map[string]string{
+		`module.web["us-east-1"].aws_instance.this`: "123456789012",
+		`module.web["eu-west-1"].aws_instance.this`: "123456789012",
+	}, tags)
+}
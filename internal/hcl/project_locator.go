@@ -273,6 +273,8 @@ func (e *EnvFileMatcher) hasEnvSuffix(clean string, name string) bool {
 
 type discoveredProject struct {
 	isTerragrunt bool
+	isStack      bool
+	isOpenTofu   bool
 
 	hasProviderBlock bool
 	hasBackendBlock  bool
@@ -282,7 +284,7 @@ type discoveredProject struct {
 }
 
 func (p discoveredProject) hasRootModuleBlocks() bool {
-	return p.hasBackendBlock || p.hasProviderBlock
+	return p.hasBackendBlock || p.hasProviderBlock || p.isStack
 }
 
 // ProjectLocator finds Terraform projects for given paths.
@@ -1134,7 +1136,13 @@ type RootPath struct {
 	HasChildVarFiles         bool
 	IsTerragrunt             bool
 	IsParentTerragruntConfig bool
-	ModuleCalls              []string
+	// IsTerraformStack is true if the project is a Terraform Stacks configuration,
+	// see LoadStack.
+	IsTerraformStack bool
+	// IsOpenTofu is true if the project has .tofu files, in which case it is
+	// parsed with the OpenTofu specific language features enabled.
+	IsOpenTofu  bool
+	ModuleCalls []string
 }
 
 func (r *RootPath) RelPath() string {
@@ -1315,6 +1323,8 @@ func (p *ProjectLocator) FindRootModules(startingPath string) ([]RootPath, strin
 				StartingPath:      startingPath,
 				DetectedPath:      detectedPath,
 				IsTerragrunt:      p.wdContainsTerragrunt,
+				IsTerraformStack:  HasStackFiles(detectedPath),
+				IsOpenTofu:        HasOpenTofuFiles(detectedPath),
 				TerraformVarFiles: p.discoveredVarFiles[startingPath],
 			},
 		}, ""
@@ -1345,6 +1355,8 @@ func (p *ProjectLocator) FindRootModules(startingPath string) ([]RootPath, strin
 				TerraformVarFiles: p.discoveredVarFiles[dir.path],
 				Matcher:           p.envMatcher,
 				IsTerragrunt:      dir.isTerragrunt,
+				IsTerraformStack:  dir.isStack,
+				IsOpenTofu:        dir.isOpenTofu,
 			})
 			projectMap[dir.path] = true
 		}
@@ -1361,6 +1373,8 @@ func (p *ProjectLocator) FindRootModules(startingPath string) ([]RootPath, strin
 					TerraformVarFiles: p.discoveredVarFiles[dir.path],
 					Matcher:           p.envMatcher,
 					IsTerragrunt:      dir.isTerragrunt,
+					IsTerraformStack:  dir.isStack,
+					IsOpenTofu:        dir.isOpenTofu,
 				})
 				projectMap[dir.path] = true
 			}
@@ -1585,6 +1599,8 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int, maxSearchDepth in
 		return
 	}
 
+	hasStackFiles := false
+	hasOpenTofuFiles := false
 	for _, info := range fileInfos {
 		if info.IsDir() {
 			continue
@@ -1593,6 +1609,14 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int, maxSearchDepth in
 		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
 		name := info.Name()
 
+		if strings.HasSuffix(name, StackFileExtension) {
+			hasStackFiles = true
+		}
+
+		if modules.HasOpenTofuExtension(name) {
+			hasOpenTofuFiles = true
+		}
+
 		if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tofu") {
 			parseFunc = hclParser.ParseHCLFile
 		}
@@ -1636,8 +1660,9 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int, maxSearchDepth in
 		}
 	}
 
-	files := hclParser.Files()
-	if len(files) > 0 {
+	if hasStackFiles {
+		p.discoverStack(fullPath, level)
+	} else if files := hclParser.Files(); len(files) > 0 {
 		blockInfo := p.shallowDecodeTerraformBlocks(fullPath, files)
 
 		p.discoveredProjects = append(p.discoveredProjects, discoveredProject{
@@ -1645,6 +1670,7 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int, maxSearchDepth in
 			files:            files,
 			hasProviderBlock: blockInfo.hasProviderBlock,
 			hasBackendBlock:  blockInfo.hasTerraformBackendBlock,
+			isOpenTofu:       hasOpenTofuFiles,
 			depth:            level,
 		})
 	}
@@ -1663,6 +1689,50 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int, maxSearchDepth in
 	}
 }
 
+// discoverStack adds the Terraform Stacks configuration at fullPath as a
+// discovered project. The local sources of its components are marked as
+// modules so that they aren't detected as projects themselves.
+func (p *ProjectLocator) discoverStack(fullPath string, level int) {
+	p.discoveredProjects = append(p.discoveredProjects, discoveredProject{
+		path:    fullPath,
+		isStack: true,
+		depth:   level,
+	})
+
+	stack, err := LoadStack(fullPath, p.logger)
+	if err != nil {
+		p.logger.Debug().Err(err).Msgf("could not load stack components for %s", fullPath)
+		return
+	}
+
+	for _, source := range stack.ComponentSources() {
+		if !modules.IsLocalModule(source) {
+			continue
+		}
+
+		mp := filepath.Join(fullPath, source)
+		p.modules[mp] = struct{}{}
+		p.moduleCalls[fullPath] = append(p.moduleCalls[fullPath], mp)
+	}
+}
+
+// HasOpenTofuFiles returns true if the directory at dir has any OpenTofu
+// specific .tofu or .tofu.json files.
+func HasOpenTofuFiles(dir string) bool {
+	fileInfos, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, info := range fileInfos {
+		if !info.IsDir() && modules.HasOpenTofuExtension(info.Name()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *ProjectLocator) isTerraformVarFile(name string, fullPath string) bool {
 	if hasDefaultVarFileExtension(name) {
 		return true
@@ -0,0 +1,598 @@
+package hcl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/rs/zerolog"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+const (
+	// StackFileExtension is the extension of Terraform Stacks configuration
+	// files, which define the components of a stack.
+	StackFileExtension = ".tfstack.hcl"
+	// DeploymentFileExtension is the extension of Terraform Stacks deployment
+	// files, which define the deployments of a stack and their inputs.
+	DeploymentFileExtension = ".tfdeploy.hcl"
+)
+
+var (
+	stackSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       "component",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "variable",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "locals",
+			},
+			{
+				Type:       "deployment",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type:       "provider",
+				LabelNames: []string{"type", "name"},
+			},
+		},
+	}
+	stackComponentSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source"},
+			{Name: "version"},
+			{Name: "inputs"},
+			{Name: "for_each"},
+			{Name: "providers"},
+		},
+	}
+	stackProviderSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "for_each"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "config"},
+		},
+	}
+	stackProviderConfigSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "region"},
+		},
+	}
+	stackVariableSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "default"},
+		},
+	}
+	stackDeploymentSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "inputs"},
+		},
+	}
+)
+
+// IsStackFile returns true if the file is a Terraform Stacks configuration or
+// deployment file.
+func IsStackFile(name string) bool {
+	return strings.HasSuffix(name, StackFileExtension) || strings.HasSuffix(name, DeploymentFileExtension)
+}
+
+// HasStackFiles returns true if the directory contains Terraform Stacks
+// configuration files.
+func HasStackFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), StackFileExtension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stack is a Terraform Stacks configuration, made up of the components in the
+// .tfstack.hcl files and the deployments in the .tfdeploy.hcl files of a
+// directory.
+type Stack struct {
+	Path string
+
+	components       []stackComponent
+	deployments      []stackDeployment
+	providers        []stackProvider
+	variables        map[string]cty.Value
+	stackLocals      []*hcl.Attribute
+	deploymentLocals []*hcl.Attribute
+	functions        map[string]function.Function
+	logger           zerolog.Logger
+}
+
+type stackComponent struct {
+	name      string
+	source    string
+	version   string
+	forEach   hcl.Expression
+	inputs    hcl.Expression
+	providers hcl.Expression
+}
+
+// stackProvider is a provider block of a stack, e.g.
+//
+//	provider "aws" "this" {
+//	  for_each = var.regions
+//	  config {
+//	    region = each.value
+//	  }
+//	}
+type stackProvider struct {
+	providerType string
+	name         string
+	forEach      hcl.Expression
+	config       hcl.Body
+}
+
+type stackDeployment struct {
+	name   string
+	inputs hcl.Expression
+}
+
+// StackComponentInstance is a component of a stack evaluated for a deployment.
+type StackComponentInstance struct {
+	Deployment string
+	// Component is the name of the component, including the instance key for
+	// components with for_each, e.g. vpc["us-east-1"].
+	Component string
+	// Source is the module source of the component, local sources are relative
+	// to the stack path.
+	Source  string
+	Version string
+	// Inputs are the input vars of the component's module. Inputs that can't be
+	// evaluated, e.g. because they reference the outputs of another component,
+	// are left out so they are mocked when the module is parsed.
+	Inputs cty.Value
+	// ProviderRegions are the regions of the providers passed to the component,
+	// keyed by the name of the provider in the component's module, e.g. aws.
+	ProviderRegions map[string]string
+}
+
+// LoadStack parses the Terraform Stacks files in the directory at path.
+func LoadStack(path string, logger zerolog.Logger) (*Stack, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read stack directory %s: %w", path, err)
+	}
+
+	s := &Stack{
+		Path:      path,
+		variables: map[string]cty.Value{},
+		functions: ExpFunctions(path, logger),
+		logger:    logger,
+	}
+
+	parser := hclparse.NewParser()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !IsStackFile(name) {
+			continue
+		}
+
+		file, diags := parser.ParseHCLFile(filepath.Join(path, name))
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("could not parse stack file %s: %w", name, diags)
+		}
+
+		content, _, diags := file.Body.PartialContent(stackSchema)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("could not decode stack file %s: %w", name, diags)
+		}
+
+		isDeploymentFile := strings.HasSuffix(name, DeploymentFileExtension)
+		for _, block := range content.Blocks {
+			err := s.addBlock(block, isDeploymentFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode %s block in stack file %s: %w", block.Type, name, err)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Stack) addBlock(block *hcl.Block, isDeploymentFile bool) error {
+	switch block.Type {
+	case "component":
+		content, _, diags := block.Body.PartialContent(stackComponentSchema)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		c := stackComponent{name: block.Labels[0]}
+		if attr, ok := content.Attributes["source"]; ok {
+			c.source = staticString(attr)
+		}
+		if attr, ok := content.Attributes["version"]; ok {
+			c.version = staticString(attr)
+		}
+		if attr, ok := content.Attributes["inputs"]; ok {
+			c.inputs = attr.Expr
+		}
+		if attr, ok := content.Attributes["for_each"]; ok {
+			c.forEach = attr.Expr
+		}
+		if attr, ok := content.Attributes["providers"]; ok {
+			c.providers = attr.Expr
+		}
+
+		s.components = append(s.components, c)
+	case "variable":
+		content, _, diags := block.Body.PartialContent(stackVariableSchema)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		s.variables[block.Labels[0]] = cty.DynamicVal
+		if attr, ok := content.Attributes["default"]; ok {
+			v, diags := attr.Expr.Value(s.evalContext(nil))
+			if !diags.HasErrors() {
+				s.variables[block.Labels[0]] = v
+			}
+		}
+	case "locals":
+		attrs, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			return diags
+		}
+
+		for _, attr := range attrs {
+			if isDeploymentFile {
+				s.deploymentLocals = append(s.deploymentLocals, attr)
+			} else {
+				s.stackLocals = append(s.stackLocals, attr)
+			}
+		}
+	case "deployment":
+		content, _, diags := block.Body.PartialContent(stackDeploymentSchema)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		d := stackDeployment{name: block.Labels[0]}
+		if attr, ok := content.Attributes["inputs"]; ok {
+			d.inputs = attr.Expr
+		}
+
+		s.deployments = append(s.deployments, d)
+	case "provider":
+		content, _, diags := block.Body.PartialContent(stackProviderSchema)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		pr := stackProvider{providerType: block.Labels[0], name: block.Labels[1]}
+		if attr, ok := content.Attributes["for_each"]; ok {
+			pr.forEach = attr.Expr
+		}
+		for _, b := range content.Blocks {
+			pr.config = b.Body
+		}
+
+		s.providers = append(s.providers, pr)
+	}
+
+	return nil
+}
+
+// ComponentSources returns the module sources of the components in the stack.
+func (s *Stack) ComponentSources() []string {
+	sources := make([]string, 0, len(s.components))
+	for _, c := range s.components {
+		if c.source != "" {
+			sources = append(sources, c.source)
+		}
+	}
+
+	return sources
+}
+
+// Instances returns the components of the stack evaluated for each of its
+// deployments. If the stack has no deployments the components are evaluated
+// once with the default values of the stack variables.
+func (s *Stack) Instances() []StackComponentInstance {
+	deployments := s.deployments
+	if len(deployments) == 0 {
+		deployments = []stackDeployment{{}}
+	}
+
+	deploymentLocals := s.evaluateLocals(s.deploymentLocals, map[string]cty.Value{})
+
+	var instances []StackComponentInstance
+	for _, d := range deployments {
+		vars := make(map[string]cty.Value, len(s.variables))
+		for k, v := range s.variables {
+			vars[k] = v
+		}
+
+		if d.inputs != nil {
+			for k, v := range evaluateObject(d.inputs, s.evalContext(map[string]cty.Value{"local": deploymentLocals})) {
+				vars[k] = v
+			}
+		}
+
+		ctxVars := map[string]cty.Value{"var": cty.ObjectVal(vars)}
+		ctxVars["local"] = s.evaluateLocals(s.stackLocals, ctxVars)
+		ctxVars["provider"] = s.evaluateProviders(ctxVars)
+
+		for _, c := range s.components {
+			instances = append(instances, s.componentInstances(d.name, c, ctxVars)...)
+		}
+	}
+
+	return instances
+}
+
+func (s *Stack) componentInstances(deployment string, c stackComponent, vars map[string]cty.Value) []StackComponentInstance {
+	if c.forEach == nil {
+		return []StackComponentInstance{s.componentInstance(deployment, c.name, c, vars)}
+	}
+
+	each, ok := s.expandForEach(c.forEach, vars)
+	if !ok {
+		s.logger.Debug().Msgf("skipping stack component %s in deployment %s as its for_each could not be evaluated", c.name, deployment)
+		return nil
+	}
+
+	instances := make([]StackComponentInstance, 0, len(each))
+	for _, e := range each {
+		instances = append(instances, s.componentInstance(deployment, fmt.Sprintf("%s[%q]", c.name, e.key), c, e.vars))
+	}
+
+	return instances
+}
+
+// forEachInstance is an instance of a block with for_each, with the vars
+// used to evaluate it, including each.key and each.value.
+type forEachInstance struct {
+	key  string
+	vars map[string]cty.Value
+}
+
+// expandForEach evaluates the for_each expression and returns an instance for
+// each of its elements. It returns false if the for_each can't be evaluated.
+func (s *Stack) expandForEach(expr hcl.Expression, vars map[string]cty.Value) ([]forEachInstance, bool) {
+	forEach, diags := expr.Value(s.evalContext(vars))
+	if diags.HasErrors() || !forEach.IsWhollyKnown() || forEach.IsNull() || !forEach.CanIterateElements() {
+		return nil, false
+	}
+
+	// deployment inputs aren't converted to the type of the stack variable, so a
+	// set(string) input is given as a tuple and is treated like a set here.
+	ty := forEach.Type()
+	isSet := ty.IsSetType() || ty.IsListType() || ty.IsTupleType()
+
+	var instances []forEachInstance
+	it := forEach.ElementIterator()
+	for it.Next() {
+		k, v := it.Element()
+		if isSet {
+			k = v
+		}
+
+		key, err := convert.Convert(k, cty.String)
+		if err != nil || key.IsNull() {
+			continue
+		}
+
+		eachVars := make(map[string]cty.Value, len(vars)+1)
+		for name, val := range vars {
+			eachVars[name] = val
+		}
+		eachVars["each"] = cty.ObjectVal(map[string]cty.Value{"key": key, "value": v})
+
+		instances = append(instances, forEachInstance{key: key.AsString(), vars: eachVars})
+	}
+
+	return instances, true
+}
+
+func (s *Stack) componentInstance(deployment string, name string, c stackComponent, vars map[string]cty.Value) StackComponentInstance {
+	inputs := map[string]cty.Value{}
+	if c.inputs != nil {
+		inputs = evaluateObject(c.inputs, s.evalContext(vars))
+	}
+
+	providerRegions := map[string]string{}
+	if c.providers != nil {
+		for k, v := range evaluateObject(c.providers, s.evalContext(vars)) {
+			if region := providerConfigRegion(v); region != "" {
+				providerRegions[k] = region
+			}
+		}
+	}
+
+	return StackComponentInstance{
+		Deployment:      deployment,
+		Component:       name,
+		Source:          c.source,
+		Version:         c.version,
+		Inputs:          cty.ObjectVal(inputs),
+		ProviderRegions: providerRegions,
+	}
+}
+
+// evaluateProviders returns the configs of the stack providers as the object
+// that is referenced as provider.<type>.<name> by components. Providers with
+// for_each are an object of configs keyed by the for_each keys. Only the
+// region is read from the provider config, as that is all that's needed to
+// price the resources of a component.
+func (s *Stack) evaluateProviders(vars map[string]cty.Value) cty.Value {
+	byType := map[string]map[string]cty.Value{}
+
+	for _, pr := range s.providers {
+		if byType[pr.providerType] == nil {
+			byType[pr.providerType] = map[string]cty.Value{}
+		}
+
+		if pr.forEach == nil {
+			byType[pr.providerType][pr.name] = s.providerConfig(pr, vars)
+			continue
+		}
+
+		each, ok := s.expandForEach(pr.forEach, vars)
+		if !ok {
+			s.logger.Debug().Msgf("skipping stack provider %s.%s as its for_each could not be evaluated", pr.providerType, pr.name)
+			continue
+		}
+
+		configs := make(map[string]cty.Value, len(each))
+		for _, e := range each {
+			configs[e.key] = s.providerConfig(pr, e.vars)
+		}
+
+		byType[pr.providerType][pr.name] = cty.ObjectVal(configs)
+	}
+
+	providers := make(map[string]cty.Value, len(byType))
+	for providerType, names := range byType {
+		providers[providerType] = cty.ObjectVal(names)
+	}
+
+	return cty.ObjectVal(providers)
+}
+
+func (s *Stack) providerConfig(pr stackProvider, vars map[string]cty.Value) cty.Value {
+	config := map[string]cty.Value{}
+	if pr.config == nil {
+		return cty.ObjectVal(config)
+	}
+
+	content, _, diags := pr.config.PartialContent(stackProviderConfigSchema)
+	if diags.HasErrors() {
+		return cty.ObjectVal(config)
+	}
+
+	for name, attr := range content.Attributes {
+		v, diags := attr.Expr.Value(s.evalContext(vars))
+		if diags.HasErrors() || !v.IsWhollyKnown() {
+			continue
+		}
+
+		config[name] = v
+	}
+
+	return cty.ObjectVal(config)
+}
+
+// providerConfigRegion returns the region of an evaluated provider config, or
+// an empty string if it has none.
+func providerConfigRegion(config cty.Value) string {
+	if !config.IsWhollyKnown() || config.IsNull() || !config.Type().IsObjectType() || !config.Type().HasAttribute("region") {
+		return ""
+	}
+
+	region := config.GetAttr("region")
+	if region.IsNull() || region.Type() != cty.String {
+		return ""
+	}
+
+	return region.AsString()
+}
+
+// evaluateLocals evaluates the locals, repeating until no more locals can be
+// evaluated so that locals can reference each other in any order.
+func (s *Stack) evaluateLocals(attrs []*hcl.Attribute, vars map[string]cty.Value) cty.Value {
+	locals := map[string]cty.Value{}
+
+	for progress := true; progress; {
+		progress = false
+
+		ctxVars := make(map[string]cty.Value, len(vars)+1)
+		for k, v := range vars {
+			ctxVars[k] = v
+		}
+		ctxVars["local"] = cty.ObjectVal(locals)
+
+		for _, attr := range attrs {
+			if _, ok := locals[attr.Name]; ok {
+				continue
+			}
+
+			v, diags := attr.Expr.Value(s.evalContext(ctxVars))
+			if diags.HasErrors() || !v.IsWhollyKnown() {
+				continue
+			}
+
+			locals[attr.Name] = v
+			progress = true
+		}
+	}
+
+	return cty.ObjectVal(locals)
+}
+
+func (s *Stack) evalContext(vars map[string]cty.Value) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Variables: vars,
+		Functions: s.functions,
+	}
+}
+
+// evaluateObject evaluates an object expression, e.g. the inputs of a
+// component. Each attribute of an object literal is evaluated separately so
+// that attributes that can't be evaluated don't stop the others being set.
+func evaluateObject(expr hcl.Expression, ctx *hcl.EvalContext) map[string]cty.Value {
+	values := map[string]cty.Value{}
+
+	if obj, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+		for _, item := range obj.Items {
+			k, diags := item.KeyExpr.Value(ctx)
+			if diags.HasErrors() || !k.IsKnown() || k.IsNull() || k.Type() != cty.String {
+				continue
+			}
+
+			v, diags := item.ValueExpr.Value(ctx)
+			if diags.HasErrors() || !v.IsWhollyKnown() {
+				continue
+			}
+
+			values[k.AsString()] = v
+		}
+
+		return values
+	}
+
+	v, diags := expr.Value(ctx)
+	if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() || !v.CanIterateElements() || v.Type().IsListType() || v.Type().IsSetType() || v.Type().IsTupleType() {
+		return values
+	}
+
+	for k, val := range v.AsValueMap() {
+		values[k] = val
+	}
+
+	return values
+}
+
+// staticString returns the value of an attribute that must be a literal
+// string, e.g. the source of a component.
+func staticString(attr *hcl.Attribute) string {
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return ""
+	}
+
+	return v.AsString()
+}
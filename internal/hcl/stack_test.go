@@ -0,0 +1,190 @@
+package hcl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStack(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"variables.tfstack.hcl": `
+variable "region" {
+  type = string
+}
+
+variable "instance_type" {
+  type    = string
+  default = "t3.micro"
+}
+
+variable "db_names" {
+  type    = set(string)
+  default = ["orders"]
+}
+`,
+		"components.tfstack.hcl": `
+locals {
+  name_prefix = "app-${var.region}"
+}
+
+component "web" {
+  source = "./modules/web"
+
+  inputs = {
+    instance_type = var.instance_type
+    name          = local.name_prefix
+    subnet_id     = component.network.subnet_id
+  }
+}
+
+component "db" {
+  for_each = var.db_names
+  source   = "./modules/db"
+
+  inputs = {
+    name = each.value
+  }
+}
+`,
+		"deployments.tfdeploy.hcl": `
+identity_token "aws" {
+  audience = ["aws.workload.identity"]
+}
+
+deployment "development" {
+  inputs = {
+    region   = "us-east-1"
+    role_arn = identity_token.aws.jwt
+  }
+}
+
+deployment "production" {
+  inputs = {
+    region        = "eu-west-1"
+    instance_type = "m5.4xlarge"
+    db_names      = ["orders", "users"]
+  }
+}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	assert.True(t, HasStackFiles(dir))
+
+	stack, err := LoadStack(dir, zerolog.Nop())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"./modules/web", "./modules/db"}, stack.ComponentSources())
+
+	type instance struct {
+		deployment string
+		component  string
+		inputs     map[string]string
+	}
+	var actual []instance
+	for _, i := range stack.Instances() {
+		inputs := map[string]string{}
+		for k, v := range i.Inputs.AsValueMap() {
+			inputs[k] = v.AsString()
+		}
+		actual = append(actual, instance{deployment: i.Deployment, component: i.Component, inputs: inputs})
+	}
+
+	assert.ElementsMatch(t, []instance{
+		{deployment: "development", component: "web", inputs: map[string]string{"instance_type": "t3.micro", "name": "app-us-east-1"}},
+		{deployment: "development", component: `db["orders"]`, inputs: map[string]string{"name": "orders"}},
+		{deployment: "production", component: "web", inputs: map[string]string{"instance_type": "m5.4xlarge", "name": "app-eu-west-1"}},
+		{deployment: "production", component: `db["orders"]`, inputs: map[string]string{"name": "orders"}},
+		{deployment: "production", component: `db["users"]`, inputs: map[string]string{"name": "users"}},
+	}, actual)
+}
+
+func TestLoadStackProviders(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"components.tfstack.hcl": `
+variable "region" {
+  type = string
+}
+
+variable "replica_regions" {
+  type    = set(string)
+  default = []
+}
+
+provider "aws" "this" {
+  config {
+    region = var.region
+
+    assume_role_with_web_identity {
+      role_arn = "arn:aws:iam::123456789012:role/stacks"
+    }
+  }
+}
+
+provider "aws" "replicas" {
+  for_each = var.replica_regions
+
+  config {
+    region = each.value
+  }
+}
+
+component "web" {
+  source = "./modules/web"
+
+  providers = {
+    aws = provider.aws.this
+  }
+}
+
+component "replica" {
+  for_each = var.replica_regions
+  source   = "./modules/replica"
+
+  providers = {
+    aws = provider.aws.replicas[each.value]
+  }
+}
+`,
+		"deployments.tfdeploy.hcl": `
+deployment "development" {
+  inputs = {
+    region = "us-east-1"
+  }
+}
+
+deployment "production" {
+  inputs = {
+    region          = "eu-west-1"
+    replica_regions = ["eu-central-1"]
+  }
+}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	stack, err := LoadStack(dir, zerolog.Nop())
+	require.NoError(t, err)
+
+	regions := map[string]map[string]string{}
+	for _, i := range stack.Instances() {
+		regions[i.Deployment+"/"+i.Component] = i.ProviderRegions
+	}
+
+	assert.Equal(t, map[string]map[string]string{
+		"development/web":                    {"aws": "us-east-1"},
+		"production/web":                     {"aws": "eu-west-1"},
+		`production/replica["eu-central-1"]`: {"aws": "eu-central-1"},
+	}, regions)
+}
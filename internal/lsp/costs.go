@@ -0,0 +1,178 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/sourcegraph/go-lsp"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// codeLenses returns a code lens with the monthly cost of each resource and
+// module block in the file. If the file is part of more than one project, e.g.
+// a shared module or a root module with a var file per environment, there is a
+// code lens for each project.
+func (s *Server) codeLenses(ctx context.Context, filename string) ([]lsp.CodeLens, error) {
+	projects, err := s.evaluated(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	lenses := []lsp.CodeLens{}
+	for _, p := range projects {
+		for _, block := range p.result.blocks[filename] {
+			title := fmt.Sprintf("Monthly cost: %s", output.FormatCost2DP(p.result.currency, block.monthlyCost()))
+			if len(block.resources) > 1 {
+				title += fmt.Sprintf(" (%d resources)", len(block.resources))
+			}
+
+			if len(projects) > 1 {
+				title += fmt.Sprintf(" in %s", p.name)
+			}
+
+			lenses = append(lenses, lsp.CodeLens{
+				Range:   lineRange(block.startLine),
+				Command: lsp.Command{Title: title},
+			})
+		}
+	}
+
+	return lenses, nil
+}
+
+// hover returns the cost breakdown of the resource or module block at the
+// position. Module blocks list the monthly cost of each of their resources and
+// resource blocks list the cost components of each resource.
+func (s *Server) hover(ctx context.Context, filename string, position lsp.Position) (*lsp.Hover, error) {
+	projects, err := s.evaluated(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []string
+	var hoverRange *lsp.Range
+
+	for _, p := range projects {
+		block := blockAt(p.result.blocks[filename], position.Line)
+		if block == nil {
+			continue
+		}
+
+		r := lsp.Range{
+			Start: lsp.Position{Line: block.startLine - 1},
+			End:   lsp.Position{Line: block.endLine - 1, Character: 1 << 16},
+		}
+		hoverRange = &r
+
+		title := fmt.Sprintf("**%s**: %s/month", block.address, output.FormatCost2DP(p.result.currency, block.monthlyCost()))
+		if len(projects) > 1 {
+			title += fmt.Sprintf(" in %s", p.name)
+		}
+
+		if block.isModule {
+			sections = append(sections, title+"\n\n"+moduleTable(p.result.currency, block.resources))
+			continue
+		}
+
+		text := title
+		for _, r := range block.resources {
+			if len(block.resources) > 1 {
+				text += fmt.Sprintf("\n\n`%s`: %s/month", r.Name, output.FormatCost2DP(p.result.currency, r.MonthlyCost))
+			}
+
+			text += "\n\n" + resourceTable(p.result.currency, r)
+		}
+
+		sections = append(sections, text)
+	}
+
+	if len(sections) == 0 {
+		return nil, nil
+	}
+
+	return &lsp.Hover{
+		Contents: []lsp.MarkedString{lsp.RawMarkedString(strings.Join(sections, "\n\n---\n\n"))},
+		Range:    hoverRange,
+	}, nil
+}
+
+// blockAt returns the innermost block containing the zero based line.
+func blockAt(blocks []*costBlock, line int) *costBlock {
+	var found *costBlock
+	for _, block := range blocks {
+		if !block.contains(line) {
+			continue
+		}
+
+		if found == nil || block.endLine-block.startLine < found.endLine-found.startLine {
+			found = block
+		}
+	}
+
+	return found
+}
+
+// moduleTable returns a markdown table of the monthly cost of the resources of
+// a module, sorted by cost.
+func moduleTable(currency string, resources []*schema.Resource) string {
+	sorted := make([]*schema.Resource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return costOrZero(sorted[i].MonthlyCost).GreaterThan(costOrZero(sorted[j].MonthlyCost))
+	})
+
+	var b strings.Builder
+	b.WriteString("| Resource | Monthly cost |\n|---|--:|\n")
+	for _, r := range sorted {
+		fmt.Fprintf(&b, "| `%s` | %s |\n", r.Name, output.FormatCost2DP(currency, r.MonthlyCost))
+	}
+
+	return b.String()
+}
+
+// resourceTable returns a markdown table of the cost components of a resource
+// and its sub resources, like the breakdown table output.
+func resourceTable(currency string, r *schema.Resource) string {
+	var b strings.Builder
+	b.WriteString("| Cost component | Monthly qty | Unit | Monthly cost |\n|---|--:|---|--:|\n")
+	writeResourceRows(&b, currency, r, "")
+
+	return b.String()
+}
+
+func writeResourceRows(b *strings.Builder, currency string, r *schema.Resource, indent string) {
+	for _, c := range r.CostComponents {
+		cost := output.FormatCost2DP(currency, c.MonthlyCost)
+		if c.PriceNotFound {
+			cost = "not found"
+		}
+
+		fmt.Fprintf(b, "| %s%s | %s | %s | %s |\n", indent, c.Name, formatQuantity(c.UnitMultiplierMonthlyQuantity()), c.Unit, cost)
+	}
+
+	for _, sub := range r.SubResources {
+		fmt.Fprintf(b, "| %s%s | | | |\n", indent, sub.Name)
+		writeResourceRows(b, currency, sub, indent+"&nbsp;&nbsp;")
+	}
+}
+
+func formatQuantity(q *decimal.Decimal) string {
+	if q == nil {
+		return "-"
+	}
+
+	return q.Round(4).String()
+}
+
+func costOrZero(d *decimal.Decimal) decimal.Decimal {
+	if d == nil {
+		return decimal.Zero
+	}
+
+	return *d
+}
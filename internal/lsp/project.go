@@ -0,0 +1,362 @@
+package lsp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sourcegraph/go-lsp"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// project is a project detected in the workspace, which is evaluated with its
+// provider when one of its files is opened or saved.
+type project struct {
+	key  string
+	name string
+	root string
+
+	// The following fields are guarded by the Server mutex.
+
+	// provider is replaced when the projects of the workspace are detected again.
+	provider schema.Provider
+
+	// queued is true if the project is waiting to be evaluated.
+	queued bool
+	// debounce queues the project once its files stop being saved, and is nil if
+	// no save is pending.
+	debounce *time.Timer
+	// saved are the files of the project that have been saved since it was last
+	// queued.
+	saved map[string]struct{}
+	// done is closed when no evaluation of the project is pending, queued or
+	// running.
+	done chan struct{}
+	// result is the latest evaluation of the project, or nil if the project
+	// hasn't been evaluated yet.
+	result *evaluation
+	// hashes are the hashes of the files of the latest evaluation, keyed by
+	// filename.
+	hashes map[string]string
+}
+
+func newProject(provider schema.Provider, workspace string) *project {
+	done := make(chan struct{})
+	close(done)
+
+	return &project{
+		provider: provider,
+		key:      provider.RelativePath() + ":" + provider.ProjectName(),
+		name:     provider.ProjectName(),
+		root:     filepath.Join(workspace, provider.RelativePath()),
+		done:     done,
+	}
+}
+
+// claims returns true if the file is part of the project. Before the project is
+// evaluated only the files in the root directory of the project are known.
+func (p *project) claims(filename string) bool {
+	if filepath.Dir(filename) == p.root {
+		return true
+	}
+
+	if p.result == nil {
+		return false
+	}
+
+	_, ok := p.result.files[filename]
+	return ok
+}
+
+// unchanged returns true if the saved files are all files of the latest
+// evaluation and none of its files have changed since, so evaluating the
+// project again would give the same result. It must be called with the Server
+// mutex held.
+func (p *project) unchanged(saved map[string]struct{}) bool {
+	if p.result == nil || len(saved) == 0 {
+		return false
+	}
+
+	for filename := range saved {
+		if _, ok := p.hashes[filename]; !ok {
+			return false
+		}
+	}
+
+	for filename, hash := range p.hashes {
+		if hashFile(filename) != hash {
+			return false
+		}
+	}
+
+	return true
+}
+
+// finish closes done unless another evaluation of the project is pending or
+// queued. It must be called with the Server mutex held.
+func (p *project) finish() {
+	if !p.queued && p.debounce == nil {
+		close(p.done)
+	}
+}
+
+func (p *project) isIdle() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// evaluation holds the costs of the resource and module blocks of a project,
+// and the diagnostics for its files, indexed by absolute filename.
+type evaluation struct {
+	currency    string
+	files       map[string]struct{}
+	blocks      map[string][]*costBlock
+	diagnostics map[string][]lsp.Diagnostic
+}
+
+// costBlock is a resource or module block along with the resources that it
+// adds to a project. A block can add more than one resource if it uses count
+// or for_each, or if it is in a module that is called more than once.
+type costBlock struct {
+	project   string
+	address   string
+	isModule  bool
+	startLine int
+	endLine   int
+	resources []*schema.Resource
+}
+
+// monthlyCost returns the total monthly cost of the resources of the block,
+// or nil if none of the resources have a monthly cost.
+func (b *costBlock) monthlyCost() *decimal.Decimal {
+	var total *decimal.Decimal
+	for _, r := range b.resources {
+		if r.MonthlyCost == nil {
+			continue
+		}
+
+		sum := *r.MonthlyCost
+		if total != nil {
+			sum = total.Add(sum)
+		}
+
+		total = &sum
+	}
+
+	return total
+}
+
+// contains returns true if the zero based line is within the block.
+func (b *costBlock) contains(line int) bool {
+	return line+1 >= b.startLine && line+1 <= b.endLine
+}
+
+func newEvaluation(currency string) *evaluation {
+	return &evaluation{
+		currency:    currency,
+		files:       make(map[string]struct{}),
+		blocks:      make(map[string][]*costBlock),
+		diagnostics: make(map[string][]lsp.Diagnostic),
+	}
+}
+
+// addProject indexes the resources of the priced project by the blocks that
+// they were defined in, using the filename and line metadata of the resources
+// that is set by the HCL provider.
+func (e *evaluation) addProject(name string, project *schema.Project) {
+	index := make(map[string]map[int]*costBlock)
+	add := func(filename string, startLine, endLine int, address string, isModule bool, r *schema.Resource) {
+		filename = absFilename(filename)
+		e.files[filename] = struct{}{}
+
+		if index[filename] == nil {
+			index[filename] = make(map[int]*costBlock)
+		}
+
+		block, ok := index[filename][startLine]
+		if !ok {
+			block = &costBlock{
+				project:   name,
+				address:   address,
+				isModule:  isModule,
+				startLine: startLine,
+				endLine:   endLine,
+			}
+			index[filename][startLine] = block
+		}
+
+		block.resources = append(block.resources, r)
+	}
+
+	for _, r := range project.Resources {
+		filename := r.Metadata["filename"].String()
+		startLine := int(r.Metadata["startLine"].Int())
+		if filename == "" || startLine == 0 {
+			continue
+		}
+
+		calls := r.Metadata["calls"].Array()
+		for i, call := range calls {
+			// the last call is the resource block itself.
+			if i == len(calls)-1 {
+				break
+			}
+
+			add(call.Get("filename").String(), int(call.Get("startLine").Int()), int(call.Get("endLine").Int()), call.Get("blockName").String(), true, r)
+		}
+
+		address := r.Name
+		if len(calls) > 0 {
+			address = calls[len(calls)-1].Get("blockName").String()
+		}
+
+		add(filename, startLine, int(r.Metadata["endLine"].Int()), address, false, r)
+
+		for _, c := range missingPrices(r) {
+			e.addDiagnostic(filename, startLine, fmt.Sprintf("No price found for %s of %s, it is not included in the monthly cost.", c, r.Name))
+		}
+	}
+
+	for filename, blocks := range index {
+		for _, block := range blocks {
+			e.blocks[filename] = append(e.blocks[filename], block)
+		}
+
+		sort.Slice(e.blocks[filename], func(i, j int) bool {
+			return e.blocks[filename][i].startLine < e.blocks[filename][j].startLine
+		})
+	}
+}
+
+// addModule adds the files of the module tree parsed by the HCL provider, so
+// that saving any of them re-evaluates the project, and the diagnostics for
+// variables of the root module that are missing values.
+func (e *evaluation) addModule(provider *terraform.HCLProvider, root string, warnings []*schema.ProjectDiag) {
+	parsed := provider.Module()
+	if parsed.Module == nil {
+		return
+	}
+
+	var walk func(m *hcl.Module)
+	walk = func(m *hcl.Module) {
+		for _, block := range m.Blocks {
+			e.files[absFilename(block.Filename)] = struct{}{}
+		}
+
+		for _, child := range m.Modules {
+			walk(child)
+		}
+	}
+	walk(parsed.Module)
+
+	for _, varFile := range provider.VarFiles() {
+		e.files[filepath.Join(root, varFile)] = struct{}{}
+	}
+
+	var missing []string
+	for _, warning := range warnings {
+		if schema.IsMissingVarsDiag(warning) {
+			vars, _ := warning.Data.([]string)
+			missing = append(missing, vars...)
+		}
+	}
+
+	for _, v := range missing {
+		name := strings.TrimPrefix(v, "variable.")
+		block := parsed.Module.Blocks.Matching(hcl.BlockMatcher{Type: "variable", Label: name})
+		if block == nil {
+			continue
+		}
+
+		e.addDiagnostic(block.Filename, block.StartLine, fmt.Sprintf("No value was provided for variable %q, so the costs of resources that use it might be missing or inaccurate. Set it in a .tfvars file of the project.", name))
+	}
+}
+
+func (e *evaluation) addDiagnostic(filename string, line int, message string) {
+	filename = absFilename(filename)
+	e.files[filename] = struct{}{}
+	e.diagnostics[filename] = append(e.diagnostics[filename], lsp.Diagnostic{
+		Range:    lineRange(line),
+		Severity: lsp.Warning,
+		Source:   "infracost",
+		Message:  message,
+	})
+}
+
+// missingPrices returns the names of the cost components of the resource and
+// its sub resources that have no price.
+func missingPrices(r *schema.Resource) []string {
+	var names []string
+	for _, c := range r.CostComponents {
+		if c.PriceNotFound {
+			names = append(names, fmt.Sprintf("%q", c.Name))
+		}
+	}
+
+	for _, sub := range r.SubResources {
+		for _, name := range missingPrices(sub) {
+			names = append(names, fmt.Sprintf("%s of %s", name, sub.Name))
+		}
+	}
+
+	return names
+}
+
+// lineRange returns the range covering the one based line. Clients limit the end
+// character to the length of the line.
+func lineRange(line int) lsp.Range {
+	if line > 0 {
+		line--
+	}
+
+	return lsp.Range{
+		Start: lsp.Position{Line: line},
+		End:   lsp.Position{Line: line, Character: 1 << 16},
+	}
+}
+
+// hashFiles returns the hashes of the files, keyed by filename.
+func hashFiles(files map[string]struct{}) map[string]string {
+	hashes := make(map[string]string, len(files))
+	for filename := range files {
+		hashes[filename] = hashFile(filename)
+	}
+
+	return hashes
+}
+
+// hashFile returns the hex encoded sha256 hash of the contents of the file, or
+// an empty string if the file can't be read.
+func hashFile(filename string) string {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// absFilename returns the absolute filename for block filenames, which the HCL
+// parser makes relative to the working directory.
+func absFilename(filename string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.Clean(filename)
+	}
+
+	return abs
+}
@@ -0,0 +1,552 @@
+// Package lsp implements a Language Server Protocol server that shows the costs
+// of Terraform projects in editors. Projects are evaluated with the HCL
+// provider when their files are opened and saved, and the costs are published
+// as code lenses on resource and module blocks, hovers with the cost component
+// breakdown and diagnostics for missing prices and variables.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// saveDelay is how long the Server waits after a file is saved before it
+// evaluates the projects of the file, so that saving repeatedly only evaluates
+// them once.
+const saveDelay = 300 * time.Millisecond
+
+// Pricer populates the prices of the cost components of a project, this is
+// usually a prices.PriceFetcher.
+type Pricer interface {
+	PopulatePrices(project *schema.Project) error
+}
+
+// Server is a Language Server Protocol server for a single workspace. Only the
+// projects that the opened and saved files belong to are evaluated, and the
+// evaluations are run one at a time in the background so that large
+// workspaces stay responsive.
+type Server struct {
+	runCtx *config.RunContext
+	pricer Pricer
+	usage  schema.UsageMap
+	logger zerolog.Logger
+
+	conn      atomic.Pointer[jsonrpc2.Conn]
+	saveDelay time.Duration
+
+	mu        sync.Mutex
+	workspace string
+	detected  bool
+	projects  []*project
+	queue     []*project
+	wake      chan struct{}
+	published map[string]struct{}
+}
+
+// NewServer returns a Server that evaluates projects with the run context
+// config and prices them with pricer. The usage is used for every project.
+func NewServer(runCtx *config.RunContext, pricer Pricer, usage schema.UsageMap, logger zerolog.Logger) *Server {
+	return &Server{
+		runCtx:    runCtx,
+		pricer:    pricer,
+		usage:     usage,
+		logger:    logger,
+		saveDelay: saveDelay,
+		wake:      make(chan struct{}, 1),
+		published: make(map[string]struct{}),
+	}
+}
+
+// Serve runs the server with the JSON-RPC messages of the client read from and
+// written to stream, until the client disconnects or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, stream io.ReadWriteCloser) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn := jsonrpc2.NewConn(
+		ctx,
+		jsonrpc2.NewBufferedStream(stream, jsonrpc2.VSCodeObjectCodec{}),
+		jsonrpc2.AsyncHandler(jsonrpc2.HandlerWithError(s.handle)),
+	)
+
+	s.conn.Store(conn)
+
+	go s.evaluateQueue(ctx)
+
+	select {
+	case <-conn.DisconnectNotify():
+	case <-ctx.Done():
+		_ = conn.Close()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var params lsp.InitializeParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		return s.initialize(params)
+	case "initialized", "shutdown", "textDocument/didChange", "textDocument/didClose", "workspace/didChangeConfiguration", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	case "exit":
+		return nil, conn.Close()
+	case "textDocument/didOpen":
+		var params lsp.DidOpenTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		s.open(uriToFilename(params.TextDocument.URI))
+		return nil, nil
+	case "textDocument/didSave":
+		var params lsp.DidSaveTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		s.save(uriToFilename(params.TextDocument.URI))
+		return nil, nil
+	case "textDocument/codeLens":
+		var params lsp.CodeLensParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		return s.codeLenses(ctx, uriToFilename(params.TextDocument.URI))
+	case "textDocument/hover":
+		var params lsp.TextDocumentPositionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		return s.hover(ctx, uriToFilename(params.TextDocument.URI), params.Position)
+	}
+
+	if req.Notif {
+		return nil, nil
+	}
+
+	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+}
+
+func (s *Server) initialize(params lsp.InitializeParams) (*lsp.InitializeResult, error) {
+	workspace := uriToFilename(params.Root())
+	if workspace == "" {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: "a workspace root is required"}
+	}
+
+	s.mu.Lock()
+	s.workspace = workspace
+	s.mu.Unlock()
+
+	return &lsp.InitializeResult{
+		Capabilities: lsp.ServerCapabilities{
+			TextDocumentSync: &lsp.TextDocumentSyncOptionsOrKind{
+				Options: &lsp.TextDocumentSyncOptions{
+					OpenClose: true,
+					Change:    lsp.TDSKNone,
+					Save:      &lsp.SaveOptions{},
+				},
+			},
+			HoverProvider:    true,
+			CodeLensProvider: &lsp.CodeLensOptions{},
+		},
+	}, nil
+}
+
+// open queues the projects of the file that haven't been evaluated yet.
+func (s *Server) open(filename string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.claimingProjects(filename) {
+		if p.result == nil {
+			s.enqueue(p)
+		}
+	}
+}
+
+// save schedules the projects of the file to be evaluated again. If no project
+// claims the file it might be in a new project, so the projects of the
+// workspace are detected again.
+func (s *Server) save(filename string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Var files are assigned to projects when they are detected, so saving a var
+	// file re-detects the projects in case it is a new one.
+	projects := s.claimingProjects(filename)
+	if len(projects) == 0 || isVarFile(filename) {
+		s.detect()
+		projects = s.claimingProjects(filename)
+	}
+
+	for _, p := range projects {
+		s.schedule(p, filename)
+	}
+}
+
+// schedule queues the project once the saveDelay has passed without any more
+// of its files being saved. It must be called with the mutex held.
+func (s *Server) schedule(p *project, filename string) {
+	if p.saved == nil {
+		p.saved = make(map[string]struct{})
+	}
+	p.saved[filename] = struct{}{}
+
+	if p.isIdle() {
+		p.done = make(chan struct{})
+	}
+
+	if p.debounce != nil {
+		p.debounce.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(s.saveDelay, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// the timer might have been replaced by a later save while it was waiting
+		// for the mutex.
+		if p.debounce != timer {
+			return
+		}
+
+		p.debounce = nil
+		s.enqueue(p)
+	})
+	p.debounce = timer
+}
+
+// claimingProjects returns the projects that the file belongs to, detecting the
+// projects of the workspace on first use. It must be called with the mutex
+// held.
+func (s *Server) claimingProjects(filename string) []*project {
+	if !s.detected {
+		s.detect()
+	}
+
+	var projects []*project
+	for _, p := range s.projects {
+		if p.claims(filename) {
+			projects = append(projects, p)
+		}
+	}
+
+	return projects
+}
+
+// detect detects the projects in the workspace, keeping the evaluations of
+// projects that were detected before and updating their providers. It must be called with the mutex held.
+func (s *Server) detect() {
+	s.detected = true
+	if s.workspace == "" {
+		return
+	}
+
+	out, err := providers.Detect(s.runCtx, &config.Project{Path: s.workspace}, false)
+	if err != nil {
+		s.logMessage(lsp.MTWarning, fmt.Sprintf("Could not detect projects in %s: %s", s.workspace, err))
+		return
+	}
+
+	existing := make(map[string]*project, len(s.projects))
+	for _, p := range s.projects {
+		existing[p.key] = p
+	}
+
+	var projects []*project
+	for _, provider := range out.Providers {
+		if h, ok := provider.(*terraform.HCLProvider); ok {
+			if h == nil {
+				continue
+			}
+
+			h.WithModuleCache()
+		}
+
+		p := newProject(provider, s.workspace)
+		if prev, ok := existing[p.key]; ok {
+			prev.provider = provider
+			p = prev
+		}
+
+		projects = append(projects, p)
+	}
+
+	s.projects = projects
+}
+
+// enqueue queues the project to be evaluated unless it is already queued. It
+// must be called with the mutex held.
+func (s *Server) enqueue(p *project) {
+	if p.queued {
+		return
+	}
+
+	p.queued = true
+	if p.isIdle() {
+		p.done = make(chan struct{})
+	}
+
+	s.queue = append(s.queue, p)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// evaluateQueue evaluates the queued projects one at a time and publishes the
+// diagnostics of their files.
+func (s *Server) evaluateQueue(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		}
+
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+
+			p := s.queue[0]
+			s.queue = s.queue[1:]
+			p.queued = false
+			saved := p.saved
+			p.saved = nil
+
+			if p.unchanged(saved) {
+				s.logger.Debug().Msgf("skipping evaluation of project %s as none of its files have changed", p.name)
+				p.finish()
+				s.mu.Unlock()
+				continue
+			}
+
+			provider := p.provider
+			s.mu.Unlock()
+
+			result := s.evaluate(p, provider)
+			hashes := hashFiles(result.files)
+
+			s.mu.Lock()
+			prev := p.result
+			p.result = result
+			p.hashes = hashes
+			p.finish()
+			s.mu.Unlock()
+
+			s.publishDiagnostics(ctx, prev, result)
+		}
+	}
+}
+
+// evaluate loads the resources of the project with its provider and prices
+// them. The HCL provider reuses the modules that were downloaded by previous
+// evaluations, and the pricing API client caches prices, so evaluating a
+// project again after a save is much quicker than the first evaluation.
+func (s *Server) evaluate(p *project, provider schema.Provider) *evaluation {
+	result := newEvaluation(s.runCtx.Config.Currency)
+
+	h, isHCL := provider.(*terraform.HCLProvider)
+	if isHCL {
+		h.Reload()
+	}
+
+	projects, err := provider.LoadResources(s.usage)
+	if err != nil {
+		s.logMessage(lsp.MTError, fmt.Sprintf("Could not evaluate project %s: %s", p.name, err))
+		return result
+	}
+
+	schema.BuildResources(projects, nil)
+
+	for _, project := range projects {
+		if err := s.pricer.PopulatePrices(project); err != nil {
+			s.logMessage(lsp.MTError, fmt.Sprintf("Could not get prices for project %s: %s", p.name, err))
+			continue
+		}
+
+		schema.CalculateCosts(project)
+		result.addProject(p.name, project)
+
+		for _, diag := range project.Metadata.Errors {
+			s.logMessage(lsp.MTWarning, fmt.Sprintf("Project %s: %s", p.name, diag.Message))
+		}
+
+		if isHCL {
+			result.addModule(h, p.root, project.Metadata.Warnings)
+		}
+	}
+
+	return result
+}
+
+// publishDiagnostics publishes the diagnostics of the files of the evaluation,
+// and clears the diagnostics of files that only had diagnostics in the
+// previous evaluation of the project.
+func (s *Server) publishDiagnostics(ctx context.Context, prev, result *evaluation) {
+	filenames := make(map[string]struct{})
+	for filename := range result.diagnostics {
+		filenames[filename] = struct{}{}
+	}
+
+	if prev != nil {
+		for filename := range prev.diagnostics {
+			filenames[filename] = struct{}{}
+		}
+	}
+
+	s.mu.Lock()
+	params := make([]lsp.PublishDiagnosticsParams, 0, len(filenames))
+	for filename := range filenames {
+		diagnostics := s.diagnostics(filename)
+		if _, ok := s.published[filename]; !ok && len(diagnostics) == 0 {
+			continue
+		}
+
+		s.published[filename] = struct{}{}
+		params = append(params, lsp.PublishDiagnosticsParams{
+			URI:         filenameToURI(filename),
+			Diagnostics: diagnostics,
+		})
+	}
+	s.mu.Unlock()
+
+	conn := s.conn.Load()
+	for _, p := range params {
+		if err := conn.Notify(ctx, "textDocument/publishDiagnostics", p); err != nil {
+			s.logger.Debug().Err(err).Msgf("could not publish diagnostics for %s", p.URI)
+		}
+	}
+}
+
+// diagnostics returns the diagnostics for the file from all the evaluated
+// projects, without duplicates from projects that share the file. It must be
+// called with the mutex held.
+func (s *Server) diagnostics(filename string) []lsp.Diagnostic {
+	seen := make(map[lsp.Diagnostic]struct{})
+	diagnostics := []lsp.Diagnostic{}
+
+	for _, p := range s.projects {
+		if p.result == nil {
+			continue
+		}
+
+		for _, d := range p.result.diagnostics[filename] {
+			if _, ok := seen[d]; ok {
+				continue
+			}
+
+			seen[d] = struct{}{}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	return diagnostics
+}
+
+// evaluated waits for the queued evaluations of the projects of the file and
+// returns the projects that have been evaluated.
+func (s *Server) evaluated(ctx context.Context, filename string) ([]*project, error) {
+	s.mu.Lock()
+	projects := s.claimingProjects(filename)
+	var waits []chan struct{}
+	for _, p := range projects {
+		if p.result == nil {
+			s.enqueue(p)
+		}
+
+		waits = append(waits, p.done)
+	}
+	s.mu.Unlock()
+
+	for _, done := range waits {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var evaluated []*project
+	for _, p := range projects {
+		if p.result != nil {
+			evaluated = append(evaluated, p)
+		}
+	}
+
+	return evaluated, nil
+}
+
+func (s *Server) logMessage(typ lsp.MessageType, message string) {
+	s.logger.Debug().Msg(message)
+
+	conn := s.conn.Load()
+	if conn == nil {
+		return
+	}
+
+	err := conn.Notify(context.Background(), "window/logMessage", lsp.LogMessageParams{Type: typ, Message: message})
+	if err != nil {
+		s.logger.Debug().Err(err).Msg("could not send log message")
+	}
+}
+
+func isVarFile(filename string) bool {
+	return strings.HasSuffix(filename, ".tfvars") || strings.HasSuffix(filename, ".tfvars.json")
+}
+
+func unmarshalParams(req *jsonrpc2.Request, v interface{}) error {
+	if req.Params == nil {
+		return &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: "missing params"}
+	}
+
+	if err := json.Unmarshal(*req.Params, v); err != nil {
+		return &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func uriToFilename(uri lsp.DocumentURI) string {
+	u, err := url.Parse(string(uri))
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+
+	return filepath.Clean(filepath.FromSlash(u.Path))
+}
+
+func filenameToURI(filename string) lsp.DocumentURI {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}
+	return lsp.DocumentURI(u.String())
+}
@@ -0,0 +1,362 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// testPricer prices every cost component at 0.01 apart from storage, which
+// has no price so that missing price diagnostics are published. It counts the
+// projects it prices so tests can check how many evaluations were run.
+type testPricer struct {
+	calls atomic.Int32
+}
+
+func (p *testPricer) PopulatePrices(project *schema.Project) error {
+	p.calls.Add(1)
+
+	var populate func(r *schema.Resource)
+	populate = func(r *schema.Resource) {
+		for _, c := range r.CostComponents {
+			if strings.HasPrefix(c.Name, "Storage") {
+				c.SetPriceNotFound()
+				continue
+			}
+
+			c.SetPrice(decimal.NewFromFloat(0.01))
+		}
+
+		for _, sub := range r.SubResources {
+			populate(sub)
+		}
+	}
+
+	for _, r := range project.Resources {
+		populate(r)
+	}
+
+	return nil
+}
+
+type testClient struct {
+	conn        *jsonrpc2.Conn
+	pricer      *testPricer
+	diagnostics chan lsp.PublishDiagnosticsParams
+}
+
+func (c *testClient) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Method != "textDocument/publishDiagnostics" {
+		return
+	}
+
+	var params lsp.PublishDiagnosticsParams
+	if err := json.Unmarshal(*req.Params, &params); err == nil {
+		c.diagnostics <- params
+	}
+}
+
+func (c *testClient) waitForDiagnostics(t *testing.T, filename string) []lsp.Diagnostic {
+	t.Helper()
+
+	for {
+		select {
+		case params := <-c.diagnostics:
+			if params.URI == filenameToURI(filename) {
+				return params.Diagnostics
+			}
+		case <-time.After(30 * time.Second):
+			t.Fatalf("timed out waiting for diagnostics for %s", filename)
+		}
+	}
+}
+
+func (c *testClient) codeLenses(t *testing.T, filename string) []lsp.CodeLens {
+	t.Helper()
+
+	var lenses []lsp.CodeLens
+	err := c.conn.Call(context.Background(), "textDocument/codeLens", lsp.CodeLensParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(filename)},
+	}, &lenses)
+	require.NoError(t, err)
+
+	return lenses
+}
+
+func lensTitles(lenses []lsp.CodeLens) map[int]string {
+	titles := make(map[int]string, len(lenses))
+	for _, lens := range lenses {
+		titles[lens.Range.Start.Line] = lens.Command.Title
+	}
+
+	return titles
+}
+
+func newTestClient(t *testing.T, workspace string) *testClient {
+	t.Helper()
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.Currency = "USD"
+	pricer := &testPricer{}
+	server := NewServer(runCtx, pricer, schema.UsageMap{}, zerolog.New(io.Discard))
+	server.saveDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serverSide, clientSide := net.Pipe()
+	go server.Serve(ctx, serverSide)
+
+	client := &testClient{pricer: pricer, diagnostics: make(chan lsp.PublishDiagnosticsParams, 100)}
+	client.conn = jsonrpc2.NewConn(ctx, jsonrpc2.NewBufferedStream(clientSide, jsonrpc2.VSCodeObjectCodec{}), client)
+	t.Cleanup(func() {
+		_ = client.conn.Close()
+		cancel()
+	})
+
+	var result lsp.InitializeResult
+	err := client.conn.Call(ctx, "initialize", lsp.InitializeParams{RootURI: filenameToURI(workspace)}, &result)
+	require.NoError(t, err)
+	assert.True(t, result.Capabilities.HoverProvider)
+	assert.NotNil(t, result.Capabilities.CodeLensProvider)
+
+	return client
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), os.ModePerm))
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.tf": `provider "aws" {
+  region = "us-east-1"
+}
+
+variable "owner" {
+  type = string
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+
+  tags = {
+    Owner = var.owner
+  }
+}
+
+module "app" {
+  source = "./app"
+}
+`,
+		"app/main.tf": `resource "aws_instance" "app" {
+  count         = 2
+  ami           = "ami-123"
+  instance_type = "m5.large"
+}
+`,
+	})
+
+	mainFile := filepath.Join(dir, "main.tf")
+	appFile := filepath.Join(dir, "app", "main.tf")
+
+	client := newTestClient(t, dir)
+	err := client.conn.Notify(context.Background(), "textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: filenameToURI(mainFile), LanguageID: "terraform"},
+	})
+	require.NoError(t, err)
+
+	diagnostics := client.waitForDiagnostics(t, mainFile)
+	messages := make(map[int]string, len(diagnostics))
+	for _, d := range diagnostics {
+		messages[d.Range.Start.Line] += d.Message
+	}
+	assert.Contains(t, messages[4], `No value was provided for variable "owner"`)
+	assert.Contains(t, messages[8], `No price found for "Storage (general purpose SSD, gp2)" of root_block_device of aws_instance.web`)
+
+	assert.Equal(t, map[int]string{
+		8:  "Monthly cost: $7.30",
+		17: "Monthly cost: $14.60 (2 resources)",
+	}, lensTitles(client.codeLenses(t, mainFile)))
+
+	assert.Equal(t, map[int]string{
+		0: "Monthly cost: $14.60 (2 resources)",
+	}, lensTitles(client.codeLenses(t, appFile)))
+
+	var hover lsp.Hover
+	err = client.conn.Call(context.Background(), "textDocument/hover", lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(mainFile)},
+		Position:     lsp.Position{Line: 10, Character: 4},
+	}, &hover)
+	require.NoError(t, err)
+	require.Len(t, hover.Contents, 1)
+	assert.Contains(t, hover.Contents[0].Value, "**aws_instance.web**: $7.30/month")
+	assert.Contains(t, hover.Contents[0].Value, "| Instance usage (Linux/UNIX, on-demand, t3.micro) | 730 | hours | $7.30 |")
+	assert.Contains(t, hover.Contents[0].Value, "Storage (general purpose SSD, gp2) | 8 | GB | not found |")
+
+	err = client.conn.Call(context.Background(), "textDocument/hover", lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(mainFile)},
+		Position:     lsp.Position{Line: 18, Character: 4},
+	}, &hover)
+	require.NoError(t, err)
+	require.Len(t, hover.Contents, 1)
+	assert.Contains(t, hover.Contents[0].Value, "| `module.app.aws_instance.app[0]` | $7.30 |")
+
+	writeFiles(t, dir, map[string]string{
+		"app/main.tf": `resource "aws_instance" "app" {
+  count         = 3
+  ami           = "ami-123"
+  instance_type = "m5.large"
+}
+`,
+	})
+	err = client.conn.Notify(context.Background(), "textDocument/didSave", lsp.DidSaveTextDocumentParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(appFile)},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, client.waitForDiagnostics(t, mainFile), 2)
+	assert.Equal(t, map[int]string{
+		8:  "Monthly cost: $7.30",
+		17: "Monthly cost: $21.90 (3 resources)",
+	}, lensTitles(client.codeLenses(t, mainFile)))
+
+	varFile := filepath.Join(dir, "terraform.tfvars")
+	writeFiles(t, dir, map[string]string{
+		"terraform.tfvars": `owner = "platform"`,
+	})
+	err = client.conn.Notify(context.Background(), "textDocument/didSave", lsp.DidSaveTextDocumentParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(varFile)},
+	})
+	require.NoError(t, err)
+
+	diagnostics = client.waitForDiagnostics(t, mainFile)
+	require.Len(t, diagnostics, 1, "the missing variable diagnostic should be cleared")
+	assert.Contains(t, diagnostics[0].Message, "No price found")
+}
+
+func TestServerSave(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+}
+`,
+	})
+
+	mainFile := filepath.Join(dir, "main.tf")
+	client := newTestClient(t, dir)
+	save := func() {
+		err := client.conn.Notify(context.Background(), "textDocument/didSave", lsp.DidSaveTextDocumentParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(mainFile)},
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[int]string{0: "Monthly cost: $7.30"}, lensTitles(client.codeLenses(t, mainFile)))
+	assert.Equal(t, int32(1), client.pricer.calls.Load())
+
+	for i := 0; i < 3; i++ {
+		save()
+	}
+	assert.Never(t, func() bool {
+		return client.pricer.calls.Load() > 1
+	}, 500*time.Millisecond, 10*time.Millisecond, "saving a file that hasn't changed shouldn't evaluate the project")
+
+	writeFiles(t, dir, map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  count         = 2
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+}
+`,
+	})
+	for i := 0; i < 3; i++ {
+		save()
+	}
+	assert.Eventually(t, func() bool {
+		return client.pricer.calls.Load() == 2
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool {
+		return client.pricer.calls.Load() > 2
+	}, 500*time.Millisecond, 10*time.Millisecond, "saving a file repeatedly should only evaluate the project once")
+
+	assert.Equal(t, map[int]string{0: "Monthly cost: $14.60 (2 resources)"}, lensTitles(client.codeLenses(t, mainFile)))
+}
+
+func TestServerFileWithoutProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.tf":         `resource "aws_instance" "web" {}`,
+		"scripts/init.sh": "#!/bin/sh",
+	})
+
+	client := newTestClient(t, dir)
+	assert.Empty(t, client.codeLenses(t, filepath.Join(dir, "scripts", "init.sh")))
+
+	var hover *lsp.Hover
+	err := client.conn.Call(context.Background(), "textDocument/hover", lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: filenameToURI(filepath.Join(dir, "scripts", "init.sh"))},
+	}, &hover)
+	require.NoError(t, err)
+	assert.Nil(t, hover)
+}
+
+func TestServerScheduleDebouncesSaves(t *testing.T) {
+	server := NewServer(config.EmptyRunContext(), &testPricer{}, schema.UsageMap{}, zerolog.New(io.Discard))
+	server.saveDelay = 500 * time.Millisecond
+
+	done := make(chan struct{})
+	close(done)
+	p := &project{name: "test", done: done}
+
+	queued := func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		return p.queued
+	}
+	schedule := func(filename string) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		server.schedule(p, filename)
+	}
+
+	schedule("main.tf")
+	assert.False(t, p.isIdle(), "the project should wait for the pending save")
+
+	time.Sleep(250 * time.Millisecond)
+	schedule("variables.tf")
+
+	time.Sleep(350 * time.Millisecond)
+	assert.False(t, queued(), "the second save should reset the delay")
+
+	assert.Eventually(t, queued, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]struct{}{"main.tf": {}, "variables.tf": {}}, p.saved)
+	assert.Len(t, server.queue, 1)
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// CheckTitle returns a one line summary of the change in the total monthly
+// cost of the run, used as the title of checks and commit statuses.
+func CheckTitle(out Root) string {
+	cost := out.TotalMonthlyCost
+	if cost == nil {
+		cost = decimalPtr(decimal.Zero)
+	}
+
+	return formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, cost, false)
+}
+
+// MonthlyCostIncrease returns the increase in the total monthly cost of the
+// run. It is negative if the cost decreased. Runs without past costs are
+// treated as an increase from zero.
+func MonthlyCostIncrease(out Root) decimal.Decimal {
+	var cost decimal.Decimal
+	if out.TotalMonthlyCost != nil {
+		cost = *out.TotalMonthlyCost
+	}
+
+	if out.PastTotalMonthlyCost == nil {
+		return cost
+	}
+
+	return cost.Sub(*out.PastTotalMonthlyCost)
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTitleAndMonthlyCostIncrease(t *testing.T) {
+	tests := []struct {
+		name         string
+		out          Root
+		wantTitle    string
+		wantIncrease decimal.Decimal
+	}{
+		{
+			name:         "increase",
+			out:          Root{Currency: "USD", PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(100)), TotalMonthlyCost: decimalPtr(decimal.NewFromInt(130))},
+			wantTitle:    "Monthly estimate increased by $30 ↑",
+			wantIncrease: decimal.NewFromInt(30),
+		},
+		{
+			name:         "decrease",
+			out:          Root{Currency: "USD", PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(100)), TotalMonthlyCost: decimalPtr(decimal.NewFromInt(90))},
+			wantTitle:    "Monthly estimate decreased by $10 ↓",
+			wantIncrease: decimal.NewFromInt(-10),
+		},
+		{
+			name:         "no past costs",
+			out:          Root{Currency: "USD", TotalMonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+			wantTitle:    "Monthly estimate increased by $50 ↑",
+			wantIncrease: decimal.NewFromInt(50),
+		},
+		{
+			name:         "no costs",
+			out:          Root{Currency: "USD", PastTotalMonthlyCost: decimalPtr(decimal.Zero)},
+			wantTitle:    "Monthly estimate generated",
+			wantIncrease: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantTitle, CheckTitle(tt.out))
+			assert.True(t, tt.wantIncrease.Equal(MonthlyCostIncrease(tt.out)), "got %s", MonthlyCostIncrease(tt.out))
+		})
+	}
+}
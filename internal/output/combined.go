@@ -36,6 +36,10 @@ var (
 	// Azure supports 150000 characters, which for ASCII is 150000 bytes, lets err on the side of caution and
 	// limit to 140000 bytes.
 	AzureReposMaxMessageSize = 140000 // bytes
+
+	// Gitea stores comments as long text so doesn't have a practical limit, use
+	// the same limit as GitLab.
+	GiteaMaxMessageSize = 1000000 // bytes
 )
 
 type ReportInput struct {
@@ -164,7 +168,7 @@ func CompareTo(c *config.Config, current, prior Root) (Root, error) {
 			if !p.Metadata.HasErrors() && !v.Metadata.HasErrors() {
 				scp.PastResources = v.Resources
 				scp.Metadata.PastPolicySha = v.Metadata.PolicySha
-				scp.Diff = schema.CalculateDiff(scp.PastResources, scp.Resources)
+				scp.CalculateDiff()
 			}
 
 			if !p.Metadata.HasErrors() && !v.Metadata.IsEmptyProjectError() && v.Metadata.HasErrors() {
@@ -210,7 +214,7 @@ func CompareTo(c *config.Config, current, prior Root) (Root, error) {
 		scp.Resources = nil
 		scp.Metadata.PolicySha = ""
 		scp.HasDiff = true
-		scp.Diff = schema.CalculateDiff(scp.PastResources, scp.Resources)
+		scp.CalculateDiff()
 
 		schemaProjects = append(schemaProjects, scp)
 	}
@@ -290,6 +294,7 @@ func Combine(inputs []ReportInput) (Root, error) {
 	var diffTotalHourlyCost *decimal.Decimal
 	var diffTotalMonthlyCost *decimal.Decimal
 	var diffTotalMonthlyUsageCost *decimal.Decimal
+	var totalForecastCosts, pastTotalForecastCosts, diffTotalForecastCosts []decimal.Decimal
 
 	projects := make([]Project, 0)
 	summaries := make([]*Summary, 0, len(inputs))
@@ -377,6 +382,10 @@ func Combine(inputs []ReportInput) (Root, error) {
 			diffTotalHourlyCost = decimalPtr(diffTotalHourlyCost.Add(*input.Root.DiffTotalHourlyCost))
 		}
 
+		totalForecastCosts = addForecastCosts(totalForecastCosts, input.Root.TotalForecastMonthlyCosts)
+		pastTotalForecastCosts = addForecastCosts(pastTotalForecastCosts, input.Root.PastTotalForecastMonthlyCosts)
+		diffTotalForecastCosts = addForecastCosts(diffTotalForecastCosts, input.Root.DiffTotalForecastMonthlyCosts)
+
 		if i != 0 && metadata.VCSRepositoryURL != input.Root.Metadata.VCSRepositoryURL {
 			invalidMetadata = true
 		}
@@ -397,6 +406,10 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.DiffTotalHourlyCost = diffTotalHourlyCost
 	combined.DiffTotalMonthlyCost = diffTotalMonthlyCost
 	combined.DiffTotalMonthlyUsageCost = diffTotalMonthlyUsageCost
+	combined.TotalCommitmentHourlyCost, combined.TotalCommitmentMonthlyCost = calculateProjectCommitmentCosts(projects)
+	combined.TotalForecastMonthlyCosts = totalForecastCosts
+	combined.PastTotalForecastMonthlyCosts = pastTotalForecastCosts
+	combined.DiffTotalForecastMonthlyCosts = diffTotalForecastCosts
 	combined.TimeGenerated = lastestGeneratedAt
 	combined.Summary = MergeSummaries(summaries)
 	combined.Metadata = metadata
@@ -453,6 +466,8 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = ToJSON(r, opts)
 	case "html":
 		b, err = ToHTML(r, opts)
+	case "html-report":
+		b, err = ToHTMLReport(r, opts)
 	case "diff":
 		b, err = ToDiff(r, opts)
 	case "azure-repos-comment":
@@ -472,6 +487,16 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = out.Msg, error
 	case "slack-message":
 		b, err = ToSlackMessage(r, opts)
+	case "csv":
+		b, err = ToCSV(r, opts)
+	case "xlsx":
+		b, err = ToXLSX(r, opts)
+	case "focus":
+		b, err = ToFOCUS(r, opts)
+	case "teams-message":
+		b, err = ToTeamsMessage(r, opts)
+	case "webhook-message":
+		b, err = ToWebhookMessage(r, opts)
 	default:
 		b, err = ToTable(r, opts)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 )
 
@@ -52,7 +53,7 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 		for _, diffResource := range project.Diff.Resources {
 			var oldResource, newResource *Resource
 			if project.PastBreakdown != nil {
-				oldResource = findResourceByName(project.PastBreakdown.Resources, diffResource.Name)
+				oldResource = findPastResource(project.PastBreakdown.Resources, diffResource)
 			}
 			if project.Breakdown != nil {
 				newResource = findResourceByName(project.Breakdown.Resources, diffResource.Name)
@@ -145,6 +146,30 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 		s += tableForDiff(out, opts)
 	}
 
+	groupBy, costGroups, err := costGroupsFromOptions(out, opts)
+	if err != nil {
+		return nil, err
+	}
+	if hasDiffProjects && costGroups != nil {
+		s += "\n\n"
+		s += tableForCostGroups(out.Currency, groupBy, costGroups, true)
+	}
+
+	if hasDiffProjects && len(out.DiffTotalForecastMonthlyCosts) > 0 {
+		s += "\n\n"
+		s += diffForecastTable(out)
+	}
+
+	if guardrailsMsg := guardrailsMessage(out); guardrailsMsg != "" {
+		s += "\n\n"
+		s += guardrailsMsg
+	}
+
+	if policiesMsg := localPoliciesMessage(out); policiesMsg != "" {
+		s += "\n\n"
+		s += policiesMsg
+	}
+
 	return []byte(s), nil
 }
 
@@ -242,6 +267,10 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		nameLabel = ui.BoldString(nameLabel)
 	}
 
+	if marker := trackingChangeLabel(diffResource); marker != "" {
+		nameLabel += " " + ui.FaintString(marker)
+	}
+
 	s += fmt.Sprintf("%s %s\n", opChar(op), nameLabel)
 
 	if isTopLevel {
@@ -432,6 +461,31 @@ func findResourceByName(resources []Resource, name string) *Resource {
 	return nil
 }
 
+// findPastResource finds the past resource of a diff resource, using the
+// address it was moved from if it was moved.
+func findPastResource(resources []Resource, diffResource Resource) *Resource {
+	if diffResource.MovedFrom != "" {
+		return findResourceByName(resources, diffResource.MovedFrom)
+	}
+
+	return findResourceByName(resources, diffResource.Name)
+}
+
+// trackingChangeLabel returns the label that is shown next to the name of
+// diff resources that were moved, imported or removed from the state.
+func trackingChangeLabel(diffResource Resource) string {
+	switch diffResource.TrackingChange {
+	case schema.TrackingChangeMoved:
+		return fmt.Sprintf("(moved from %s)", diffResource.MovedFrom)
+	case schema.TrackingChangeImported:
+		return "(now tracked)"
+	case schema.TrackingChangeRemoved:
+		return "(no longer tracked)"
+	default:
+		return ""
+	}
+}
+
 // findMatchingCostComponent finds a matching cost component by first looking for an exact match by name
 // and if that's not found, looking for a match of everything before any brackets.
 func findMatchingCostComponent(costComponents []CostComponent, name string) *CostComponent {
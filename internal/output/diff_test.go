@@ -0,0 +1,59 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToDiffTrackingChanges(t *testing.T) {
+	d := func(v int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(v)) }
+
+	out := Root{
+		Currency:             "USD",
+		TotalMonthlyCost:     d(35),
+		PastTotalMonthlyCost: d(20),
+		DiffTotalMonthlyCost: d(15),
+		Projects: Projects{
+			{
+				Name:     "infracost/infracost/dev",
+				Metadata: &schema.ProjectMetadata{},
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: d(20),
+					Resources: []Resource{
+						{Name: "aws_instance.old", MonthlyCost: d(10)},
+						{Name: "aws_instance.kept", MonthlyCost: d(10)},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: d(35),
+					Resources: []Resource{
+						{Name: "aws_instance.new", MonthlyCost: d(15)},
+						{Name: "aws_instance.existing", MonthlyCost: d(20)},
+					},
+				},
+				Diff: &Breakdown{
+					TotalMonthlyCost: d(15),
+					Resources: []Resource{
+						{Name: "aws_instance.new", MonthlyCost: d(5), TrackingChange: schema.TrackingChangeMoved, MovedFrom: "aws_instance.old"},
+						{Name: "aws_instance.existing", MonthlyCost: d(20), TrackingChange: schema.TrackingChangeImported},
+						{Name: "aws_instance.kept", MonthlyCost: d(-10), TrackingChange: schema.TrackingChangeRemoved},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := ToDiff(out, Options{})
+	require.NoError(t, err)
+
+	s := string(b)
+	assert.Contains(t, s, "~ aws_instance.new (moved from aws_instance.old)")
+	assert.Contains(t, s, "+$5 ($10 → $15)")
+	assert.Contains(t, s, "+ aws_instance.existing (now tracked)")
+	assert.Contains(t, s, "- aws_instance.kept (no longer tracked)")
+}
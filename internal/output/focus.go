@@ -0,0 +1,218 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// focusHeaders are the columns of the focus output. They are a subset of the
+// FinOps Open Cost and Usage Specification (FOCUS) 1.0 columns that can be
+// populated from an estimate, followed by custom x_ prefixed columns.
+var focusHeaders = []string{
+	"BilledCost",
+	"BillingCurrency",
+	"BillingPeriodStart",
+	"BillingPeriodEnd",
+	"ChargeCategory",
+	"ChargeDescription",
+	"ChargeFrequency",
+	"ChargePeriodStart",
+	"ChargePeriodEnd",
+	"CommitmentDiscountName",
+	"ConsumedQuantity",
+	"ConsumedUnit",
+	"EffectiveCost",
+	"ListCost",
+	"ListUnitPrice",
+	"PricingCategory",
+	"PricingQuantity",
+	"PricingUnit",
+	"ProviderName",
+	"RegionId",
+	"ResourceId",
+	"ResourceName",
+	"ResourceType",
+	"ServiceName",
+	"SubAccountName",
+	"Tags",
+	"x_CostSource",
+	"x_ProjectPath",
+	"x_SubResource",
+	"x_PriceOverride",
+}
+
+// focusCostSource marks the rows of the focus output as estimates so they can
+// be told apart from the rows of billing exports.
+const focusCostSource = "Infracost estimate"
+
+// focusProviderNames maps the vendor names of the pricing API to the provider
+// names used in the FOCUS exports of the cloud providers.
+var focusProviderNames = map[string]string{
+	"aws":   "AWS",
+	"azure": "Microsoft",
+	"gcp":   "Google Cloud",
+}
+
+// focusResourceTypePrefixes is used to find the provider of cost components
+// that have no vendor name, e.g. when loaded from an older JSON output.
+var focusResourceTypePrefixes = map[string]string{
+	"aws_":     "aws",
+	"azurerm_": "azure",
+	"google_":  "gcp",
+}
+
+func focusProviderName(vendorName string, resourceType string) string {
+	if vendorName == "" {
+		for prefix, v := range focusResourceTypePrefixes {
+			if strings.HasPrefix(resourceType, prefix) {
+				vendorName = v
+				break
+			}
+		}
+	}
+
+	if name, ok := focusProviderNames[vendorName]; ok {
+		return name
+	}
+
+	return vendorName
+}
+
+// focusTags returns the tags as the JSON object used by the FOCUS Tags column.
+func focusTags(tags map[string]string) (string, error) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// focusPeriod returns the calendar month of the time the output was
+// generated, which is the period the monthly estimates are charged in.
+func focusPeriod(t time.Time) (string, string) {
+	if t.IsZero() {
+		return "", ""
+	}
+
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	return start.Format(time.RFC3339), end.Format(time.RFC3339)
+}
+
+func focusDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
+
+// ToFOCUS returns the current monthly costs as CSV in the FOCUS format with a
+// row for each cost component of each project.
+func ToFOCUS(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write(focusHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart, periodEnd := focusPeriod(out.TimeGenerated)
+
+	for _, project := range out.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		var projectPath string
+		if project.Metadata != nil {
+			projectPath = project.Metadata.Path
+		}
+
+		for _, c := range flattenResources(project.Breakdown.Resources) {
+			comp := c.component
+
+			tags, err := focusTags(c.tags)
+			if err != nil {
+				return nil, err
+			}
+
+			chargeFrequency := "Recurring"
+			if comp.UsageBased {
+				chargeFrequency = "Usage-Based"
+			}
+
+			pricingCategory := "Standard"
+			effectiveCost := comp.MonthlyCost
+			if comp.Commitment != "" {
+				pricingCategory = "Committed"
+				effectiveCost = comp.CommitmentMonthlyCost
+			}
+
+			listPrice := comp.Price
+			if comp.ListPrice != nil {
+				listPrice = *comp.ListPrice
+			}
+
+			var listCost *decimal.Decimal
+			if comp.MonthlyQuantity != nil {
+				listCost = decimalPtr(listPrice.Mul(*comp.MonthlyQuantity))
+			}
+
+			err = w.Write([]string{
+				focusDecimal(comp.MonthlyCost),
+				out.Currency,
+				periodStart,
+				periodEnd,
+				"Usage",
+				comp.Name,
+				chargeFrequency,
+				periodStart,
+				periodEnd,
+				comp.Commitment,
+				focusDecimal(comp.MonthlyQuantity),
+				comp.Unit,
+				focusDecimal(effectiveCost),
+				focusDecimal(listCost),
+				listPrice.String(),
+				pricingCategory,
+				focusDecimal(comp.MonthlyQuantity),
+				comp.Unit,
+				focusProviderName(comp.VendorName, c.resourceType),
+				comp.Region,
+				c.key.resource,
+				c.key.resource,
+				c.resourceType,
+				comp.Service,
+				project.Label(),
+				tags,
+				focusCostSource,
+				projectPath,
+				c.key.subresource,
+				comp.PriceOverride,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
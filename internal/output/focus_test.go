@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToFOCUS(t *testing.T) {
+	d := func(v float64) *decimal.Decimal { return decimalPtr(decimal.NewFromFloat(v)) }
+	tags := map[string]string{"env": "prod"}
+
+	out := Root{
+		Currency:      "USD",
+		TimeGenerated: time.Date(2024, 2, 14, 10, 30, 0, 0, time.UTC),
+		Projects: Projects{
+			{
+				Name:     "infracost/infracost/dev",
+				Metadata: &schema.ProjectMetadata{Path: "dev"},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         &tags,
+							CostComponents: []CostComponent{
+								{
+									Name:                  "Instance usage",
+									Unit:                  "hours",
+									Price:                 decimal.NewFromFloat(0.02),
+									MonthlyQuantity:       d(730),
+									MonthlyCost:           d(14.6),
+									Commitment:            "Savings Plan",
+									CommitmentMonthlyCost: d(10),
+									VendorName:            "aws",
+									Service:               "AmazonEC2",
+									Region:                "us-east-1",
+								},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage", Unit: "GB", Price: decimal.NewFromFloat(0.08), MonthlyQuantity: d(8), MonthlyCost: d(0.64), UsageBased: true, PriceOverride: "EDP", ListPrice: d(0.1)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{Name: "infracost/infracost/empty"},
+		},
+	}
+
+	b, err := ToFOCUS(out, Options{})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, focusHeaders, records[0])
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for i, h := range focusHeaders {
+			row[h] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	assert.Equal(t, "14.6", rows[0]["BilledCost"])
+	assert.Equal(t, "10", rows[0]["EffectiveCost"])
+	assert.Equal(t, "14.6", rows[0]["ListCost"])
+	assert.Equal(t, "Committed", rows[0]["PricingCategory"])
+	assert.Equal(t, "Recurring", rows[0]["ChargeFrequency"])
+	assert.Equal(t, "AWS", rows[0]["ProviderName"])
+	assert.Equal(t, "AmazonEC2", rows[0]["ServiceName"])
+	assert.Equal(t, "us-east-1", rows[0]["RegionId"])
+	assert.Equal(t, "2024-02-01T00:00:00Z", rows[0]["ChargePeriodStart"])
+	assert.Equal(t, "2024-03-01T00:00:00Z", rows[0]["ChargePeriodEnd"])
+	assert.Equal(t, `{"env":"prod"}`, rows[0]["Tags"])
+	assert.Equal(t, "Infracost estimate", rows[0]["x_CostSource"])
+	assert.Equal(t, "infracost/infracost/dev", rows[0]["SubAccountName"])
+
+	assert.Equal(t, "aws_instance.web", rows[1]["ResourceId"])
+	assert.Equal(t, "root_block_device", rows[1]["x_SubResource"])
+	assert.Equal(t, "Standard", rows[1]["PricingCategory"])
+	assert.Equal(t, "Usage-Based", rows[1]["ChargeFrequency"])
+	assert.Equal(t, "AWS", rows[1]["ProviderName"], "provider is found from the resource type")
+	assert.Equal(t, "", rows[1]["RegionId"])
+	assert.Equal(t, "0.64", rows[1]["BilledCost"])
+	assert.Equal(t, "0.1", rows[1]["ListUnitPrice"], "list price is from before the price override")
+	assert.Equal(t, "0.8", rows[1]["ListCost"])
+	assert.Equal(t, "EDP", rows[1]["x_PriceOverride"])
+}
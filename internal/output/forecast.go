@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// forecastTable returns a table of the total monthly cost for each month of
+// the forecast, with a column for each project when there is more than one.
+func forecastTable(out Root) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+
+	header := table.Row{"Month"}
+	columnConfigs := []table.ColumnConfig{{Number: 1, WidthMin: 5, Align: text.AlignRight}}
+
+	var projects []Project
+	if len(out.Projects) > 1 {
+		for _, project := range out.Projects {
+			if project.Breakdown == nil {
+				continue
+			}
+
+			projects = append(projects, project)
+			header = append(header, truncateMiddle(project.Label(), 32, "..."))
+		}
+	}
+
+	header = append(header, "Total cost", "Cumulative cost")
+	for i := 2; i <= len(header); i++ {
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: i, WidthMin: 10, Align: text.AlignRight})
+	}
+
+	t.AppendHeader(header)
+	t.SetColumnConfigs(columnConfigs)
+
+	cumulative := decimal.Zero
+	for i, total := range out.TotalForecastMonthlyCosts {
+		cumulative = cumulative.Add(total)
+
+		row := table.Row{strconv.Itoa(i + 1)}
+		for _, project := range projects {
+			row = append(row, formatCost(out.Currency, forecastMonthCost(project.Breakdown.TotalForecastMonthlyCosts, i)))
+		}
+
+		row = append(row, formatCost(out.Currency, decimalPtr(total)), formatCost(out.Currency, decimalPtr(cumulative)))
+		t.AppendRow(row)
+	}
+
+	return fmt.Sprintf("%s\n%s",
+		ui.BoldString(formatTitleWithCurrency(fmt.Sprintf("Forecast for %d months", len(out.TotalForecastMonthlyCosts)), out.Currency)),
+		t.Render(),
+	)
+}
+
+// diffForecastTable returns a table of the past, current and diff monthly
+// costs for each month of the forecast.
+func diffForecastTable(out Root) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+	t.AppendHeader(table.Row{
+		"Month",
+		"Previous",
+		"New",
+		"Diff",
+		"Cumulative diff",
+	})
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Month", WidthMin: 5, Align: text.AlignRight},
+		{Name: "Previous", WidthMin: 10, Align: text.AlignRight},
+		{Name: "New", WidthMin: 10, Align: text.AlignRight},
+		{Name: "Diff", WidthMin: 10, Align: text.AlignRight},
+		{Name: "Cumulative diff", WidthMin: 10, Align: text.AlignRight},
+	})
+
+	cumulative := decimal.Zero
+	for i, diff := range out.DiffTotalForecastMonthlyCosts {
+		cumulative = cumulative.Add(diff)
+
+		t.AppendRow(table.Row{
+			strconv.Itoa(i + 1),
+			formatCost(out.Currency, forecastMonthCost(out.PastTotalForecastMonthlyCosts, i)),
+			formatCost(out.Currency, forecastMonthCost(out.TotalForecastMonthlyCosts, i)),
+			formatCostChange(out.Currency, decimalPtr(diff)),
+			formatCostChange(out.Currency, decimalPtr(cumulative)),
+		})
+	}
+
+	return fmt.Sprintf("%s\n%s",
+		ui.BoldString(formatTitleWithCurrency(fmt.Sprintf("Forecast for %d months", len(out.DiffTotalForecastMonthlyCosts)), out.Currency)),
+		t.Render(),
+	)
+}
+
+// forecastMonthCost returns the cost for the month at index i of the
+// forecast, or nil if the forecast doesn't cover that month.
+func forecastMonthCost(costs []decimal.Decimal, i int) *decimal.Decimal {
+	if i >= len(costs) {
+		return nil
+	}
+
+	return decimalPtr(costs[i])
+}
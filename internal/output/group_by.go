@@ -0,0 +1,366 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+)
+
+// ValidGroupBys are the values that can be passed to --group-by. The tag
+// group by also needs the tag key, e.g. tag:team.
+var ValidGroupBys = []string{"tag:<key>", "module", "resource-type", "provider", "region"}
+
+// untaggedGroup is the group of resources that don't have the tag being
+// grouped by, so allocation gaps are easy to spot.
+const untaggedGroup = "untagged"
+
+// unknownGroup is the group of resources whose module, provider or region
+// couldn't be found.
+const unknownGroup = "unknown"
+
+// rootModuleGroup is the group of resources that aren't in a module.
+const rootModuleGroup = "root"
+
+// GroupBy is how the resource costs are rolled up with --group-by.
+type GroupBy struct {
+	Kind   string
+	TagKey string
+}
+
+// ParseGroupBy parses the value of --group-by.
+func ParseGroupBy(s string) (GroupBy, error) {
+	if tagKey, ok := strings.CutPrefix(s, "tag:"); ok {
+		if tagKey == "" {
+			return GroupBy{}, fmt.Errorf("--group-by tag needs a tag key, e.g. tag:team")
+		}
+
+		return GroupBy{Kind: "tag", TagKey: tagKey}, nil
+	}
+
+	switch s {
+	case "module", "resource-type", "provider", "region":
+		return GroupBy{Kind: s}, nil
+	}
+
+	return GroupBy{}, fmt.Errorf("--group-by only supports %s", strings.Join(ValidGroupBys, ", "))
+}
+
+func (g GroupBy) String() string {
+	if g.Kind == "tag" {
+		return "tag:" + g.TagKey
+	}
+
+	return g.Kind
+}
+
+// Title is the column title used for the group names in tables.
+func (g GroupBy) Title() string {
+	switch g.Kind {
+	case "tag":
+		return fmt.Sprintf("Tag: %s", g.TagKey)
+	case "resource-type":
+		return "Resource type"
+	}
+
+	return strings.ToUpper(g.Kind[:1]) + g.Kind[1:]
+}
+
+// CostGroups are the monthly costs of all the projects' resources rolled up
+// by --group-by.
+type CostGroups struct {
+	GroupBy string      `json:"groupBy"`
+	Groups  []CostGroup `json:"groups"`
+}
+
+// CostGroup is the monthly cost of the resources in a group. The past and
+// diff costs are only set for diffs.
+type CostGroup struct {
+	Name            string           `json:"name"`
+	ResourceCount   int              `json:"resourceCount"`
+	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	PastMonthlyCost *decimal.Decimal `json:"pastMonthlyCost,omitempty"`
+	DiffMonthlyCost *decimal.Decimal `json:"diffMonthlyCost,omitempty"`
+}
+
+// costGroupsFromOptions returns the grouped costs for opts.GroupBy, or nil if
+// it isn't set.
+func costGroupsFromOptions(out Root, opts Options) (GroupBy, *CostGroups, error) {
+	if opts.GroupBy == "" {
+		return GroupBy{}, nil, nil
+	}
+
+	groupBy, err := ParseGroupBy(opts.GroupBy)
+	if err != nil {
+		return GroupBy{}, nil, err
+	}
+
+	return groupBy, groupCosts(out, groupBy), nil
+}
+
+// groupCosts rolls up the monthly costs of the resources of all projects
+// into groups, sorted by the monthly cost, highest first. Resources are
+// grouped by their current state, or their past state if they were removed.
+func groupCosts(out Root, groupBy GroupBy) *CostGroups {
+	groups := map[string]*CostGroup{}
+	hasPast := false
+
+	group := func(name string) *CostGroup {
+		g, ok := groups[name]
+		if !ok {
+			g = &CostGroup{Name: name, MonthlyCost: decimalPtr(decimal.Zero)}
+			groups[name] = g
+		}
+
+		return g
+	}
+
+	for _, project := range out.Projects {
+		if project.Breakdown != nil {
+			for _, r := range project.Breakdown.Resources {
+				g := group(groupBy.groupName(r))
+				g.ResourceCount++
+				if r.MonthlyCost != nil {
+					g.MonthlyCost = decimalPtr(g.MonthlyCost.Add(*r.MonthlyCost))
+				}
+			}
+		}
+
+		if project.PastBreakdown == nil {
+			continue
+		}
+		hasPast = true
+
+		for _, r := range project.PastBreakdown.Resources {
+			g := group(groupBy.groupName(r))
+			if project.Breakdown == nil || findResourceByName(project.Breakdown.Resources, r.Name) == nil {
+				g.ResourceCount++
+			}
+
+			if g.PastMonthlyCost == nil {
+				g.PastMonthlyCost = decimalPtr(decimal.Zero)
+			}
+			if r.MonthlyCost != nil {
+				g.PastMonthlyCost = decimalPtr(g.PastMonthlyCost.Add(*r.MonthlyCost))
+			}
+		}
+	}
+
+	result := &CostGroups{GroupBy: groupBy.String(), Groups: make([]CostGroup, 0, len(groups))}
+	for _, g := range groups {
+		if hasPast {
+			if g.PastMonthlyCost == nil {
+				g.PastMonthlyCost = decimalPtr(decimal.Zero)
+			}
+			g.DiffMonthlyCost = decimalPtr(g.MonthlyCost.Sub(*g.PastMonthlyCost))
+		}
+
+		result.Groups = append(result.Groups, *g)
+	}
+
+	sort.Slice(result.Groups, func(i, j int) bool {
+		a, b := result.Groups[i], result.Groups[j]
+		if !a.MonthlyCost.Equal(*b.MonthlyCost) {
+			return a.MonthlyCost.GreaterThan(*b.MonthlyCost)
+		}
+
+		return a.Name < b.Name
+	})
+
+	return result
+}
+
+func (g GroupBy) groupName(r Resource) string {
+	var name string
+
+	switch g.Kind {
+	case "tag":
+		name = ResourceTags(r)[g.TagKey]
+		if name == "" {
+			return untaggedGroup
+		}
+	case "module":
+		name = resourceModule(r.Name)
+		if name == "" {
+			return rootModuleGroup
+		}
+	case "resource-type":
+		name = r.ResourceType
+	case "provider":
+		name = resourceProvider(r)
+	case "region":
+		name = resourceRegion(r)
+	}
+
+	if name == "" {
+		return unknownGroup
+	}
+
+	return name
+}
+
+// ResourceTags returns the tags that apply to the resource: the provider
+// default tags, overridden by the tags propagated to the resource, overridden
+// by the tags set on the resource.
+func ResourceTags(r Resource) map[string]string {
+	tags := map[string]string{}
+
+	for _, t := range []*map[string]string{r.DefaultTags, tagPropagationTags(r.TagPropagation), r.Tags} {
+		if t == nil {
+			continue
+		}
+
+		for k, v := range *t {
+			tags[k] = v
+		}
+	}
+
+	return tags
+}
+
+func tagPropagationTags(p *TagPropagation) *map[string]string {
+	if p == nil {
+		return nil
+	}
+
+	return p.Tags
+}
+
+// resourceModule returns the module address of the resource, e.g.
+// module.vpc.module.subnets, or an empty string for resources in the root
+// module.
+func resourceModule(address string) string {
+	var parts []string
+
+	for _, part := range splitAddress(address) {
+		if len(parts)%2 == 0 && part != "module" {
+			break
+		}
+		parts = append(parts, part)
+	}
+
+	// Drop a trailing "module" without a module name
+	if len(parts)%2 == 1 {
+		parts = parts[:len(parts)-1]
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// splitAddress splits a resource address on the dots that aren't inside an
+// index, e.g. module.a["x.y"].aws_instance.b is split into module,
+// a["x.y"], aws_instance and b.
+func splitAddress(address string) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	inQuotes := false
+
+	for _, c := range address {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == '[' && !inQuotes:
+			depth++
+		case c == ']' && !inQuotes:
+			depth--
+		case c == '.' && !inQuotes && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+
+		current.WriteRune(c)
+	}
+
+	return append(parts, current.String())
+}
+
+// resourceProvider returns the vendor of the first cost component of the
+// resource, or the provider from the resource type if it has none.
+func resourceProvider(r Resource) string {
+	vendorName := firstCostComponentValue(r, func(c CostComponent) string { return c.VendorName })
+	if vendorName != "" {
+		return vendorName
+	}
+
+	for prefix, v := range focusResourceTypePrefixes {
+		if strings.HasPrefix(r.ResourceType, prefix) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// resourceRegion returns the region of the first cost component of the
+// resource that has one.
+func resourceRegion(r Resource) string {
+	return firstCostComponentValue(r, func(c CostComponent) string { return c.Region })
+}
+
+func firstCostComponentValue(r Resource, f func(c CostComponent) string) string {
+	for _, c := range r.CostComponents {
+		if v := f(c); v != "" {
+			return v
+		}
+	}
+
+	for _, s := range r.SubResources {
+		if v := firstCostComponentValue(s, f); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// tableForCostGroups returns a table of the grouped costs. The past cost and
+// change columns are only shown for diffs.
+func tableForCostGroups(currency string, groupBy GroupBy, groups *CostGroups, showDiff bool) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+
+	if showDiff {
+		t.AppendHeader(table.Row{groupBy.Title(), "Resources", "Baseline cost", "Monthly cost", "Total change"})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 1, WidthMin: 30},
+			{Number: 2, Align: text.AlignRight},
+			{Number: 3, WidthMin: 10, Align: text.AlignRight},
+			{Number: 4, WidthMin: 10, Align: text.AlignRight},
+			{Number: 5, WidthMin: 10, Align: text.AlignRight},
+		})
+	} else {
+		t.AppendHeader(table.Row{groupBy.Title(), "Resources", "Monthly cost"})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 1, WidthMin: 30},
+			{Number: 2, Align: text.AlignRight},
+			{Number: 3, WidthMin: 10, Align: text.AlignRight},
+		})
+	}
+
+	for _, g := range groups.Groups {
+		if showDiff {
+			t.AppendRow(table.Row{
+				truncateMiddle(g.Name, 64, "..."),
+				g.ResourceCount,
+				FormatCost2DP(currency, g.PastMonthlyCost),
+				FormatCost2DP(currency, g.MonthlyCost),
+				formatCostChange(currency, g.DiffMonthlyCost),
+			})
+			continue
+		}
+
+		t.AppendRow(table.Row{
+			truncateMiddle(g.Name, 64, "..."),
+			g.ResourceCount,
+			FormatCost2DP(currency, g.MonthlyCost),
+		})
+	}
+
+	return t.Render()
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	g, err := ParseGroupBy("tag:team")
+	require.NoError(t, err)
+	assert.Equal(t, GroupBy{Kind: "tag", TagKey: "team"}, g)
+	assert.Equal(t, "Tag: team", g.Title())
+
+	g, err = ParseGroupBy("resource-type")
+	require.NoError(t, err)
+	assert.Equal(t, "Resource type", g.Title())
+
+	_, err = ParseGroupBy("tag:")
+	assert.Error(t, err)
+
+	_, err = ParseGroupBy("team")
+	assert.EqualError(t, err, "--group-by only supports tag:<key>, module, resource-type, provider, region")
+}
+
+func TestResourceModule(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"aws_instance.web", ""},
+		{"module.vpc.aws_subnet.private[0]", "module.vpc"},
+		{"module.db.module.primary.aws_db_instance.this", "module.db.module.primary"},
+		{`module.app["eu.west"].aws_instance.web`, `module.app["eu.west"]`},
+		{"module.app[0].module.lb.aws_lb.this", "module.app[0].module.lb"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, resourceModule(tt.address), tt.address)
+	}
+}
+
+func TestGroupCosts(t *testing.T) {
+	d := func(v int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(v)) }
+	propagatedFrom := "aws_ecs_service.app"
+
+	out := Root{
+		Currency: "USD",
+		Projects: Projects{
+			{
+				Name: "infracost/infracost/dev",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", ResourceType: "aws_instance", Tags: &map[string]string{"team": "web"}, MonthlyCost: d(10)},
+						{Name: "aws_instance.old", ResourceType: "aws_instance", Tags: &map[string]string{"team": "data"}, MonthlyCost: d(5)},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", ResourceType: "aws_instance", Tags: &map[string]string{"team": "web"}, MonthlyCost: d(20)},
+						{Name: "aws_db_instance.db", ResourceType: "aws_db_instance", DefaultTags: &map[string]string{"team": "data"}, MonthlyCost: d(30)},
+						{
+							Name:         "aws_ecs_task_definition.app",
+							ResourceType: "aws_ecs_task_definition",
+							DefaultTags:  &map[string]string{"team": "platform"},
+							TagPropagation: &TagPropagation{
+								From: &propagatedFrom,
+								Tags: &map[string]string{"team": "web"},
+							},
+							MonthlyCost: d(7),
+						},
+						{Name: "aws_s3_bucket.logs", ResourceType: "aws_s3_bucket", MonthlyCost: d(1)},
+					},
+				},
+			},
+		},
+	}
+
+	groups := groupCosts(out, GroupBy{Kind: "tag", TagKey: "team"})
+	assert.Equal(t, "tag:team", groups.GroupBy)
+	actual := make([][]string, 0, len(groups.Groups))
+	for _, g := range groups.Groups {
+		actual = append(actual, []string{g.Name, fmt.Sprint(g.ResourceCount), g.MonthlyCost.String(), g.PastMonthlyCost.String(), g.DiffMonthlyCost.String()})
+	}
+
+	assert.Equal(t, [][]string{
+		{"data", "2", "30", "5", "25"},
+		{"web", "2", "27", "10", "17"},
+		{"untagged", "1", "1", "0", "1"},
+	}, actual)
+}
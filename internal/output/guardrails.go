@@ -0,0 +1,298 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// GuardrailExitCode is the exit code used when a run breaches one or more
+// guardrails. It is different to the generic error exit code so that CI
+// pipelines can tell a cost guardrail apart from a failed run.
+const GuardrailExitCode = 3
+
+const (
+	guardrailThresholdMonthlyCost            = "max_monthly_cost"
+	guardrailThresholdMonthlyIncrease        = "max_monthly_increase"
+	guardrailThresholdMonthlyIncreasePercent = "max_monthly_increase_percent"
+)
+
+// GuardrailViolation is a single guardrail threshold that has been exceeded.
+type GuardrailViolation struct {
+	Name         string          `json:"name,omitempty"`
+	Scope        string          `json:"scope"`
+	ProjectName  string          `json:"projectName,omitempty"`
+	ResourceType string          `json:"resourceType,omitempty"`
+	CostType     string          `json:"costType"`
+	Threshold    string          `json:"threshold"`
+	Limit        decimal.Decimal `json:"limit"`
+	Actual       decimal.Decimal `json:"actual"`
+	Message      string          `json:"message"`
+}
+
+// GuardrailViolations defines a list of guardrail violations found when evaluating a run.
+type GuardrailViolations []GuardrailViolation
+
+// Error implements the Error interface returning the violations as a single message that can be used in stderr.
+func (g GuardrailViolations) Error() string {
+	if len(g) == 0 {
+		return ""
+	}
+
+	out := &strings.Builder{}
+	out.WriteString("Guardrail check failed:\n\n")
+
+	for _, v := range g {
+		out.WriteString(" - " + v.Message + "\n")
+	}
+
+	return out.String()
+}
+
+// ExitCode returns the exit code that the CLI should use when guardrails are breached.
+func (g GuardrailViolations) ExitCode() int {
+	return GuardrailExitCode
+}
+
+// guardrailCosts are the current and past monthly costs that a guardrail is checked against.
+type guardrailCosts struct {
+	current decimal.Decimal
+	past    decimal.Decimal
+	hasPast bool
+}
+
+// EvaluateGuardrails checks the global and project guardrails against the
+// costs in the Root and returns any that are breached. Project guardrails are
+// matched to the output projects by name, or by path if the project has no name.
+func EvaluateGuardrails(r Root, global []config.Guardrail, projects []*config.Project) GuardrailViolations {
+	var violations GuardrailViolations
+
+	for _, g := range global {
+		if g.ScopeOrDefault(config.GuardrailScopeTotal) == config.GuardrailScopeProject {
+			for _, p := range r.Projects {
+				violations = append(violations, checkGuardrail(r.Currency, g, p.Label(), costsForProjects(Projects{p}, g))...)
+			}
+			continue
+		}
+
+		// total and resource_type guardrails are checked against the sum of all projects.
+		violations = append(violations, checkGuardrail(r.Currency, g, "", costsForProjects(r.Projects, g))...)
+	}
+
+	for _, projectCfg := range projects {
+		if len(projectCfg.Guardrails) == 0 {
+			continue
+		}
+
+		for _, p := range r.Projects {
+			if !guardrailProjectMatches(projectCfg, p) {
+				continue
+			}
+
+			for _, g := range projectCfg.Guardrails {
+				violations = append(violations, checkGuardrail(r.Currency, g, p.Label(), costsForProjects(Projects{p}, g))...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func guardrailProjectMatches(projectCfg *config.Project, p Project) bool {
+	if projectCfg.Name != "" {
+		return projectCfg.Name == p.Name
+	}
+
+	if p.Metadata == nil {
+		return false
+	}
+
+	return filepath.Clean(projectCfg.Path) == filepath.Clean(p.Metadata.Path)
+}
+
+func costsForProjects(projects Projects, g config.Guardrail) guardrailCosts {
+	var costs guardrailCosts
+
+	for _, p := range projects {
+		costs.current = costs.current.Add(guardrailBreakdownCost(p.Breakdown, g))
+
+		if p.PastBreakdown != nil {
+			costs.hasPast = true
+			costs.past = costs.past.Add(guardrailBreakdownCost(p.PastBreakdown, g))
+		}
+	}
+
+	return costs
+}
+
+func guardrailBreakdownCost(b *Breakdown, g config.Guardrail) decimal.Decimal {
+	if b == nil {
+		return decimal.Zero
+	}
+
+	if g.ResourceType != "" {
+		total := decimal.Zero
+		for _, r := range b.Resources {
+			if r.ResourceType != g.ResourceType {
+				continue
+			}
+
+			total = total.Add(guardrailCost(g.CostTypeOrDefault(), r.MonthlyCost, r.MonthlyUsageCost))
+		}
+
+		return total
+	}
+
+	return guardrailCost(g.CostTypeOrDefault(), b.TotalMonthlyCost, b.TotalMonthlyUsageCost)
+}
+
+func guardrailCost(costType string, monthlyCost, monthlyUsageCost *decimal.Decimal) decimal.Decimal {
+	var d *decimal.Decimal
+
+	switch costType {
+	case config.GuardrailCostTypeUsage:
+		d = monthlyUsageCost
+	case config.GuardrailCostTypeBaseline:
+		d = (&Breakdown{TotalMonthlyCost: monthlyCost, TotalMonthlyUsageCost: monthlyUsageCost}).TotalMonthlyBaselineCost()
+	default:
+		d = monthlyCost
+	}
+
+	if d == nil {
+		return decimal.Zero
+	}
+
+	return *d
+}
+
+func checkGuardrail(currency string, g config.Guardrail, projectName string, costs guardrailCosts) GuardrailViolations {
+	var violations GuardrailViolations
+
+	scope := config.GuardrailScopeTotal
+	if projectName != "" {
+		scope = config.GuardrailScopeProject
+	}
+	if g.ResourceType != "" {
+		scope = config.GuardrailScopeResourceType
+	}
+
+	newViolation := func(threshold string, limit, actual decimal.Decimal, msg string) GuardrailViolation {
+		if g.Name != "" {
+			msg = g.Name + ": " + msg
+		}
+
+		return GuardrailViolation{
+			Name:         g.Name,
+			Scope:        scope,
+			ProjectName:  projectName,
+			ResourceType: g.ResourceType,
+			CostType:     g.CostTypeOrDefault(),
+			Threshold:    threshold,
+			Limit:        limit,
+			Actual:       actual,
+			Message:      msg,
+		}
+	}
+
+	if g.MaxMonthlyCost != nil {
+		limit := decimal.NewFromFloat(*g.MaxMonthlyCost)
+		if costs.current.GreaterThan(limit) {
+			violations = append(violations, newViolation(guardrailThresholdMonthlyCost, limit, costs.current,
+				fmt.Sprintf("%s is %s, which is above the limit of %s",
+					guardrailSubject(g, projectName, false),
+					FormatCost2DP(currency, &costs.current),
+					FormatCost2DP(currency, &limit),
+				),
+			))
+		}
+	}
+
+	if !costs.hasPast {
+		return violations
+	}
+
+	increase := costs.current.Sub(costs.past)
+
+	if g.MaxMonthlyIncrease != nil {
+		limit := decimal.NewFromFloat(*g.MaxMonthlyIncrease)
+		if increase.GreaterThan(limit) {
+			violations = append(violations, newViolation(guardrailThresholdMonthlyIncrease, limit, increase,
+				fmt.Sprintf("%s is %s, which is above the limit of %s",
+					guardrailSubject(g, projectName, true),
+					FormatCost2DP(currency, &increase),
+					FormatCost2DP(currency, &limit),
+				),
+			))
+		}
+	}
+
+	if g.MaxMonthlyIncreasePercent != nil && costs.past.IsPositive() {
+		limit := decimal.NewFromFloat(*g.MaxMonthlyIncreasePercent)
+		percent := increase.Div(costs.past).Mul(decimal.NewFromInt(100))
+		if percent.GreaterThan(limit) {
+			violations = append(violations, newViolation(guardrailThresholdMonthlyIncreasePercent, limit, percent,
+				fmt.Sprintf("%s is %s, which is above the limit of %s",
+					guardrailSubject(g, projectName, true),
+					formatGuardrailPercent(percent),
+					formatGuardrailPercent(limit),
+				),
+			))
+		}
+	}
+
+	return violations
+}
+
+// guardrailSubject returns a description of the cost that the guardrail checks,
+// e.g. "Monthly cost increase of aws_instance resources in project my-project".
+func guardrailSubject(g config.Guardrail, projectName string, increase bool) string {
+	s := "Monthly cost"
+	switch g.CostTypeOrDefault() {
+	case config.GuardrailCostTypeBaseline:
+		s = "Monthly baseline cost"
+	case config.GuardrailCostTypeUsage:
+		s = "Monthly usage cost"
+	}
+
+	if g.ResourceType == "" && projectName == "" {
+		s = "Total " + strings.ToLower(s)
+	}
+
+	if increase {
+		s += " increase"
+	}
+
+	if g.ResourceType != "" {
+		s += fmt.Sprintf(" of %s resources", g.ResourceType)
+	}
+
+	if projectName != "" {
+		s += fmt.Sprintf(" in project %s", projectName)
+	}
+
+	return s
+}
+
+func formatGuardrailPercent(d decimal.Decimal) string {
+	return d.Round(1).String() + "%"
+}
+
+// guardrailsMessage returns the list of guardrail violations that is shown at the
+// end of the table and diff outputs. An empty string is returned if there are none.
+func guardrailsMessage(out Root) string {
+	if len(out.GuardrailViolations) == 0 {
+		return ""
+	}
+
+	s := ui.BoldString("Guardrails failed:") + "\n"
+	for _, v := range out.GuardrailViolations {
+		s += " - " + v.Message + "\n"
+	}
+
+	return s
+}
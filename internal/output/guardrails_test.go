@@ -0,0 +1,174 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func guardrailTestRoot() Root {
+	return Root{
+		Currency: "USD",
+		Projects: Projects{
+			{
+				Name:     "infracost/infracost/dev",
+				Metadata: &schema.ProjectMetadata{Path: "dev"},
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost:      decimalPtr(decimal.NewFromInt(100)),
+					TotalMonthlyUsageCost: decimalPtr(decimal.NewFromInt(20)),
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							ResourceType:     "aws_instance",
+							MonthlyCost:      decimalPtr(decimal.NewFromInt(150)),
+							MonthlyUsageCost: decimalPtr(decimal.NewFromInt(0)),
+						},
+						{
+							ResourceType:     "aws_lambda_function",
+							MonthlyCost:      decimalPtr(decimal.NewFromInt(50)),
+							MonthlyUsageCost: decimalPtr(decimal.NewFromInt(50)),
+						},
+					},
+					TotalMonthlyCost:      decimalPtr(decimal.NewFromInt(200)),
+					TotalMonthlyUsageCost: decimalPtr(decimal.NewFromInt(50)),
+				},
+			},
+			{
+				Name:     "infracost/infracost/prod",
+				Metadata: &schema.ProjectMetadata{Path: "prod"},
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(1000)),
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							ResourceType: "aws_instance",
+							MonthlyCost:  decimalPtr(decimal.NewFromInt(1000)),
+						},
+					},
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(1000)),
+				},
+			},
+		},
+	}
+}
+
+func TestEvaluateGuardrails(t *testing.T) {
+	tests := []struct {
+		name     string
+		global   []config.Guardrail
+		projects []*config.Project
+		expected []string
+	}{
+		{
+			name:   "total cost under limit",
+			global: []config.Guardrail{{MaxMonthlyCost: floatPtr(1500)}},
+		},
+		{
+			name:     "total cost over limit",
+			global:   []config.Guardrail{{Name: "Budget", MaxMonthlyCost: floatPtr(1000)}},
+			expected: []string{"Budget: Total monthly cost is $1,200.00, which is above the limit of $1,000.00"},
+		},
+		{
+			name:   "total increase over limits",
+			global: []config.Guardrail{{MaxMonthlyIncrease: floatPtr(50), MaxMonthlyIncreasePercent: floatPtr(5)}},
+			expected: []string{
+				"Total monthly cost increase is $100.00, which is above the limit of $50.00",
+				"Total monthly cost increase is 9.1%, which is above the limit of 5%",
+			},
+		},
+		{
+			name:   "each project is checked for project scope",
+			global: []config.Guardrail{{Scope: "project", MaxMonthlyIncreasePercent: floatPtr(50)}},
+			expected: []string{
+				"Monthly cost increase in project infracost/infracost/dev is 100%, which is above the limit of 50%",
+			},
+		},
+		{
+			name:   "resource type costs are summed across projects",
+			global: []config.Guardrail{{ResourceType: "aws_instance", MaxMonthlyCost: floatPtr(1000)}},
+			expected: []string{
+				"Monthly cost of aws_instance resources is $1,150.00, which is above the limit of $1,000.00",
+			},
+		},
+		{
+			name: "baseline and usage costs are checked separately",
+			global: []config.Guardrail{
+				{Name: "Baseline", CostType: "baseline", MaxMonthlyIncrease: floatPtr(70)},
+				{Name: "Usage", CostType: "usage", MaxMonthlyIncrease: floatPtr(20)},
+			},
+			expected: []string{
+				"Usage: Total monthly usage cost increase is $30.00, which is above the limit of $20.00",
+			},
+		},
+		{
+			name: "project guardrails match by path",
+			projects: []*config.Project{
+				{
+					Path:       "./prod",
+					Guardrails: []config.Guardrail{{MaxMonthlyCost: floatPtr(500)}},
+				},
+				{
+					Path:       "staging",
+					Guardrails: []config.Guardrail{{MaxMonthlyCost: floatPtr(1)}},
+				},
+			},
+			expected: []string{
+				"Monthly cost in project infracost/infracost/prod is $1,000.00, which is above the limit of $500.00",
+			},
+		},
+		{
+			name: "project guardrails match by name",
+			projects: []*config.Project{
+				{
+					Path:       "dev",
+					Name:       "infracost/infracost/dev",
+					Guardrails: []config.Guardrail{{ResourceType: "aws_lambda_function", CostType: "usage", MaxMonthlyCost: floatPtr(10)}},
+				},
+			},
+			expected: []string{
+				"Monthly usage cost of aws_lambda_function resources in project infracost/infracost/dev is $50.00, which is above the limit of $10.00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := EvaluateGuardrails(guardrailTestRoot(), tt.global, tt.projects)
+
+			var actual []string
+			for _, v := range violations {
+				actual = append(actual, v.Message)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestEvaluateGuardrailsSkipsIncreaseWithoutPastCosts(t *testing.T) {
+	r := guardrailTestRoot()
+	for i := range r.Projects {
+		r.Projects[i].PastBreakdown = nil
+	}
+
+	violations := EvaluateGuardrails(r, []config.Guardrail{{MaxMonthlyIncrease: floatPtr(0)}}, nil)
+	assert.Empty(t, violations)
+}
+
+func TestGuardrailViolationsError(t *testing.T) {
+	violations := EvaluateGuardrails(guardrailTestRoot(), []config.Guardrail{{Name: "Budget", MaxMonthlyCost: floatPtr(1000)}}, nil)
+	require.Len(t, violations, 1)
+
+	assert.Equal(t, "Guardrail check failed:\n\n - Budget: Total monthly cost is $1,200.00, which is above the limit of $1,000.00\n", violations.Error())
+	assert.Equal(t, GuardrailExitCode, violations.ExitCode())
+}
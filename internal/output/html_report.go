@@ -0,0 +1,258 @@
+package output
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// htmlReportData is the data embedded in the html-report format. It is a
+// flattened view of the output that the bundled script can render without
+// having to understand the full Infracost JSON.
+type htmlReportData struct {
+	Currency             string               `json:"currency"`
+	TimeGenerated        time.Time            `json:"timeGenerated"`
+	HasDiff              bool                 `json:"hasDiff"`
+	TotalMonthlyCost     *float64             `json:"totalMonthlyCost"`
+	PastTotalMonthlyCost *float64             `json:"pastTotalMonthlyCost"`
+	DiffTotalMonthlyCost *float64             `json:"diffTotalMonthlyCost"`
+	TagKeys              []string             `json:"tagKeys"`
+	Projects             []htmlReportProject  `json:"projects"`
+	Resources            []htmlReportResource `json:"resources"`
+}
+
+type htmlReportProject struct {
+	Name            string   `json:"name"`
+	Path            string   `json:"path"`
+	ModulePath      string   `json:"modulePath"`
+	Workspace       string   `json:"workspace"`
+	ResourceCount   int      `json:"resourceCount"`
+	MonthlyCost     *float64 `json:"monthlyCost"`
+	PastMonthlyCost *float64 `json:"pastMonthlyCost"`
+	DiffMonthlyCost *float64 `json:"diffMonthlyCost"`
+}
+
+type htmlReportResource struct {
+	Project      string            `json:"project"`
+	Name         string            `json:"name"`
+	ResourceType string            `json:"resourceType"`
+	Module       string            `json:"module"`
+	Provider     string            `json:"provider"`
+	Tags         map[string]string `json:"tags"`
+	// Status is added, removed or changed for diffs, otherwise it is empty.
+	Status string `json:"status"`
+	// TrackingChange is set for diffs if the resource was moved, imported or
+	// removed from the state, see schema.TrackingChangeMoved.
+	TrackingChange  string                    `json:"trackingChange,omitempty"`
+	MovedFrom       string                    `json:"movedFrom,omitempty"`
+	MonthlyCost     *float64                  `json:"monthlyCost"`
+	PastMonthlyCost *float64                  `json:"pastMonthlyCost"`
+	DiffMonthlyCost *float64                  `json:"diffMonthlyCost"`
+	CostComponents  []htmlReportCostComponent `json:"costComponents"`
+}
+
+type htmlReportCostComponent struct {
+	Name            string   `json:"name"`
+	SubResource     string   `json:"subresource"`
+	Unit            string   `json:"unit"`
+	Price           *float64 `json:"price"`
+	MonthlyQuantity *float64 `json:"monthlyQuantity"`
+	MonthlyCost     *float64 `json:"monthlyCost"`
+	PastMonthlyCost *float64 `json:"pastMonthlyCost"`
+	DiffMonthlyCost *float64 `json:"diffMonthlyCost"`
+}
+
+// ToHTMLReport returns a single self-contained HTML file with the output
+// embedded as JSON and a bundled script that renders sortable and searchable
+// tables and charts of the costs, so it can be explored offline.
+func ToHTMLReport(out Root, opts Options) ([]byte, error) {
+	tmpl, err := template.ParseFS(templatesFS, "templates/html-report.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := templatesFS.ReadFile("templates/html-report.js")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Data   htmlReportData
+		Script template.JS
+	}{
+		Data:   newHTMLReportData(out),
+		Script: template.JS(script), // nolint:gosec
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newHTMLReportData(out Root) htmlReportData {
+	data := htmlReportData{
+		Currency:             out.Currency,
+		TimeGenerated:        out.TimeGenerated,
+		TotalMonthlyCost:     htmlReportFloat(out.TotalMonthlyCost),
+		PastTotalMonthlyCost: htmlReportFloat(out.PastTotalMonthlyCost),
+		DiffTotalMonthlyCost: htmlReportFloat(out.DiffTotalMonthlyCost),
+		Projects:             make([]htmlReportProject, 0, len(out.Projects)),
+		Resources:            []htmlReportResource{},
+	}
+
+	tagKeys := map[string]bool{}
+
+	for i := range out.Projects {
+		project := &out.Projects[i]
+		if project.Diff != nil {
+			data.HasDiff = true
+		}
+
+		p := htmlReportProject{Name: project.Label()}
+		if project.Metadata != nil {
+			p.Path = project.Metadata.Path
+			p.ModulePath = project.Metadata.TerraformModulePath
+			p.Workspace = project.Metadata.WorkspaceLabel()
+		}
+		if project.Breakdown != nil {
+			p.MonthlyCost = htmlReportFloat(project.Breakdown.TotalMonthlyCost)
+		}
+		if project.PastBreakdown != nil {
+			p.PastMonthlyCost = htmlReportFloat(project.PastBreakdown.TotalMonthlyCost)
+		}
+		if project.Diff != nil {
+			p.DiffMonthlyCost = htmlReportFloat(project.Diff.TotalMonthlyCost)
+		}
+
+		resources := htmlReportResources(project)
+		for _, r := range resources {
+			for k := range r.Tags {
+				tagKeys[k] = true
+			}
+		}
+
+		p.ResourceCount = len(resources)
+		data.Projects = append(data.Projects, p)
+		data.Resources = append(data.Resources, resources...)
+	}
+
+	data.TagKeys = make([]string, 0, len(tagKeys))
+	for k := range tagKeys {
+		data.TagKeys = append(data.TagKeys, k)
+	}
+	sort.Strings(data.TagKeys)
+
+	return data
+}
+
+// htmlReportResources returns the current resources of the project followed
+// by the resources that were removed, with their cost components.
+func htmlReportResources(project *Project) []htmlReportResource {
+	var current, past, diff []Resource
+	if project.Breakdown != nil {
+		current = project.Breakdown.Resources
+	}
+	if project.PastBreakdown != nil {
+		past = project.PastBreakdown.Resources
+	}
+	if project.Diff != nil {
+		diff = project.Diff.Resources
+	}
+
+	componentsByResource := map[string][]htmlReportCostComponent{}
+	for _, row := range projectCostComponentRows(project) {
+		componentsByResource[row.resource] = append(componentsByResource[row.resource], htmlReportCostComponent{
+			Name:            row.costComponent,
+			SubResource:     row.subresource,
+			Unit:            row.unit,
+			Price:           htmlReportFloat(row.price),
+			MonthlyQuantity: htmlReportFloat(row.monthlyQuantity),
+			MonthlyCost:     htmlReportFloat(row.monthlyCost),
+			PastMonthlyCost: htmlReportFloat(row.pastMonthlyCost),
+			DiffMonthlyCost: htmlReportFloat(row.diffMonthlyCost),
+		})
+	}
+
+	movedFrom := map[string]bool{}
+	for _, d := range diff {
+		if d.MovedFrom != "" {
+			movedFrom[d.MovedFrom] = true
+		}
+	}
+
+	newResource := func(r Resource, status string) htmlReportResource {
+		res := htmlReportResource{
+			Project:        project.Label(),
+			Name:           r.Name,
+			ResourceType:   r.ResourceType,
+			Module:         resourceModule(r.Name),
+			Provider:       resourceProvider(r),
+			Tags:           ResourceTags(r),
+			Status:         status,
+			CostComponents: componentsByResource[r.Name],
+		}
+
+		if status != "removed" {
+			res.MonthlyCost = htmlReportFloat(r.MonthlyCost)
+		}
+		d := findResourceByName(diff, r.Name)
+		if d != nil {
+			res.DiffMonthlyCost = htmlReportFloat(d.MonthlyCost)
+			res.TrackingChange = d.TrackingChange
+			res.MovedFrom = d.MovedFrom
+		}
+		if status == "removed" {
+			res.PastMonthlyCost = htmlReportFloat(r.MonthlyCost)
+		} else if d != nil && d.MovedFrom != "" {
+			if p := findResourceByName(past, d.MovedFrom); p != nil {
+				res.PastMonthlyCost = htmlReportFloat(p.MonthlyCost)
+			}
+		} else if p := findResourceByName(past, r.Name); p != nil {
+			res.PastMonthlyCost = htmlReportFloat(p.MonthlyCost)
+		}
+
+		return res
+	}
+
+	resources := make([]htmlReportResource, 0, len(current))
+	for _, r := range current {
+		status := ""
+		if project.Diff != nil {
+			d := findResourceByName(diff, r.Name)
+			switch {
+			case d != nil && d.MovedFrom != "":
+				status = "changed"
+			case findResourceByName(past, r.Name) == nil:
+				status = "added"
+			case d != nil:
+				status = "changed"
+			}
+		}
+
+		resources = append(resources, newResource(r, status))
+	}
+
+	for _, r := range past {
+		if movedFrom[r.Name] || findResourceByName(current, r.Name) != nil {
+			continue
+		}
+
+		resources = append(resources, newResource(r, "removed"))
+	}
+
+	return resources
+}
+
+func htmlReportFloat(d *decimal.Decimal) *float64 {
+	if d == nil {
+		return nil
+	}
+
+	f := d.InexactFloat64()
+	return &f
+}
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var htmlReportDataPattern = regexp.MustCompile(`(?s)<script id="infracost-data" type="application/json">(.*?)</script>`)
+
+func TestToHTMLReport(t *testing.T) {
+	d := func(v float64) *decimal.Decimal { return decimalPtr(decimal.NewFromFloat(v)) }
+	tags := map[string]string{"team": "</script><script>alert(1)</script>"}
+
+	out := Root{
+		Currency:             "USD",
+		TotalMonthlyCost:     d(30),
+		PastTotalMonthlyCost: d(25),
+		DiffTotalMonthlyCost: d(5),
+		Projects: Projects{
+			{
+				Name: "infracost/infracost/dev",
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: d(25),
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							ResourceType:   "aws_instance",
+							MonthlyCost:    d(10),
+							CostComponents: []CostComponent{{Name: "Instance usage", Price: decimal.NewFromFloat(0.01), MonthlyQuantity: d(1000), MonthlyCost: d(10)}},
+						},
+						{
+							Name:           "aws_instance.old",
+							ResourceType:   "aws_instance",
+							MonthlyCost:    d(15),
+							CostComponents: []CostComponent{{Name: "Instance usage", MonthlyCost: d(15)}},
+						},
+						{
+							Name:         "aws_instance.api",
+							ResourceType: "aws_instance",
+							MonthlyCost:  d(5),
+						},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: d(30),
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							ResourceType:   "aws_instance",
+							Tags:           &tags,
+							MonthlyCost:    d(20),
+							CostComponents: []CostComponent{{Name: "Instance usage", Price: decimal.NewFromFloat(0.02), MonthlyQuantity: d(1000), MonthlyCost: d(20)}},
+						},
+						{
+							Name:         "module.db.aws_db_instance.main",
+							ResourceType: "aws_db_instance",
+							MonthlyCost:  d(10),
+						},
+						{
+							Name:         "module.api.aws_instance.this",
+							ResourceType: "aws_instance",
+							MonthlyCost:  d(5),
+						},
+					},
+				},
+				Diff: &Breakdown{
+					TotalMonthlyCost: d(5),
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: d(10), CostComponents: []CostComponent{{Name: "Instance usage", MonthlyCost: d(10)}}},
+						{Name: "module.db.aws_db_instance.main", MonthlyCost: d(10)},
+						{Name: "aws_instance.old", MonthlyCost: d(-15)},
+						{Name: "module.api.aws_instance.this", MonthlyCost: d(0), TrackingChange: "moved", MovedFrom: "aws_instance.api"},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := ToHTMLReport(out, Options{})
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(b), "<script>alert(1)")
+
+	m := htmlReportDataPattern.FindSubmatch(b)
+	require.NotNil(t, m)
+
+	var data htmlReportData
+	require.NoError(t, json.Unmarshal(m[1], &data))
+
+	assert.True(t, data.HasDiff)
+	assert.Equal(t, []string{"team"}, data.TagKeys)
+	require.Len(t, data.Projects, 1)
+	assert.Equal(t, 4, data.Projects[0].ResourceCount)
+	assert.InDelta(t, 5, *data.Projects[0].DiffMonthlyCost, 0.001)
+
+	require.Len(t, data.Resources, 4)
+
+	web := data.Resources[0]
+	assert.Equal(t, "aws_instance.web", web.Name)
+	assert.Equal(t, "changed", web.Status)
+	assert.Equal(t, "</script><script>alert(1)</script>", web.Tags["team"])
+	assert.InDelta(t, 20, *web.MonthlyCost, 0.001)
+	assert.InDelta(t, 10, *web.PastMonthlyCost, 0.001)
+	assert.InDelta(t, 10, *web.DiffMonthlyCost, 0.001)
+	require.Len(t, web.CostComponents, 1)
+	assert.InDelta(t, 0.02, *web.CostComponents[0].Price, 0.0001)
+	assert.InDelta(t, 10, *web.CostComponents[0].PastMonthlyCost, 0.001)
+
+	db := data.Resources[1]
+	assert.Equal(t, "added", db.Status)
+	assert.Equal(t, "module.db", db.Module)
+	assert.Nil(t, db.PastMonthlyCost)
+
+	api := data.Resources[2]
+	assert.Equal(t, "module.api.aws_instance.this", api.Name)
+	assert.Equal(t, "changed", api.Status)
+	assert.Equal(t, "moved", api.TrackingChange)
+	assert.Equal(t, "aws_instance.api", api.MovedFrom)
+	assert.InDelta(t, 5, *api.PastMonthlyCost, 0.001)
+
+	old := data.Resources[3]
+	assert.Equal(t, "aws_instance.old", old.Name)
+	assert.Equal(t, "removed", old.Status)
+	assert.Nil(t, old.MonthlyCost)
+	assert.InDelta(t, 15, *old.PastMonthlyCost, 0.001)
+	assert.InDelta(t, -15, *old.DiffMonthlyCost, 0.001)
+}
+
+func TestToHTMLReportBreakdown(t *testing.T) {
+	out := Root{
+		Currency: "EUR",
+		Projects: Projects{
+			{
+				Name: "infracost/infracost/dev",
+				Breakdown: &Breakdown{
+					Resources: []Resource{{Name: "aws_instance.web", ResourceType: "aws_instance"}},
+				},
+			},
+		},
+	}
+
+	b, err := ToHTMLReport(out, Options{})
+	require.NoError(t, err)
+
+	m := htmlReportDataPattern.FindSubmatch(b)
+	require.NotNil(t, m)
+
+	var data htmlReportData
+	require.NoError(t, json.Unmarshal(m[1], &data))
+
+	assert.False(t, data.HasDiff)
+	assert.Equal(t, "EUR", data.Currency)
+	assert.Equal(t, []string{}, data.TagKeys)
+	require.Len(t, data.Resources, 1)
+	assert.Equal(t, "", data.Resources[0].Status)
+	assert.Equal(t, "aws", data.Resources[0].Provider)
+}
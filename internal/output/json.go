@@ -5,5 +5,13 @@ import (
 )
 
 func ToJSON(out Root, opts Options) ([]byte, error) {
+	_, costGroups, err := costGroupsFromOptions(out, opts)
+	if err != nil {
+		return nil, err
+	}
+	if costGroups != nil {
+		out.CostGroups = costGroups
+	}
+
 	return json.Marshal(out)
 }
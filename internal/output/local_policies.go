@@ -0,0 +1,169 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// AddLocalPolicies adds a failing check for each policy that was evaluated
+// locally and has been stored in the project metadata. Resources that violate
+// the same policy in multiple projects are grouped together.
+func (po *PolicyOutput) AddLocalPolicies(projects Projects) {
+	var checks []*PolicyCheckOutput
+	byID := map[string]*PolicyCheckOutput{}
+
+	for _, p := range projects {
+		if p.Metadata == nil {
+			continue
+		}
+
+		resources := map[string]Resource{}
+		if p.Breakdown != nil {
+			for _, r := range p.Breakdown.Resources {
+				resources[r.Name] = r
+			}
+		}
+
+		for _, policy := range p.Metadata.Policies {
+			check, ok := byID[policy.ID]
+			if !ok {
+				check = &PolicyCheckOutput{
+					Name:    policy.Title,
+					Failure: true,
+					Message: policy.Description,
+				}
+				byID[policy.ID] = check
+				checks = append(checks, check)
+			}
+
+			details := localPolicyDetails(policy.ResourceAttributes, policy.Suggested)
+			addLocalPolicyResource(check, resources[policy.Address], policy.Address, policy.ResourceType, details, p.Label())
+		}
+	}
+
+	for _, check := range checks {
+		po.HasFailures = true
+		po.Checks = append(po.Checks, *check)
+	}
+}
+
+func addLocalPolicyResource(check *PolicyCheckOutput, r Resource, address, resourceType string, details []string, projectName string) {
+	var rd *PolicyCheckResourceDetails
+	for i := range check.ResourceDetails {
+		if check.ResourceDetails[i].Address == address {
+			rd = &check.ResourceDetails[i]
+			break
+		}
+	}
+
+	if rd == nil {
+		filename, _ := r.Metadata["filename"].(string)
+		line, _ := r.Metadata["startLine"].(float64)
+
+		check.ResourceDetails = append(check.ResourceDetails, PolicyCheckResourceDetails{
+			Address:      address,
+			ResourceType: resourceType,
+			Path:         filename,
+			Line:         int(line),
+		})
+		rd = &check.ResourceDetails[len(check.ResourceDetails)-1]
+	}
+
+	for i, v := range rd.Violations {
+		if reflect.DeepEqual(v.Details, details) {
+			rd.Violations[i].ProjectNames = append(rd.Violations[i].ProjectNames, projectName)
+			return
+		}
+	}
+
+	rd.Violations = append(rd.Violations, PolicyCheckViolations{
+		Details:      details,
+		ProjectNames: []string{projectName},
+	})
+}
+
+// localPolicyDetails returns a line for each of the attributes that caused the
+// policy to fail, followed by the suggestion if there is one.
+func localPolicyDetails(rawAttributes json.RawMessage, suggested string) []string {
+	var attributes map[string]interface{}
+	_ = json.Unmarshal(rawAttributes, &attributes)
+
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	details := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		details = append(details, fmt.Sprintf("`%s`: %s", k, formatLocalPolicyValue(attributes[k])))
+	}
+
+	if suggested != "" {
+		details = append(details, "Suggested: "+suggested)
+	}
+
+	return details
+}
+
+func formatLocalPolicyValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "not set"
+	case string:
+		return val
+	case []interface{}:
+		s := make([]string, 0, len(val))
+		for _, item := range val {
+			s = append(s, formatLocalPolicyValue(item))
+		}
+		return strings.Join(s, ", ")
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+// LocalPolicyFailures returns a failure for each resource that violates a policy
+// that was evaluated locally, so that breakdown and diff runs with failing
+// policies exit with an error.
+func LocalPolicyFailures(out Root) PolicyCheckFailures {
+	var failures PolicyCheckFailures
+	for _, p := range out.Projects {
+		if p.Metadata == nil {
+			continue
+		}
+
+		for _, policy := range p.Metadata.Policies {
+			failures = append(failures, fmt.Sprintf("%s: %s in project %s", policy.Title, policy.Address, p.Label()))
+		}
+	}
+
+	return failures
+}
+
+// localPoliciesMessage returns the list of local policy failures that is shown at
+// the end of the table and diff outputs. An empty string is returned if there are
+// none.
+func localPoliciesMessage(out Root) string {
+	failures := LocalPolicyFailures(out)
+	if len(failures) == 0 {
+		return ""
+	}
+
+	s := ui.BoldString("Policies failed:") + "\n"
+	for _, f := range failures {
+		s += " - " + f + "\n"
+	}
+
+	return s
+}
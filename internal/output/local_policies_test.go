@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestAddLocalPolicies(t *testing.T) {
+	tagPolicy := schema.Policy{
+		ID:                 "cost-center-tag",
+		Title:              "All resources must carry a cost-center tag",
+		ResourceType:       "aws_instance",
+		ResourceAttributes: json.RawMessage(`{"missing_tags":["cost-center","team"]}`),
+		Address:            "aws_instance.web",
+	}
+
+	projects := Projects{
+		{
+			Name: "dev",
+			Metadata: &schema.ProjectMetadata{
+				Policies: schema.Policies{
+					{
+						ID:                 "aws-instance-previous-generation",
+						Title:              "EC2 instances must not use previous generation families",
+						Description:        "Previous generation instances are more expensive",
+						ResourceType:       "aws_instance",
+						ResourceAttributes: json.RawMessage(`{"instance_type":"m4.large"}`),
+						Address:            "aws_instance.web",
+						Suggested:          "Use m5.large",
+					},
+					tagPolicy,
+				},
+			},
+			Breakdown: &Breakdown{
+				Resources: []Resource{
+					{
+						Name:     "aws_instance.web",
+						Metadata: map[string]interface{}{"filename": "main.tf", "startLine": float64(12)},
+					},
+				},
+			},
+		},
+		{
+			Name:     "prod",
+			Metadata: &schema.ProjectMetadata{Policies: schema.Policies{tagPolicy}},
+		},
+	}
+
+	po := NewPolicyOutput(PolicyCheck{})
+	po.AddLocalPolicies(projects)
+
+	assert.True(t, po.HasFailures)
+	assert.Equal(t, []PolicyCheckOutput{
+		{
+			Name:    "EC2 instances must not use previous generation families",
+			Failure: true,
+			Message: "Previous generation instances are more expensive",
+			ResourceDetails: []PolicyCheckResourceDetails{
+				{
+					Address:      "aws_instance.web",
+					ResourceType: "aws_instance",
+					Path:         "main.tf",
+					Line:         12,
+					Violations: []PolicyCheckViolations{
+						{
+							Details:      []string{"`instance_type`: m4.large", "Suggested: Use m5.large"},
+							ProjectNames: []string{"dev"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "All resources must carry a cost-center tag",
+			Failure: true,
+			ResourceDetails: []PolicyCheckResourceDetails{
+				{
+					Address:      "aws_instance.web",
+					ResourceType: "aws_instance",
+					Path:         "main.tf",
+					Line:         12,
+					Violations: []PolicyCheckViolations{
+						{
+							Details:      []string{"`missing_tags`: cost-center, team"},
+							ProjectNames: []string{"dev", "prod"},
+						},
+					},
+				},
+			},
+		},
+	}, po.Checks)
+}
+
+func TestAddLocalPoliciesNoPolicies(t *testing.T) {
+	po := NewPolicyOutput(PolicyCheck{})
+	po.AddLocalPolicies(Projects{{Name: "dev", Metadata: &schema.ProjectMetadata{}}})
+
+	assert.False(t, po.HasFailures)
+	assert.Empty(t, po.Checks)
+}
+
+func TestLocalPolicyFailures(t *testing.T) {
+	out := Root{
+		Currency: "USD",
+		Projects: Projects{
+			{
+				Name: "dev",
+				Metadata: &schema.ProjectMetadata{
+					Policies: schema.Policies{
+						{ID: "cost-center-tag", Title: "All resources must carry a cost-center tag", Address: "aws_instance.web"},
+					},
+				},
+				Breakdown: &Breakdown{},
+			},
+		},
+	}
+
+	failures := LocalPolicyFailures(out)
+	assert.Equal(t, PolicyCheckFailures{"All resources must carry a cost-center tag: aws_instance.web in project dev"}, failures)
+	assert.Contains(t, failures.Error(), "Policy check failed:")
+
+	b, err := ToTable(out, Options{NoColor: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "Policies failed:\n - All resources must carry a cost-center tag: aws_instance.web in project dev")
+
+	assert.Empty(t, LocalPolicyFailures(Root{Projects: Projects{{Name: "dev", Metadata: &schema.ProjectMetadata{}}}}))
+}
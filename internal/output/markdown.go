@@ -151,7 +151,12 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) (MarkdownO
 	if opts.diffMsg != "" {
 		diffMsg = opts.diffMsg
 	} else {
-		diff, err := ToDiff(out, opts)
+		// guardrail violations have their own section in the comment so
+		// leave them out of the diff to avoid listing them twice.
+		diffOut := out
+		diffOut.GuardrailViolations = nil
+
+		diff, err := ToDiff(diffOut, opts)
 		if err != nil {
 			return MarkdownOutput{}, errors.Wrap(err, "Failed to generate diff")
 		}
@@ -159,8 +164,6 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) (MarkdownO
 		diffMsg = ui.StripColor(string(diff))
 	}
 
-	hasModulePath, hasWorkspace := calculateMetadataToDisplay(out.Projects)
-
 	var buf bytes.Buffer
 	bufw := bufio.NewWriter(&buf)
 
@@ -174,11 +177,113 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) (MarkdownO
 		filename = "run-quota-exceeded.tmpl"
 	}
 
-	skipUsageCostIfZero := !usageCostsEnabled(out)
-
 	tmpl := template.New(filename)
 	tmpl.Funcs(sprig.TxtFuncMap())
-	tmpl.Funcs(template.FuncMap{
+	tmpl.Funcs(markdownTemplateFuncs(out, opts, markdownOpts))
+
+	var err error
+	if opts.TemplatePath != "" && !exceeded {
+		err = parseUserTemplate(tmpl, opts.TemplatePath)
+	} else {
+		_, err = tmpl.ParseFS(templatesFS, "templates/"+filename)
+	}
+	if err != nil {
+		return MarkdownOutput{}, err
+	}
+
+	skippedProjectCount := 0
+	for _, p := range out.Projects {
+		if p.Metadata.HasErrors() {
+			continue
+		}
+
+		if (p.Diff == nil || len(p.Diff.Resources) == 0) && !hasCodeChanges(opts, p) {
+			skippedProjectCount++
+		}
+	}
+
+	erroredProjectCount := 0
+	for _, p := range out.Projects {
+		if p.Metadata.HasErrors() {
+			erroredProjectCount++
+		}
+	}
+
+	skippedUnchangedProjectCount := 0
+	if opts.ShowOnlyChanges {
+		for _, p := range out.Projects {
+			if !hasCodeChanges(opts, p) {
+				skippedUnchangedProjectCount++
+			}
+		}
+	}
+
+	err = tmpl.Execute(bufw, MarkdownCtx{
+		Root:                         out,
+		SkippedProjectCount:          skippedProjectCount,
+		ErroredProjectCount:          erroredProjectCount,
+		SkippedUnchangedProjectCount: skippedUnchangedProjectCount,
+		DiffOutput:                   diffMsg,
+		Options:                      opts,
+		MarkdownOptions:              markdownOpts,
+		RunQuotaMsg:                  runQuotaMsg,
+		UsageCostsMsg:                usageCostsMessage(out, true),
+		CostDetailsMsg:               costsDetailsMessage(out),
+	})
+	if err != nil {
+		return MarkdownOutput{}, err
+	}
+
+	bufw.Flush()
+	msg := buf.Bytes()
+
+	msgByteLength := len(msg)
+	msgRuneLength := utf8.RuneCount(msg)
+
+	originalSize := msgRuneLength
+	if opts.originalSize > 0 {
+		originalSize = opts.originalSize
+	}
+
+	if markdownOpts.MaxMessageSize > 0 && msgByteLength > markdownOpts.MaxMessageSize {
+		// Calculate how much we need to reduce the message size
+		excessBytes := msgByteLength - markdownOpts.MaxMessageSize
+
+		// Use the diff message's own rune-to-byte ratio for more accurate truncation
+		diffMsgRunes := utf8.RuneCountInString(diffMsg)
+		diffMsgBytes := len([]byte(diffMsg))
+
+		var truncateRunes int
+		if diffMsgBytes > 0 {
+			diffRatio := float64(diffMsgRunes) / float64(diffMsgBytes)
+			truncateRunes = int(float64(excessBytes) * diffRatio)
+		} else {
+			truncateRunes = excessBytes // fallback
+		}
+
+		newLength := diffMsgRunes - truncateRunes - 1000
+
+		if newLength < 0 {
+			// trimming diff msg is not enough, so we truncate the whole message
+			truncated := truncateMiddle(string(msg), markdownOpts.MaxMessageSize-1000, "\n\n...(truncated due to message size limit)...\n\n")
+			return MarkdownOutput{Msg: []byte(truncated), RuneLen: utf8.RuneCountInString(truncated), OriginalMsgSize: originalSize}, nil
+		}
+
+		opts.diffMsg = truncateMiddle(diffMsg, newLength, "\n\n...(truncated due to message size limit)...\n\n")
+		opts.originalSize = originalSize
+		return ToMarkdown(out, opts, markdownOpts)
+	}
+
+	return MarkdownOutput{Msg: msg, RuneLen: msgRuneLength, OriginalMsgSize: originalSize}, nil
+}
+
+// markdownTemplateFuncs returns the helper funcs available to the markdown
+// templates, including user templates passed with --template-path.
+func markdownTemplateFuncs(out Root, opts Options, markdownOpts MarkdownOptions) template.FuncMap {
+	hasModulePath, hasWorkspace := calculateMetadataToDisplay(out.Projects)
+	skipUsageCostIfZero := !usageCostsEnabled(out)
+
+	return template.FuncMap{
 		"formatCost": func(d *decimal.Decimal) string {
 			if d == nil || d.IsZero() {
 				return formatWholeDecimalCurrency(out.Currency, decimal.Zero)
@@ -272,98 +377,13 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) (MarkdownO
 			}
 			return placeholders
 		},
+		"formatPercentChange": formatPercentChange,
+		"projectLabel": func(p Project) string {
+			return p.Label()
+		},
 		"stringsJoin":    strings.Join,
 		"truncateMiddle": truncateMiddle,
-	})
-	_, err := tmpl.ParseFS(templatesFS, "templates/"+filename)
-	if err != nil {
-		return MarkdownOutput{}, err
-	}
-
-	skippedProjectCount := 0
-	for _, p := range out.Projects {
-		if p.Metadata.HasErrors() {
-			continue
-		}
-
-		if (p.Diff == nil || len(p.Diff.Resources) == 0) && !hasCodeChanges(opts, p) {
-			skippedProjectCount++
-		}
-	}
-
-	erroredProjectCount := 0
-	for _, p := range out.Projects {
-		if p.Metadata.HasErrors() {
-			erroredProjectCount++
-		}
-	}
-
-	skippedUnchangedProjectCount := 0
-	if opts.ShowOnlyChanges {
-		for _, p := range out.Projects {
-			if !hasCodeChanges(opts, p) {
-				skippedUnchangedProjectCount++
-			}
-		}
 	}
-
-	err = tmpl.Execute(bufw, MarkdownCtx{
-		Root:                         out,
-		SkippedProjectCount:          skippedProjectCount,
-		ErroredProjectCount:          erroredProjectCount,
-		SkippedUnchangedProjectCount: skippedUnchangedProjectCount,
-		DiffOutput:                   diffMsg,
-		Options:                      opts,
-		MarkdownOptions:              markdownOpts,
-		RunQuotaMsg:                  runQuotaMsg,
-		UsageCostsMsg:                usageCostsMessage(out, true),
-		CostDetailsMsg:               costsDetailsMessage(out),
-	})
-	if err != nil {
-		return MarkdownOutput{}, err
-	}
-
-	bufw.Flush()
-	msg := buf.Bytes()
-
-	msgByteLength := len(msg)
-	msgRuneLength := utf8.RuneCount(msg)
-
-	originalSize := msgRuneLength
-	if opts.originalSize > 0 {
-		originalSize = opts.originalSize
-	}
-
-	if markdownOpts.MaxMessageSize > 0 && msgByteLength > markdownOpts.MaxMessageSize {
-		// Calculate how much we need to reduce the message size
-		excessBytes := msgByteLength - markdownOpts.MaxMessageSize
-
-		// Use the diff message's own rune-to-byte ratio for more accurate truncation
-		diffMsgRunes := utf8.RuneCountInString(diffMsg)
-		diffMsgBytes := len([]byte(diffMsg))
-
-		var truncateRunes int
-		if diffMsgBytes > 0 {
-			diffRatio := float64(diffMsgRunes) / float64(diffMsgBytes)
-			truncateRunes = int(float64(excessBytes) * diffRatio)
-		} else {
-			truncateRunes = excessBytes // fallback
-		}
-
-		newLength := diffMsgRunes - truncateRunes - 1000
-
-		if newLength < 0 {
-			// trimming diff msg is not enough, so we truncate the whole message
-			truncated := truncateMiddle(string(msg), markdownOpts.MaxMessageSize-1000, "\n\n...(truncated due to message size limit)...\n\n")
-			return MarkdownOutput{Msg: []byte(truncated), RuneLen: utf8.RuneCountInString(truncated), OriginalMsgSize: originalSize}, nil
-		}
-
-		opts.diffMsg = truncateMiddle(diffMsg, newLength, "\n\n...(truncated due to message size limit)...\n\n")
-		opts.originalSize = originalSize
-		return ToMarkdown(out, opts, markdownOpts)
-	}
-
-	return MarkdownOutput{Msg: msg, RuneLen: msgRuneLength, OriginalMsgSize: originalSize}, nil
 }
 
 func hasCodeChanges(options Options, project Project) bool {
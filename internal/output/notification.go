@@ -0,0 +1,143 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostSummary is the change in the monthly cost of a project, or of all
+// projects, as shown in the chat and webhook notification formats.
+type CostSummary struct {
+	Name     string           `json:"name"`
+	PastCost *decimal.Decimal `json:"pastMonthlyCost"`
+	Cost     *decimal.Decimal `json:"monthlyCost"`
+	DiffCost *decimal.Decimal `json:"diffMonthlyCost"`
+	// Diff is the formatted cost change, e.g. "+$10 ($20 → $30)".
+	Diff string `json:"diff"`
+}
+
+func newCostSummary(name string, currency string, cost, pastCost, diffCost *decimal.Decimal) CostSummary {
+	if cost == nil {
+		cost = decimalPtr(decimal.Zero)
+	}
+
+	if diffCost == nil {
+		// If we don't have a past cost or a diff cost then it means the cost increase is the total cost
+		if pastCost == nil {
+			diffCost = cost
+		} else {
+			diffCost = decimalPtr(decimal.Zero)
+		}
+	}
+
+	if pastCost == nil {
+		pastCost = decimalPtr(decimal.Zero)
+	}
+
+	return CostSummary{
+		Name:     name,
+		PastCost: pastCost,
+		Cost:     cost,
+		DiffCost: diffCost,
+		Diff:     fmt.Sprintf("%s%s", formatCostChange(currency, diffCost), formatCostChangeDetails(currency, pastCost, cost)),
+	}
+}
+
+func projectCostSummary(project Project, currency string) CostSummary {
+	var pastCost, cost, diffCost *decimal.Decimal
+
+	if project.PastBreakdown != nil {
+		pastCost = project.PastBreakdown.TotalMonthlyCost
+	}
+
+	if project.Breakdown != nil {
+		cost = project.Breakdown.TotalMonthlyCost
+	}
+
+	if project.Diff != nil {
+		diffCost = project.Diff.TotalMonthlyCost
+	}
+
+	return newCostSummary(truncateMiddle(project.Label(), 42, "..."), currency, cost, pastCost, diffCost)
+}
+
+func allProjectsCostSummary(out Root) CostSummary {
+	return newCostSummary("All projects", out.Currency, out.TotalMonthlyCost, out.PastTotalMonthlyCost, out.DiffTotalMonthlyCost)
+}
+
+// notificationCostSummaries returns the cost summaries of the projects that
+// have changes, followed by a summary of all projects if there are multiple
+// projects. A single project is always included. It also returns the number
+// of projects that have no changes.
+func notificationCostSummaries(out Root) ([]CostSummary, int) {
+	summaries, skippedProjectCount := notificationProjectCostSummaries(out)
+
+	if len(out.Projects) > 1 {
+		summaries = append(summaries, allProjectsCostSummary(out))
+	}
+
+	return summaries, skippedProjectCount
+}
+
+// notificationProjectCostSummaries returns the cost summaries of the projects
+// that have changes, or of the project if there is only one, and the number of
+// projects that have no changes.
+func notificationProjectCostSummaries(out Root) ([]CostSummary, int) {
+	var summaries []CostSummary
+	skippedProjectCount := 0
+
+	for _, project := range out.Projects {
+		hasChanges := project.Diff != nil && len(project.Diff.Resources) > 0
+		if !hasChanges {
+			skippedProjectCount++
+		}
+
+		if len(out.Projects) != 1 && !hasChanges {
+			continue
+		}
+		summaries = append(summaries, projectCostSummary(project, out.Currency))
+	}
+
+	return summaries, skippedProjectCount
+}
+
+// skippedProjectsMessage returns the message shown for projects with no cost
+// changes. It is empty when there is only one project.
+func skippedProjectsMessage(out Root, skippedProjectCount int) string {
+	if len(out.Projects) <= 1 {
+		return ""
+	}
+
+	if skippedProjectCount == 1 {
+		return "1 project has no cost estimate changes."
+	} else if skippedProjectCount > 0 {
+		return fmt.Sprintf("%d projects have no cost estimate changes.", skippedProjectCount)
+	}
+
+	return ""
+}
+
+// notificationURL returns the link to the run in Infracost Cloud, or to the
+// shared report if there is no Cloud link.
+func notificationURL(out Root) string {
+	if out.CloudURL != "" {
+		return out.CloudURL
+	}
+
+	return out.ShareURL
+}
+
+// ExceedsNotifyThreshold returns true if the monthly cost of any of the
+// summarized projects, or of all projects, changed by more than the
+// threshold. Increases and decreases are both counted.
+func ExceedsNotifyThreshold(out Root, threshold decimal.Decimal) bool {
+	summaries, _ := notificationCostSummaries(out)
+	for _, s := range summaries {
+		if s.DiffCost.Abs().GreaterThan(threshold) {
+			return true
+		}
+	}
+
+	return false
+}
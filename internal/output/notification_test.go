@@ -0,0 +1,35 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExceedsNotifyThreshold(t *testing.T) {
+	out := Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(200)),
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(180)),
+		DiffTotalMonthlyCost: decimalPtr(decimal.NewFromInt(-20)),
+		Projects: Projects{
+			{
+				Name:          "increased",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+				Breakdown:     &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+				Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(50)), Resources: []Resource{{Name: "aws_instance.web"}}},
+			},
+			{
+				Name:          "decreased",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+				Breakdown:     &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+				Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(-70)), Resources: []Resource{{Name: "aws_instance.db"}}},
+			},
+		},
+	}
+
+	assert.True(t, ExceedsNotifyThreshold(out, decimal.NewFromInt(60)), "project decrease is above the threshold")
+	assert.False(t, ExceedsNotifyThreshold(out, decimal.NewFromInt(70)))
+	assert.False(t, ExceedsNotifyThreshold(Root{Currency: "USD", Projects: Projects{{Name: "empty"}}}, decimal.Zero))
+}
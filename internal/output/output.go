@@ -42,6 +42,24 @@ type Root struct {
 	Summary                   *Summary         `json:"summary"`
 	FullSummary               *Summary         `json:"-"`
 	IsCIRun                   bool             `json:"-"`
+
+	// GuardrailViolations are the guardrails from the config file that this run breaches.
+	GuardrailViolations GuardrailViolations `json:"guardrailViolations,omitempty"`
+
+	// TotalCommitmentHourlyCost and TotalCommitmentMonthlyCost are the total costs with commitments
+	// applied. They are only set when a commitment covers some of the usage.
+	TotalCommitmentHourlyCost  *decimal.Decimal `json:"totalCommitmentHourlyCost,omitempty"`
+	TotalCommitmentMonthlyCost *decimal.Decimal `json:"totalCommitmentMonthlyCost,omitempty"`
+
+	// TotalForecastMonthlyCosts, PastTotalForecastMonthlyCosts and DiffTotalForecastMonthlyCosts are the
+	// total monthly costs for each month of the forecast. They are only set when running with a forecast.
+	TotalForecastMonthlyCosts     []decimal.Decimal `json:"totalForecastMonthlyCosts,omitempty"`
+	PastTotalForecastMonthlyCosts []decimal.Decimal `json:"pastTotalForecastMonthlyCosts,omitempty"`
+	DiffTotalForecastMonthlyCosts []decimal.Decimal `json:"diffTotalForecastMonthlyCosts,omitempty"`
+
+	// CostGroups are the monthly costs of the resources rolled up by --group-by.
+	// They are only set when the output is grouped.
+	CostGroups *CostGroups `json:"costGroups,omitempty"`
 }
 
 // HasUnsupportedResources returns if the summary has any unsupported resources.
@@ -132,9 +150,14 @@ func convertOutputResources(outResources []Resource, skip bool) []*schema.Resour
 			HourlyCost:                              resource.HourlyCost,
 			MonthlyCost:                             resource.MonthlyCost,
 			MonthlyUsageCost:                        resource.MonthlyUsageCost,
+			CommitmentHourlyCost:                    resource.CommitmentHourlyCost,
+			CommitmentMonthlyCost:                   resource.CommitmentMonthlyCost,
+			ForecastMonthlyCosts:                    resource.ForecastMonthlyCosts,
 			ResourceType:                            resource.ResourceType,
 			MissingVarsCausingUnknownTagKeys:        resource.MissingVarsCausingUnknownTagKeys,
 			MissingVarsCausingUnknownDefaultTagKeys: resource.MissingVarsCausingUnknownDefaultTagKeys,
+			TrackingChange:                          resource.TrackingChange,
+			MovedFrom:                               resource.MovedFrom,
 		}
 	}
 
@@ -155,8 +178,26 @@ func convertCostComponents(outComponents []CostComponent) []*schema.CostComponen
 			MonthlyQuantity: c.MonthlyQuantity,
 			UsageBased:      c.UsageBased,
 			PriceNotFound:   c.PriceNotFound,
+
+			Commitment:            c.Commitment,
+			CommitmentHourlyCost:  c.CommitmentHourlyCost,
+			CommitmentMonthlyCost: c.CommitmentMonthlyCost,
+			PriceOverride:         c.PriceOverride,
+		}
+		if c.VendorName != "" || c.Service != "" || c.Region != "" {
+			sc.ProductFilter = &schema.ProductFilter{
+				VendorName: stringPtr(c.VendorName),
+				Service:    stringPtr(c.Service),
+				Region:     stringPtr(c.Region),
+			}
+		}
+
+		if c.ListPrice != nil {
+			sc.SetPrice(*c.ListPrice)
+			sc.SetPriceOverride(c.PriceOverride, c.Price)
+		} else {
+			sc.SetPrice(c.Price)
 		}
-		sc.SetPrice(c.Price)
 
 		components[i] = sc
 	}
@@ -268,6 +309,13 @@ type Breakdown struct {
 	TotalHourlyCost       *decimal.Decimal `json:"totalHourlyCost"`
 	TotalMonthlyCost      *decimal.Decimal `json:"totalMonthlyCost"`
 	TotalMonthlyUsageCost *decimal.Decimal `json:"totalMonthlyUsageCost"`
+	// TotalCommitmentHourlyCost and TotalCommitmentMonthlyCost are the total costs with commitments
+	// applied. They are only set when a commitment covers some of the usage.
+	TotalCommitmentHourlyCost  *decimal.Decimal `json:"totalCommitmentHourlyCost,omitempty"`
+	TotalCommitmentMonthlyCost *decimal.Decimal `json:"totalCommitmentMonthlyCost,omitempty"`
+	// TotalForecastMonthlyCosts is the total monthly cost for each month of the forecast.
+	// It is only set when running with a forecast.
+	TotalForecastMonthlyCosts []decimal.Decimal `json:"totalForecastMonthlyCosts,omitempty"`
 }
 
 // HasResources returns true if the breakdown has any resources or free resources.
@@ -298,6 +346,24 @@ type CostComponent struct {
 	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
 	UsageBased      bool             `json:"usageBased,omitempty"`
 	PriceNotFound   bool             `json:"priceNotFound"`
+	// Commitment is the name of the commitment that covers some or all of the usage of
+	// the cost component, the commitment costs are the costs with the commitment applied.
+	Commitment            string           `json:"commitment,omitempty"`
+	CommitmentHourlyCost  *decimal.Decimal `json:"commitmentHourlyCost,omitempty"`
+	CommitmentMonthlyCost *decimal.Decimal `json:"commitmentMonthlyCost,omitempty"`
+	// PriceOverride is the name of the price override that changed the price of the
+	// cost component from the list price, ListPrice is the price before the override.
+	PriceOverride string           `json:"priceOverride,omitempty"`
+	ListPrice     *decimal.Decimal `json:"listPrice,omitempty"`
+	// VendorName, Service and Region are from the product filter used to look up
+	// the price of the cost component.
+	VendorName string `json:"vendorName,omitempty"`
+	Service    string `json:"service,omitempty"`
+	Region     string `json:"region,omitempty"`
+	// AttributeFilters and PriceFilter are the rest of the filters used to look
+	// up the price of the cost component. They are only set with --explore-details.
+	AttributeFilters []*schema.AttributeFilter `json:"attributeFilters,omitempty"`
+	PriceFilter      *schema.PriceFilter       `json:"priceFilter,omitempty"`
 }
 
 type ActualCosts struct {
@@ -319,11 +385,22 @@ type Resource struct {
 	HourlyCost                              *decimal.Decimal       `json:"hourlyCost,omitempty"`
 	MonthlyCost                             *decimal.Decimal       `json:"monthlyCost,omitempty"`
 	MonthlyUsageCost                        *decimal.Decimal       `json:"monthlyUsageCost,omitempty"`
+	CommitmentHourlyCost                    *decimal.Decimal       `json:"commitmentHourlyCost,omitempty"`
+	CommitmentMonthlyCost                   *decimal.Decimal       `json:"commitmentMonthlyCost,omitempty"`
+	ForecastMonthlyCosts                    []decimal.Decimal      `json:"forecastMonthlyCosts,omitempty"`
 	CostComponents                          []CostComponent        `json:"costComponents,omitempty"`
 	ActualCosts                             []ActualCosts          `json:"actualCosts,omitempty"`
 	SubResources                            []Resource             `json:"subresources,omitempty"`
 	MissingVarsCausingUnknownTagKeys        []string               `json:"missingVarsCausingUnknownTagKeys,omitempty"`
 	MissingVarsCausingUnknownDefaultTagKeys []string               `json:"missingVarsCausingUnknownDefaultTagKeys,omitempty"`
+	// Usage is the usage data the resource was estimated with. It is only set
+	// with --explore-details.
+	Usage map[string]interface{} `json:"usage,omitempty"`
+	// TrackingChange is set on diff resources that were moved, imported or removed
+	// from the state without being destroyed: moved, imported or removed.
+	TrackingChange string `json:"trackingChange,omitempty"`
+	// MovedFrom is the past address of diff resources that were moved.
+	MovedFrom string `json:"movedFrom,omitempty"`
 }
 
 type TagPropagation struct {
@@ -368,6 +445,15 @@ type Options struct {
 	diffMsg           string
 	originalSize      int
 	CurrencyFormat    string
+	// TemplatePath is the path of an optional Go template that replaces the
+	// built-in markdown template of the comment formats.
+	TemplatePath string
+	// WebhookTemplatePath is the path of an optional Go template used to
+	// build the payload of the webhook-message format.
+	WebhookTemplatePath string
+	// GroupBy rolls up the resource costs by a tag, module, resource type,
+	// provider or region in the json, table and diff formats.
+	GroupBy string
 }
 
 // PolicyOutput holds normalized PolicyCheck and TagPolicyCheck data so it can be output in
@@ -501,53 +587,87 @@ type MarkdownOptions struct {
 	Additional          string
 }
 
-func outputBreakdown(c *config.Config, resources []*schema.Resource) *Breakdown {
+// outputBreakdown converts the resources to a breakdown. Resources whose
+// address is in excludeFromTotals are listed but not counted in the totals.
+func outputBreakdown(c *config.Config, resources []*schema.Resource, excludeFromTotals ...map[string]bool) *Breakdown {
 	supportedResources := make([]Resource, 0, len(resources))
+	totalResources := make([]Resource, 0, len(resources))
 	freeResources := make([]Resource, 0, len(resources))
 
 	for _, r := range resources {
 		if r.IsSkipped {
 			if c.TagPoliciesEnabled && r.Tags != nil {
-				freeResources = append(freeResources, newResource(r, nil, nil, nil))
+				freeResources = append(freeResources, newResource(r, nil, nil, nil, false))
 			}
 
 			continue
 		}
-		supportedResources = append(supportedResources, outputResource(r))
+
+		out := outputResource(r, c.ExploreDetails)
+		supportedResources = append(supportedResources, out)
+		if !isExcluded(r.Name, excludeFromTotals) {
+			totalResources = append(totalResources, out)
+		}
 	}
 
 	sortResources(supportedResources, "")
 	sortResources(freeResources, "")
 
-	totalHourlyCost, totalMonthlyCost, totalMonthlyUsageCost := calculateTotalCosts(supportedResources)
+	totalHourlyCost, totalMonthlyCost, totalMonthlyUsageCost := calculateTotalCosts(totalResources)
+	totalCommitmentHourlyCost, totalCommitmentMonthlyCost := calculateTotalCommitmentCosts(totalResources)
 
 	return &Breakdown{
-		Resources:             supportedResources,
-		FreeResources:         freeResources,
-		TotalHourlyCost:       totalHourlyCost,
-		TotalMonthlyCost:      totalMonthlyCost,
-		TotalMonthlyUsageCost: totalMonthlyUsageCost,
+		Resources:                  supportedResources,
+		FreeResources:              freeResources,
+		TotalHourlyCost:            totalHourlyCost,
+		TotalMonthlyCost:           totalMonthlyCost,
+		TotalMonthlyUsageCost:      totalMonthlyUsageCost,
+		TotalCommitmentHourlyCost:  totalCommitmentHourlyCost,
+		TotalCommitmentMonthlyCost: totalCommitmentMonthlyCost,
+		TotalForecastMonthlyCosts:  calculateTotalForecastCosts(totalResources),
 	}
 }
-func outputResource(r *schema.Resource) Resource {
-	comps := outputCostComponents(r.CostComponents)
+
+func isExcluded(address string, exclusions []map[string]bool) bool {
+	for _, excluded := range exclusions {
+		if excluded[address] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outputResource converts the resource to its output format. The price
+// filters and usage values are only included when exploreDetails is set, as
+// they are only needed by infracost explore and would bloat the JSON output.
+func outputResource(r *schema.Resource, exploreDetails bool) Resource {
+	comps := outputCostComponents(r.CostComponents, exploreDetails)
 
 	actualCosts := outputActualCosts(r.ActualCosts)
 
 	subresources := make([]Resource, 0, len(r.SubResources))
 	for _, s := range r.SubResources {
-		subresources = append(subresources, outputResource(s))
+		subresources = append(subresources, outputResource(s, exploreDetails))
 	}
 
-	return newResource(r, comps, actualCosts, subresources)
+	return newResource(r, comps, actualCosts, subresources, exploreDetails)
 }
 
-func newResource(r *schema.Resource, comps []CostComponent, actualCosts []ActualCosts, subresources []Resource) Resource {
+func newResource(r *schema.Resource, comps []CostComponent, actualCosts []ActualCosts, subresources []Resource, exploreDetails bool) Resource {
 	metadata := make(map[string]interface{})
 	for k, v := range r.Metadata {
 		metadata[k] = v.Value()
 	}
 
+	var usage map[string]interface{}
+	if exploreDetails && r.Usage != nil && len(r.Usage.Attributes) > 0 {
+		usage = make(map[string]interface{}, len(r.Usage.Attributes))
+		for k, v := range r.Usage.Attributes {
+			usage[k] = v.Value()
+		}
+	}
+
 	var tagProp *TagPropagation
 	if r.TagPropagation != nil {
 		tagProp = &TagPropagation{
@@ -571,18 +691,31 @@ func newResource(r *schema.Resource, comps []CostComponent, actualCosts []Actual
 		HourlyCost:                              r.HourlyCost,
 		MonthlyCost:                             r.MonthlyCost,
 		MonthlyUsageCost:                        r.MonthlyUsageCost,
+		CommitmentHourlyCost:                    r.CommitmentHourlyCost,
+		CommitmentMonthlyCost:                   r.CommitmentMonthlyCost,
+		ForecastMonthlyCosts:                    r.ForecastMonthlyCosts,
 		CostComponents:                          comps,
 		ActualCosts:                             actualCosts,
 		SubResources:                            subresources,
 		MissingVarsCausingUnknownTagKeys:        r.MissingVarsCausingUnknownTagKeys,
 		MissingVarsCausingUnknownDefaultTagKeys: r.MissingVarsCausingUnknownDefaultTagKeys,
+		Usage:                                   usage,
+		TrackingChange:                          r.TrackingChange,
+		MovedFrom:                               r.MovedFrom,
 	}
 }
 
-func outputCostComponents(costComponents []*schema.CostComponent) []CostComponent {
+func outputCostComponents(costComponents []*schema.CostComponent, exploreDetails bool) []CostComponent {
 	comps := make([]CostComponent, 0, len(costComponents))
 	for _, c := range costComponents {
-		comps = append(comps, CostComponent{
+		var vendorName, service, region string
+		if c.ProductFilter != nil {
+			vendorName = stringValue(c.ProductFilter.VendorName)
+			service = stringValue(c.ProductFilter.Service)
+			region = stringValue(c.ProductFilter.Region)
+		}
+
+		comp := CostComponent{
 			Name:            c.Name,
 			Unit:            c.Unit,
 			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
@@ -592,11 +725,45 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 			MonthlyCost:     c.MonthlyCost,
 			UsageBased:      c.UsageBased,
 			PriceNotFound:   c.PriceNotFound,
-		})
+
+			Commitment:            c.Commitment,
+			CommitmentHourlyCost:  c.CommitmentHourlyCost,
+			CommitmentMonthlyCost: c.CommitmentMonthlyCost,
+			PriceOverride:         c.PriceOverride,
+			ListPrice:             c.UnitMultiplierListPrice(),
+
+			VendorName: vendorName,
+			Service:    service,
+			Region:     region,
+		}
+
+		if exploreDetails {
+			if c.ProductFilter != nil {
+				comp.AttributeFilters = c.ProductFilter.AttributeFilters
+			}
+			comp.PriceFilter = c.PriceFilter
+		}
+
+		comps = append(comps, comp)
 	}
 	return comps
 }
 
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// stringPtr returns a pointer to s, or nil if s is empty.
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func outputActualCosts(actualCosts []*schema.ActualCosts) []ActualCosts {
 	acs := make([]ActualCosts, 0, len(actualCosts))
 	for _, ac := range actualCosts {
@@ -604,7 +771,7 @@ func outputActualCosts(actualCosts []*schema.ActualCosts) []ActualCosts {
 			ResourceID:     ac.ResourceID,
 			StartTimestamp: ac.StartTimestamp,
 			EndTimestamp:   ac.EndTimestamp,
-			CostComponents: outputCostComponents(ac.CostComponents),
+			CostComponents: outputCostComponents(ac.CostComponents, false),
 		})
 	}
 	return acs
@@ -614,6 +781,7 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 	var totalMonthlyCost, totalHourlyCost, totalMonthlyUsageCost,
 		pastTotalMonthlyCost, pastTotalHourlyCost, pastTotalMonthlyUsageCost,
 		diffTotalMonthlyCost, diffTotalHourlyCost, diffTotalMonthlyUsageCost *decimal.Decimal
+	var totalForecastCosts, pastTotalForecastCosts, diffTotalForecastCosts []decimal.Decimal
 
 	outProjects := make([]Project, 0, len(projects))
 	summaries := make([]*Summary, 0, len(projects))
@@ -645,11 +813,18 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 				}
 				totalMonthlyUsageCost = decimalPtr(totalMonthlyUsageCost.Add(*breakdown.TotalMonthlyUsageCost))
 			}
+
+			totalForecastCosts = addForecastCosts(totalForecastCosts, breakdown.TotalForecastMonthlyCosts)
 		}
 
 		if project.HasDiff {
+			// Imported resources and resources removed from the state without being
+			// destroyed aren't a cost change, so they're left out of the diff totals.
+			// They're still counted in the past and current totals as they exist.
+			imported, removed := schema.UntrackedDiffAddresses(project.Diff)
+
 			pastBreakdown = outputBreakdown(c, project.PastResources)
-			diff = outputBreakdown(c, project.Diff)
+			diff = outputBreakdown(c, project.Diff, imported, removed)
 
 			if pastBreakdown != nil {
 				if pastBreakdown.TotalHourlyCost != nil {
@@ -672,6 +847,8 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 					}
 					pastTotalMonthlyUsageCost = decimalPtr(pastTotalMonthlyUsageCost.Add(*pastBreakdown.TotalMonthlyUsageCost))
 				}
+
+				pastTotalForecastCosts = addForecastCosts(pastTotalForecastCosts, pastBreakdown.TotalForecastMonthlyCosts)
 			}
 
 			if diff != nil {
@@ -695,6 +872,8 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 					}
 					diffTotalMonthlyUsageCost = decimalPtr(diffTotalMonthlyUsageCost.Add(*diff.TotalMonthlyUsageCost))
 				}
+
+				diffTotalForecastCosts = addForecastCosts(diffTotalForecastCosts, diff.TotalForecastMonthlyCosts)
 			}
 		}
 
@@ -732,21 +911,29 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 		})
 	}
 
+	totalCommitmentHourlyCost, totalCommitmentMonthlyCost := calculateProjectCommitmentCosts(outProjects)
+
 	out := Root{
-		Version:                   outputVersion,
-		Projects:                  outProjects,
-		TotalHourlyCost:           totalHourlyCost,
-		TotalMonthlyCost:          totalMonthlyCost,
-		TotalMonthlyUsageCost:     totalMonthlyUsageCost,
-		PastTotalHourlyCost:       pastTotalHourlyCost,
-		PastTotalMonthlyCost:      pastTotalMonthlyCost,
-		PastTotalMonthlyUsageCost: pastTotalMonthlyUsageCost,
-		DiffTotalHourlyCost:       diffTotalHourlyCost,
-		DiffTotalMonthlyCost:      diffTotalMonthlyCost,
-		DiffTotalMonthlyUsageCost: diffTotalMonthlyUsageCost,
-		TimeGenerated:             time.Now().UTC(),
-		Summary:                   MergeSummaries(summaries),
-		FullSummary:               MergeSummaries(fullSummaries),
+		Version:                    outputVersion,
+		Projects:                   outProjects,
+		TotalHourlyCost:            totalHourlyCost,
+		TotalMonthlyCost:           totalMonthlyCost,
+		TotalMonthlyUsageCost:      totalMonthlyUsageCost,
+		PastTotalHourlyCost:        pastTotalHourlyCost,
+		PastTotalMonthlyCost:       pastTotalMonthlyCost,
+		PastTotalMonthlyUsageCost:  pastTotalMonthlyUsageCost,
+		DiffTotalHourlyCost:        diffTotalHourlyCost,
+		DiffTotalMonthlyCost:       diffTotalMonthlyCost,
+		DiffTotalMonthlyUsageCost:  diffTotalMonthlyUsageCost,
+		TotalCommitmentHourlyCost:  totalCommitmentHourlyCost,
+		TotalCommitmentMonthlyCost: totalCommitmentMonthlyCost,
+		TimeGenerated:              time.Now().UTC(),
+		Summary:                    MergeSummaries(summaries),
+		FullSummary:                MergeSummaries(fullSummaries),
+
+		TotalForecastMonthlyCosts:     totalForecastCosts,
+		PastTotalForecastMonthlyCosts: pastTotalForecastCosts,
+		DiffTotalForecastMonthlyCosts: diffTotalForecastCosts,
 	}
 
 	return out, nil
@@ -1028,6 +1215,95 @@ func calculateTotalCosts(resources []Resource) (*decimal.Decimal, *decimal.Decim
 	return totalHourlyCost, totalMonthlyCost, totalMonthlyUsageCost
 }
 
+// calculateTotalCommitmentCosts returns the total costs of the resources with
+// commitments applied, using the normal costs of resources that aren't covered
+// by a commitment. These are nil if no resources are covered by a commitment.
+func calculateTotalCommitmentCosts(resources []Resource) (*decimal.Decimal, *decimal.Decimal) {
+	totalHourlyCost := decimal.Zero
+	totalMonthlyCost := decimal.Zero
+	hasCommitment := false
+
+	for _, r := range resources {
+		if r.CommitmentHourlyCost != nil || r.CommitmentMonthlyCost != nil {
+			hasCommitment = true
+		}
+
+		totalHourlyCost = totalHourlyCost.Add(costOrFallback(r.CommitmentHourlyCost, r.HourlyCost))
+		totalMonthlyCost = totalMonthlyCost.Add(costOrFallback(r.CommitmentMonthlyCost, r.MonthlyCost))
+	}
+
+	if !hasCommitment {
+		return nil, nil
+	}
+
+	return &totalHourlyCost, &totalMonthlyCost
+}
+
+// calculateProjectCommitmentCosts returns the total costs of the projects with
+// commitments applied. These are nil if no project has a commitment.
+func calculateProjectCommitmentCosts(projects []Project) (*decimal.Decimal, *decimal.Decimal) {
+	totalHourlyCost := decimal.Zero
+	totalMonthlyCost := decimal.Zero
+	hasCommitment := false
+
+	for _, p := range projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		if p.Breakdown.TotalCommitmentHourlyCost != nil || p.Breakdown.TotalCommitmentMonthlyCost != nil {
+			hasCommitment = true
+		}
+
+		totalHourlyCost = totalHourlyCost.Add(costOrFallback(p.Breakdown.TotalCommitmentHourlyCost, p.Breakdown.TotalHourlyCost))
+		totalMonthlyCost = totalMonthlyCost.Add(costOrFallback(p.Breakdown.TotalCommitmentMonthlyCost, p.Breakdown.TotalMonthlyCost))
+	}
+
+	if !hasCommitment {
+		return nil, nil
+	}
+
+	return &totalHourlyCost, &totalMonthlyCost
+}
+
+// calculateTotalForecastCosts returns the total monthly cost of the resources
+// for each month of the forecast. This is nil if no resources have a forecast.
+func calculateTotalForecastCosts(resources []Resource) []decimal.Decimal {
+	var total []decimal.Decimal
+	for _, r := range resources {
+		total = addForecastCosts(total, r.ForecastMonthlyCosts)
+	}
+
+	return total
+}
+
+// addForecastCosts adds the forecast costs to the total for each month,
+// extending the total if the forecast costs cover more months.
+func addForecastCosts(total []decimal.Decimal, costs []decimal.Decimal) []decimal.Decimal {
+	for i, cost := range costs {
+		if i < len(total) {
+			total[i] = total[i].Add(cost)
+		} else {
+			total = append(total, cost)
+		}
+	}
+
+	return total
+}
+
+// costOrFallback returns the cost if it is set, otherwise the fallback cost or zero.
+func costOrFallback(cost *decimal.Decimal, fallback *decimal.Decimal) decimal.Decimal {
+	if cost != nil {
+		return *cost
+	}
+
+	if fallback != nil {
+		return *fallback
+	}
+
+	return decimal.Zero
+}
+
 func sortResources(resources []Resource, groupKey string) {
 	sort.Slice(resources, func(i, j int) bool {
 		// if they are in different groups, sort by group name
@@ -5,6 +5,10 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
 )
 
 func TestCalculateTotalCosts(t *testing.T) {
@@ -38,3 +42,100 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyUsageCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestCalculateTotalForecastCosts(t *testing.T) {
+	resources := []Resource{
+		{ForecastMonthlyCosts: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(11)}},
+		{ForecastMonthlyCosts: []decimal.Decimal{decimal.NewFromInt(5), decimal.NewFromInt(5), decimal.NewFromInt(5)}},
+		{},
+	}
+
+	total := calculateTotalForecastCosts(resources)
+	assert.Equal(t, []string{"15", "16", "5"}, []string{total[0].String(), total[1].String(), total[2].String()})
+	assert.Equal(t, "10", resources[0].ForecastMonthlyCosts[0].String(), "resource costs should not be modified")
+
+	assert.Nil(t, calculateTotalForecastCosts([]Resource{{}}))
+}
+
+func TestConvertCostComponentsRoundTrip(t *testing.T) {
+	comps := []CostComponent{
+		{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromFloat(0.1), VendorName: "aws", Service: "AmazonEC2", Region: "us-east-1"},
+		{Name: "Storage", Unit: "GB", Price: decimal.NewFromFloat(0.08), PriceOverride: "EDP", ListPrice: decimalPtr(decimal.NewFromFloat(0.1))},
+	}
+
+	got := outputCostComponents(convertCostComponents(comps), false)
+
+	assert.Equal(t, "aws", got[0].VendorName)
+	assert.Equal(t, "AmazonEC2", got[0].Service)
+	assert.Equal(t, "us-east-1", got[0].Region)
+	assert.Equal(t, "", got[1].VendorName)
+	assert.Equal(t, "", got[1].Region)
+	assert.Equal(t, "0.08", got[1].Price.String())
+	assert.Equal(t, "0.1", got[1].ListPrice.String())
+	assert.Equal(t, "EDP", got[1].PriceOverride)
+}
+
+func TestOutputCostComponentsExploreDetails(t *testing.T) {
+	c := &schema.CostComponent{
+		Name:           "Instance usage",
+		UnitMultiplier: decimal.NewFromInt(1),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:       stringPtr("aws"),
+			AttributeFilters: []*schema.AttributeFilter{{Key: "instanceType", Value: stringPtr("m5.large")}},
+		},
+		PriceFilter: &schema.PriceFilter{PurchaseOption: stringPtr("on_demand")},
+	}
+
+	got := outputCostComponents([]*schema.CostComponent{c}, false)
+	assert.Equal(t, "aws", got[0].VendorName)
+	assert.Nil(t, got[0].AttributeFilters, "price filters should only be included with explore details")
+	assert.Nil(t, got[0].PriceFilter)
+
+	got = outputCostComponents([]*schema.CostComponent{c}, true)
+	assert.Len(t, got[0].AttributeFilters, 1)
+	assert.Equal(t, "on_demand", *got[0].PriceFilter.PurchaseOption)
+}
+
+func TestToOutputFormatUntrackedDiffTotals(t *testing.T) {
+	resource := func(name string, cost int64) *schema.Resource {
+		return &schema.Resource{
+			Name:        name,
+			HourlyCost:  decimalPtr(decimal.NewFromInt(cost).Div(schema.HourToMonthUnitMultiplier)),
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+			CostComponents: []*schema.CostComponent{
+				{Name: "cc", UnitMultiplier: decimal.NewFromInt(1), MonthlyCost: decimalPtr(decimal.NewFromInt(cost))},
+			},
+		}
+	}
+
+	project := &schema.Project{
+		Name:    "test",
+		HasDiff: true,
+		Metadata: &schema.ProjectMetadata{
+			AddressChanges: &schema.AddressChanges{
+				Imported: []string{"aws_instance.imported"},
+				Removed:  []string{"aws_instance.removed"},
+			},
+		},
+		PastResources: []*schema.Resource{
+			resource("aws_instance.web", 10),
+			resource("aws_instance.removed", 40),
+		},
+		Resources: []*schema.Resource{
+			resource("aws_instance.web", 15),
+			resource("aws_instance.imported", 50),
+		},
+	}
+	project.CalculateDiff()
+
+	out, err := ToOutputFormat(&config.Config{}, []*schema.Project{project})
+	require.NoError(t, err)
+
+	assert.Len(t, out.Projects[0].Diff.Resources, 3, "untracked resources should still be listed in the diff")
+	assert.Equal(t, "50", out.PastTotalMonthlyCost.String(), "removed resources should still be counted in the past total")
+	assert.Equal(t, "50", out.Projects[0].PastBreakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "65", out.TotalMonthlyCost.String(), "imported resources should still be counted in the current total")
+	assert.Equal(t, "65", out.Projects[0].Breakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "5", out.DiffTotalMonthlyCost.String())
+	assert.Equal(t, "5", out.Projects[0].Diff.TotalMonthlyCost.String())
+}
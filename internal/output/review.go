@@ -0,0 +1,175 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReviewResource is a resource whose monthly cost changed, along with the
+// location of the block that defines it. It is used to post review comments
+// on the lines of code that caused a cost change.
+type ReviewResource struct {
+	// Project is the name of the project the resource belongs to.
+	Project string
+	// Address is the address of the resource, e.g. aws_instance.web.
+	Address string
+	// Filename is the path of the file containing the resource block, relative
+	// to the directory Infracost was run from.
+	Filename  string
+	StartLine int
+	EndLine   int
+	// Body is the markdown body of the review comment.
+	Body string
+}
+
+// ToReviewResources returns a ReviewResource for each resource in the diff of
+// the projects that has a monthly cost change and whose block location is
+// known. Removed resources are not included since their block no longer
+// exists in the code.
+func ToReviewResources(out Root) []ReviewResource {
+	var resources []ReviewResource
+
+	for _, project := range out.Projects {
+		if project.Diff == nil || project.Breakdown == nil {
+			continue
+		}
+
+		current := make(map[string]Resource, len(project.Breakdown.Resources))
+		for _, r := range project.Breakdown.Resources {
+			current[r.Name] = r
+		}
+
+		past := make(map[string]Resource)
+		if project.PastBreakdown != nil {
+			for _, r := range project.PastBreakdown.Resources {
+				past[r.Name] = r
+			}
+		}
+
+		for _, diff := range project.Diff.Resources {
+			if diff.MonthlyCost == nil || diff.MonthlyCost.IsZero() {
+				continue
+			}
+
+			r, ok := current[diff.Name]
+			if !ok {
+				continue
+			}
+
+			filename, _ := r.Metadata["filename"].(string)
+			startLine := metadataInt(r.Metadata["startLine"])
+			endLine := metadataInt(r.Metadata["endLine"])
+			if filename == "" || startLine == 0 {
+				continue
+			}
+
+			if endLine < startLine {
+				endLine = startLine
+			}
+
+			var pastCost *decimal.Decimal
+			if p, ok := past[diff.Name]; ok {
+				pastCost = p.MonthlyCost
+			}
+
+			var projectName string
+			if len(out.Projects) > 1 {
+				projectName = project.Label()
+			}
+
+			resources = append(resources, ReviewResource{
+				Project:   project.Name,
+				Address:   diff.Name,
+				Filename:  filename,
+				StartLine: startLine,
+				EndLine:   endLine,
+				Body:      reviewCommentBody(out.Currency, projectName, diff, pastCost, r.MonthlyCost),
+			})
+		}
+	}
+
+	return resources
+}
+
+// reviewCommentBody returns a short markdown summary of the cost change of
+// the resource with a row for each cost component that changed.
+func reviewCommentBody(currency, projectName string, diff Resource, pastCost, cost *decimal.Decimal) string {
+	var b strings.Builder
+
+	change := "increase"
+	if diff.MonthlyCost.IsNegative() {
+		change = "decrease"
+	}
+
+	if pastCost == nil {
+		pastCost = decimalPtr(decimal.Zero)
+	}
+
+	if cost == nil {
+		cost = decimalPtr(decimal.Zero)
+	}
+
+	fmt.Fprintf(&b, "💰 Monthly cost of `%s` will %s by **%s** (%s → %s)",
+		diff.Name,
+		change,
+		formatMarkdownCostChange(currency, pastCost, cost, true, true, false),
+		formatCost(currency, pastCost),
+		formatCost(currency, cost),
+	)
+
+	if projectName != "" {
+		fmt.Fprintf(&b, " in %s", projectName)
+	}
+	b.WriteString("\n")
+
+	rows := reviewCostComponentRows(currency, "", diff)
+	if len(rows) > 0 {
+		b.WriteString("\n| Cost component | Monthly cost change |\n| --- | ---: |\n")
+		for _, row := range rows {
+			b.WriteString(row)
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+func reviewCostComponentRows(currency, prefix string, r Resource) []string {
+	var rows []string
+
+	for _, c := range r.CostComponents {
+		if c.MonthlyCost == nil || c.MonthlyCost.IsZero() {
+			continue
+		}
+
+		cost := formatCost(currency, c.MonthlyCost)
+		if c.MonthlyCost.IsPositive() {
+			cost = "+" + cost
+		}
+
+		rows = append(rows, fmt.Sprintf("| %s%s | %s |", prefix, strings.ReplaceAll(c.Name, "|", `\|`), cost))
+	}
+
+	for _, s := range r.SubResources {
+		rows = append(rows, reviewCostComponentRows(currency, prefix+s.Name+" / ", s)...)
+	}
+
+	return rows
+}
+
+// metadataInt returns the metadata value as an int. Values are ints when the
+// output is built in memory and float64s when it is loaded from JSON.
+func metadataInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
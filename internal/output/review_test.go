@@ -0,0 +1,94 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToReviewResources(t *testing.T) {
+	out := Root{
+		Currency: "USD",
+		Projects: Projects{
+			{
+				Name:     "infracost/infracost/dev",
+				Metadata: &schema.ProjectMetadata{Path: "dev"},
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(40)),
+							Metadata:    map[string]interface{}{"filename": "dev/main.tf", "startLine": 3, "endLine": 8},
+						},
+						{
+							Name:        "aws_instance.db",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+							// Loaded from JSON so the lines are float64s.
+							Metadata: map[string]interface{}{"filename": "dev/main.tf", "startLine": float64(10), "endLine": float64(12)},
+						},
+						{
+							Name:        "aws_instance.unchanged",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+							Metadata:    map[string]interface{}{"filename": "dev/main.tf", "startLine": 14, "endLine": 16},
+						},
+						{
+							Name:        "aws_instance.no_metadata",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+						},
+					},
+				},
+				Diff: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(30)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage (Linux/UNIX, on-demand, m5.large)", MonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+								{Name: "EC2 detailed monitoring", MonthlyCost: decimalPtr(decimal.Zero)},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage (general purpose SSD, gp2)", MonthlyCost: decimalPtr(decimal.NewFromInt(-2))},
+									},
+								},
+							},
+						},
+						{Name: "aws_instance.db", MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+						{Name: "aws_instance.unchanged", MonthlyCost: decimalPtr(decimal.Zero)},
+						{Name: "aws_instance.no_metadata", MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+						{Name: "aws_instance.removed", MonthlyCost: decimalPtr(decimal.NewFromInt(-5))},
+					},
+				},
+			},
+		},
+	}
+
+	resources := ToReviewResources(out)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "aws_instance.web", resources[0].Address)
+	assert.Equal(t, "dev/main.tf", resources[0].Filename)
+	assert.Equal(t, 3, resources[0].StartLine)
+	assert.Equal(t, 8, resources[0].EndLine)
+	assert.Equal(t, "💰 Monthly cost of `aws_instance.web` will increase by **$30** ($10 → $40)\n"+
+		"\n| Cost component | Monthly cost change |\n| --- | ---: |\n"+
+		"| Instance usage (Linux/UNIX, on-demand, m5.large) | +$30 |\n"+
+		"| root_block_device / Storage (general purpose SSD, gp2) | -$2 |\n",
+		resources[0].Body)
+
+	assert.Equal(t, "aws_instance.db", resources[1].Address)
+	assert.Equal(t, 10, resources[1].StartLine)
+	assert.Equal(t, 12, resources[1].EndLine)
+	assert.Contains(t, resources[1].Body, "will increase by **$5** ($0.00 → $5)")
+}
@@ -6,66 +6,31 @@ import (
 	"math"
 
 	"github.com/pkg/errors"
-	"github.com/shopspring/decimal"
 	"github.com/slack-go/slack"
 
 	"github.com/infracost/infracost/internal/ui"
 )
 
-func slackSummaryBlock(name string, currency string, cost, pastCost, diffCost *decimal.Decimal) []*slack.TextBlockObject {
-	if cost == nil {
-		cost = decimalPtr(decimal.Zero)
-	}
-
-	if diffCost == nil {
-		// If we don't have a past cost or a diff cost then it means the cost increase is the total cost
-		if pastCost == nil {
-			diffCost = cost
-		} else {
-			diffCost = decimalPtr(decimal.Zero)
-		}
-	}
-
-	if pastCost == nil {
-		pastCost = decimalPtr(decimal.Zero)
-	}
-
+func slackSummaryBlock(summary CostSummary) []*slack.TextBlockObject {
 	return []*slack.TextBlockObject{
 		{
 			Type: slack.PlainTextType,
-			Text: name,
+			Text: summary.Name,
 		},
 		{
 			Type: slack.PlainTextType,
-			Text: fmt.Sprintf("%s%s", formatCostChange(currency, diffCost), formatCostChangeDetails(currency, pastCost, cost)),
+			Text: summary.Diff,
 		},
 	}
 }
 
-func slackProjectSummaryBlock(project Project, currency string) []*slack.TextBlockObject {
-	var pastCost, cost, diffCost *decimal.Decimal
-
-	if project.PastBreakdown != nil {
-		pastCost = project.PastBreakdown.TotalMonthlyCost
-	}
-
-	if project.Breakdown != nil {
-		cost = project.Breakdown.TotalMonthlyCost
-	}
-
-	if project.Diff != nil {
-		diffCost = project.Diff.TotalMonthlyCost
-	}
-
-	return slackSummaryBlock(truncateMiddle(project.Label(), 42, "..."), currency, cost, pastCost, diffCost)
-}
-
-func slackAllProjectsSummaryBlock(out Root, currency string) []*slack.TextBlockObject {
-	return slackSummaryBlock("All projects", currency, out.TotalMonthlyCost, out.PastTotalMonthlyCost, out.DiffTotalMonthlyCost)
-}
-
 func ToSlackMessage(out Root, opts Options) ([]byte, error) {
-	diff, err := ToDiff(out, opts)
+	// guardrail violations are added as their own section so that they
+	// are never truncated from the diff output.
+	diffOut := out
+	diffOut.GuardrailViolations = nil
+
+	diff, err := ToDiff(diffOut, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to generate diff")
 	}
@@ -81,15 +46,9 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		},
 	}
 
-	for _, project := range out.Projects {
-		if len(out.Projects) != 1 && (project.Diff == nil || len(project.Diff.Resources) == 0) {
-			continue
-		}
-		projectBlocks = append(projectBlocks, slackProjectSummaryBlock(project, out.Currency)...)
-	}
-
-	if len(out.Projects) > 1 {
-		projectBlocks = append(projectBlocks, slackAllProjectsSummaryBlock(out, out.Currency)...)
+	summaries, skippedProjectCount := notificationCostSummaries(out)
+	for _, summary := range summaries {
+		projectBlocks = append(projectBlocks, slackSummaryBlock(summary)...)
 	}
 
 	// Slack limits to 10 fields per section block, so we should chunk by these to create a new section for each
@@ -101,13 +60,6 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		projectSections = append(projectSections, slack.NewSectionBlock(nil, fieldBlocks, nil))
 	}
 
-	skippedProjectCount := 0
-	for _, p := range out.Projects {
-		if p.Diff == nil || len(p.Diff.Resources) == 0 {
-			skippedProjectCount++
-		}
-	}
-
 	blocks := []slack.Block{
 		slack.NewSectionBlock(
 			&slack.TextBlockObject{
@@ -123,14 +75,7 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		blocks = append(blocks, section)
 	}
 
-	skippedProjectMessage := ""
-	if len(out.Projects) > 1 {
-		if skippedProjectCount == 1 {
-			skippedProjectMessage = "1 project has no cost estimate changes."
-		} else if skippedProjectCount > 0 {
-			skippedProjectMessage = fmt.Sprintf("%d projects have no cost estimate changes.", skippedProjectCount)
-		}
-	}
+	skippedProjectMessage := skippedProjectsMessage(out, skippedProjectCount)
 
 	if skippedProjectMessage != "" {
 		blocks = append(blocks, slack.NewSectionBlock(
@@ -142,6 +87,21 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		))
 	}
 
+	if len(out.GuardrailViolations) > 0 {
+		guardrailsMsg := "*❌ Guardrails failed*"
+		for _, v := range out.GuardrailViolations {
+			guardrailsMsg += "\n• " + v.Message
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: guardrailsMsg,
+			},
+			[]*slack.TextBlockObject{}, nil,
+		))
+	}
+
 	diffMsg := fmt.Sprintf("*Infracost output*\n```%s```", ui.StripColor(string(diff)))
 	diffMsg = truncateMiddle(diffMsg, 3000, "\n\n...(truncated due to Slack message length)...\n\n")
 
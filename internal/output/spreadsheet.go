@@ -0,0 +1,385 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// spreadsheetHeaders are the columns of the csv output and of the project
+// sheets of the xlsx output.
+var spreadsheetHeaders = []string{
+	"Project",
+	"Project path",
+	"Module path",
+	"Workspace",
+	"Resource",
+	"Resource type",
+	"Subresource",
+	"Tags",
+	"Cost component",
+	"Unit",
+	"Price",
+	"Monthly quantity",
+	"Hourly cost",
+	"Monthly cost",
+	"Past monthly quantity",
+	"Past monthly cost",
+	"Diff monthly quantity",
+	"Diff monthly cost",
+	"Currency",
+}
+
+// costComponentRow is a cost component flattened with the project and
+// resource it belongs to, so that the costs can be pivoted in a spreadsheet.
+type costComponentRow struct {
+	project         *Project
+	resource        string
+	resourceType    string
+	subresource     string
+	tags            string
+	costComponent   string
+	unit            string
+	price           *decimal.Decimal
+	monthlyQuantity *decimal.Decimal
+	hourlyCost      *decimal.Decimal
+	monthlyCost     *decimal.Decimal
+
+	pastMonthlyQuantity *decimal.Decimal
+	pastMonthlyCost     *decimal.Decimal
+	diffMonthlyQuantity *decimal.Decimal
+	diffMonthlyCost     *decimal.Decimal
+}
+
+// costComponentKey identifies a cost component across the past, current
+// and diff breakdowns of a project.
+type costComponentKey struct {
+	resource      string
+	subresource   string
+	costComponent string
+}
+
+type flatCostComponent struct {
+	key          costComponentKey
+	resourceType string
+	tags         map[string]string
+	component    CostComponent
+}
+
+// flattenResources returns the cost components of the resources and their
+// subresources in order. Subresources are identified by the path of their
+// names from the top-level resource and share its type and tags, which include
+// the provider default tags and propagated tags like when grouping by tag.
+func flattenResources(resources []Resource) []flatCostComponent {
+	var flat []flatCostComponent
+
+	var walk func(r Resource, resource, resourceType string, tags map[string]string, subresource string)
+	walk = func(r Resource, resource, resourceType string, tags map[string]string, subresource string) {
+		for _, c := range r.CostComponents {
+			flat = append(flat, flatCostComponent{
+				key:          costComponentKey{resource: resource, subresource: subresource, costComponent: c.Name},
+				resourceType: resourceType,
+				tags:         tags,
+				component:    c,
+			})
+		}
+
+		for _, s := range r.SubResources {
+			path := s.Name
+			if subresource != "" {
+				path = subresource + " / " + s.Name
+			}
+			walk(s, resource, resourceType, tags, path)
+		}
+	}
+
+	for _, r := range resources {
+		walk(r, r.Name, r.ResourceType, ResourceTags(r), "")
+	}
+
+	return flat
+}
+
+func formatSpreadsheetTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "; ")
+}
+
+// projectCostComponentRows returns a row for each cost component of the
+// project. Cost components that only exist in the past breakdown, e.g. of
+// removed resources, are added after the current ones. Past and diff values
+// are only set when the project has a past breakdown.
+func projectCostComponentRows(project *Project) []costComponentRow {
+	var current, past, diff []flatCostComponent
+	if project.Breakdown != nil {
+		current = flattenResources(project.Breakdown.Resources)
+	}
+
+	hasPast := project.PastBreakdown != nil
+	if hasPast {
+		past = flattenResources(project.PastBreakdown.Resources)
+	}
+
+	if project.Diff != nil {
+		diff = flattenResources(project.Diff.Resources)
+	}
+
+	pastByKey := make(map[costComponentKey]CostComponent, len(past))
+	for _, c := range past {
+		pastByKey[c.key] = c.component
+	}
+
+	diffByKey := make(map[costComponentKey]CostComponent, len(diff))
+	for _, c := range diff {
+		diffByKey[c.key] = c.component
+	}
+
+	newRow := func(c flatCostComponent) costComponentRow {
+		price := c.component.Price
+		return costComponentRow{
+			project:       project,
+			resource:      c.key.resource,
+			resourceType:  c.resourceType,
+			subresource:   c.key.subresource,
+			tags:          formatSpreadsheetTags(c.tags),
+			costComponent: c.key.costComponent,
+			unit:          c.component.Unit,
+			price:         &price,
+		}
+	}
+
+	setPastAndDiff := func(row *costComponentRow, key costComponentKey) {
+		if !hasPast {
+			return
+		}
+
+		if p, ok := pastByKey[key]; ok {
+			row.pastMonthlyQuantity = p.MonthlyQuantity
+			row.pastMonthlyCost = p.MonthlyCost
+		}
+
+		// The diff only contains the cost components that changed.
+		row.diffMonthlyQuantity = decimalPtr(decimal.Zero)
+		row.diffMonthlyCost = decimalPtr(decimal.Zero)
+		if d, ok := diffByKey[key]; ok {
+			row.diffMonthlyQuantity = d.MonthlyQuantity
+			row.diffMonthlyCost = d.MonthlyCost
+		}
+	}
+
+	rows := make([]costComponentRow, 0, len(current))
+	seen := make(map[costComponentKey]bool, len(current))
+
+	for _, c := range current {
+		seen[c.key] = true
+
+		row := newRow(c)
+		row.monthlyQuantity = c.component.MonthlyQuantity
+		row.hourlyCost = c.component.HourlyCost
+		row.monthlyCost = c.component.MonthlyCost
+		setPastAndDiff(&row, c.key)
+
+		rows = append(rows, row)
+	}
+
+	for _, c := range past {
+		if seen[c.key] {
+			continue
+		}
+		seen[c.key] = true
+
+		row := newRow(c)
+		setPastAndDiff(&row, c.key)
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (r costComponentRow) cells(currency string) []xlsxCell {
+	text := func(s string) xlsxCell { return xlsxCell{Value: s} }
+	number := xlsxNumber
+
+	var projectPath, modulePath, workspace string
+	if r.project.Metadata != nil {
+		projectPath = r.project.Metadata.Path
+		modulePath = r.project.Metadata.TerraformModulePath
+		workspace = r.project.Metadata.WorkspaceLabel()
+	}
+
+	return []xlsxCell{
+		text(r.project.Label()),
+		text(projectPath),
+		text(modulePath),
+		text(workspace),
+		text(r.resource),
+		text(r.resourceType),
+		text(r.subresource),
+		text(r.tags),
+		text(r.costComponent),
+		text(r.unit),
+		number(r.price),
+		number(r.monthlyQuantity),
+		number(r.hourlyCost),
+		number(r.monthlyCost),
+		number(r.pastMonthlyQuantity),
+		number(r.pastMonthlyCost),
+		number(r.diffMonthlyQuantity),
+		number(r.diffMonthlyCost),
+		text(currency),
+	}
+}
+
+// ToCSV returns the output as CSV with a row for each cost component of each
+// project.
+func ToCSV(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write(spreadsheetHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range out.Projects {
+		for _, row := range projectCostComponentRows(&out.Projects[i]) {
+			cells := row.cells(out.Currency)
+
+			record := make([]string, len(cells))
+			for j, c := range cells {
+				record[j] = c.Value
+			}
+
+			err := w.Write(record)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ToXLSX returns the output as an Excel workbook with a summary sheet of the
+// project totals and a sheet for each project with a row for each of its
+// cost components.
+func ToXLSX(out Root, opts Options) ([]byte, error) {
+	names := []string{"Summary"}
+	for i := range out.Projects {
+		names = append(names, out.Projects[i].Label())
+	}
+	names = xlsxSheetNames(names)
+
+	sheets := []xlsxSheet{{Name: names[0], Rows: xlsxSummaryRows(out)}}
+
+	headerRow := make([]xlsxCell, len(spreadsheetHeaders))
+	for i, h := range spreadsheetHeaders {
+		headerRow[i] = xlsxCell{Value: h}
+	}
+
+	for i := range out.Projects {
+		rows := [][]xlsxCell{headerRow}
+		for _, row := range projectCostComponentRows(&out.Projects[i]) {
+			rows = append(rows, row.cells(out.Currency))
+		}
+
+		sheets = append(sheets, xlsxSheet{Name: names[i+1], Rows: rows})
+	}
+
+	b, err := writeXLSX(sheets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write xlsx: %w", err)
+	}
+
+	return b, nil
+}
+
+// xlsxNumber returns a numeric cell for the value, or an empty cell if it
+// is nil.
+func xlsxNumber(d *decimal.Decimal) xlsxCell {
+	if d == nil {
+		return xlsxCell{}
+	}
+
+	return xlsxCell{Value: d.String(), Numeric: true}
+}
+
+func xlsxSummaryRows(out Root) [][]xlsxCell {
+	number := xlsxNumber
+
+	rows := [][]xlsxCell{{
+		{Value: "Project"},
+		{Value: "Project path"},
+		{Value: "Module path"},
+		{Value: "Workspace"},
+		{Value: "Past monthly cost"},
+		{Value: "Monthly cost"},
+		{Value: "Diff monthly cost"},
+		{Value: "Currency"},
+	}}
+
+	for i := range out.Projects {
+		p := &out.Projects[i]
+
+		var projectPath, modulePath, workspace string
+		if p.Metadata != nil {
+			projectPath = p.Metadata.Path
+			modulePath = p.Metadata.TerraformModulePath
+			workspace = p.Metadata.WorkspaceLabel()
+		}
+
+		var pastCost, cost, diffCost *decimal.Decimal
+		if p.PastBreakdown != nil {
+			pastCost = p.PastBreakdown.TotalMonthlyCost
+		}
+		if p.Breakdown != nil {
+			cost = p.Breakdown.TotalMonthlyCost
+		}
+		if p.Diff != nil {
+			diffCost = p.Diff.TotalMonthlyCost
+		}
+
+		rows = append(rows, []xlsxCell{
+			{Value: p.Label()},
+			{Value: projectPath},
+			{Value: modulePath},
+			{Value: workspace},
+			number(pastCost),
+			number(cost),
+			number(diffCost),
+			{Value: out.Currency},
+		})
+	}
+
+	rows = append(rows, []xlsxCell{
+		{Value: "Total"},
+		{},
+		{},
+		{},
+		number(out.PastTotalMonthlyCost),
+		number(out.TotalMonthlyCost),
+		number(out.DiffTotalMonthlyCost),
+		{Value: out.Currency},
+	})
+
+	return rows
+}
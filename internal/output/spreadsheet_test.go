@@ -0,0 +1,162 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func spreadsheetTestRoot() Root {
+	d := func(v float64) *decimal.Decimal { return decimalPtr(decimal.NewFromFloat(v)) }
+	tags := map[string]string{"env": "prod", "app": "web"}
+	defaultTags := map[string]string{"env": "dev", "team": "platform"}
+
+	return Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: d(30),
+		TotalMonthlyCost:     d(50),
+		DiffTotalMonthlyCost: d(20),
+		Projects: Projects{
+			{
+				Name:     "infracost/infracost/dev",
+				Metadata: &schema.ProjectMetadata{Path: "dev", TerraformModulePath: "modules/web"},
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: d(30),
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         &tags,
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromFloat(0.01), MonthlyQuantity: d(730), MonthlyCost: d(7.3)},
+							},
+						},
+						{
+							Name:         "aws_eip.old",
+							ResourceType: "aws_eip",
+							DefaultTags:  &defaultTags,
+							CostComponents: []CostComponent{
+								{Name: "IP address", Unit: "hours", Price: decimal.NewFromFloat(0.005), MonthlyQuantity: d(730), MonthlyCost: d(3.65)},
+							},
+						},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: d(50),
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         &tags,
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromFloat(0.02), MonthlyQuantity: d(730), HourlyCost: d(0.02), MonthlyCost: d(14.6)},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage", Unit: "GB", Price: decimal.NewFromFloat(0.1), MonthlyQuantity: d(8), MonthlyCost: d(0.8)},
+									},
+								},
+							},
+						},
+					},
+				},
+				Diff: &Breakdown{
+					TotalMonthlyCost: d(20),
+					Resources: []Resource{
+						{
+							Name: "aws_instance.web",
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Unit: "hours", Price: decimal.NewFromFloat(0.01), MonthlyQuantity: d(0), MonthlyCost: d(7.3)},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage", Unit: "GB", Price: decimal.NewFromFloat(0.1), MonthlyQuantity: d(8), MonthlyCost: d(0.8)},
+									},
+								},
+							},
+						},
+						{
+							Name: "aws_eip.old",
+							CostComponents: []CostComponent{
+								{Name: "IP address", Unit: "hours", Price: decimal.NewFromFloat(-0.005), MonthlyQuantity: d(-730), MonthlyCost: d(-3.65)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	b, err := ToCSV(spreadsheetTestRoot(), Options{})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, spreadsheetHeaders, records[0])
+	assert.Equal(t, [][]string{
+		{"infracost/infracost/dev", "dev", "modules/web", "", "aws_instance.web", "aws_instance", "", "app=web; env=prod", "Instance usage", "hours", "0.02", "730", "0.02", "14.6", "730", "7.3", "0", "7.3", "USD"},
+		{"infracost/infracost/dev", "dev", "modules/web", "", "aws_instance.web", "aws_instance", "root_block_device", "app=web; env=prod", "Storage", "GB", "0.1", "8", "", "0.8", "", "", "8", "0.8", "USD"},
+		{"infracost/infracost/dev", "dev", "modules/web", "", "aws_eip.old", "aws_eip", "", "env=dev; team=platform", "IP address", "hours", "0.005", "", "", "", "730", "3.65", "-730", "-3.65", "USD"},
+	}, records[1:])
+}
+
+func TestToXLSX(t *testing.T) {
+	out := spreadsheetTestRoot()
+	out.Projects = append(out.Projects, Project{Name: "infracost/infracost/prod"})
+
+	b, err := ToXLSX(out, Options{})
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Summary" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="infracost_infracost_dev" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="infracost_infracost_prod" sheetId="3" r:id="rId3"/>`)
+	assert.Contains(t, files, "xl/worksheets/sheet3.xml")
+
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="A4" t="inlineStr"><is><t xml:space="preserve">Total</t></is></c>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="F4"><v>50</v></c>`)
+	assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<c r="G3" t="inlineStr"><is><t xml:space="preserve">root_block_device</t></is></c>`)
+	assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<c r="N2"><v>14.6</v></c>`)
+}
+
+func TestXLSXSheetNames(t *testing.T) {
+	assert.Equal(t,
+		[]string{"Summary", "summary (2)", "a_b_c", "abcdefghijklmnopqrstuvwxyz01234", "abcdefghijklmnopqrstuvwxyz0 (2)"},
+		xlsxSheetNames([]string{"Summary", "summary", "a/b:c", "abcdefghijklmnopqrstuvwxyz0123456789", "abcdefghijklmnopqrstuvwxyz0123456789"}),
+	)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
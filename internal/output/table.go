@@ -88,6 +88,33 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		fmt.Sprintf("%*s ", padding, totalOut), // pad based on the last line length
 	)
 
+	if out.TotalCommitmentMonthlyCost != nil {
+		commitmentTitle := formatTitleWithCurrency(" OVERALL TOTAL WITH COMMITMENTS", out.Currency)
+		commitmentPadding := padding + len(overallTitle) - len(commitmentTitle)
+		if commitmentPadding < 1 {
+			commitmentPadding = 1
+		}
+
+		s += fmt.Sprintf("\n%s%s",
+			ui.BoldString(commitmentTitle),
+			fmt.Sprintf("%*s ", commitmentPadding, FormatCost2DP(out.Currency, out.TotalCommitmentMonthlyCost)),
+		)
+	}
+
+	groupBy, costGroups, err := costGroupsFromOptions(out, opts)
+	if err != nil {
+		return nil, err
+	}
+	if costGroups != nil {
+		s += "\n\n"
+		s += tableForCostGroups(out.Currency, groupBy, costGroups, false)
+	}
+
+	if len(out.TotalForecastMonthlyCosts) > 0 {
+		s += "\n\n"
+		s += forecastTable(out)
+	}
+
 	if hasUsageFootnote {
 		s += "\n\n"
 		s += usageCostsMessage(out, false)
@@ -105,6 +132,16 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += breakdownSummaryTable(out, opts)
 	}
 
+	if guardrailsMsg := guardrailsMessage(out); guardrailsMsg != "" {
+		s += "\n\n"
+		s += guardrailsMsg
+	}
+
+	if policiesMsg := localPoliciesMessage(out); policiesMsg != "" {
+		s += "\n\n"
+		s += policiesMsg
+	}
+
 	return []byte(s), nil
 }
 
@@ -240,6 +277,16 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		}
 		totalCostRow = append(totalCostRow, FormatCost2DP(currency, breakdown.TotalMonthlyCost))
 		t.AppendRow(totalCostRow)
+
+		if breakdown.TotalCommitmentMonthlyCost != nil {
+			var commitmentCostRow table.Row
+			commitmentCostRow = append(commitmentCostRow, ui.BoldString(formatTitleWithCurrency("Project total with commitments", currency)))
+			for q := 0; q < numOfFields; q++ {
+				commitmentCostRow = append(commitmentCostRow, "")
+			}
+			commitmentCostRow = append(commitmentCostRow, FormatCost2DP(currency, breakdown.TotalCommitmentMonthlyCost))
+			t.AppendRow(commitmentCostRow)
+		}
 	}
 
 	return t.Render()
@@ -332,10 +379,39 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			}
 
 			t.AppendRow(tableRow)
+
+			if c.Commitment != "" {
+				childPrefix := prefix + "│  "
+				if !hasSubResources && i == len(costComponents)-1 {
+					childPrefix = prefix + "   "
+				}
+
+				t.AppendRow(commitmentRow(currency, c, childPrefix, fields))
+			}
 		}
 	}
 }
 
+// commitmentRow returns a row that shows the cost of the cost component with
+// its commitment applied, this is shown under the normal cost component row.
+func commitmentRow(currency string, c CostComponent, prefix string, fields []string) table.Row {
+	tableRow := table.Row{fmt.Sprintf("%s with %s", ui.FaintString(prefix+"└─"), c.Commitment)}
+
+	for _, f := range []string{"price", "monthlyQuantity", "unit"} {
+		if contains(fields, f) {
+			tableRow = append(tableRow, "")
+		}
+	}
+	if contains(fields, "hourlyCost") {
+		tableRow = append(tableRow, FormatCost2DP(currency, c.CommitmentHourlyCost))
+	}
+	if contains(fields, "monthlyCost") {
+		tableRow = append(tableRow, FormatCost2DP(currency, c.CommitmentMonthlyCost))
+	}
+
+	return tableRow
+}
+
 func buildActualCostRows(t table.Writer, currency string, actualCosts []ActualCosts, prefix string, fields []string) {
 	for i, ac := range actualCosts {
 		labelPrefix := prefix + "├─"
@@ -0,0 +1,151 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// teamsMaxDiffLength is the maximum length of the diff included in a Teams
+// message. Teams rejects messages larger than about 28KB.
+const teamsMaxDiffLength = 10000
+
+// teamsMessage is the payload of a Microsoft Teams incoming webhook with an
+// Adaptive Card attachment.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     teamsAdaptiveCard `json:"content"`
+}
+
+type teamsAdaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	Actions []map[string]interface{} `json:"actions,omitempty"`
+	MSTeams map[string]interface{}   `json:"msteams,omitempty"`
+}
+
+func teamsTextBlock(text string, props map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{
+		"type": "TextBlock",
+		"text": text,
+		"wrap": true,
+	}
+
+	for k, v := range props {
+		block[k] = v
+	}
+
+	return block
+}
+
+// ToTeamsMessage returns the output as a Microsoft Teams incoming webhook
+// payload containing an Adaptive Card with the same summary as the Slack
+// message.
+func ToTeamsMessage(out Root, opts Options) ([]byte, error) {
+	// guardrail violations are added as their own block so that they
+	// are never truncated from the diff output.
+	diffOut := out
+	diffOut.GuardrailViolations = nil
+
+	diff, err := ToDiff(diffOut, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate diff")
+	}
+
+	summaries, skippedProjectCount := notificationCostSummaries(out)
+
+	facts := make([]map[string]interface{}, 0, len(summaries))
+	for _, summary := range summaries {
+		facts = append(facts, map[string]interface{}{
+			"title": summary.Name,
+			"value": summary.Diff,
+		})
+	}
+
+	body := []map[string]interface{}{
+		teamsTextBlock(fmt.Sprintf("💰 Infracost estimate: **%s**", formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, true)), map[string]interface{}{
+			"size": "Medium",
+		}),
+		{
+			"type":      "FactSet",
+			"separator": true,
+			"facts":     facts,
+		},
+	}
+
+	if msg := skippedProjectsMessage(out, skippedProjectCount); msg != "" {
+		body = append(body, teamsTextBlock(msg, nil))
+	}
+
+	if len(out.GuardrailViolations) > 0 {
+		guardrailsMsg := "**❌ Guardrails failed**"
+		for _, v := range out.GuardrailViolations {
+			guardrailsMsg += "\n\n- " + v.Message
+		}
+
+		body = append(body, teamsTextBlock(guardrailsMsg, map[string]interface{}{
+			"color": "Attention",
+		}))
+	}
+
+	diffMsg := truncateMiddle(ui.StripColor(string(diff)), teamsMaxDiffLength, "\n\n...(truncated due to Teams message length)...\n\n")
+	body = append(body,
+		teamsTextBlock("**Infracost output**", map[string]interface{}{
+			"separator": true,
+		}),
+		// CodeBlock keeps the indentation of the diff, clients that don't
+		// support it fall back to a monospace TextBlock.
+		map[string]interface{}{
+			"type":        "CodeBlock",
+			"codeSnippet": diffMsg,
+			"language":    "PlainText",
+			"fallback": teamsTextBlock(diffMsg, map[string]interface{}{
+				"fontType": "Monospace",
+				"size":     "Small",
+			}),
+		},
+	)
+
+	card := teamsAdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		// Use the full width of the chat so the diff isn't wrapped.
+		MSTeams: map[string]interface{}{
+			"width": "Full",
+		},
+	}
+
+	if url := notificationURL(out); url != "" {
+		card.Actions = []map[string]interface{}{
+			{
+				"type":  "Action.OpenUrl",
+				"title": "View in Infracost",
+				"url":   url,
+			},
+		}
+	}
+
+	msg := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content:     card,
+			},
+		},
+	}
+
+	return json.Marshal(msg)
+}
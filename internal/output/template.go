@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+)
+
+// ValidateTemplate checks that the user template at path can be read and
+// parsed with the helper funcs of the built-in markdown templates, so that a
+// broken template is reported before any costs are calculated or comments
+// posted.
+func ValidateTemplate(path string) error {
+	tmpl := template.New(filepath.Base(path))
+	tmpl.Funcs(sprig.TxtFuncMap())
+	tmpl.Funcs(markdownTemplateFuncs(Root{}, Options{}, MarkdownOptions{}))
+
+	return parseUserTemplate(tmpl, path)
+}
+
+// parseUserTemplate parses the user template at path as the body of tmpl.
+// The template is executed with a MarkdownCtx, so the output is available as
+// .Root.
+func parseUserTemplate(tmpl *template.Template, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading template %s: %w", path, err)
+	}
+
+	_, err = tmpl.Parse(string(b))
+	if err != nil {
+		return fmt.Errorf("Invalid template %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "comment.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestToMarkdownWithTemplatePath(t *testing.T) {
+	r, err := Load("testdata/ToMarkdown_NoPreviousBreakdown.json")
+	require.NoError(t, err)
+
+	path := writeTemplate(t, `Cost change: {{ formatCostChange .Root.PastTotalMonthlyCost .Root.TotalMonthlyCost }}
+{{- range .Root.Projects }}
+- {{ projectLabel . }}: {{ formatCost .Breakdown.TotalMonthlyCost }}
+{{- end }}
+`)
+
+	actual, err := ToMarkdown(r, Options{TemplatePath: path}, MarkdownOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Cost change: +$0\n- main: $0\n", string(actual.Msg))
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, ValidateTemplate(writeTemplate(t, `{{ formatCost .Root.TotalMonthlyCost }} {{ formatPercentChange .Root.PastTotalMonthlyCost .Root.TotalMonthlyCost }}`)))
+
+	err := ValidateTemplate(writeTemplate(t, `{{ unknownFunc .Root }}`))
+	assert.ErrorContains(t, err, `function "unknownFunc" not defined`)
+
+	err = ValidateTemplate(writeTemplate(t, `{{ range .Root.Projects }}`))
+	assert.ErrorContains(t, err, "Invalid template")
+
+	err = ValidateTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.ErrorContains(t, err, "Error reading template")
+}
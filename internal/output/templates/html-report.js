@@ -0,0 +1,547 @@
+(function () {
+  'use strict';
+
+  var SVG_NS = 'http://www.w3.org/2000/svg';
+  var MAX_BARS = 15;
+  var COLORS = ['#3b82f6', '#10b981', '#f59e0b', '#8b5cf6', '#ef4444', '#06b6d4', '#84cc16', '#ec4899', '#6366f1', '#14b8a6', '#f97316', '#a855f7'];
+
+  var data = JSON.parse(document.getElementById('infracost-data').textContent);
+
+  var currencyFormat;
+  try {
+    currencyFormat = new Intl.NumberFormat(undefined, { style: 'currency', currency: data.currency || 'USD' });
+  } catch (e) {
+    currencyFormat = new Intl.NumberFormat(undefined, { minimumFractionDigits: 2, maximumFractionDigits: 2 });
+  }
+
+  function formatCost(v) {
+    if (v === null || v === undefined) {
+      return '-';
+    }
+    return currencyFormat.format(v);
+  }
+
+  function formatChange(v) {
+    if (v === null || v === undefined) {
+      return '-';
+    }
+    if (v > 0) {
+      return '+' + currencyFormat.format(v);
+    }
+    return currencyFormat.format(v);
+  }
+
+  function formatNumber(v) {
+    if (v === null || v === undefined) {
+      return '-';
+    }
+    return String(Math.round(v * 10000) / 10000);
+  }
+
+  function changeClass(v) {
+    if (v > 0) {
+      return 'increase';
+    }
+    if (v < 0) {
+      return 'decrease';
+    }
+    return '';
+  }
+
+  function el(tag, attrs, children) {
+    var e = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === 'text') {
+        e.textContent = attrs[k];
+      } else if (k === 'className') {
+        e.className = attrs[k];
+      } else {
+        e.setAttribute(k, attrs[k]);
+      }
+    });
+    (children || []).forEach(function (c) {
+      e.appendChild(c);
+    });
+    return e;
+  }
+
+  function svgEl(tag, attrs, text) {
+    var e = document.createElementNS(SVG_NS, tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      e.setAttribute(k, attrs[k]);
+    });
+    if (text !== undefined) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  function tagsText(tags) {
+    return Object.keys(tags || {}).sort().map(function (k) {
+      return k + '=' + tags[k];
+    }).join(', ');
+  }
+
+  // Summary
+
+  function renderSummary() {
+    var summary = document.getElementById('summary');
+    var cards = [['Monthly cost', formatCost(data.totalMonthlyCost), '']];
+    if (data.hasDiff) {
+      cards.unshift(['Baseline cost', formatCost(data.pastTotalMonthlyCost), '']);
+      cards.push(['Monthly change', formatChange(data.diffTotalMonthlyCost), changeClass(data.diffTotalMonthlyCost)]);
+    }
+    cards.push(['Projects', String(data.projects.length), '']);
+    cards.push(['Resources', String(data.resources.length), '']);
+    if (data.timeGenerated) {
+      cards.push(['Generated', new Date(data.timeGenerated).toLocaleString(), '']);
+    }
+
+    cards.forEach(function (c) {
+      summary.appendChild(el('div', { className: 'card' }, [
+        el('div', { className: 'label', text: c[0] }),
+        el('div', { className: 'value ' + c[2], text: c[1] })
+      ]));
+    });
+  }
+
+  // Sortable tables
+
+  function costColumns() {
+    var cols = [];
+    if (data.hasDiff) {
+      cols.push({ key: 'pastMonthlyCost', label: 'Baseline cost', number: true, format: formatCost });
+    }
+    cols.push({ key: 'monthlyCost', label: 'Monthly cost', number: true, format: formatCost });
+    if (data.hasDiff) {
+      cols.push({ key: 'diffMonthlyCost', label: 'Monthly change', number: true, format: formatChange, className: changeClass });
+    }
+    return cols;
+  }
+
+  function compare(a, b, col) {
+    var av = a[col.key];
+    var bv = b[col.key];
+    if (col.number) {
+      av = av === null || av === undefined ? -Infinity : av;
+      bv = bv === null || bv === undefined ? -Infinity : bv;
+      return av - bv;
+    }
+    return String(av || '').localeCompare(String(bv || ''));
+  }
+
+  // SortableTable renders rows into a table, re-rendering them when a column
+  // header is clicked. renderRow appends the rows for a single item so that
+  // tables can add extra rows, e.g. for expanded cost components.
+  function SortableTable(table, columns, sort, renderRow) {
+    this.table = table;
+    this.columns = columns;
+    this.sort = sort;
+    this.renderRow = renderRow;
+    this.items = [];
+  }
+
+  SortableTable.prototype.render = function (items) {
+    var self = this;
+    if (items) {
+      this.items = items;
+    }
+
+    var col = this.columns[this.sort.column];
+    var sorted = this.items.slice().sort(function (a, b) {
+      var c = compare(a, b, col);
+      return self.sort.desc ? -c : c;
+    });
+
+    var headerRow = el('tr');
+    this.columns.forEach(function (c, i) {
+      var th = el('th', { text: c.label, className: c.number ? 'number' : '' });
+      if (i === self.sort.column) {
+        th.className += self.sort.desc ? ' sorted-desc' : ' sorted-asc';
+      }
+      th.addEventListener('click', function () {
+        if (self.sort.column === i) {
+          self.sort.desc = !self.sort.desc;
+        } else {
+          self.sort.column = i;
+          self.sort.desc = !!c.number;
+        }
+        self.render();
+      });
+      headerRow.appendChild(th);
+    });
+
+    var tbody = el('tbody');
+    sorted.forEach(function (item) {
+      self.renderRow(tbody, item);
+    });
+    if (sorted.length === 0) {
+      tbody.appendChild(el('tr', {}, [el('td', { colspan: String(this.columns.length), className: 'empty', text: 'Nothing to show' })]));
+    }
+
+    this.table.textContent = '';
+    this.table.appendChild(el('thead', {}, [headerRow]));
+    this.table.appendChild(tbody);
+  };
+
+  function cells(item, columns) {
+    return columns.map(function (c) {
+      var v = item[c.key];
+      var className = (c.number ? 'number ' : '') + (c.className ? c.className(v) : '');
+      return el('td', { className: className, text: c.format ? c.format(v) : (v || '') });
+    });
+  }
+
+  // Projects
+
+  function renderProjects() {
+    var columns = [
+      { key: 'name', label: 'Project' },
+      { key: 'resourceCount', label: 'Resources', number: true, format: String }
+    ].concat(costColumns());
+
+    var table = new SortableTable(document.getElementById('projects'), columns, { column: columns.length - (data.hasDiff ? 2 : 1), desc: true }, function (tbody, p) {
+      tbody.appendChild(el('tr', {}, cells(p, columns)));
+    });
+    table.render(data.projects);
+  }
+
+  // Resources
+
+  var expanded = {};
+
+  function resourceKey(r) {
+    return r.project + '\u0000' + r.name;
+  }
+
+  function trackingChangeLabel(r) {
+    switch (r.trackingChange) {
+      case 'moved':
+        return 'moved from ' + r.movedFrom;
+      case 'imported':
+        return 'now tracked';
+      case 'removed':
+        return 'no longer tracked';
+      default:
+        return r.trackingChange;
+    }
+  }
+
+  function matchesSearch(r, query) {
+    if (!query) {
+      return true;
+    }
+    var haystack = [r.name, r.resourceType, r.project, r.module, r.provider, tagsText(r.tags)].join(' ').toLowerCase();
+    return query.split(/\s+/).every(function (term) {
+      return haystack.indexOf(term) !== -1;
+    });
+  }
+
+  function filteredResources() {
+    var query = document.getElementById('search').value.trim().toLowerCase();
+    var changedOnly = document.getElementById('changed-only').checked;
+    return data.resources.filter(function (r) {
+      if (changedOnly && !r.status) {
+        return false;
+      }
+      return matchesSearch(r, query);
+    });
+  }
+
+  function renderResourceRow(columns, table) {
+    // The cost component rows line up with the resource columns, with the
+    // quantity and price in the column after the name.
+    var componentColumns = columns.map(function (c, i) {
+      if (i === 0) {
+        return { key: 'label' };
+      }
+      if (i === 1) {
+        return { key: 'detail' };
+      }
+      return c.number ? c : { key: 'blank' };
+    });
+
+    return function (tbody, r) {
+      var key = resourceKey(r);
+      var row = el('tr', { className: 'resource ' + (r.status || '') }, cells(r, columns));
+      row.firstChild.className = 'name';
+      if (expanded[key]) {
+        row.className += ' expanded';
+      }
+      if (r.status) {
+        row.firstChild.appendChild(el('span', { className: 'status', text: ' (' + r.status + ')' }));
+      }
+      if (r.trackingChange) {
+        row.firstChild.appendChild(el('span', { className: 'status', text: ' (' + trackingChangeLabel(r) + ')' }));
+      }
+      row.addEventListener('click', function () {
+        expanded[key] = !expanded[key];
+        table.render();
+      });
+      tbody.appendChild(row);
+
+      if (!expanded[key]) {
+        return;
+      }
+
+      (r.costComponents || []).forEach(function (c) {
+        var item = {
+          label: c.subresource ? c.subresource + ' › ' + c.name : c.name,
+          detail: formatNumber(c.monthlyQuantity) + ' ' + (c.unit || '') + ' × ' + formatCost(c.price),
+          pastMonthlyCost: c.pastMonthlyCost,
+          monthlyCost: c.monthlyCost,
+          diffMonthlyCost: c.diffMonthlyCost
+        };
+        var componentRow = el('tr', { className: 'cost-component' }, cells(item, componentColumns));
+        componentRow.firstChild.className = 'name';
+        tbody.appendChild(componentRow);
+      });
+    };
+  }
+
+  var resourcesTable;
+
+  function renderResources() {
+    var columns = [
+      { key: 'name', label: 'Resource' },
+      { key: 'resourceType', label: 'Type' },
+      { key: 'tagsText', label: 'Tags' }
+    ].concat(costColumns());
+    if (data.projects.length > 1) {
+      columns.splice(1, 0, { key: 'project', label: 'Project' });
+    }
+
+    data.resources.forEach(function (r) {
+      r.tagsText = tagsText(r.tags);
+    });
+
+    var sort = { column: columns.length - (data.hasDiff ? 2 : 1), desc: true };
+    resourcesTable = new SortableTable(document.getElementById('resources'), columns, sort, null);
+    resourcesTable.renderRow = renderResourceRow(columns, resourcesTable);
+
+    resourcesTable.render(filteredResources());
+  }
+
+  // Charts
+
+  function groupName(r, groupBy) {
+    if (groupBy.indexOf('tag:') === 0) {
+      return (r.tags || {})[groupBy.slice(4)] || 'untagged';
+    }
+    switch (groupBy) {
+      case 'project':
+        return r.project;
+      case 'resource-type':
+        return r.resourceType || 'unknown';
+      case 'module':
+        return r.module || 'root';
+      case 'provider':
+        return r.provider || 'unknown';
+    }
+    return 'unknown';
+  }
+
+  function chartGroups(resources, groupBy, valueKey) {
+    var groups = {};
+    resources.forEach(function (r) {
+      var name = groupName(r, groupBy);
+      var g = groups[name] || (groups[name] = { name: name, value: 0, diff: 0 });
+      g.value += r[valueKey] || 0;
+      g.diff += r.diffMonthlyCost || 0;
+    });
+
+    return Object.keys(groups).map(function (k) {
+      return groups[k];
+    }).filter(function (g) {
+      return valueKey === 'diffMonthlyCost' ? g.value !== 0 : g.value > 0;
+    }).sort(function (a, b) {
+      return Math.abs(b.value) - Math.abs(a.value) || a.name.localeCompare(b.name);
+    });
+  }
+
+  // squarify lays out the items in the rectangle so that the tiles are as
+  // close to square as possible, see Bruls, Huizing and van Wijk, 2000.
+  function squarify(items, x, y, w, h) {
+    var total = items.reduce(function (s, i) { return s + i.size; }, 0);
+    var tiles = [];
+    var scale = total > 0 ? (w * h) / total : 0;
+    var remaining = items.map(function (i) { return { item: i, area: i.size * scale }; });
+
+    function worst(row, side) {
+      var sum = row.reduce(function (s, r) { return s + r.area; }, 0);
+      var max = Math.max.apply(null, row.map(function (r) { return r.area; }));
+      var min = Math.min.apply(null, row.map(function (r) { return r.area; }));
+      return Math.max((side * side * max) / (sum * sum), (sum * sum) / (side * side * min));
+    }
+
+    while (remaining.length > 0) {
+      var side = Math.min(w, h);
+      var row = [remaining.shift()];
+      while (remaining.length > 0 && worst(row.concat([remaining[0]]), side) <= worst(row, side)) {
+        row.push(remaining.shift());
+      }
+
+      var sum = row.reduce(function (s, r) { return s + r.area; }, 0);
+      var thickness = side > 0 ? sum / side : 0;
+      var offset = 0;
+      row.forEach(function (r) {
+        var length = thickness > 0 ? r.area / thickness : 0;
+        if (w >= h) {
+          tiles.push({ item: r.item, x: x, y: y + offset, w: thickness, h: length });
+        } else {
+          tiles.push({ item: r.item, x: x + offset, y: y, w: length, h: thickness });
+        }
+        offset += length;
+      });
+
+      if (w >= h) {
+        x += thickness;
+        w -= thickness;
+      } else {
+        y += thickness;
+        h -= thickness;
+      }
+    }
+
+    return tiles;
+  }
+
+  function renderTreemap(groups, format) {
+    var svg = document.getElementById('treemap');
+    var width = 600;
+    var height = 360;
+    svg.setAttribute('viewBox', '0 0 ' + width + ' ' + height);
+    svg.textContent = '';
+
+    if (groups.length === 0) {
+      svg.appendChild(svgEl('text', { x: 8, y: 20, 'class': 'empty' }, 'No costs to show'));
+      return;
+    }
+
+    var items = groups.map(function (g, i) {
+      return { group: g, size: Math.abs(g.value), color: COLORS[i % COLORS.length] };
+    });
+
+    squarify(items, 0, 0, width, height).forEach(function (t) {
+      var g = svgEl('g');
+      var rect = svgEl('rect', { 'class': 'tile', x: t.x, y: t.y, width: Math.max(t.w, 0), height: Math.max(t.h, 0), fill: t.item.color });
+      rect.appendChild(svgEl('title', {}, t.item.group.name + ': ' + format(t.item.group.value)));
+      g.appendChild(rect);
+
+      if (t.w > 60 && t.h > 30) {
+        var maxChars = Math.floor((t.w - 8) / 6.5);
+        var name = t.item.group.name;
+        if (name.length > maxChars) {
+          name = name.slice(0, Math.max(maxChars - 1, 1)) + '…';
+        }
+        g.appendChild(svgEl('text', { x: t.x + 4, y: t.y + 14, fill: '#fff' }, name));
+        g.appendChild(svgEl('text', { x: t.x + 4, y: t.y + 28, fill: '#fff' }, format(t.item.group.value)));
+      }
+      svg.appendChild(g);
+    });
+  }
+
+  function renderBars(groups, format, showChange) {
+    var svg = document.getElementById('bars');
+    var shown = groups.slice(0, MAX_BARS);
+    var labelWidth = 200;
+    var valueWidth = 90;
+    var width = 600;
+    var barHeight = 20;
+    var height = Math.max(shown.length * (barHeight + 4) + 20, 40);
+    svg.setAttribute('viewBox', '0 0 ' + width + ' ' + height);
+    svg.textContent = '';
+
+    if (shown.length === 0) {
+      svg.appendChild(svgEl('text', { x: 8, y: 20, 'class': 'empty' }, 'No costs to show'));
+      return;
+    }
+
+    var max = Math.max.apply(null, shown.map(function (g) { return Math.abs(g.value); }));
+    var barSpace = width - labelWidth - valueWidth;
+
+    shown.forEach(function (g, i) {
+      var y = i * (barHeight + 4);
+      var name = g.name.length > 32 ? g.name.slice(0, 31) + '…' : g.name;
+      var fill = COLORS[i % COLORS.length];
+      if (showChange) {
+        fill = g.value > 0 ? '#ef4444' : '#10b981';
+      }
+
+      svg.appendChild(svgEl('text', { x: 0, y: y + 14 }, name));
+      var bar = svgEl('rect', { x: labelWidth, y: y, width: max > 0 ? (Math.abs(g.value) / max) * barSpace : 0, height: barHeight, fill: fill });
+      bar.appendChild(svgEl('title', {}, g.name + ': ' + format(g.value)));
+      svg.appendChild(bar);
+      svg.appendChild(svgEl('text', { x: width, y: y + 14, 'text-anchor': 'end' }, format(g.value)));
+    });
+
+    if (groups.length > shown.length) {
+      svg.appendChild(svgEl('text', { x: 0, y: height - 4, 'class': 'empty' }, (groups.length - shown.length) + ' more not shown'));
+    }
+  }
+
+  function renderCharts() {
+    var groupBy = document.getElementById('group-by').value;
+    var valueKey = document.getElementById('chart-value').value;
+    var format = valueKey === 'diffMonthlyCost' ? formatChange : formatCost;
+    var groups = chartGroups(filteredResources(), groupBy, valueKey);
+
+    renderTreemap(groups, format);
+    renderBars(groups, format, valueKey === 'diffMonthlyCost');
+  }
+
+  function setupChartControls() {
+    var groupBy = document.getElementById('group-by');
+    var options = [['project', 'Project'], ['resource-type', 'Resource type'], ['module', 'Module'], ['provider', 'Provider']];
+    data.tagKeys.forEach(function (k) {
+      options.push(['tag:' + k, 'Tag: ' + k]);
+    });
+    options.forEach(function (o) {
+      groupBy.appendChild(el('option', { value: o[0], text: o[1] }));
+    });
+    if (data.projects.length === 1) {
+      groupBy.value = 'resource-type';
+    }
+
+    var chartValue = document.getElementById('chart-value');
+    var values = [['monthlyCost', 'Monthly cost']];
+    if (data.hasDiff) {
+      values.push(['pastMonthlyCost', 'Baseline cost'], ['diffMonthlyCost', 'Monthly change']);
+    } else {
+      document.getElementById('chart-value-label').style.display = 'none';
+      document.getElementById('changed-only-label').style.display = 'none';
+    }
+    values.forEach(function (v) {
+      chartValue.appendChild(el('option', { value: v[0], text: v[1] }));
+    });
+
+    groupBy.addEventListener('change', renderCharts);
+    chartValue.addEventListener('change', renderCharts);
+  }
+
+  function setupResourceControls() {
+    function update() {
+      resourcesTable.render(filteredResources());
+      renderCharts();
+    }
+
+    document.getElementById('search').addEventListener('input', update);
+    document.getElementById('changed-only').addEventListener('change', update);
+    document.getElementById('expand-all').addEventListener('change', function (e) {
+      expanded = {};
+      if (e.target.checked) {
+        data.resources.forEach(function (r) {
+          expanded[resourceKey(r)] = true;
+        });
+      }
+      resourcesTable.render();
+    });
+  }
+
+  renderSummary();
+  renderProjects();
+  setupChartControls();
+  renderResources();
+  setupResourceControls();
+  renderCharts();
+})();
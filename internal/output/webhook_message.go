@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// WebhookMessage is the data of the webhook-message format. Without a
+// template it is sent as JSON, otherwise it is the data the template is
+// executed with, so that it can be shaped into the payload of any chat tool
+// that accepts incoming webhooks, e.g. Discord or Mattermost.
+type WebhookMessage struct {
+	// Title is a one line summary of the change in the total monthly cost.
+	Title    string `json:"title"`
+	Currency string `json:"currency"`
+	// Projects are the cost summaries of the projects with changes, or of the
+	// project if there is only one.
+	Projects            []CostSummary `json:"projects"`
+	Total               CostSummary   `json:"total"`
+	SkippedProjectCount int           `json:"skippedProjectCount"`
+	GuardrailViolations []string      `json:"guardrailViolations,omitempty"`
+	URL                 string        `json:"url,omitempty"`
+	// Text is a plain text message with all of the above, for chat tools that
+	// only accept a single text field.
+	Text string `json:"text"`
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes a value so it can be safely embedded in a JSON template,
+	// e.g. {"content": {{ json .Text }}}.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ToWebhookMessage returns the output as a generic webhook payload. If
+// opts.WebhookTemplatePath is set, the template at that path is executed with
+// the WebhookMessage, otherwise the WebhookMessage is returned as JSON.
+func ToWebhookMessage(out Root, opts Options) ([]byte, error) {
+	msg := newWebhookMessage(out)
+
+	if opts.WebhookTemplatePath == "" {
+		return json.Marshal(msg)
+	}
+
+	b, err := os.ReadFile(opts.WebhookTemplatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read webhook template")
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookTemplateFuncs).Parse(string(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse webhook template")
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to execute webhook template")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newWebhookMessage(out Root) WebhookMessage {
+	projects, skippedProjectCount := notificationProjectCostSummaries(out)
+
+	msg := WebhookMessage{
+		Title:               formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, false),
+		Currency:            out.Currency,
+		Projects:            projects,
+		Total:               allProjectsCostSummary(out),
+		SkippedProjectCount: skippedProjectCount,
+		URL:                 notificationURL(out),
+	}
+
+	for _, v := range out.GuardrailViolations {
+		msg.GuardrailViolations = append(msg.GuardrailViolations, v.Message)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "💰 Infracost estimate: %s\n", formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, true))
+
+	summaries, _ := notificationCostSummaries(out)
+	for _, s := range summaries {
+		fmt.Fprintf(&text, "\n• %s: %s", s.Name, s.Diff)
+	}
+
+	if m := skippedProjectsMessage(out, skippedProjectCount); m != "" {
+		fmt.Fprintf(&text, "\n\n%s", m)
+	}
+
+	if len(msg.GuardrailViolations) > 0 {
+		text.WriteString("\n\n❌ Guardrails failed")
+		for _, v := range msg.GuardrailViolations {
+			fmt.Fprintf(&text, "\n• %s", v)
+		}
+	}
+
+	if msg.URL != "" {
+		fmt.Fprintf(&text, "\n\n%s", msg.URL)
+	}
+
+	msg.Text = text.String()
+
+	return msg
+}
@@ -0,0 +1,181 @@
+package policy
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// EvaluateProject checks all the policies in the file against the resources in
+// the project and returns a schema.Policy for each resource that violates a
+// policy. The project costs must already have been calculated so that
+// max_monthly_cost policies can be checked.
+func (f *File) EvaluateProject(project *schema.Project) schema.Policies {
+	if f == nil || project == nil {
+		return nil
+	}
+
+	costs := make(map[string]*schema.Resource, len(project.Resources))
+	for _, r := range project.Resources {
+		costs[r.Name] = r
+	}
+
+	var policies schema.Policies
+	for _, rule := range f.Policies {
+		for _, partial := range project.PartialResources {
+			if !rule.appliesTo(partial.Type) {
+				continue
+			}
+
+			p, ok := rule.evaluate(partial, costs[partial.Address])
+			if ok {
+				policies = append(policies, p)
+			}
+		}
+	}
+
+	return policies
+}
+
+func (r Rule) appliesTo(resourceType string) bool {
+	if len(r.ResourceTypes) == 0 {
+		return true
+	}
+
+	for _, t := range r.ResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evaluate returns the policy violation for the resource, the bool is false if
+// the resource passes the policy.
+func (r Rule) evaluate(partial *schema.PartialResource, resource *schema.Resource) (schema.Policy, bool) {
+	attributes := map[string]interface{}{}
+
+	for _, c := range r.Assert {
+		value := partial.RawValues.Get(c.Attribute)
+		if !c.check(value) {
+			attributes[c.Attribute] = value.Value()
+		}
+	}
+
+	if missing := r.missingTags(partial); len(missing) > 0 {
+		attributes["missing_tags"] = missing
+	}
+
+	var cost *decimal.Decimal
+	if resource != nil {
+		cost = resource.MonthlyCost
+	}
+
+	if r.MaxMonthlyCost != nil && cost != nil {
+		limit := decimal.NewFromFloat(*r.MaxMonthlyCost)
+		if cost.GreaterThan(limit) {
+			attributes["monthly_cost"] = cost.StringFixed(2)
+			attributes["max_monthly_cost"] = limit.StringFixed(2)
+		}
+	}
+
+	if len(attributes) == 0 {
+		return schema.Policy{}, false
+	}
+
+	// the attributes are only used for display so there's no way for this to
+	// fail, but fall back to an empty object just in case.
+	b, err := json.Marshal(attributes)
+	if err != nil {
+		b = []byte("{}")
+	}
+
+	return schema.Policy{
+		ID:                 r.ID,
+		Title:              r.Title,
+		Description:        r.Description,
+		ResourceType:       partial.Type,
+		ResourceAttributes: b,
+		Address:            partial.Address,
+		Suggested:          r.Suggested,
+		NoCost:             cost == nil,
+		Cost:               cost,
+	}, true
+}
+
+// missingTags returns the required tags that are not set on the resource or by
+// the provider default tags. Resources that don't support tags are skipped.
+func (r Rule) missingTags(partial *schema.PartialResource) []string {
+	if len(r.RequiredTags) == 0 || partial.Tags == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, key := range r.RequiredTags {
+		if _, ok := (*partial.Tags)[key]; ok {
+			continue
+		}
+
+		if partial.DefaultTags != nil {
+			if _, ok := (*partial.DefaultTags)[key]; ok {
+				continue
+			}
+		}
+
+		missing = append(missing, key)
+	}
+
+	sort.Strings(missing)
+	return missing
+}
+
+// check returns true if the value passes the condition.
+func (c Condition) check(value gjson.Result) bool {
+	switch {
+	case c.Exists != nil:
+		return value.Exists() == *c.Exists
+	case c.Equals != nil:
+		return value.Exists() && value.String() == *c.Equals
+	case c.NotEquals != nil:
+		return !value.Exists() || value.String() != *c.NotEquals
+	case c.In != nil:
+		return value.Exists() && contains(c.In, value.String())
+	case c.NotIn != nil:
+		return !value.Exists() || !contains(c.NotIn, value.String())
+	case c.Matches != "":
+		return value.Exists() && c.regexp().MatchString(value.String())
+	case c.NotMatches != "":
+		return !value.Exists() || !c.regexp().MatchString(value.String())
+	}
+
+	return true
+}
+
+func (c Condition) regexp() *regexp.Regexp {
+	if c.pattern != nil {
+		return c.pattern
+	}
+
+	// conditions that weren't loaded with LoadFile haven't been compiled yet.
+	if c.Matches != "" {
+		return regexp.MustCompile(c.Matches)
+	}
+
+	return regexp.MustCompile(c.NotMatches)
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+// Package policy evaluates cost and tagging policies locally, without sending
+// any resource information to Infracost Cloud.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	minPolicyFileVersion = "0.1"
+	maxPolicyFileVersion = "0.1"
+)
+
+// File is a local policy file containing a list of policies, e.g.
+//
+//	version: 0.1
+//	policies:
+//	  - id: aws-instance-previous-generation
+//	    title: EC2 instances must not use previous generation families
+//	    resource_types: [aws_instance]
+//	    assert:
+//	      - attribute: instance_type
+//	        not_matches: '^(t2|m4|c4|r4)\.'
+//	    suggested: Use a current generation instance family, e.g. t3 or m5
+//	  - id: cost-center-tag
+//	    title: All resources must carry a cost-center tag
+//	    required_tags: [cost-center]
+type File struct {
+	Version  string `yaml:"version"`
+	Policies []Rule `yaml:"policies"`
+}
+
+// Rule is a single declarative policy. A resource violates the policy if it
+// matches one of the ResourceTypes and fails any of the Assert conditions,
+// is missing any of the RequiredTags or costs more than MaxMonthlyCost.
+type Rule struct {
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	// Suggested is shown alongside the violation to help fix it.
+	Suggested string `yaml:"suggested,omitempty"`
+	// ResourceTypes limits the policy to these resource types, all resources are checked if empty.
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+	// Assert is a list of conditions on the resource attributes that must all be true.
+	Assert []Condition `yaml:"assert,omitempty"`
+	// RequiredTags are tag keys that must be set on all resources that support tags.
+	RequiredTags []string `yaml:"required_tags,omitempty"`
+	// MaxMonthlyCost is the maximum monthly cost of a single resource.
+	MaxMonthlyCost *float64 `yaml:"max_monthly_cost,omitempty"`
+}
+
+// Condition checks the value of a resource attribute. Attribute is a path into
+// the resource values, e.g. root_block_device.0.volume_type, and exactly one of
+// the operators must be set.
+type Condition struct {
+	Attribute  string   `yaml:"attribute"`
+	Equals     *string  `yaml:"equals,omitempty"`
+	NotEquals  *string  `yaml:"not_equals,omitempty"`
+	In         []string `yaml:"in,omitempty"`
+	NotIn      []string `yaml:"not_in,omitempty"`
+	Matches    string   `yaml:"matches,omitempty"`
+	NotMatches string   `yaml:"not_matches,omitempty"`
+	Exists     *bool    `yaml:"exists,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// LoadFile reads and validates the policy file at path.
+func LoadFile(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file %w", err)
+	}
+
+	var f File
+	err = yaml.UnmarshalStrict(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing policy file %s: %w", path, err)
+	}
+
+	err = f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s is invalid: %w", path, err)
+	}
+
+	return &f, nil
+}
+
+func (f *File) validate() error {
+	if !checkVersion(f.Version) {
+		return fmt.Errorf("version '%s' is not supported, valid versions are %s ≤ x ≤ %s", f.Version, minPolicyFileVersion, maxPolicyFileVersion)
+	}
+
+	seen := map[string]bool{}
+	for i := range f.Policies {
+		rule := &f.Policies[i]
+		if rule.ID == "" {
+			return fmt.Errorf("policy at index %d must have an id", i)
+		}
+
+		if seen[rule.ID] {
+			return fmt.Errorf("policy id %s is used more than once", rule.ID)
+		}
+		seen[rule.ID] = true
+
+		if rule.Title == "" {
+			return fmt.Errorf("policy %s must have a title", rule.ID)
+		}
+
+		if len(rule.Assert) == 0 && len(rule.RequiredTags) == 0 && rule.MaxMonthlyCost == nil {
+			return fmt.Errorf("policy %s must set at least one of assert, required_tags or max_monthly_cost", rule.ID)
+		}
+
+		for j := range rule.Assert {
+			err := rule.Assert[j].compile()
+			if err != nil {
+				return fmt.Errorf("policy %s condition at index %d is invalid: %w", rule.ID, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Condition) compile() error {
+	if c.Attribute == "" {
+		return errors.New("attribute must be set")
+	}
+
+	var ops []string
+	if c.Equals != nil {
+		ops = append(ops, "equals")
+	}
+	if c.NotEquals != nil {
+		ops = append(ops, "not_equals")
+	}
+	if c.In != nil {
+		ops = append(ops, "in")
+	}
+	if c.NotIn != nil {
+		ops = append(ops, "not_in")
+	}
+	if c.Matches != "" {
+		ops = append(ops, "matches")
+	}
+	if c.NotMatches != "" {
+		ops = append(ops, "not_matches")
+	}
+	if c.Exists != nil {
+		ops = append(ops, "exists")
+	}
+
+	if len(ops) != 1 {
+		return fmt.Errorf("exactly one of equals, not_equals, in, not_in, matches, not_matches or exists must be set, got [%s]", strings.Join(ops, ", "))
+	}
+
+	pattern := c.Matches
+	if pattern == "" {
+		pattern = c.NotMatches
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		c.pattern = re
+	}
+
+	return nil
+}
+
+func checkVersion(v string) bool {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Compare(v, "v"+minPolicyFileVersion) >= 0 && semver.Compare(v, "v"+maxPolicyFileVersion) <= 0
+}
@@ -0,0 +1,199 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const testPolicyFile = `version: 0.1
+policies:
+  - id: aws-instance-previous-generation
+    title: EC2 instances must not use previous generation families
+    resource_types: [aws_instance]
+    assert:
+      - attribute: instance_type
+        not_matches: '^(t2|m4|c4|r4)\.'
+    suggested: Use a current generation instance family
+  - id: gp3-volumes
+    title: EBS volumes must use gp3
+    resource_types: [aws_ebs_volume]
+    assert:
+      - attribute: type
+        in: [gp3]
+  - id: cost-center-tag
+    title: All resources must carry a cost-center tag
+    required_tags: [cost-center, team]
+  - id: expensive-resources
+    title: Resources must cost less than $100/month
+    max_monthly_cost: 100
+`
+
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policies.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	f, err := LoadFile(writePolicyFile(t, testPolicyFile))
+	require.NoError(t, err)
+	require.Len(t, f.Policies, 4)
+	assert.Equal(t, []string{"aws_instance"}, f.Policies[0].ResourceTypes)
+	assert.NotNil(t, f.Policies[0].Assert[0].pattern)
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unsupported version",
+			content: "version: 0.2\npolicies: []\n",
+			err:     "version '0.2' is not supported",
+		},
+		{
+			name:    "unknown key",
+			content: "version: 0.1\npolicies:\n  - id: a\n    title: A\n    max_cost: 1\n",
+			err:     "field max_cost not found",
+		},
+		{
+			name:    "no checks",
+			content: "version: 0.1\npolicies:\n  - id: a\n    title: A\n",
+			err:     "policy a must set at least one of assert, required_tags or max_monthly_cost",
+		},
+		{
+			name:    "duplicate id",
+			content: "version: 0.1\npolicies:\n  - id: a\n    title: A\n    required_tags: [team]\n  - id: a\n    title: B\n    required_tags: [team]\n",
+			err:     "policy id a is used more than once",
+		},
+		{
+			name:    "multiple operators",
+			content: "version: 0.1\npolicies:\n  - id: a\n    title: A\n    assert:\n      - attribute: type\n        equals: gp3\n        in: [gp3]\n",
+			err:     "policy a condition at index 0 is invalid: exactly one of equals, not_equals, in, not_in, matches, not_matches or exists must be set, got [equals, in]",
+		},
+		{
+			name:    "invalid regex",
+			content: "version: 0.1\npolicies:\n  - id: a\n    title: A\n    assert:\n      - attribute: type\n        matches: '('\n",
+			err:     "invalid regex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writePolicyFile(t, tt.content))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func TestEvaluateProject(t *testing.T) {
+	f, err := LoadFile(writePolicyFile(t, testPolicyFile))
+	require.NoError(t, err)
+
+	project := &schema.Project{
+		PartialResources: []*schema.PartialResource{
+			{
+				Type:        "aws_instance",
+				Address:     "aws_instance.old",
+				Tags:        &map[string]string{"team": "platform"},
+				DefaultTags: &map[string]string{"cost-center": "123"},
+				RawValues:   gjson.Parse(`{"instance_type": "m4.large"}`),
+			},
+			{
+				Type:      "aws_instance",
+				Address:   "aws_instance.new",
+				Tags:      &map[string]string{"team": "platform", "cost-center": "123"},
+				RawValues: gjson.Parse(`{"instance_type": "m5.large"}`),
+			},
+			{
+				Type:      "aws_ebs_volume",
+				Address:   "aws_ebs_volume.data",
+				Tags:      &map[string]string{},
+				RawValues: gjson.Parse(`{"size": 10}`),
+			},
+			{
+				Type:      "aws_lambda_function",
+				Address:   "aws_lambda_function.untaggable",
+				RawValues: gjson.Parse(`{}`),
+			},
+		},
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(70))},
+			{Name: "aws_instance.new", MonthlyCost: decimalPtr(decimal.NewFromInt(140))},
+			{Name: "aws_ebs_volume.data", MonthlyCost: decimalPtr(decimal.NewFromInt(1))},
+			{Name: "aws_lambda_function.untaggable"},
+		},
+	}
+
+	policies := f.EvaluateProject(project)
+
+	type result struct {
+		id         string
+		address    string
+		attributes string
+		noCost     bool
+	}
+
+	var actual []result
+	for _, p := range policies {
+		actual = append(actual, result{p.ID, p.Address, string(p.ResourceAttributes), p.NoCost})
+	}
+
+	assert.Equal(t, []result{
+		{"aws-instance-previous-generation", "aws_instance.old", `{"instance_type":"m4.large"}`, false},
+		{"gp3-volumes", "aws_ebs_volume.data", `{"type":null}`, false},
+		{"cost-center-tag", "aws_ebs_volume.data", `{"missing_tags":["cost-center","team"]}`, false},
+		{"expensive-resources", "aws_instance.new", `{"max_monthly_cost":"100.00","monthly_cost":"140.00"}`, false},
+	}, actual)
+
+	assert.Equal(t, "Use a current generation instance family", policies[0].Suggested)
+	assert.Equal(t, "70", policies[0].Cost.String())
+}
+
+func TestConditionCheck(t *testing.T) {
+	str := func(s string) *string { return &s }
+	boolean := func(b bool) *bool { return &b }
+
+	values := gjson.Parse(`{"type": "gp2", "root_block_device": [{"encrypted": true}]}`)
+
+	tests := []struct {
+		name      string
+		condition Condition
+		expected  bool
+	}{
+		{"equals", Condition{Attribute: "type", Equals: str("gp2")}, true},
+		{"equals missing", Condition{Attribute: "iops", Equals: str("gp2")}, false},
+		{"not equals", Condition{Attribute: "type", NotEquals: str("gp2")}, false},
+		{"not equals missing", Condition{Attribute: "iops", NotEquals: str("gp2")}, true},
+		{"in", Condition{Attribute: "type", In: []string{"gp3", "io2"}}, false},
+		{"not in", Condition{Attribute: "type", NotIn: []string{"gp3", "io2"}}, true},
+		{"matches", Condition{Attribute: "type", Matches: "^gp"}, true},
+		{"not matches", Condition{Attribute: "type", NotMatches: "^gp"}, false},
+		{"nested path", Condition{Attribute: "root_block_device.0.encrypted", Equals: str("true")}, true},
+		{"exists", Condition{Attribute: "iops", Exists: boolean(true)}, false},
+		{"not exists", Condition{Attribute: "iops", Exists: boolean(false)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.condition.check(values.Get(tt.condition.Attribute)))
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package prices
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var (
+	commitmentTermMapping = map[string]string{
+		config.CommitmentTerm1Year: "1yr",
+		config.CommitmentTerm3Year: "3yr",
+	}
+
+	commitmentPaymentOptionMapping = map[string]string{
+		config.CommitmentPaymentNoUpfront:      "No Upfront",
+		config.CommitmentPaymentPartialUpfront: "Partial Upfront",
+		config.CommitmentPaymentAllUpfront:     "All Upfront",
+	}
+
+	gcpCommitmentPurchaseOptionMapping = map[string]string{
+		config.CommitmentTerm1Year: "commit1yr",
+		config.CommitmentTerm3Year: "commit3yr",
+	}
+
+	ec2InstanceProducts = []commitmentProduct{
+		{service: "AmazonEC2", productFamily: "Compute Instance"},
+		{service: "AmazonEC2", productFamily: "Compute Instance (bare metal)"},
+	}
+
+	// reservedInstanceProducts are the AWS services and product families of the
+	// cost components each type of reserved instance covers.
+	reservedInstanceProducts = map[string][]commitmentProduct{
+		config.CommitmentServiceEC2: ec2InstanceProducts,
+		config.CommitmentServiceRDS: {
+			{service: "AmazonRDS", productFamily: "Database Instance"},
+		},
+		config.CommitmentServiceElastiCache: {
+			{service: "AmazonElastiCache", productFamily: "Cache Instance"},
+		},
+	}
+
+	// savingsPlanProducts are the AWS services and product families of the cost
+	// components each type of savings plan covers. Compute savings plans also
+	// cover Fargate and the duration charges of Lambda functions.
+	savingsPlanProducts = map[string][]commitmentProduct{
+		config.SavingsPlanTypeEC2Instance: ec2InstanceProducts,
+		config.SavingsPlanTypeCompute: append([]commitmentProduct{
+			{service: "AmazonECS", productFamily: "Compute", usageType: "Fargate", onDemandOnly: true},
+			{service: "AmazonEKS", productFamily: "Compute", usageType: "Fargate", onDemandOnly: true},
+			{service: "AWSLambda", productFamily: "Serverless", groups: []string{"AWS-Lambda-Duration", "AWS-Lambda-Duration-ARM"}, onDemandOnly: true},
+		}, ec2InstanceProducts...),
+	}
+)
+
+type commitmentProduct struct {
+	service       string
+	productFamily string
+	// usageType is part of the usagetype attribute the cost component must
+	// filter on, if it is set.
+	usageType string
+	// groups are the values of the group attribute the cost component must
+	// filter on, if they are set.
+	groups []string
+	// onDemandOnly is true if the product only has on-demand prices, so its
+	// cost components don't filter on the purchase option.
+	onDemandOnly bool
+}
+
+// matches returns true if the cost component is for the product and has been
+// priced at an on-demand rate.
+func (p commitmentProduct) matches(c *schema.CostComponent) bool {
+	filter := c.ProductFilter
+	if *filter.Service != p.service || *filter.ProductFamily != p.productFamily {
+		return false
+	}
+
+	if p.usageType != "" && !strings.Contains(attributeValue(filter, "usagetype"), p.usageType) {
+		return false
+	}
+
+	if len(p.groups) > 0 && !containsString(p.groups, attributeValue(filter, "group")) {
+		return false
+	}
+
+	if c.PriceFilter == nil || c.PriceFilter.PurchaseOption == nil {
+		return p.onDemandOnly
+	}
+
+	return isOnDemandPurchaseOption(c)
+}
+
+// isOnDemandPurchaseOption returns true if the cost component filters on the
+// on-demand purchase option.
+func isOnDemandPurchaseOption(c *schema.CostComponent) bool {
+	if c.PriceFilter == nil || c.PriceFilter.PurchaseOption == nil {
+		return false
+	}
+
+	switch strings.ToLower(*c.PriceFilter.PurchaseOption) {
+	case "on_demand", "ondemand":
+		return true
+	default:
+		return false
+	}
+}
+
+// commitmentMatch is a cost component that is covered by a commitment.
+type commitmentMatch struct {
+	resource      *schema.Resource
+	costComponent *schema.CostComponent
+	commitment    config.Commitment
+	// lookup is a copy of the cost component with a price filter for the
+	// committed price. It is nil if the commitment has a fixed discount.
+	lookup *schema.CostComponent
+}
+
+// ApplyCommitments sets the commitment of the cost components in the project
+// that are covered by one of the given commitments. This must be called after
+// PopulatePrices. When a cost component matches multiple commitments the
+// first one is used. The committed price is either the on-demand price less
+// the commitment discount, or is looked up using the price filter for the
+// commitment term. Cost components that no committed price can be found for
+// are left at their on-demand price and do not count as missing prices.
+func (p *PriceFetcher) ApplyCommitments(project *schema.Project, commitments []config.Commitment) error {
+	if len(commitments) == 0 {
+		return nil
+	}
+
+	var matches []*commitmentMatch
+	var lookupResources []*schema.Resource
+
+	for _, r := range project.AllResources() {
+		for _, resource := range append([]*schema.Resource{r}, r.FlattenedSubResources()...) {
+			for _, c := range resource.CostComponents {
+				commitment, ok := matchCommitment(commitments, resource, c)
+				if !ok {
+					continue
+				}
+
+				m := &commitmentMatch{
+					resource:      resource,
+					costComponent: c,
+					commitment:    commitment,
+				}
+
+				if commitment.DiscountPercent == nil {
+					m.lookup = commitmentLookupCostComponent(commitment, c)
+					lookupResources = append(lookupResources, &schema.Resource{
+						Name:           resource.Name,
+						ResourceType:   resource.ResourceType,
+						CostComponents: []*schema.CostComponent{m.lookup},
+					})
+				}
+
+				matches = append(matches, m)
+			}
+		}
+	}
+
+	prices, err := p.lookupCommitmentPrices(lookupResources)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		var price decimal.Decimal
+
+		if m.lookup == nil {
+			discount := decimal.NewFromFloat(*m.commitment.DiscountPercent).Div(decimal.NewFromInt(100))
+			price = m.costComponent.Price().Mul(decimal.NewFromInt(1).Sub(discount))
+		} else {
+			var ok bool
+			price, ok = prices[m.lookup]
+			if !ok {
+				logging.Logger.Warn().Msgf("No %s price found for %s %s, set discount_percent on the commitment to apply it", m.commitment.DisplayName(), m.resource.Name, m.costComponent.Name)
+				continue
+			}
+		}
+
+		logging.Logger.Debug().Msgf("Applying %s to %s %s at %s", m.commitment.DisplayName(), m.resource.Name, m.costComponent.Name, price)
+		m.costComponent.SetCommitment(m.commitment.DisplayName(), m.commitment.Coverage(), price)
+	}
+
+	return nil
+}
+
+// lookupCommitmentPrices returns the smallest non-zero price for each of the
+// cost components of the given resources. Cost components with no price are
+// not included in the result.
+func (p *PriceFetcher) lookupCommitmentPrices(resources []*schema.Resource) (map[*schema.CostComponent]decimal.Decimal, error) {
+	prices := make(map[*schema.CostComponent]decimal.Decimal)
+	if len(resources) == 0 {
+		return prices, nil
+	}
+
+	currency := p.runCtx.Config.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	for _, req := range p.client.BatchRequests(resources, batchSize, p.runCtx.Config.Currency) {
+		results, err := p.client.PerformRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			if price, ok := lowestPrice(result, currency); ok {
+				prices[result.CostComponent] = price
+			}
+		}
+	}
+
+	return prices, nil
+}
+
+// lowestPrice returns the smallest non-zero price in the result.
+func lowestPrice(result apiclient.PriceQueryResult, currency string) (decimal.Decimal, bool) {
+	var lowest decimal.Decimal
+	found := false
+
+	for _, product := range result.Result.Get("data.products").Array() {
+		for _, price := range product.Get("prices").Array() {
+			d, err := decimal.NewFromString(price.Get(currency).String())
+			if err != nil || d.IsZero() {
+				continue
+			}
+
+			if !found || d.LessThan(lowest) {
+				lowest = d
+				found = true
+			}
+		}
+	}
+
+	return lowest, found
+}
+
+// commitmentLookupCostComponent returns a copy of the cost component with the
+// price filter set to find the committed price.
+func commitmentLookupCostComponent(commitment config.Commitment, c *schema.CostComponent) *schema.CostComponent {
+	priceFilter := &schema.PriceFilter{}
+
+	if commitment.Type == config.CommitmentTypeCommittedUseDiscount {
+		priceFilter.PurchaseOption = strPtr(gcpCommitmentPurchaseOptionMapping[commitment.TermOrDefault()])
+	} else {
+		priceFilter.StartUsageAmount = strPtr("0")
+		priceFilter.TermLength = strPtr(commitmentTermMapping[commitment.TermOrDefault()])
+		priceFilter.TermPurchaseOption = strPtr(commitmentPaymentOptionMapping[commitment.PaymentOptionOrDefault()])
+
+		// Only EC2 reserved instances have offering classes in the pricing data.
+		if *c.ProductFilter.Service == "AmazonEC2" {
+			priceFilter.TermOfferingClass = strPtr(commitment.OfferingClassOrDefault())
+		}
+	}
+
+	return &schema.CostComponent{
+		Name:           c.Name,
+		Unit:           c.Unit,
+		UnitMultiplier: c.UnitMultiplier,
+		ProductFilter:  c.ProductFilter,
+		PriceFilter:    priceFilter,
+	}
+}
+
+// matchCommitment returns the first commitment that covers the cost component.
+func matchCommitment(commitments []config.Commitment, resource *schema.Resource, c *schema.CostComponent) (config.Commitment, bool) {
+	if !isCommitmentCandidate(c) {
+		return config.Commitment{}, false
+	}
+
+	for _, commitment := range commitments {
+		if commitmentMatches(commitment, resource, c) {
+			return commitment, true
+		}
+	}
+
+	return config.Commitment{}, false
+}
+
+// isCommitmentCandidate returns true if the cost component has a list price
+// that could be covered by a commitment. Components that are already reserved,
+// use a custom price or have a price override, are skipped. Whether the price is an on-demand price
+// is checked against the products that the commitment covers.
+func isCommitmentCandidate(c *schema.CostComponent) bool {
+	if c.PriceNotFound || c.Price().IsZero() || c.CustomPrice() != nil || c.PriceOverride != "" {
+		return false
+	}
+
+	if c.ProductFilter == nil || c.ProductFilter.VendorName == nil || c.ProductFilter.Service == nil || c.ProductFilter.ProductFamily == nil {
+		return false
+	}
+
+	return c.PriceFilter == nil || c.PriceFilter.TermLength == nil
+}
+
+func commitmentMatches(commitment config.Commitment, resource *schema.Resource, c *schema.CostComponent) bool {
+	filter := c.ProductFilter
+
+	if commitment.Region != "" && (filter.Region == nil || *filter.Region != commitment.Region) {
+		return false
+	}
+
+	if len(commitment.ResourceTypes) > 0 && !containsString(commitment.ResourceTypes, resource.BaseResourceType()) {
+		return false
+	}
+
+	if commitment.InstanceFamily != "" && !strings.EqualFold(instanceFamily(filter), commitment.InstanceFamily) {
+		return false
+	}
+
+	if commitment.Type == config.CommitmentTypeCommittedUseDiscount {
+		if *filter.VendorName != "gcp" || *filter.Service != "Compute Engine" || !isOnDemandPurchaseOption(c) {
+			return false
+		}
+
+		return *filter.ProductFamily == "Compute Instance" || *filter.ProductFamily == "Compute"
+	}
+
+	if *filter.VendorName != "aws" {
+		return false
+	}
+
+	products := reservedInstanceProducts[commitment.ServiceOrDefault()]
+	if commitment.Type == config.CommitmentTypeSavingsPlan {
+		products = savingsPlanProducts[commitment.PlanTypeOrDefault()]
+	}
+
+	for _, product := range products {
+		if !product.matches(c) {
+			continue
+		}
+
+		// EC2 components that only filter on the instance type, e.g. EBS-optimized
+		// usage, are charges on top of the instance usage that commitments don't cover.
+		return product.service != "AmazonEC2" || attributeValue(filter, "operatingSystem") != ""
+	}
+
+	return false
+}
+
+// instanceFamily returns the instance family of the cost component's product
+// filter, e.g. m5 for db.m5.large or n2 for n2-standard-4.
+func instanceFamily(filter *schema.ProductFilter) string {
+	if instanceType := attributeValue(filter, "instanceType"); instanceType != "" {
+		instanceType = strings.TrimPrefix(instanceType, "db.")
+		instanceType = strings.TrimPrefix(instanceType, "cache.")
+		return strings.SplitN(instanceType, ".", 2)[0]
+	}
+
+	if machineType := attributeValue(filter, "machineType"); machineType != "" {
+		return strings.SplitN(machineType, "-", 2)[0]
+	}
+
+	return ""
+}
+
+// attributeValue returns the value of the attribute filter with the given key.
+// Regex values, e.g. /^n2-standard-4$/i, are returned without their delimiters
+// and anchors.
+func attributeValue(filter *schema.ProductFilter, key string) string {
+	for _, f := range filter.AttributeFilters {
+		if f.Key != key {
+			continue
+		}
+
+		if f.Value != nil {
+			return *f.Value
+		}
+
+		if f.ValueRegex != nil {
+			v := strings.TrimPrefix(*f.ValueRegex, "/")
+			v = strings.TrimSuffix(strings.TrimSuffix(v, "/i"), "/")
+			return strings.TrimSuffix(strings.TrimPrefix(v, "^"), "$")
+		}
+	}
+
+	return ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,259 @@
+package prices
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// fakeResolver returns the price from the prices func for each cost component.
+// Cost components without a price return no products.
+type fakeResolver struct {
+	prices    func(c *schema.CostComponent) (string, bool)
+	resources []*schema.Resource
+}
+
+func (f *fakeResolver) BatchRequests(resources []*schema.Resource, batchSize int, currency string) []apiclient.BatchRequest {
+	f.resources = resources
+	return []apiclient.BatchRequest{{}}
+}
+
+func (f *fakeResolver) PerformRequest(req apiclient.BatchRequest) ([]apiclient.PriceQueryResult, error) {
+	var results []apiclient.PriceQueryResult
+	for _, r := range f.resources {
+		for _, c := range r.CostComponents {
+			body := `{"data":{"products":[]}}`
+			if price, ok := f.prices(c); ok {
+				body = fmt.Sprintf(`{"data":{"products":[{"prices":[{"priceHash":"hash","USD":"%s"}]}]}}`, price)
+			}
+
+			results = append(results, apiclient.PriceQueryResult{
+				PriceQueryKey: apiclient.PriceQueryKey{Resource: r, CostComponent: c},
+				Result:        gjson.Parse(body),
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func awsInstanceComponent(region, instanceType, purchaseOption string, price float64) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:            fmt.Sprintf("Instance usage (Linux/UNIX, %s, %s)", purchaseOption, instanceType),
+		Unit:            "hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(region),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("Compute Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr(instanceType)},
+				{Key: "operatingSystem", Value: strPtr("Linux")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr(purchaseOption)},
+	}
+	c.SetPrice(decimal.NewFromFloat(price))
+
+	return c
+}
+
+func gcpInstanceComponent(machineType string, price float64, sud float64) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:                fmt.Sprintf("Instance usage (Linux/UNIX, on-demand, %s)", machineType),
+		Unit:                "hours",
+		UnitMultiplier:      decimal.NewFromInt(1),
+		MonthlyQuantity:     decimalPtr(decimal.NewFromInt(730)),
+		MonthlyDiscountPerc: sud,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr("us-central1"),
+			Service:       strPtr("Compute Engine"),
+			ProductFamily: strPtr("Compute Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "machineType", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", machineType))},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+	}
+	c.SetPrice(decimal.NewFromFloat(price))
+
+	return c
+}
+
+func TestApplyCommitments(t *testing.T) {
+	web := awsInstanceComponent("us-east-1", "m5.large", "on_demand", 0.096)
+	spot := awsInstanceComponent("us-east-1", "m5.large", "spot", 0.04)
+	west := awsInstanceComponent("eu-west-1", "m5.large", "on_demand", 0.107)
+	unpriced := awsInstanceComponent("us-east-1", "x9.large", "on_demand", 1)
+	gcp := gcpInstanceComponent("n2-standard-4", 0.19, 0.2)
+	gcpE2 := gcpInstanceComponent("e2-standard-4", 0.13, 0)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{web, spot, west, unpriced}},
+			{Name: "google_compute_instance.app", ResourceType: "google_compute_instance", CostComponents: []*schema.CostComponent{gcp, gcpE2}},
+		},
+	}
+
+	resolver := &fakeResolver{prices: func(c *schema.CostComponent) (string, bool) {
+		if c.ProductFilter.Service != nil && *c.ProductFilter.Service == "AmazonEC2" && attributeValue(c.ProductFilter, "instanceType") == "m5.large" {
+			assert.Equal(t, "3yr", *c.PriceFilter.TermLength)
+			assert.Equal(t, "No Upfront", *c.PriceFilter.TermPurchaseOption)
+			assert.Equal(t, "convertible", *c.PriceFilter.TermOfferingClass)
+			return "0.06", true
+		}
+
+		return "", false
+	}}
+
+	p := &PriceFetcher{
+		resources:  make(map[string]*notFoundData),
+		components: make(map[string]int),
+		mux:        &sync.RWMutex{},
+		client:     resolver,
+		runCtx:     config.EmptyRunContext(),
+	}
+
+	coverage := 60.0
+	discount := 37.0
+	err := p.ApplyCommitments(project, []config.Commitment{
+		{Name: "Convertible RI", Type: config.CommitmentTypeReservedInstance, OfferingClass: "convertible", Term: config.CommitmentTerm3Year, Region: "us-east-1", CoveragePercent: &coverage},
+		{Type: config.CommitmentTypeCommittedUseDiscount, InstanceFamily: "n2", DiscountPercent: &discount},
+	})
+	require.NoError(t, err)
+
+	schema.CalculateCosts(project)
+
+	assert.Equal(t, "Convertible RI", web.Commitment)
+	assert.Equal(t, "70.1", web.MonthlyCost.StringFixed(1))
+	// 60% at the committed price and 40% at the on-demand price
+	assert.Equal(t, "54.3", web.CommitmentMonthlyCost.StringFixed(1))
+
+	assert.Empty(t, spot.Commitment, "spot usage should not be covered")
+	assert.Empty(t, west.Commitment, "usage in other regions should not be covered")
+	assert.Empty(t, unpriced.Commitment, "usage without a committed price should not be covered")
+	assert.Nil(t, unpriced.CommitmentMonthlyCost)
+
+	// The sustained use discount does not apply to usage covered by the CUD
+	assert.Equal(t, "1 year committed use discount", gcp.Commitment)
+	assert.Equal(t, "111.0", gcp.MonthlyCost.StringFixed(1))
+	assert.Equal(t, "87.4", gcp.CommitmentMonthlyCost.StringFixed(1))
+	assert.Empty(t, gcpE2.Commitment, "other machine families should not be covered")
+
+	aws := project.Resources[0]
+	require.NotNil(t, aws.CommitmentMonthlyCost)
+	assert.Equal(t, aws.MonthlyCost.Sub(*web.MonthlyCost).Add(*web.CommitmentMonthlyCost).StringFixed(2), aws.CommitmentMonthlyCost.StringFixed(2))
+
+	assert.Equal(t, 0, p.MissingPricesLen(), "missing committed prices should not be reported as missing prices")
+}
+
+func awsComponent(service, productFamily string, attrs map[string]string, purchaseOption string, price float64) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:            service,
+		Unit:            "hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr("us-east-1"),
+			Service:       strPtr(service),
+			ProductFamily: strPtr(productFamily),
+		},
+	}
+
+	for k, v := range attrs {
+		c.ProductFilter.AttributeFilters = append(c.ProductFilter.AttributeFilters, &schema.AttributeFilter{Key: k, ValueRegex: strPtr("/" + v + "/")})
+	}
+
+	if purchaseOption != "" {
+		c.PriceFilter = &schema.PriceFilter{PurchaseOption: strPtr(purchaseOption)}
+	}
+
+	c.SetPrice(decimal.NewFromFloat(price))
+
+	return c
+}
+
+func TestApplyCommitmentsSavingsPlans(t *testing.T) {
+	ec2 := awsInstanceComponent("us-east-1", "m5.large", "on_demand", 0.1)
+	spot := awsInstanceComponent("us-east-1", "m5.large", "spot", 0.04)
+	c5 := awsInstanceComponent("us-east-1", "c5.large", "on_demand", 0.1)
+	fargate := awsComponent("AmazonECS", "Compute", map[string]string{"usagetype": "Fargate-vCPU-Hours:perCPU"}, "", 0.1)
+	lambdaDuration := awsComponent("AWSLambda", "Serverless", map[string]string{"group": "AWS-Lambda-Duration"}, "", 0.1)
+	lambdaRequests := awsComponent("AWSLambda", "Serverless", map[string]string{"group": "AWS-Lambda-Requests"}, "", 0.1)
+	rds := awsComponent("AmazonRDS", "Database Instance", map[string]string{"instanceType": "db.m5.large"}, "on_demand", 0.1)
+	negotiated := awsInstanceComponent("us-east-1", "m5.xlarge", "on_demand", 0.2)
+	negotiated.SetPriceOverride("Negotiated m5.xlarge", decimal.NewFromFloat(0.15))
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{ec2, spot, c5, negotiated}},
+			{Name: "aws_ecs_service.api", ResourceType: "aws_ecs_service", CostComponents: []*schema.CostComponent{fargate}},
+			{Name: "aws_lambda_function.worker", ResourceType: "aws_lambda_function", CostComponents: []*schema.CostComponent{lambdaDuration, lambdaRequests}},
+			{Name: "aws_db_instance.db", ResourceType: "aws_db_instance", CostComponents: []*schema.CostComponent{rds}},
+		},
+	}
+
+	resolver := &fakeResolver{prices: func(c *schema.CostComponent) (string, bool) {
+		t.Errorf("savings plan prices should not be looked up, got %s", c.Name)
+		return "", false
+	}}
+
+	p := &PriceFetcher{
+		resources:  make(map[string]*notFoundData),
+		components: make(map[string]int),
+		mux:        &sync.RWMutex{},
+		client:     resolver,
+		runCtx:     config.EmptyRunContext(),
+	}
+
+	instanceDiscount := 40.0
+	computeDiscount := 20.0
+	err := p.ApplyCommitments(project, []config.Commitment{
+		{Name: "EC2 Instance SP", Type: config.CommitmentTypeSavingsPlan, PlanType: config.SavingsPlanTypeEC2Instance, InstanceFamily: "m5", DiscountPercent: &instanceDiscount},
+		{Name: "Compute SP", Type: config.CommitmentTypeSavingsPlan, DiscountPercent: &computeDiscount},
+	})
+	require.NoError(t, err)
+
+	schema.CalculateCosts(project)
+
+	assert.Equal(t, "EC2 Instance SP", ec2.Commitment)
+	assert.Equal(t, "43.80", ec2.CommitmentMonthlyCost.StringFixed(2))
+	assert.Empty(t, spot.Commitment, "spot usage should not be covered")
+	assert.Equal(t, "Compute SP", c5.Commitment)
+	assert.Equal(t, "58.40", c5.CommitmentMonthlyCost.StringFixed(2))
+	assert.Equal(t, "Compute SP", fargate.Commitment, "compute savings plans should cover Fargate")
+	assert.Equal(t, "Compute SP", lambdaDuration.Commitment, "compute savings plans should cover Lambda duration")
+	assert.Empty(t, lambdaRequests.Commitment, "Lambda requests should not be covered")
+	assert.Empty(t, rds.Commitment, "savings plans should not cover RDS")
+	assert.Empty(t, negotiated.Commitment, "overridden prices should not be discounted again")
+}
+
+func TestAttributeValue(t *testing.T) {
+	filter := &schema.ProductFilter{AttributeFilters: []*schema.AttributeFilter{
+		{Key: "instanceType", Value: strPtr("db.m5.large")},
+		{Key: "machineType", ValueRegex: strPtr("/^n2-standard-4$/i")},
+	}}
+
+	assert.Equal(t, "db.m5.large", attributeValue(filter, "instanceType"))
+	assert.Equal(t, "n2-standard-4", attributeValue(filter, "machineType"))
+	assert.Equal(t, "", attributeValue(filter, "usagetype"))
+	assert.Equal(t, "m5", instanceFamily(&schema.ProductFilter{AttributeFilters: filter.AttributeFilters[:1]}))
+	assert.Equal(t, "n2", instanceFamily(&schema.ProductFilter{AttributeFilters: filter.AttributeFilters[1:]}))
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
@@ -0,0 +1,223 @@
+package prices
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v2"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	minOverridesFileVersion = "0.1"
+	maxOverridesFileVersion = "0.1"
+)
+
+// OverridesFile is a price overrides file containing a list of rules that
+// replace the list prices of matching cost components with negotiated prices,
+// e.g.
+//
+//	version: 0.1
+//	overrides:
+//	  - name: AWS EDP
+//	    match:
+//	      vendor: aws
+//	    discount_percent: 8
+//	  - name: Negotiated m5.large rate
+//	    match:
+//	      service: AmazonEC2
+//	      region: us-east-1
+//	      cost_component: 'Instance usage \(Linux/UNIX, on-demand, m5\.large\)'
+//	    price: 0.08
+type OverridesFile struct {
+	Version   string          `yaml:"version"`
+	Overrides []PriceOverride `yaml:"overrides"`
+}
+
+// PriceOverride is a single price override rule. A cost component matches the
+// rule if it matches all the fields that are set in Match. Exactly one of
+// DiscountPercent or Price must be set.
+type PriceOverride struct {
+	// Name is shown in the output next to the cost components the override changed.
+	Name  string        `yaml:"name"`
+	Match OverrideMatch `yaml:"match"`
+	// DiscountPercent is the discount from the list price, e.g. 8 for an 8% discount.
+	DiscountPercent *float64 `yaml:"discount_percent,omitempty"`
+	// Price is the price per unit shown in the output, in the currency of the run.
+	Price *float64 `yaml:"price,omitempty"`
+}
+
+// OverrideMatch are the conditions a cost component must meet for a price
+// override to apply. Vendor, service, product family, region and resource type
+// are compared case-insensitively, CostComponent is a regular expression that
+// the cost component name must match.
+type OverrideMatch struct {
+	Vendor        string `yaml:"vendor,omitempty"`
+	Service       string `yaml:"service,omitempty"`
+	ProductFamily string `yaml:"product_family,omitempty"`
+	Region        string `yaml:"region,omitempty"`
+	ResourceType  string `yaml:"resource_type,omitempty"`
+	CostComponent string `yaml:"cost_component,omitempty"`
+	SKU           string `yaml:"sku,omitempty"`
+
+	costComponentPattern *regexp.Regexp
+}
+
+// LoadOverridesFile reads and validates the price overrides file at path.
+func LoadOverridesFile(path string) (*OverridesFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading price overrides file %w", err)
+	}
+
+	var f OverridesFile
+	err = yaml.UnmarshalStrict(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing price overrides file %s: %w", path, err)
+	}
+
+	err = f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("price overrides file %s is invalid: %w", path, err)
+	}
+
+	return &f, nil
+}
+
+func (f *OverridesFile) validate() error {
+	if !checkOverridesFileVersion(f.Version) {
+		return fmt.Errorf("version '%s' is not supported, valid versions are %s ≤ x ≤ %s", f.Version, minOverridesFileVersion, maxOverridesFileVersion)
+	}
+
+	for i := range f.Overrides {
+		o := &f.Overrides[i]
+		if o.Name == "" {
+			return fmt.Errorf("override at index %d must have a name", i)
+		}
+
+		if (o.DiscountPercent == nil) == (o.Price == nil) {
+			return fmt.Errorf("override %s must set exactly one of discount_percent or price", o.Name)
+		}
+
+		if o.DiscountPercent != nil && (*o.DiscountPercent < 0 || *o.DiscountPercent > 100) {
+			return fmt.Errorf("override %s discount_percent must be between 0 and 100", o.Name)
+		}
+
+		if o.Price != nil && *o.Price < 0 {
+			return fmt.Errorf("override %s price must not be negative", o.Name)
+		}
+
+		if o.Match == (OverrideMatch{}) {
+			return fmt.Errorf("override %s must match on at least one of vendor, service, product_family, region, resource_type, cost_component or sku", o.Name)
+		}
+
+		if o.Match.CostComponent != "" {
+			re, err := regexp.Compile(o.Match.CostComponent)
+			if err != nil {
+				return fmt.Errorf("override %s has an invalid cost_component regex %q: %w", o.Name, o.Match.CostComponent, err)
+			}
+			o.Match.costComponentPattern = re
+		}
+	}
+
+	return nil
+}
+
+// Apply sets the price of each cost component in the project that matches one
+// of the overrides. This must be called after PriceFetcher.PopulatePrices and
+// before the costs are calculated. When a cost component matches multiple
+// overrides the first one is used. Cost components that have no price are
+// not changed. The list price of overridden cost components is kept so it can
+// still be shown in outputs such as FOCUS.
+func (f *OverridesFile) Apply(project *schema.Project) {
+	if f == nil || project == nil {
+		return
+	}
+
+	for _, r := range project.AllResources() {
+		for _, resource := range append([]*schema.Resource{r}, r.FlattenedSubResources()...) {
+			for _, c := range resource.CostComponents {
+				if c.PriceNotFound || c.PriceOverride != "" {
+					continue
+				}
+
+				for _, o := range f.Overrides {
+					if !o.Match.matches(resource, c) {
+						continue
+					}
+
+					price := o.price(c)
+					logging.Logger.Debug().Msgf("Applying price override %s to %s %s, changing the price from %s to %s", o.Name, resource.Name, c.Name, c.Price(), price)
+					c.SetPriceOverride(o.Name, price)
+					break
+				}
+			}
+		}
+	}
+}
+
+// price returns the overridden price of the cost component. Prices in the
+// override file are per output unit, so they are converted to the price of
+// the cost component's base unit.
+func (o PriceOverride) price(c *schema.CostComponent) decimal.Decimal {
+	if o.DiscountPercent != nil {
+		discount := decimal.NewFromFloat(*o.DiscountPercent).Div(decimal.NewFromInt(100))
+		return c.Price().Mul(decimal.NewFromInt(1).Sub(discount))
+	}
+
+	price := decimal.NewFromFloat(*o.Price)
+	if c.UnitMultiplier.IsZero() {
+		return price
+	}
+
+	return price.Div(c.UnitMultiplier)
+}
+
+func (m OverrideMatch) matches(resource *schema.Resource, c *schema.CostComponent) bool {
+	filter := c.ProductFilter
+	if filter == nil {
+		filter = &schema.ProductFilter{}
+	}
+
+	if !matchesField(m.Vendor, filter.VendorName) ||
+		!matchesField(m.Service, filter.Service) ||
+		!matchesField(m.ProductFamily, filter.ProductFamily) ||
+		!matchesField(m.Region, filter.Region) {
+		return false
+	}
+
+	if m.ResourceType != "" && !strings.EqualFold(m.ResourceType, resource.BaseResourceType()) {
+		return false
+	}
+
+	if m.SKU != "" && m.SKU != c.SKU() {
+		return false
+	}
+
+	if m.costComponentPattern != nil && !m.costComponentPattern.MatchString(c.Name) {
+		return false
+	}
+
+	return true
+}
+
+func matchesField(want string, got *string) bool {
+	if want == "" {
+		return true
+	}
+
+	return got != nil && strings.EqualFold(want, *got)
+}
+
+func checkOverridesFileVersion(v string) bool {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Compare(v, "v"+minOverridesFileVersion) >= 0 && semver.Compare(v, "v"+maxOverridesFileVersion) <= 0
+}
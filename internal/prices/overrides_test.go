@@ -0,0 +1,179 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func writeOverridesFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "price-overrides.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestLoadOverridesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: `version: 0.1
+overrides:
+  - name: AWS EDP
+    match:
+      vendor: aws
+    discount_percent: 8
+  - name: Negotiated m5.large rate
+    match:
+      cost_component: 'm5\.large'
+    price: 0.08
+`,
+		},
+		{
+			name:    "unsupported version",
+			content: "version: 0.2\noverrides: []\n",
+			wantErr: "version '0.2' is not supported",
+		},
+		{
+			name: "both discount and price",
+			content: `version: 0.1
+overrides:
+  - name: both
+    match:
+      vendor: aws
+    discount_percent: 8
+    price: 0.08
+`,
+			wantErr: "override both must set exactly one of discount_percent or price",
+		},
+		{
+			name: "no match",
+			content: `version: 0.1
+overrides:
+  - name: everything
+    discount_percent: 8
+`,
+			wantErr: "override everything must match on at least one of",
+		},
+		{
+			name: "invalid regex",
+			content: `version: 0.1
+overrides:
+  - name: bad regex
+    match:
+      cost_component: '('
+    discount_percent: 8
+`,
+			wantErr: "override bad regex has an invalid cost_component regex",
+		},
+		{
+			name: "unknown field",
+			content: `version: 0.1
+overrides:
+  - name: typo
+    match:
+      vendr: aws
+    discount_percent: 8
+`,
+			wantErr: "field vendr not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := LoadOverridesFile(writeOverridesFile(t, tt.content))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, f.Overrides, 2)
+		})
+	}
+}
+
+func TestOverridesFileApply(t *testing.T) {
+	web := awsInstanceComponent("us-east-1", "m5.large", "on_demand", 0.096)
+	west := awsInstanceComponent("eu-west-1", "m5.large", "on_demand", 0.107)
+	west.SetSKU("ABC123")
+	gcp := gcpInstanceComponent("n2-standard-4", 0.19, 0)
+	missing := awsInstanceComponent("us-east-1", "x9.large", "on_demand", 0)
+	missing.SetPriceNotFound()
+
+	requests := &schema.CostComponent{
+		Name:            "Requests",
+		Unit:            "1M requests",
+		UnitMultiplier:  decimal.NewFromInt(1000000),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(5000000)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("us-east-1"),
+			Service:    strPtr("AWSLambda"),
+		},
+	}
+	requests.SetPrice(decimal.NewFromFloat(0.0000002))
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{web, west, missing}},
+			{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function", CostComponents: []*schema.CostComponent{requests}},
+			{Name: "google_compute_instance.app", ResourceType: "google_compute_instance", CostComponents: []*schema.CostComponent{gcp}},
+		},
+	}
+
+	f, err := LoadOverridesFile(writeOverridesFile(t, `version: 0.1
+overrides:
+  - name: Reserved SKU
+    match:
+      sku: ABC123
+    price: 0.05
+  - name: Lambda requests
+    match:
+      resource_type: aws_lambda_function
+      cost_component: '^Requests$'
+    price: 0.15
+  - name: AWS EDP
+    match:
+      vendor: AWS
+      region: us-east-1
+    discount_percent: 10
+`))
+	require.NoError(t, err)
+
+	f.Apply(project)
+	// Applying the overrides again should not discount the prices twice.
+	f.Apply(project)
+	schema.CalculateCosts(project)
+
+	assert.Equal(t, "AWS EDP", web.PriceOverride)
+	assert.Equal(t, "0.0864", web.Price().String())
+	assert.Equal(t, "0.096", web.ListPrice().String(), "the list price should be kept")
+
+	assert.Equal(t, "Reserved SKU", west.PriceOverride)
+	assert.Equal(t, "0.05", west.Price().String())
+
+	assert.Equal(t, "Lambda requests", requests.PriceOverride)
+	assert.Equal(t, "0.15", requests.UnitMultiplierPrice().String())
+	assert.Equal(t, "0.75", requests.MonthlyCost.String())
+	assert.Equal(t, "0.2", requests.UnitMultiplierListPrice().String())
+
+	assert.Empty(t, gcp.PriceOverride, "other vendors should not be overridden")
+	assert.Equal(t, "0.19", gcp.Price().String())
+	assert.Nil(t, gcp.UnitMultiplierListPrice())
+
+	assert.Empty(t, missing.PriceOverride, "missing prices should not be overridden")
+}
@@ -30,6 +30,14 @@ type notFoundData struct {
 	Count         int
 }
 
+// priceResolver resolves batches of price queries for cost components. It is
+// implemented by apiclient.PricingAPIClient, which queries the Cloud Pricing
+// API, and apiclient.PricingSnapshotResolver, which uses a local snapshot.
+type priceResolver interface {
+	BatchRequests(resources []*schema.Resource, batchSize int, currency string) []apiclient.BatchRequest
+	PerformRequest(req apiclient.BatchRequest) ([]apiclient.PriceQueryResult, error)
+}
+
 // PriceFetcher provides a thread-safe way to aggregate 'price not found'
 // data. This is used to provide a summary of missing prices at the end of a run.
 // It should be used as a singleton which is shared across the application.
@@ -37,7 +45,7 @@ type PriceFetcher struct {
 	resources         map[string]*notFoundData
 	components        map[string]int
 	mux               *sync.RWMutex
-	client            *apiclient.PricingAPIClient
+	client            priceResolver
 	runCtx            *config.RunContext
 	warnOnPriceErrors bool
 }
@@ -53,6 +61,51 @@ func NewPriceFetcher(ctx *config.RunContext, warnOnPriceErrors bool) *PriceFetch
 	}
 }
 
+// NewOfflinePriceFetcher returns a PriceFetcher that resolves prices from the
+// pricing snapshot at ctx.Config.PricingSnapshotPath rather than the Cloud
+// Pricing API. Prices that are missing from the snapshot are reported in the
+// same way as prices missing from the API.
+func NewOfflinePriceFetcher(ctx *config.RunContext, warnOnPriceErrors bool) (*PriceFetcher, error) {
+	snapshot, err := apiclient.LoadPricingSnapshot(ctx.Config.PricingSnapshotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := ctx.Config.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	if snapshot.Currency != currency {
+		return nil, fmt.Errorf("pricing snapshot %s contains %s prices but the currency is set to %s", ctx.Config.PricingSnapshotPath, snapshot.Currency, currency)
+	}
+
+	logging.Logger.Debug().Msgf("Using %d prices from pricing snapshot %s created at %s", snapshot.Len(), ctx.Config.PricingSnapshotPath, snapshot.CreatedAt)
+
+	return &PriceFetcher{
+		resources:         make(map[string]*notFoundData),
+		components:        make(map[string]int),
+		mux:               &sync.RWMutex{},
+		runCtx:            ctx,
+		client:            apiclient.NewPricingSnapshotResolver(snapshot),
+		warnOnPriceErrors: warnOnPriceErrors,
+	}, nil
+}
+
+// Fork returns a PriceFetcher that resolves prices in the same way as p, but
+// keeps its own record of missing prices. This is used to price resources
+// again, e.g. for forecasts, without reporting their missing prices twice.
+func (p *PriceFetcher) Fork() *PriceFetcher {
+	return &PriceFetcher{
+		resources:         make(map[string]*notFoundData),
+		components:        make(map[string]int),
+		mux:               &sync.RWMutex{},
+		runCtx:            p.runCtx,
+		client:            p.client,
+		warnOnPriceErrors: p.warnOnPriceErrors,
+	}
+}
+
 // addNotFoundResult adds an instance of a missing price to the aggregator.
 func (p *PriceFetcher) addNotFoundResult(result apiclient.PriceQueryResult) {
 	p.mux.Lock()
@@ -275,6 +328,7 @@ func (p *PriceFetcher) logPriceLookupErr(cc *schema.CostComponent, format string
 
 type productPrice struct {
 	Hash  string
+	SKU   string
 	Price decimal.Decimal
 }
 
@@ -318,6 +372,7 @@ func (p *PriceFetcher) setCostComponentPrice(result apiclient.PriceQueryResult)
 	distinctPrices := map[string]bool{}
 	for _, product := range products {
 		pricesResults := product.Get("prices").Array()
+		sku := product.Get("sku").String()
 		if len(pricesResults) > 0 {
 			// map pricesResults to decimals
 			var prices []productPrice
@@ -326,10 +381,10 @@ func (p *PriceFetcher) setCostComponentPrice(result apiclient.PriceQueryResult)
 				p, err := decimal.NewFromString(priceStr)
 				if err != nil {
 					logging.Logger.Warn().Msgf("Error converting price to '%v' (using 0.00)  '%v': %s", currency, price.Get(currency).String(), err.Error())
-					prices = append(prices, productPrice{Hash: price.Get("priceHash").String(), Price: decimal.Zero})
+					prices = append(prices, productPrice{Hash: price.Get("priceHash").String(), SKU: sku, Price: decimal.Zero})
 					continue
 				}
-				prices = append(prices, productPrice{Hash: price.Get("priceHash").String(), Price: p})
+				prices = append(prices, productPrice{Hash: price.Get("priceHash").String(), SKU: sku, Price: p})
 
 				distinctPrices[priceStr] = true
 			}
@@ -388,4 +443,5 @@ func (p *PriceFetcher) setCostComponentPrice(result apiclient.PriceQueryResult)
 
 	result.CostComponent.SetPrice(productPrices[0][0].Price)
 	result.CostComponent.SetPriceHash(productPrices[0][0].Hash)
+	result.CostComponent.SetSKU(productPrices[0][0].SKU)
 }
@@ -80,6 +80,10 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 
 // FreeResources grouped alphabetically
 var FreeResources = []string{
+	// AWS CloudFormation
+	"AWS::CDK::Metadata",
+	"AWS::CloudFormation::Stack",
+
 	// AWS Certificate Manager
 	"aws_acm_certificate_validation",
 
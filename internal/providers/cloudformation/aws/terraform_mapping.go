@@ -0,0 +1,327 @@
+package aws
+
+import (
+	"strings"
+	"unicode"
+)
+
+// mapAttributes are properties that are maps in Terraform, as opposed to
+// nested blocks, so their keys are kept as they are.
+var mapAttributes = map[string]bool{
+	"variables": true,
+}
+
+// rootDeviceNames are the device names AWS uses for the root volume of an instance.
+var rootDeviceNames = map[string]bool{
+	"/dev/xvda":  true,
+	"/dev/sda1":  true,
+	"/dev/sda":   true,
+	"/dev/nvme0": true,
+}
+
+// TerraformMapping describes how a CloudFormation resource is converted to the
+// equivalent Terraform resource so that it can be priced using the Terraform
+// resource registry. Properties are converted to snake case, nested objects
+// are converted to single item lists to match Terraform blocks, and Renames
+// maps the converted property names that differ from the Terraform attribute.
+type TerraformMapping struct {
+	Type    string
+	Renames map[string]string
+	// Transform is called after the properties have been converted and renamed
+	// for any values that can't be converted with a rename.
+	Transform func(values map[string]interface{})
+}
+
+// TerraformMappings is a map of CloudFormation resource types to Terraform
+// resource types. CloudFormation resources in the ResourceRegistry are
+// priced using the CloudFormation registry item instead.
+var TerraformMappings = map[string]TerraformMapping{
+	"AWS::ApiGateway::RestApi": {Type: "aws_api_gateway_rest_api"},
+	"AWS::ApiGatewayV2::Api":   {Type: "aws_apigatewayv2_api"},
+	"AWS::CloudWatch::Alarm": {
+		Type: "aws_cloudwatch_metric_alarm",
+		Renames: map[string]string{
+			"metrics": "metric_query",
+		},
+	},
+	"AWS::EC2::EIP": {
+		Type: "aws_eip",
+		Renames: map[string]string{
+			"instance_id": "instance",
+		},
+	},
+	"AWS::EC2::Instance": {
+		Type: "aws_instance",
+		Renames: map[string]string{
+			"image_id": "ami",
+		},
+		Transform: transformInstance,
+	},
+	"AWS::EC2::InternetGateway": {Type: "aws_internet_gateway"},
+	"AWS::EC2::NatGateway":      {Type: "aws_nat_gateway"},
+	"AWS::EC2::Route":           {Type: "aws_route"},
+	"AWS::EC2::RouteTable":      {Type: "aws_route_table"},
+	"AWS::EC2::SecurityGroup":   {Type: "aws_security_group"},
+	"AWS::EC2::Subnet":          {Type: "aws_subnet"},
+	"AWS::EC2::SubnetRouteTableAssociation": {
+		Type: "aws_route_table_association",
+	},
+	"AWS::EC2::Volume": {
+		Type: "aws_ebs_volume",
+		Renames: map[string]string{
+			"volume_type": "type",
+		},
+	},
+	"AWS::EC2::VPC":            {Type: "aws_vpc"},
+	"AWS::EC2::VPCEndpoint":    {Type: "aws_vpc_endpoint"},
+	"AWS::ECR::Repository":     {Type: "aws_ecr_repository"},
+	"AWS::ECS::Cluster":        {Type: "aws_ecs_cluster"},
+	"AWS::ECS::Service":        {Type: "aws_ecs_service"},
+	"AWS::ECS::TaskDefinition": {Type: "aws_ecs_task_definition"},
+	"AWS::EFS::FileSystem": {
+		Type: "aws_efs_file_system",
+		Renames: map[string]string{
+			"lifecycle_policies": "lifecycle_policy",
+		},
+	},
+	"AWS::EKS::Cluster": {Type: "aws_eks_cluster"},
+	"AWS::EKS::Nodegroup": {
+		Type: "aws_eks_node_group",
+		Renames: map[string]string{
+			"nodegroup_name": "node_group_name",
+		},
+	},
+	"AWS::ElastiCache::CacheCluster": {
+		Type: "aws_elasticache_cluster",
+		Renames: map[string]string{
+			"cache_node_type": "node_type",
+		},
+	},
+	"AWS::ElastiCache::ReplicationGroup": {
+		Type: "aws_elasticache_replication_group",
+		Renames: map[string]string{
+			"cache_node_type": "node_type",
+		},
+	},
+	"AWS::ElasticLoadBalancing::LoadBalancer": {Type: "aws_elb"},
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": {
+		Type: "aws_lb",
+		Renames: map[string]string{
+			"type": "load_balancer_type",
+		},
+	},
+	"AWS::IAM::InstanceProfile": {Type: "aws_iam_instance_profile"},
+	"AWS::IAM::ManagedPolicy":   {Type: "aws_iam_policy"},
+	"AWS::IAM::Policy":          {Type: "aws_iam_policy"},
+	"AWS::IAM::Role":            {Type: "aws_iam_role"},
+	"AWS::KMS::Key": {
+		Type: "aws_kms_key",
+		Renames: map[string]string{
+			"key_spec": "customer_master_key_spec",
+		},
+	},
+	"AWS::Kinesis::Stream":           {Type: "aws_kinesis_stream"},
+	"AWS::Lambda::Function":          {Type: "aws_lambda_function"},
+	"AWS::Lambda::Permission":        {Type: "aws_lambda_permission"},
+	"AWS::Logs::LogGroup":            {Type: "aws_cloudwatch_log_group"},
+	"AWS::MSK::Cluster":              {Type: "aws_msk_cluster"},
+	"AWS::OpenSearchService::Domain": {Type: "aws_opensearch_domain"},
+	"AWS::RDS::DBCluster":            {Type: "aws_rds_cluster"},
+	"AWS::RDS::DBInstance": {
+		Type: "aws_db_instance",
+		Renames: map[string]string{
+			"db_instance_class":             "instance_class",
+			"enable_performance_insights":   "performance_insights_enabled",
+			"source_db_instance_identifier": "replicate_source_db",
+		},
+	},
+	"AWS::Redshift::Cluster": {Type: "aws_redshift_cluster"},
+	"AWS::Route53::HostedZone": {
+		Type: "aws_route53_zone",
+	},
+	"AWS::S3::Bucket": {
+		Type: "aws_s3_bucket",
+		Renames: map[string]string{
+			"bucket_name": "bucket",
+		},
+	},
+	"AWS::S3::BucketPolicy":         {Type: "aws_s3_bucket_policy"},
+	"AWS::SNS::Subscription":        {Type: "aws_sns_topic_subscription"},
+	"AWS::SNS::Topic":               {Type: "aws_sns_topic"},
+	"AWS::SQS::Queue":               {Type: "aws_sqs_queue"},
+	"AWS::SQS::QueuePolicy":         {Type: "aws_sqs_queue_policy"},
+	"AWS::SSM::Parameter":           {Type: "aws_ssm_parameter"},
+	"AWS::SecretsManager::Secret":   {Type: "aws_secretsmanager_secret"},
+	"AWS::Serverless::Api":          {Type: "aws_api_gateway_rest_api"},
+	"AWS::Serverless::Function":     {Type: "aws_lambda_function"},
+	"AWS::Serverless::HttpApi":      {Type: "aws_apigatewayv2_api", Transform: transformHTTPAPI},
+	"AWS::Serverless::StateMachine": {Type: "aws_sfn_state_machine"},
+	"AWS::StepFunctions::StateMachine": {
+		Type: "aws_sfn_state_machine",
+		Renames: map[string]string{
+			"state_machine_type": "type",
+		},
+	},
+}
+
+// ToTerraformValues returns the Terraform attributes for the properties of a
+// CloudFormation resource.
+func (m TerraformMapping) ToTerraformValues(properties map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(properties))
+
+	for k, v := range properties {
+		key := toSnakeCase(k)
+		if rename, ok := m.Renames[key]; ok {
+			key = rename
+		}
+
+		if key == "tags" {
+			values[key] = convertTags(v)
+			continue
+		}
+
+		values[key] = convertValue(key, v)
+	}
+
+	if m.Transform != nil {
+		m.Transform(values)
+	}
+
+	return values
+}
+
+// convertValue converts nested objects to single item lists and converts the
+// property names of any objects to snake case.
+func convertValue(key string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if mapAttributes[key] {
+			return val
+		}
+
+		return []interface{}{convertObject(val)}
+	case []interface{}:
+		items := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if m, ok := item.(map[string]interface{}); ok {
+				items = append(items, convertObject(m))
+				continue
+			}
+
+			items = append(items, item)
+		}
+
+		return items
+	}
+
+	return v
+}
+
+func convertObject(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		key := toSnakeCase(k)
+		out[key] = convertValue(key, v)
+	}
+
+	return out
+}
+
+// convertTags converts a list of CloudFormation Key/Value tags to a map. SAM
+// resources already use a map for their tags.
+func convertTags(v interface{}) map[string]interface{} {
+	tags := map[string]interface{}{}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, tag := range val {
+			tags[k] = tag
+		}
+	case []interface{}:
+		for _, item := range val {
+			tag, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			key, ok := tag["Key"].(string)
+			if !ok {
+				continue
+			}
+
+			tags[key] = tag["Value"]
+		}
+	}
+
+	return tags
+}
+
+// transformInstance converts the block device mappings of an instance to the
+// root and EBS block devices.
+func transformInstance(values map[string]interface{}) {
+	mappings, ok := values["block_device_mappings"].([]interface{})
+	if !ok {
+		return
+	}
+	delete(values, "block_device_mappings")
+
+	var ebsBlockDevices []interface{}
+	for _, item := range mappings {
+		mapping, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ebs, ok := mapping["ebs"].([]interface{})
+		if !ok || len(ebs) == 0 {
+			continue
+		}
+
+		device, ok := ebs[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		deviceName, _ := mapping["device_name"].(string)
+		device["device_name"] = deviceName
+
+		if rootDeviceNames[deviceName] {
+			values["root_block_device"] = []interface{}{device}
+			continue
+		}
+
+		ebsBlockDevices = append(ebsBlockDevices, device)
+	}
+
+	if len(ebsBlockDevices) > 0 {
+		values["ebs_block_device"] = ebsBlockDevices
+	}
+}
+
+// transformHTTPAPI sets the protocol type, which is implied by the SAM
+// AWS::Serverless::HttpApi resource type.
+func transformHTTPAPI(values map[string]interface{}) {
+	values["protocol_type"] = "HTTP"
+}
+
+// toSnakeCase converts a CloudFormation property name to snake case, e.g.
+// DBInstanceClass returns db_instance_class.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
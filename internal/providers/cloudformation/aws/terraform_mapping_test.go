@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+func TestTerraformMappingsExist(t *testing.T) {
+	for cfType, mapping := range TerraformMappings {
+		_, ok := (*terraform.ResourceRegistryMap)[mapping.Type]
+		assert.Truef(t, ok, "%s is mapped to %s which is not in the Terraform registry", cfType, mapping.Type)
+	}
+}
+
+func TestToTerraformValues(t *testing.T) {
+	values := TerraformMappings["AWS::EC2::Instance"].ToTerraformValues(map[string]interface{}{
+		"ImageId":             "ami-123",
+		"InstanceType":        "m5.large",
+		"CreditSpecification": map[string]interface{}{"CPUCredits": "unlimited"},
+		"BlockDeviceMappings": []interface{}{
+			map[string]interface{}{"DeviceName": "/dev/sda1", "Ebs": map[string]interface{}{"VolumeSize": float64(20)}},
+			map[string]interface{}{"DeviceName": "/dev/sdb", "Ebs": map[string]interface{}{"VolumeSize": float64(50)}},
+		},
+		"Tags": []interface{}{
+			map[string]interface{}{"Key": "team", "Value": "infra"},
+		},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"ami":                  "ami-123",
+		"instance_type":        "m5.large",
+		"credit_specification": []interface{}{map[string]interface{}{"cpu_credits": "unlimited"}},
+		"root_block_device":    []interface{}{map[string]interface{}{"device_name": "/dev/sda1", "volume_size": float64(20)}},
+		"ebs_block_device":     []interface{}{map[string]interface{}{"device_name": "/dev/sdb", "volume_size": float64(50)}},
+		"tags":                 map[string]interface{}{"team": "infra"},
+	}, values)
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"DBInstanceClass":              "db_instance_class",
+		"MultiAZ":                      "multi_az",
+		"EBSOptions":                   "ebs_options",
+		"ProvisionedThroughputInMibps": "provisioned_throughput_in_mibps",
+		"TransitionToIA":               "transition_to_ia",
+		"Ipv6AddressCount":             "ipv6_address_count",
+	}
+
+	for in, expected := range tests {
+		assert.Equal(t, expected, toSnakeCase(in), in)
+	}
+}
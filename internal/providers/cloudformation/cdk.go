@@ -0,0 +1,178 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	cdkManifestFile = "manifest.json"
+	cdkOutDir       = "cdk.out"
+
+	cdkStackArtifactType    = "aws:cloudformation:stack"
+	cdkAssemblyArtifactType = "cdk:cloud-assembly"
+
+	// cdkUnknownPrefix is used by CDK for the account and region of
+	// environment-agnostic stacks, e.g. aws://unknown-account/unknown-region.
+	cdkUnknownPrefix = "unknown-"
+)
+
+// CDKStack is a CloudFormation stack in a CDK cloud assembly.
+type CDKStack struct {
+	// Name is the stack name, or the hierarchical ID of the stack for stacks
+	// in a stage, e.g. Prod/Api.
+	Name string
+	// Environment is the target environment of the stack, e.g. aws://123456789012/us-east-1.
+	Environment  string
+	Account      string
+	Region       string
+	TemplatePath string
+	Parameters   map[string]string
+}
+
+// cdkManifest is the manifest.json of a CDK cloud assembly.
+type cdkManifest struct {
+	Version   string                 `json:"version"`
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type        string `json:"type"`
+	Environment string `json:"environment"`
+	DisplayName string `json:"displayName"`
+	Properties  struct {
+		TemplateFile  string            `json:"templateFile"`
+		StackName     string            `json:"stackName"`
+		Parameters    map[string]string `json:"parameters"`
+		DirectoryName string            `json:"directoryName"`
+	} `json:"properties"`
+}
+
+// CloudAssemblyManifest returns the path of the manifest.json for a CDK cloud
+// assembly. The path can be the manifest.json, the cloud assembly directory,
+// or a CDK app directory that contains a synthesized cdk.out directory.
+func CloudAssemblyManifest(path string) (string, bool) {
+	candidates := []string{path}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+
+	if info.IsDir() {
+		candidates = []string{
+			filepath.Join(path, cdkManifestFile),
+			filepath.Join(path, cdkOutDir, cdkManifestFile),
+		}
+	} else if filepath.Base(path) != cdkManifestFile {
+		return "", false
+	}
+
+	for _, candidate := range candidates {
+		if _, err := readCDKManifest(candidate); err == nil {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func readCDKManifest(path string) (*cdkManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest cdkManifest
+	err = json.Unmarshal(b, &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Version == "" || len(manifest.Artifacts) == 0 {
+		return nil, errors.New("not a CDK cloud assembly manifest")
+	}
+
+	return &manifest, nil
+}
+
+// LoadCloudAssembly returns the stacks in the CDK cloud assembly at the given
+// manifest.json path, including the stacks of any nested cloud assemblies
+// that CDK creates for stages.
+func LoadCloudAssembly(manifestPath string) ([]CDKStack, error) {
+	return loadCloudAssembly(manifestPath, "")
+}
+
+func loadCloudAssembly(manifestPath string, prefix string) ([]CDKStack, error) {
+	manifest, err := readCDKManifest(manifestPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading CDK cloud assembly %s", manifestPath)
+	}
+
+	dir := filepath.Dir(manifestPath)
+
+	ids := make([]string, 0, len(manifest.Artifacts))
+	for id := range manifest.Artifacts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var stacks []CDKStack
+	for _, id := range ids {
+		artifact := manifest.Artifacts[id]
+
+		switch artifact.Type {
+		case cdkStackArtifactType:
+			name := artifact.DisplayName
+			if name == "" {
+				name = prefix + id
+			}
+
+			account, region := parseCDKEnvironment(artifact.Environment)
+
+			stacks = append(stacks, CDKStack{
+				Name:         name,
+				Environment:  artifact.Environment,
+				Account:      account,
+				Region:       region,
+				TemplatePath: filepath.Join(dir, artifact.Properties.TemplateFile),
+				Parameters:   artifact.Properties.Parameters,
+			})
+		case cdkAssemblyArtifactType:
+			nestedPath := filepath.Join(dir, artifact.Properties.DirectoryName, cdkManifestFile)
+			nested, err := loadCloudAssembly(nestedPath, fmt.Sprintf("%s%s/", prefix, id))
+			if err != nil {
+				return nil, err
+			}
+
+			stacks = append(stacks, nested...)
+		}
+	}
+
+	return stacks, nil
+}
+
+// parseCDKEnvironment returns the account and region of a CDK environment, e.g.
+// aws://123456789012/us-east-1. These are empty for environment-agnostic stacks.
+func parseCDKEnvironment(env string) (string, string) {
+	parts := strings.Split(strings.TrimPrefix(env, "aws://"), "/")
+	if len(parts) != 2 {
+		return "", ""
+	}
+
+	account, region := parts[0], parts[1]
+	if strings.HasPrefix(account, cdkUnknownPrefix) {
+		account = ""
+	}
+	if strings.HasPrefix(region, cdkUnknownPrefix) {
+		region = ""
+	}
+
+	return account, region
+}
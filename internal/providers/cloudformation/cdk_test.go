@@ -0,0 +1,92 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCloudAssemblyManifest(t *testing.T) {
+	expected := filepath.Join("testdata", "cdk", "cdk.out", "manifest.json")
+
+	for _, path := range []string{"testdata/cdk", "testdata/cdk/cdk.out", expected} {
+		manifest, ok := CloudAssemblyManifest(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, manifest, path)
+	}
+
+	_, ok := CloudAssemblyManifest("testdata/template.yml")
+	assert.False(t, ok)
+
+	_, ok = CloudAssemblyManifest("testdata")
+	assert.False(t, ok)
+}
+
+func TestLoadCloudAssembly(t *testing.T) {
+	stacks, err := LoadCloudAssembly("testdata/cdk/cdk.out/manifest.json")
+	require.NoError(t, err)
+	require.Len(t, stacks, 2)
+
+	assert.Equal(t, CDKStack{
+		Name:         "AppStack",
+		Environment:  "aws://123456789012/eu-west-1",
+		Account:      "123456789012",
+		Region:       "eu-west-1",
+		TemplatePath: filepath.Join("testdata", "cdk", "cdk.out", "AppStack.template.json"),
+		Parameters:   map[string]string{"InstanceType": "m5.large"},
+	}, stacks[0])
+
+	assert.Equal(t, CDKStack{
+		Name:         "Prod/Api",
+		Environment:  "aws://unknown-account/unknown-region",
+		TemplatePath: filepath.Join("testdata", "cdk", "cdk.out", "assembly-Prod", "ProdApi.template.json"),
+	}, stacks[1])
+}
+
+func TestCDKStackProvider(t *testing.T) {
+	stacks, err := LoadCloudAssembly("testdata/cdk/cdk.out/manifest.json")
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/cdk", Name: "app"}, map[string]interface{}{})
+
+	projects, err := NewCDKStackProvider(ctx, stacks[0], false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	assert.Equal(t, "app-AppStack", project.Name)
+	assert.Equal(t, "cdk", project.Metadata.Type)
+	assert.Equal(t, "AppStack", project.Metadata.CDKStackName)
+	assert.Equal(t, "aws://123456789012/eu-west-1", project.Metadata.CDKEnvironment)
+
+	resources := map[string]*schema.PartialResource{}
+	for _, r := range project.PartialResources {
+		resources[r.Address] = r
+	}
+
+	web := resources["aws_instance.WebServer1A2B3C4D"]
+	require.NotNil(t, web)
+	assert.Equal(t, "m5.large", web.RawValues.Get("instance_type").String())
+	assert.Equal(t, "eu-west-1", web.RawValues.Get("region").String())
+
+	queue := resources["aws_sqs_queue.DataNestedStackDataNestedStackResource5E6F7A8B/Queue4A7E3555"]
+	require.NotNil(t, queue, "resources from the nested stack should be loaded")
+	assert.Equal(t, "WebServer1A2B3C4D-queue", queue.RawValues.Get("queue_name").String())
+
+	metadata := resources["CDKMetadata"]
+	require.NotNil(t, metadata)
+	assert.True(t, metadata.Resource.NoPrice)
+
+	projects, err = NewCDKStackProvider(ctx, stacks[1], false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	assert.Equal(t, "app-Prod-Api", projects[0].Name)
+	require.Len(t, projects[0].PartialResources, 1)
+	assert.Equal(t, "us-east-1", projects[0].PartialResources[0].RawValues.Get("region").String())
+}
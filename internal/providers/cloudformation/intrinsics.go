@@ -0,0 +1,374 @@
+package cloudformation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultAccountID = "123456789012"
+	defaultStackName = "infracost"
+)
+
+var subVariableRegex = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+
+// noValue is returned when a value resolves to AWS::NoValue, the property it
+// is set on is removed from the template.
+type noValue struct{}
+
+// templateEvaluator resolves the intrinsic functions in a CloudFormation
+// template. Ref and Fn::GetAtt to other resources can't be known before the
+// stack is deployed, so they resolve to the logical ID of the resource, and
+// the logical ID and attribute name, e.g. MyBucket.Arn.
+type templateEvaluator struct {
+	region     string
+	accountID  string
+	parameters map[string]interface{}
+	mappings   map[string]interface{}
+	conditions map[string]interface{}
+	resources  map[string]interface{}
+
+	resolvedConditions map[string]bool
+	evaluating         map[string]bool
+}
+
+func newTemplateEvaluator(template map[string]interface{}, parameterOverrides map[string]string, region, accountID string) *templateEvaluator {
+	if accountID == "" {
+		accountID = defaultAccountID
+	}
+
+	e := &templateEvaluator{
+		region:             region,
+		accountID:          accountID,
+		parameters:         map[string]interface{}{},
+		mappings:           mapValue(template["Mappings"]),
+		conditions:         mapValue(template["Conditions"]),
+		resources:          mapValue(template["Resources"]),
+		resolvedConditions: map[string]bool{},
+		evaluating:         map[string]bool{},
+	}
+
+	for name, p := range mapValue(template["Parameters"]) {
+		param := mapValue(p)
+
+		if v, ok := parameterOverrides[name]; ok {
+			e.parameters[name] = parameterValue(param, v)
+			continue
+		}
+
+		if def, ok := param["Default"]; ok {
+			e.parameters[name] = parameterValue(param, def)
+		}
+	}
+
+	return e
+}
+
+// parameterValue converts list parameters to a slice so they can be used with
+// Fn::Select and Fn::Join.
+func parameterValue(param map[string]interface{}, v interface{}) interface{} {
+	t, _ := param["Type"].(string)
+	s, ok := v.(string)
+	if ok && (strings.HasPrefix(t, "List<") || t == "CommaDelimitedList") {
+		var items []interface{}
+		for _, item := range strings.Split(s, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items
+	}
+
+	return v
+}
+
+// resourceEnabled returns false if the resource has a condition that is false.
+func (e *templateEvaluator) resourceEnabled(resource map[string]interface{}) bool {
+	name, ok := resource["Condition"].(string)
+	if !ok {
+		return true
+	}
+
+	return e.condition(name)
+}
+
+func (e *templateEvaluator) condition(name string) bool {
+	if v, ok := e.resolvedConditions[name]; ok {
+		return v
+	}
+
+	// conditions that reference themselves are treated as false.
+	if e.evaluating[name] {
+		return false
+	}
+
+	e.evaluating[name] = true
+	v := truthy(e.eval(e.conditions[name]))
+	delete(e.evaluating, name)
+
+	e.resolvedConditions[name] = v
+	return v
+}
+
+// eval returns the value with all intrinsic functions resolved.
+func (e *templateEvaluator) eval(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for fn, args := range val {
+				if res, ok := e.evalFunction(fn, args); ok {
+					return res
+				}
+			}
+		}
+
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res := e.eval(item)
+			if _, ok := res.(noValue); ok {
+				continue
+			}
+			out[k] = res
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			res := e.eval(item)
+			if _, ok := res.(noValue); ok {
+				continue
+			}
+			out = append(out, res)
+		}
+		return out
+	}
+
+	return v
+}
+
+// evalFunction resolves the intrinsic function fn. The bool is false if fn is
+// not an intrinsic function.
+func (e *templateEvaluator) evalFunction(fn string, args interface{}) (interface{}, bool) {
+	switch fn {
+	case "Ref":
+		name, _ := args.(string)
+		return e.ref(name), true
+	case "Fn::GetAtt":
+		return e.getAtt(e.eval(args)), true
+	case "Fn::Sub":
+		return e.sub(args), true
+	case "Fn::If":
+		list, ok := args.([]interface{})
+		if !ok || len(list) != 3 {
+			return nil, true
+		}
+		name, _ := list[0].(string)
+		if e.condition(name) {
+			return e.eval(list[1]), true
+		}
+		return e.eval(list[2]), true
+	case "Condition":
+		name, ok := args.(string)
+		if !ok {
+			return nil, false
+		}
+		return e.condition(name), true
+	case "Fn::Equals":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) != 2 {
+			return false, true
+		}
+		return fmt.Sprint(list[0]) == fmt.Sprint(list[1]), true
+	case "Fn::Not":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) != 1 {
+			return false, true
+		}
+		return !truthy(list[0]), true
+	case "Fn::And", "Fn::Or":
+		list, _ := e.eval(args).([]interface{})
+		result := fn == "Fn::And"
+		for _, item := range list {
+			if fn == "Fn::And" {
+				result = result && truthy(item)
+			} else {
+				result = result || truthy(item)
+			}
+		}
+		return result, true
+	case "Fn::Join":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) != 2 {
+			return nil, true
+		}
+		items, _ := list[1].([]interface{})
+		s := make([]string, 0, len(items))
+		for _, item := range items {
+			s = append(s, stringValue(item))
+		}
+		return strings.Join(s, stringValue(list[0])), true
+	case "Fn::Select":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) != 2 {
+			return nil, true
+		}
+		i, err := strconv.Atoi(stringValue(list[0]))
+		items, _ := list[1].([]interface{})
+		if err != nil || i < 0 || i >= len(items) {
+			return nil, true
+		}
+		return items[i], true
+	case "Fn::Split":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) != 2 {
+			return nil, true
+		}
+		var out []interface{}
+		for _, s := range strings.Split(stringValue(list[1]), stringValue(list[0])) {
+			out = append(out, s)
+		}
+		return out, true
+	case "Fn::FindInMap":
+		list, ok := e.eval(args).([]interface{})
+		if !ok || len(list) < 3 {
+			return nil, true
+		}
+		top := mapValue(e.mappings[stringValue(list[0])])
+		second := mapValue(top[stringValue(list[1])])
+		return second[stringValue(list[2])], true
+	case "Fn::Base64":
+		return base64.StdEncoding.EncodeToString([]byte(stringValue(e.eval(args)))), true
+	case "Fn::GetAZs":
+		region := stringValue(e.eval(args))
+		if region == "" {
+			region = e.region
+		}
+		return []interface{}{region + "a", region + "b", region + "c"}, true
+	case "Fn::ImportValue", "Fn::Cidr", "Fn::Transform":
+		return nil, true
+	}
+
+	return nil, false
+}
+
+func (e *templateEvaluator) ref(name string) interface{} {
+	switch name {
+	case "AWS::Region":
+		return e.region
+	case "AWS::AccountId":
+		return e.accountID
+	case "AWS::Partition":
+		return "aws"
+	case "AWS::URLSuffix":
+		return "amazonaws.com"
+	case "AWS::StackName":
+		return defaultStackName
+	case "AWS::StackId":
+		return fmt.Sprintf("arn:aws:cloudformation:%s:%s:stack/%s", e.region, e.accountID, defaultStackName)
+	case "AWS::NotificationARNs":
+		return []interface{}{}
+	case "AWS::NoValue":
+		return noValue{}
+	}
+
+	if v, ok := e.parameters[name]; ok {
+		return v
+	}
+
+	if _, ok := e.resources[name]; ok {
+		return name
+	}
+
+	return nil
+}
+
+func (e *templateEvaluator) getAtt(args interface{}) interface{} {
+	var parts []string
+	switch val := args.(type) {
+	case string:
+		parts = strings.SplitN(val, ".", 2)
+	case []interface{}:
+		for _, p := range val {
+			parts = append(parts, stringValue(p))
+		}
+	}
+
+	if len(parts) != 2 {
+		return nil
+	}
+
+	return parts[0] + "." + parts[1]
+}
+
+func (e *templateEvaluator) sub(args interface{}) interface{} {
+	var s string
+	vars := map[string]interface{}{}
+
+	switch val := args.(type) {
+	case string:
+		s = val
+	case []interface{}:
+		if len(val) != 2 {
+			return nil
+		}
+		s, _ = val[0].(string)
+		for k, v := range mapValue(val[1]) {
+			vars[k] = e.eval(v)
+		}
+	default:
+		return nil
+	}
+
+	out := subVariableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSpace(match[2 : len(match)-1])
+
+		if v, ok := vars[name]; ok {
+			return stringValue(v)
+		}
+
+		if strings.Contains(name, ".") {
+			return stringValue(e.getAtt(name))
+		}
+
+		return stringValue(e.ref(name))
+	})
+
+	// ${!Literal} is written as ${Literal}
+	return strings.ReplaceAll(out, "${!", "${")
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		return strings.EqualFold(val, "true")
+	}
+
+	return false
+}
+
+func mapValue(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func stringValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil, noValue:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		s := make([]string, 0, len(val))
+		for _, item := range val {
+			s = append(s, stringValue(item))
+		}
+		return strings.Join(s, ",")
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,75 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateEvaluator(t *testing.T) {
+	template := map[string]interface{}{
+		"Parameters": map[string]interface{}{
+			"Env":     map[string]interface{}{"Type": "String", "Default": "dev"},
+			"Subnets": map[string]interface{}{"Type": "CommaDelimitedList", "Default": "a, b, c"},
+			"NoDef":   map[string]interface{}{"Type": "String"},
+		},
+		"Mappings": map[string]interface{}{
+			"Sizes": map[string]interface{}{
+				"dev":  map[string]interface{}{"Type": "t3.micro"},
+				"prod": map[string]interface{}{"Type": "m5.large"},
+			},
+		},
+		"Conditions": map[string]interface{}{
+			"IsProd":    map[string]interface{}{"Fn::Equals": []interface{}{map[string]interface{}{"Ref": "Env"}, "prod"}},
+			"IsNotProd": map[string]interface{}{"Fn::Not": []interface{}{map[string]interface{}{"Condition": "IsProd"}}},
+			"Both": map[string]interface{}{"Fn::And": []interface{}{
+				map[string]interface{}{"Condition": "IsNotProd"},
+				map[string]interface{}{"Fn::Equals": []interface{}{"a", "a"}},
+			}},
+			"Loop": map[string]interface{}{"Condition": "Loop"},
+		},
+		"Resources": map[string]interface{}{
+			"Bucket": map[string]interface{}{"Type": "AWS::S3::Bucket"},
+		},
+	}
+
+	e := newTemplateEvaluator(template, map[string]string{"NoDef": "override"}, "eu-west-1", "")
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+	}{
+		{"ref parameter", map[string]interface{}{"Ref": "Env"}, "dev"},
+		{"ref override", map[string]interface{}{"Ref": "NoDef"}, "override"},
+		{"ref region", map[string]interface{}{"Ref": "AWS::Region"}, "eu-west-1"},
+		{"ref resource", map[string]interface{}{"Ref": "Bucket"}, "Bucket"},
+		{"get att string", map[string]interface{}{"Fn::GetAtt": "Bucket.Arn"}, "Bucket.Arn"},
+		{"get att list", map[string]interface{}{"Fn::GetAtt": []interface{}{"Bucket", "Arn"}}, "Bucket.Arn"},
+		{"sub", map[string]interface{}{"Fn::Sub": "${Env}-${AWS::Region}-${Bucket.Arn}-${!Literal}"}, "dev-eu-west-1-Bucket.Arn-${Literal}"},
+		{"sub with vars", map[string]interface{}{"Fn::Sub": []interface{}{"${Name}-${Env}", map[string]interface{}{"Name": "app"}}}, "app-dev"},
+		{"if", map[string]interface{}{"Fn::If": []interface{}{"IsProd", "big", "small"}}, "small"},
+		{"and", map[string]interface{}{"Condition": "Both"}, true},
+		{"self referencing condition", map[string]interface{}{"Condition": "Loop"}, false},
+		{"find in map", map[string]interface{}{"Fn::FindInMap": []interface{}{"Sizes", map[string]interface{}{"Ref": "Env"}, "Type"}}, "t3.micro"},
+		{"select list parameter", map[string]interface{}{"Fn::Select": []interface{}{"1", map[string]interface{}{"Ref": "Subnets"}}}, "b"},
+		{"join", map[string]interface{}{"Fn::Join": []interface{}{"-", []interface{}{"a", map[string]interface{}{"Ref": "Env"}}}}, "a-dev"},
+		{"split", map[string]interface{}{"Fn::Split": []interface{}{",", "x,y"}}, []interface{}{"x", "y"}},
+		{"import value", map[string]interface{}{"Fn::ImportValue": "shared"}, nil},
+		{
+			"no value is removed",
+			map[string]interface{}{"A": "1", "B": map[string]interface{}{"Fn::If": []interface{}{"IsProd", "2", map[string]interface{}{"Ref": "AWS::NoValue"}}}},
+			map[string]interface{}{"A": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, e.eval(tt.input))
+		})
+	}
+
+	assert.True(t, e.resourceEnabled(map[string]interface{}{"Condition": "IsNotProd"}))
+	assert.False(t, e.resourceEnabled(map[string]interface{}{"Condition": "IsProd"}))
+	assert.True(t, e.resourceEnabled(map[string]interface{}{}))
+}
@@ -1,9 +1,18 @@
 package cloudformation
 
 import (
+	"encoding/json"
+	"sort"
+	"strings"
+
 	"github.com/awslabs/goformation/v7/cloudformation"
+	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	tfaws "github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/schema"
 )
 
@@ -28,6 +37,33 @@ type parsedResource struct {
 	ResourceData    *schema.ResourceData
 }
 
+// templateResource is a resource from a CloudFormation template with all its
+// intrinsic functions evaluated.
+type templateResource struct {
+	LogicalID  string
+	Type       string
+	Properties map[string]interface{}
+	// Stack is the logical ID of the nested stack that the resource is in,
+	// joined with the logical IDs of any parent nested stacks, e.g. Api/Queues.
+	// It is empty for resources in the root template.
+	Stack string
+}
+
+// key returns the logical ID of the resource prefixed with its nested stack, so
+// that resources in nested stacks that reuse a logical ID, e.g. Bucket, don't
+// collide with each other or with the resources of the root template.
+func (r templateResource) key() string {
+	return stackKey(r.Stack, r.LogicalID)
+}
+
+func stackKey(stack, logicalID string) string {
+	if stack == "" {
+		return logicalID
+	}
+
+	return stack + "/" + logicalID
+}
+
 func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) parsedResource {
 	registryMap := GetResourceRegistryMap()
 
@@ -74,11 +110,73 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) par
 		}
 	}
 
+	return unsupportedResource(d, d.Type+"."+d.Address)
+}
+
+// createTerraformResource creates the resource using the Terraform resource
+// registry. d must have been converted to the Terraform resource type.
+func (p *Parser) createTerraformResource(d *schema.ResourceData, u *schema.UsageData) parsedResource {
+	for cKey, cValue := range tfaws.GetSpecialContext(d) {
+		p.ctx.ContextValues.SetValue(cKey, cValue)
+	}
+
+	if registryItem, ok := (*terraform.ResourceRegistryMap)[d.Type]; ok {
+		if registryItem.NoPrice {
+			resource := &schema.Resource{
+				Name:        d.Address,
+				IsSkipped:   true,
+				NoPrice:     true,
+				SkipMessage: "Free resource.",
+				Metadata:    d.Metadata,
+			}
+			return parsedResource{
+				PartialResource: schema.NewPartialResource(d, resource, nil, registryItem.CloudResourceIDFunc(d)),
+				ResourceData:    d,
+			}
+		}
+
+		if registryItem.CoreRFunc != nil {
+			coreRes := registryItem.CoreRFunc(d)
+			if coreRes != nil {
+				return parsedResource{
+					PartialResource: schema.NewPartialResource(d, nil, coreRes, registryItem.CloudResourceIDFunc(d)),
+					ResourceData:    d,
+				}
+			}
+		} else {
+			build := func(u *schema.UsageData) *schema.Resource {
+				res := registryItem.RFunc(d, u)
+				if res != nil && u != nil {
+					res.EstimationSummary = u.CalcEstimationSummary()
+				}
+
+				return res
+			}
+
+			res := build(u)
+			if res != nil {
+				partial := schema.NewPartialResource(d, res, nil, registryItem.CloudResourceIDFunc(d))
+				if p.ctx.RunContext.Config.ForecastMonths > 0 {
+					partial.BuildWithUsage = build
+				}
+
+				return parsedResource{
+					PartialResource: partial,
+					ResourceData:    d,
+				}
+			}
+		}
+	}
+
+	return unsupportedResource(d, d.Address)
+}
+
+func unsupportedResource(d *schema.ResourceData, name string) parsedResource {
 	return parsedResource{
 		PartialResource: schema.NewPartialResource(
 			d,
 			&schema.Resource{
-				Name:        d.Type + "." + d.Address,
+				Name:        name,
 				IsSkipped:   true,
 				SkipMessage: "This resource is not currently supported",
 				Metadata:    d.Metadata,
@@ -90,14 +188,135 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) par
 	}
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage schema.UsageMap) []parsedResource {
-	resources := make([]parsedResource, 0, len(t.Resources))
+// parseTemplate creates the resources for the template. Resources that have a
+// CloudFormation registry item use that, otherwise resources that can be mapped
+// to a Terraform resource are priced using the Terraform registry.
+func (p *Parser) parseTemplate(resources []templateResource, region string, usage schema.UsageMap) []parsedResource {
+	parsed := make([]parsedResource, 0, len(resources))
+	registryMap := GetResourceRegistryMap()
+	cfResources := cloudformation.AllResources()
+
+	tfResources := map[string]*schema.ResourceData{}
+	stacks := map[string]string{}
+
+	for _, r := range resources {
+		if _, ok := (*registryMap)[r.Type]; ok {
+			cfr, err := unmarshalResource(cfResources, r)
+			if err != nil {
+				logging.Logger.Debug().Err(err).Msgf("Could not read CloudFormation resource %s", r.key())
+			}
+
+			resourceData := schema.NewCFResourceData(r.Type, "aws", r.key(), nil, cfr)
+			resourceData.Region = region
+			usageData := usage.Get(resourceData.Type + "." + resourceData.Address)
+			parsed = append(parsed, p.createResource(resourceData, usageData))
+			continue
+		}
+
+		mapping, ok := aws.TerraformMappings[r.Type]
+		if !ok {
+			resourceData := schema.NewCFResourceData(r.Type, "aws", r.key(), nil, nil)
+			parsed = append(parsed, unsupportedResource(resourceData, r.Type+"."+r.key()))
+			continue
+		}
+
+		tfResources[r.key()] = p.terraformResourceData(r, mapping, region)
+		stacks[r.key()] = r.Stack
+	}
+
+	p.parseReferences(tfResources, stacks)
+
+	// sort the resources so they are created in the same order each run, since
+	// the special context values are set while the resources are created.
+	logicalIDs := make([]string, 0, len(tfResources))
+	for id := range tfResources {
+		logicalIDs = append(logicalIDs, id)
+	}
+	sort.Strings(logicalIDs)
+
+	for _, id := range logicalIDs {
+		d := tfResources[id]
+		d.UsageData = usage.Get(d.Address)
+		parsed = append(parsed, p.createTerraformResource(d, d.UsageData))
+	}
+
+	return parsed
+}
+
+// terraformResourceData returns the ResourceData for the CloudFormation
+// resource in the same format the Terraform parser creates, with an address
+// of the Terraform resource type and the logical ID, e.g. aws_instance.WebServer,
+// or aws_instance.Api/WebServer for a resource in the nested stack Api. The id
+// is set to the logical ID, since this is what Ref returns, so that references
+// between resources can be found.
+func (p *Parser) terraformResourceData(r templateResource, mapping aws.TerraformMapping, region string) *schema.ResourceData {
+	values := mapping.ToTerraformValues(r.Properties)
+	values["id"] = r.LogicalID
+	values["region"] = region
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		logging.Logger.Debug().Err(err).Msgf("Could not convert CloudFormation resource %s", r.key())
+		b = []byte("{}")
+	}
+
+	var tags *map[string]string
+	if t, ok := values["tags"].(map[string]interface{}); ok {
+		m := make(map[string]string, len(t))
+		for k, v := range t {
+			m[k] = stringValue(v)
+		}
+		tags = &m
+	}
+
+	d := schema.NewResourceData(mapping.Type, "aws", mapping.Type+"."+r.key(), tags, gjson.ParseBytes(b))
+	d.Region = region
+	for k, v := range p.ctx.ProjectConfig.Metadata {
+		d.ProjectMetadata[k] = v
+	}
+
+	return d
+}
+
+// parseReferences adds the references between resources. Ref and Fn::GetAtt
+// evaluate to the logical ID of the resource, or the logical ID and the
+// attribute, so these are used to find the referenced resource in the same
+// stack. stacks holds the nested stack of each resource.
+func (p *Parser) parseReferences(resources map[string]*schema.ResourceData, stacks map[string]string) {
+	for key, d := range resources {
+		for _, attr := range terraform.ResourceRegistryMap.GetReferenceAttributes(d.Type) {
+			for _, refVal := range d.Get(attr).Array() {
+				logicalID, _, _ := strings.Cut(refVal.String(), ".")
+				ref, ok := resources[stackKey(stacks[key], logicalID)]
+				if !ok || ref == d {
+					continue
+				}
+
+				d.AddReference(attr, ref, terraform.ResourceRegistryMap.GetReferenceAttributes(ref.Type))
+			}
+		}
+	}
+}
+
+// unmarshalResource returns the goformation resource for the template resource.
+func unmarshalResource(cfResources map[string]cloudformation.Resource, r templateResource) (cloudformation.Resource, error) {
+	cfr, ok := cfResources[r.Type]
+	if !ok {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"Type":       r.Type,
+		"Properties": r.Properties,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	for name, d := range t.Resources {
-		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, nil, d)
-		usageData := usage.Get(resourceData.Type + "." + resourceData.Address)
-		resources = append(resources, p.createResource(resourceData, usageData))
+	err = json.Unmarshal(b, cfr)
+	if err != nil {
+		return nil, err
 	}
 
-	return resources
+	return cfr, nil
 }
@@ -1,18 +1,30 @@
 package cloudformation
 
 import (
-	"github.com/awslabs/goformation/v7"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v7/intrinsics"
 	"github.com/pkg/errors"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
+	tfaws "github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/schema"
 )
 
+// maxNestedStackDepth is the maximum depth of nested stacks that are loaded.
+const maxNestedStackDepth = 10
+
 type TemplateProvider struct {
 	ctx                  *config.ProjectContext
 	Path                 string
 	includePastResources bool
+	// stack is set when the template is a stack from a CDK cloud assembly.
+	stack *CDKStack
 }
 
 func NewTemplateProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
@@ -23,7 +35,22 @@ func NewTemplateProvider(ctx *config.ProjectContext, includePastResources bool)
 	}
 }
 
+// NewCDKStackProvider returns a provider for a single stack in a CDK cloud
+// assembly. Each stack is reported as its own project.
+func NewCDKStackProvider(ctx *config.ProjectContext, stack CDKStack, includePastResources bool) schema.Provider {
+	return &TemplateProvider{
+		ctx:                  ctx,
+		Path:                 stack.TemplatePath,
+		includePastResources: includePastResources,
+		stack:                &stack,
+	}
+}
+
 func (p *TemplateProvider) ProjectName() string {
+	if p.stack != nil {
+		return config.CleanProjectName(p.ctx.ProjectConfig.Path) + "-" + config.CleanProjectName(p.stack.Name)
+	}
+
 	return config.CleanProjectName(p.ctx.ProjectConfig.Path)
 }
 
@@ -34,15 +61,28 @@ func (p *TemplateProvider) VarFiles() []string {
 func (p *TemplateProvider) Context() *config.ProjectContext { return p.ctx }
 
 func (p *TemplateProvider) Type() string {
+	if p.stack != nil {
+		return "cdk"
+	}
+
 	return "cloudformation"
 }
 
 func (p *TemplateProvider) DisplayType() string {
+	if p.stack != nil {
+		return "AWS CDK"
+	}
+
 	return "CloudFormation"
 }
 
 func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+
+	if p.stack != nil {
+		metadata.CDKStackName = p.stack.Name
+		metadata.CDKEnvironment = p.stack.Environment
+	}
 }
 
 func (p *TemplateProvider) RelativePath() string {
@@ -50,7 +90,7 @@ func (p *TemplateProvider) RelativePath() string {
 }
 
 func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
-	template, err := goformation.Open(p.Path)
+	template, err := loadTemplate(p.Path)
 	if err != nil {
 		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
 	}
@@ -64,13 +104,15 @@ func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Proje
 	if name == "" {
 		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
 	}
+	if p.stack != nil {
+		name += "-" + config.CleanProjectName(p.stack.Name)
+	}
 
 	project := schema.NewProject(name, metadata)
 	parser := NewParser(p.ctx, p.includePastResources)
-	parsed := parser.parseTemplate(template, usage)
-	if err != nil {
-		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
-	}
+	region := p.region()
+	resources := p.evaluateResources(template, p.parameters(), region, filepath.Dir(p.Path), "", 0)
+	parsed := parser.parseTemplate(resources, region, usage)
 
 	for _, item := range parsed {
 		project.PartialResources = append(project.PartialResources, item.PartialResource)
@@ -78,3 +120,183 @@ func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Proje
 
 	return []*schema.Project{project}, nil
 }
+
+// loadTemplate reads the template without resolving any intrinsic functions.
+// Short form YAML intrinsic functions, e.g. !Ref, are converted to their long
+// form so that the template can be evaluated.
+func loadTemplate(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &intrinsics.ProcessorOptions{NoProcess: true}
+
+	var j []byte
+	if strings.HasSuffix(path, ".json") {
+		j, err = intrinsics.ProcessJSON(data, opts)
+	} else {
+		j, err = intrinsics.ProcessYAML(data, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var template map[string]interface{}
+	err = json.Unmarshal(j, &template)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// region returns the region the stack is deployed to. This uses the AWS
+// override region if it is set, otherwise the region of the CDK stack
+// environment or the region in the project config.
+func (p *TemplateProvider) region() string {
+	if p.ctx.RunContext.Config.AWSOverrideRegion != "" {
+		return p.ctx.RunContext.Config.AWSOverrideRegion
+	}
+
+	if p.stack != nil && p.stack.Region != "" {
+		return p.stack.Region
+	}
+
+	if p.ctx.ProjectConfig.CloudFormationRegion != "" {
+		return p.ctx.ProjectConfig.CloudFormationRegion
+	}
+
+	return tfaws.DefaultProviderRegion
+}
+
+// parameters returns the parameter values for the template. Parameters in the
+// project config take precedence over the CDK stack parameters.
+func (p *TemplateProvider) parameters() map[string]string {
+	params := map[string]string{}
+
+	if p.stack != nil {
+		for k, v := range p.stack.Parameters {
+			params[k] = v
+		}
+	}
+
+	for k, v := range p.ctx.ProjectConfig.CloudFormationParameters {
+		params[k] = v
+	}
+
+	return params
+}
+
+func (p *TemplateProvider) accountID() string {
+	if p.stack != nil {
+		return p.stack.Account
+	}
+
+	return ""
+}
+
+// evaluateResources returns the resources in the template with their intrinsic
+// functions evaluated using the given parameters. Resources with a condition
+// that evaluates to false are not returned. SAM global properties are added to
+// the SAM resources that don't set them. The resources of nested stacks with a
+// local template, e.g. the nested stack assets in a CDK cloud assembly, are
+// also returned with the logical IDs of the nested stacks that they are in.
+func (p *TemplateProvider) evaluateResources(template map[string]interface{}, params map[string]string, region, dir, stack string, depth int) []templateResource {
+	e := newTemplateEvaluator(template, params, region, p.accountID())
+	globals := mapValue(e.eval(template["Globals"]))
+
+	logicalIDs := make([]string, 0, len(e.resources))
+	for id := range e.resources {
+		logicalIDs = append(logicalIDs, id)
+	}
+	sort.Strings(logicalIDs)
+
+	resources := make([]templateResource, 0, len(logicalIDs))
+	for _, id := range logicalIDs {
+		resource := mapValue(e.resources[id])
+		t, _ := resource["Type"].(string)
+		if t == "" || !e.resourceEnabled(resource) {
+			continue
+		}
+
+		props := mapValue(e.eval(resource["Properties"]))
+		if props == nil {
+			props = map[string]interface{}{}
+		}
+
+		if strings.HasPrefix(t, "AWS::Serverless::") {
+			for k, v := range mapValue(globals[strings.TrimPrefix(t, "AWS::Serverless::")]) {
+				if _, ok := props[k]; !ok {
+					props[k] = v
+				}
+			}
+		}
+
+		r := templateResource{
+			LogicalID:  id,
+			Type:       t,
+			Properties: props,
+			Stack:      stack,
+		}
+		resources = append(resources, r)
+
+		nestedPath := nestedTemplatePath(resource, t, props, dir)
+		if nestedPath == "" {
+			continue
+		}
+
+		if depth >= maxNestedStackDepth {
+			logging.Logger.Debug().Msgf("Skipping nested stack %s as the maximum nested stack depth has been reached", id)
+			continue
+		}
+
+		nested, err := loadTemplate(nestedPath)
+		if err != nil {
+			logging.Logger.Debug().Err(err).Msgf("Could not read the template for nested stack %s", id)
+			continue
+		}
+
+		nestedParams := map[string]string{}
+		for k, v := range mapValue(props["Parameters"]) {
+			nestedParams[k] = stringValue(v)
+		}
+
+		resources = append(resources, p.evaluateResources(nested, nestedParams, region, filepath.Dir(nestedPath), r.key(), depth+1)...)
+	}
+
+	return resources
+}
+
+// nestedTemplatePath returns the path of the local template file for a nested
+// stack. CDK sets the path of the nested stack asset in the resource metadata.
+// Otherwise the template location is used if it is a local file.
+func nestedTemplatePath(resource map[string]interface{}, t string, props map[string]interface{}, dir string) string {
+	var location string
+
+	switch t {
+	case "AWS::CloudFormation::Stack":
+		location, _ = mapValue(resource["Metadata"])["aws:asset:path"].(string)
+		if location == "" {
+			location, _ = props["TemplateURL"].(string)
+		}
+	case "AWS::Serverless::Application":
+		location, _ = props["Location"].(string)
+	default:
+		return ""
+	}
+
+	if location == "" || strings.Contains(location, "://") {
+		return ""
+	}
+
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(dir, location)
+	}
+
+	if _, err := os.Stat(location); err != nil {
+		return ""
+	}
+
+	return location
+}
@@ -0,0 +1,128 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func loadTestProject(t *testing.T, projectConfig *config.Project) map[string]*schema.PartialResource {
+	t.Helper()
+
+	projectConfig.Path = "testdata/template.yml"
+	ctx := config.NewProjectContext(config.EmptyRunContext(), projectConfig, map[string]interface{}{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	resources := map[string]*schema.PartialResource{}
+	for _, r := range projects[0].PartialResources {
+		resources[r.Address] = r
+	}
+
+	return resources
+}
+
+func TestLoadResources(t *testing.T) {
+	resources := loadTestProject(t, &config.Project{})
+
+	assert.NotContains(t, resources, "aws_db_instance.ReadReplica")
+
+	web := resources["aws_instance.WebServer"]
+	require.NotNil(t, web)
+	require.NotNil(t, web.CoreResource)
+	assert.Equal(t, "t3.micro", web.RawValues.Get("instance_type").String())
+	assert.Equal(t, "ami-0123456789abcdef0", web.RawValues.Get("ami").String())
+	assert.False(t, web.RawValues.Get("monitoring").Bool())
+	assert.Equal(t, int64(30), web.RawValues.Get("root_block_device.0.volume_size").Int())
+	assert.Equal(t, "io1", web.RawValues.Get("ebs_block_device.0.volume_type").String())
+	assert.Equal(t, "us-east-1", web.RawValues.Get("region").String())
+	require.NotNil(t, web.Tags)
+	assert.Equal(t, "infracost-dev-web", (*web.Tags)["Name"])
+
+	db := resources["aws_db_instance.Database"]
+	require.NotNil(t, db)
+	assert.Equal(t, "db.t3.medium", db.RawValues.Get("instance_class").String())
+	assert.Equal(t, int64(20), db.RawValues.Get("allocated_storage").Int())
+	assert.True(t, db.RawValues.Get("performance_insights_enabled").Bool())
+	assert.False(t, db.RawValues.Get("multi_az").Bool())
+
+	bucket := resources["aws_s3_bucket.Assets"]
+	require.NotNil(t, bucket)
+	assert.Equal(t, "dev-assets-us-east-1", bucket.RawValues.Get("bucket").String())
+
+	worker := resources["aws_lambda_function.Worker"]
+	require.NotNil(t, worker)
+	assert.Equal(t, int64(512), worker.RawValues.Get("memory_size").Int())
+	assert.Equal(t, "arm64", worker.RawValues.Get("architectures.0").String())
+	assert.Equal(t, "Assets.Arn", worker.RawValues.Get("environment.0.variables.BUCKET").String())
+
+	table := resources["Table"]
+	require.NotNil(t, table)
+	require.NotNil(t, table.Resource)
+	assert.Equal(t, "AWS::DynamoDB::Table.Table", table.Resource.Name)
+
+	topic := resources["aws_sns_topic.Topic"]
+	require.NotNil(t, topic)
+	assert.NotNil(t, topic.CoreResource)
+
+	custom := resources["Custom"]
+	require.NotNil(t, custom)
+	assert.True(t, custom.Resource.IsSkipped)
+}
+
+func TestLoadResourcesWithParameters(t *testing.T) {
+	resources := loadTestProject(t, &config.Project{
+		CloudFormationParameters: map[string]string{
+			"Environment":  "prod",
+			"InstanceType": "m5.xlarge",
+		},
+		CloudFormationRegion: "eu-west-2",
+	})
+
+	web := resources["aws_instance.WebServer"]
+	require.NotNil(t, web)
+	assert.Equal(t, "m5.xlarge", web.RawValues.Get("instance_type").String())
+	assert.True(t, web.RawValues.Get("monitoring").Bool())
+	assert.Equal(t, "eu-west-2", web.RawValues.Get("region").String())
+
+	db := resources["aws_db_instance.Database"]
+	require.NotNil(t, db)
+	assert.Equal(t, "db.m5.large", db.RawValues.Get("instance_class").String())
+	assert.Equal(t, int64(100), db.RawValues.Get("allocated_storage").Int())
+	assert.True(t, db.RawValues.Get("multi_az").Bool())
+
+	replica := resources["aws_db_instance.ReadReplica"]
+	require.NotNil(t, replica)
+	assert.Equal(t, "Database", replica.RawValues.Get("replicate_source_db").String())
+
+	bucket := resources["aws_s3_bucket.Assets"]
+	require.NotNil(t, bucket)
+	assert.Equal(t, "prod-assets-eu-west-2", bucket.RawValues.Get("bucket").String())
+}
+
+func TestLoadResourcesNestedStacksWithSameLogicalID(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/nested/template.yml"}, map[string]interface{}{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	buckets := map[string]string{}
+	for _, r := range projects[0].PartialResources {
+		if r.Type == "aws_s3_bucket" {
+			buckets[r.Address] = r.RawValues.Get("bucket").String()
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"aws_s3_bucket.Bucket":          "root",
+		"aws_s3_bucket.Docs/Bucket":     "docs",
+		"aws_s3_bucket.Frontend/Bucket": "frontend",
+	}, buckets)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/providers/pulumi"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -51,6 +52,12 @@ func Detect(ctx *config.RunContext, project *config.Project, includePastResource
 		return &DetectionOutput{Providers: []schema.Provider{terraform.NewStateJSONProvider(projectContext, includePastResources)}, RootModules: 1}, nil
 	case ProjectTypeCloudFormation:
 		return &DetectionOutput{Providers: []schema.Provider{cloudformation.NewTemplateProvider(projectContext, includePastResources)}, RootModules: 1}, nil
+	case ProjectTypeCDK:
+		return detectCDKStacks(ctx, project, includePastResources)
+	case ProjectTypePulumiPreviewJSON:
+		return &DetectionOutput{Providers: []schema.Provider{pulumi.NewPreviewJSONProvider(projectContext, includePastResources)}, RootModules: 1}, nil
+	case ProjectTypePulumiStateJSON:
+		return &DetectionOutput{Providers: []schema.Provider{pulumi.NewStateJSONProvider(projectContext, includePastResources)}, RootModules: 1}, nil
 	}
 
 	pathOverrides := make([]hcl.PathOverrideConfig, len(ctx.Config.Autodetect.PathOverrides))
@@ -99,7 +106,10 @@ func Detect(ctx *config.RunContext, project *config.Project, includePastResource
 	var autoProviders []schema.Provider
 	for _, rootPath := range rootPaths {
 		detectedProjectContext := config.NewProjectContext(ctx, project, nil)
-		if rootPath.IsTerragrunt {
+		if rootPath.IsTerraformStack {
+			detectedProjectContext.ContextValues.SetValue("project_type", "terraform_stack")
+			autoProviders = append(autoProviders, terraform.NewStackProvider(rootPath, detectedProjectContext))
+		} else if rootPath.IsTerragrunt {
 			detectedProjectContext.ContextValues.SetValue("project_type", "terragrunt_dir")
 			autoProviders = append(autoProviders, terraform.NewTerragruntHCLProvider(rootPath, detectedProjectContext))
 		} else {
@@ -195,6 +205,29 @@ func autodetectedRootToProviders(projectContext *config.ProjectContext, rootPath
 	return []schema.Provider{provider}
 }
 
+// detectCDKStacks returns a provider for each stack in the CDK cloud assembly
+// at the project path.
+func detectCDKStacks(ctx *config.RunContext, project *config.Project, includePastResources bool) (*DetectionOutput, error) {
+	manifestPath, _ := cloudformation.CloudAssemblyManifest(project.Path)
+	stacks, err := cloudformation.LoadCloudAssembly(manifestPath)
+	if err != nil {
+		return &DetectionOutput{}, err
+	}
+
+	if len(stacks) == 0 {
+		return &DetectionOutput{}, fmt.Errorf("no stacks found in CDK cloud assembly %s", manifestPath)
+	}
+
+	providers := make([]schema.Provider, 0, len(stacks))
+	for _, stack := range stacks {
+		stackContext := config.NewProjectContext(ctx, project, nil)
+		stackContext.ContextValues.SetValue("project_type", ProjectTypeCDK)
+		providers = append(providers, cloudformation.NewCDKStackProvider(stackContext, stack, includePastResources))
+	}
+
+	return &DetectionOutput{Providers: providers, RootModules: len(providers)}, nil
+}
+
 type ProjectType string
 
 var (
@@ -204,10 +237,17 @@ var (
 	ProjectTypeTerragruntCLI       ProjectType = "terragrunt_cli"
 	ProjectTypeTerraformStateJSON  ProjectType = "terraform_state_json"
 	ProjectTypeCloudFormation      ProjectType = "cloudformation"
+	ProjectTypeCDK                 ProjectType = "cdk"
+	ProjectTypePulumiPreviewJSON   ProjectType = "pulumi_preview_json"
+	ProjectTypePulumiStateJSON     ProjectType = "pulumi_state_json"
 	ProjectTypeAutodetect          ProjectType = "autodetect"
 )
 
 func DetectProjectType(path string, forceCLI bool) ProjectType {
+	if _, ok := cloudformation.CloudAssemblyManifest(path); ok {
+		return ProjectTypeCDK
+	}
+
 	if isCloudFormationTemplate(path) {
 		return ProjectTypeCloudFormation
 	}
@@ -224,6 +264,14 @@ func DetectProjectType(path string, forceCLI bool) ProjectType {
 		return ProjectTypeTerraformPlanBinary
 	}
 
+	if isPulumiPreviewJSON(path) {
+		return ProjectTypePulumiPreviewJSON
+	}
+
+	if isPulumiStateJSON(path) {
+		return ProjectTypePulumiStateJSON
+	}
+
 	if forceCLI {
 		if isTerragruntNestedDir(path, 5) {
 			return ProjectTypeTerragruntCLI
@@ -283,6 +331,24 @@ func isTerraformStateJSON(path string) bool {
 	return jsonFormat.FormatVersion != "" && jsonFormat.Values != nil
 }
 
+func isPulumiPreviewJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return pulumi.IsPreviewJSON(b)
+}
+
+func isPulumiStateJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return pulumi.IsStateJSON(b)
+}
+
 func isTerraformPlan(path string) bool {
 	r, err := zip.OpenReader(path)
 	if err != nil {
@@ -0,0 +1,463 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	// unknownValue is the value Pulumi uses for outputs that are not known until apply.
+	unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+	// secretSig is the key Pulumi uses to mark an object as a secret value.
+	secretSig = "4dabf18193072939515e22adb298388d"
+
+	providerTypePrefix = "pulumi:providers:"
+	stackType          = "pulumi:pulumi:Stack"
+)
+
+var (
+	// mapAttributes are properties that are maps in Terraform, as opposed to
+	// nested blocks, so their keys are kept as they are.
+	mapAttributes = map[string]bool{
+		"tags":             true,
+		"tags_all":         true,
+		"labels":           true,
+		"effective_labels": true,
+		"variables":        true,
+	}
+
+	invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+)
+
+// resourceState is a resource in a Pulumi stack export or in the old and new
+// states of a step in the preview JSON.
+type resourceState struct {
+	URN      string                 `json:"urn"`
+	Custom   bool                   `json:"custom"`
+	Type     string                 `json:"type"`
+	ID       string                 `json:"id"`
+	Inputs   map[string]interface{} `json:"inputs"`
+	Outputs  map[string]interface{} `json:"outputs"`
+	Provider string                 `json:"provider"`
+	Parent   string                 `json:"parent"`
+}
+
+// previewJSON is the output of `pulumi preview --json`.
+type previewJSON struct {
+	Config map[string]string `json:"config"`
+	Steps  []struct {
+		Op       string         `json:"op"`
+		URN      string         `json:"urn"`
+		OldState *resourceState `json:"oldState"`
+		NewState *resourceState `json:"newState"`
+	} `json:"steps"`
+}
+
+// stateJSON is the output of `pulumi stack export`.
+type stateJSON struct {
+	Version    int `json:"version"`
+	Deployment struct {
+		Resources []resourceState `json:"resources"`
+	} `json:"deployment"`
+}
+
+// Parser converts Pulumi resources to the Terraform JSON format and parses them
+// with the Terraform parser so that the Terraform resource registry is used to
+// calculate the costs.
+type Parser struct {
+	tfParser *terraform.Parser
+}
+
+func NewParser(ctx *config.ProjectContext, includePastResources bool) *Parser {
+	return &Parser{
+		tfParser: terraform.NewParser(ctx, includePastResources),
+	}
+}
+
+func (p *Parser) parsePreviewJSON(j []byte, usage schema.UsageMap) (*terraform.ParsedPlanConfiguration, error) {
+	var preview previewJSON
+	err := json.Unmarshal(j, &preview)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Pulumi preview JSON")
+	}
+
+	var current, past []resourceState
+	seenCurrent := map[string]bool{}
+	seenPast := map[string]bool{}
+
+	for _, step := range preview.Steps {
+		if step.NewState != nil && step.Op != "delete" && step.Op != "delete-replaced" && !seenCurrent[step.URN] {
+			seenCurrent[step.URN] = true
+			current = append(current, *step.NewState)
+		}
+
+		if step.OldState != nil && !seenPast[step.URN] {
+			seenPast[step.URN] = true
+			past = append(past, *step.OldState)
+		}
+	}
+
+	tfJSON, err := toTerraformJSON(current, past, true, preview.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.tfParser.ParseJSON(tfJSON, usage)
+}
+
+func (p *Parser) parseStateJSON(j []byte, usage schema.UsageMap) (*terraform.ParsedPlanConfiguration, error) {
+	var state stateJSON
+	err := json.Unmarshal(j, &state)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Pulumi stack export")
+	}
+
+	tfJSON, err := toTerraformJSON(state.Deployment.Resources, nil, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.tfParser.ParseJSON(tfJSON, usage)
+}
+
+// toTerraformJSON returns a Terraform plan JSON document containing the Pulumi
+// resources. Each Pulumi provider resource is added to the provider config so
+// that the region of the resources can be worked out by the Terraform parser.
+func toTerraformJSON(current, past []resourceState, includePast bool, stackConfig map[string]string) ([]byte, error) {
+	providerConfig := map[string]interface{}{}
+	for _, resources := range [][]resourceState{past, current} {
+		for _, r := range resources {
+			if !strings.HasPrefix(r.Type, providerTypePrefix) {
+				continue
+			}
+
+			key, ok := providerConfigKey(r.URN)
+			if !ok {
+				continue
+			}
+
+			expressions := map[string]interface{}{}
+			region, _ := r.Inputs["region"].(string)
+			if region == "" {
+				pkg := strings.TrimPrefix(r.Type, providerTypePrefix)
+				region = stackConfig[pkg+":region"]
+			}
+			if region != "" {
+				expressions["region"] = map[string]interface{}{"constant_value": region}
+			}
+
+			providerConfig[key] = map[string]interface{}{
+				"name":        strings.Split(key, ".")[0],
+				"expressions": expressions,
+			}
+		}
+	}
+
+	// default providers aren't in the preview steps if they haven't changed, so
+	// use the stack config for their region.
+	for key, value := range stackConfig {
+		parts := strings.Split(key, ":")
+		if len(parts) != 2 || parts[1] != "region" {
+			continue
+		}
+
+		prefix, ok := packagePrefixes[parts[0]]
+		if !ok {
+			continue
+		}
+
+		if _, exists := providerConfig[prefix]; !exists {
+			providerConfig[prefix] = map[string]interface{}{
+				"name":        prefix,
+				"expressions": map[string]interface{}{"region": map[string]interface{}{"constant_value": value}},
+			}
+		}
+	}
+
+	currentResources, conf := toTerraformResources(current)
+	pastResources, pastConf := toTerraformResources(past)
+
+	// add the config of any deleted resources so their region can be found.
+	inConf := make(map[string]bool, len(conf))
+	for _, c := range conf {
+		inConf[c.Address] = true
+	}
+	for _, c := range pastConf {
+		if !inConf[c.Address] {
+			conf = append(conf, c)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"format_version": "1.0",
+		"planned_values": map[string]interface{}{
+			"root_module": map[string]interface{}{"resources": currentResources},
+		},
+		"configuration": map[string]interface{}{
+			"provider_config": providerConfig,
+			"root_module":     map[string]interface{}{"resources": conf},
+		},
+	}
+
+	if includePast {
+		doc["prior_state"] = map[string]interface{}{
+			"values": map[string]interface{}{
+				"root_module": map[string]interface{}{"resources": pastResources},
+			},
+		}
+
+		// the Terraform parser only keeps past resources that are in the
+		// resource changes or the planned values, so deleted resources need
+		// to be listed as changes.
+		changes := make([]map[string]string, 0, len(pastResources))
+		for _, r := range pastResources {
+			changes = append(changes, map[string]string{"address": r.Address})
+		}
+		doc["resource_changes"] = changes
+	}
+
+	return json.Marshal(doc)
+}
+
+// tfResource is a resource in the Terraform plan JSON planned values.
+type tfResource struct {
+	Address      string                 `json:"address"`
+	Mode         string                 `json:"mode"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	ProviderName string                 `json:"provider_name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+// tfResourceConf is a resource in the Terraform plan JSON configuration.
+type tfResourceConf struct {
+	Address           string `json:"address"`
+	Mode              string `json:"mode"`
+	Type              string `json:"type"`
+	Name              string `json:"name"`
+	ProviderConfigKey string `json:"provider_config_key"`
+}
+
+// toTerraformResources returns the planned values and the configuration of
+// the custom resources, provider and component resources are skipped.
+func toTerraformResources(resources []resourceState) ([]tfResource, []tfResourceConf) {
+	values := make([]tfResource, 0, len(resources))
+	conf := make([]tfResourceConf, 0, len(resources))
+	seen := map[string]bool{}
+
+	byURN := make(map[string]resourceState, len(resources))
+	for _, r := range resources {
+		byURN[r.URN] = r
+	}
+
+	for _, r := range resources {
+		if !r.Custom || strings.HasPrefix(r.Type, "pulumi:") {
+			continue
+		}
+
+		tfType := terraformType(r.Type)
+		name := qualifiedName(r, byURN)
+		address := tfType + "." + name
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		props := make(map[string]interface{}, len(r.Inputs)+len(r.Outputs))
+		for k, v := range r.Inputs {
+			props[k] = v
+		}
+		for k, v := range r.Outputs {
+			if s, ok := v.(string); ok && s == unknownValue {
+				continue
+			}
+			props[k] = v
+		}
+
+		attrs := convertProperties(props)
+		if _, ok := attrs["id"]; !ok && r.ID != "" {
+			attrs["id"] = r.ID
+		}
+
+		providerKey, _ := providerConfigKey(r.Provider)
+
+		values = append(values, tfResource{
+			Address:      address,
+			Mode:         "managed",
+			Type:         tfType,
+			Name:         name,
+			ProviderName: strings.Split(providerKey, ".")[0],
+			Values:       attrs,
+		})
+		conf = append(conf, tfResourceConf{
+			Address:           address,
+			Mode:              "managed",
+			Type:              tfType,
+			Name:              name,
+			ProviderConfigKey: providerKey,
+		})
+	}
+
+	return values, conf
+}
+
+// resourceName returns the name of the resource from the URN, e.g.
+// urn:pulumi:dev::app::aws:ec2/instance:Instance::web returns web.
+func resourceName(urn string) string {
+	parts := strings.Split(urn, "::")
+	return parts[len(parts)-1]
+}
+
+// qualifiedName returns the name of the resource prefixed with the names of its
+// parent resources, e.g. a bucket named bucket in a component named frontend
+// returns frontend/bucket. Pulumi names only have to be unique for the same
+// parent, so resources in different components can have the same name.
+func qualifiedName(r resourceState, byURN map[string]resourceState) string {
+	names := []string{invalidNameChars.ReplaceAllString(resourceName(r.URN), "_")}
+
+	parentURN := r.Parent
+	seen := map[string]bool{}
+	for parentURN != "" && !seen[parentURN] {
+		seen[parentURN] = true
+
+		if resourceType(parentURN) == stackType {
+			break
+		}
+
+		names = append([]string{invalidNameChars.ReplaceAllString(resourceName(parentURN), "_")}, names...)
+		parentURN = byURN[parentURN].Parent
+	}
+
+	return strings.Join(names, "/")
+}
+
+// resourceType returns the type of the resource from the URN, e.g.
+// urn:pulumi:dev::app::my:component:Web$aws:ec2/instance:Instance::web returns
+// aws:ec2/instance:Instance.
+func resourceType(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return ""
+	}
+
+	types := strings.Split(parts[2], "$")
+	return types[len(types)-1]
+}
+
+// providerConfigKey returns the Terraform provider config key for a Pulumi
+// provider reference, e.g. urn:pulumi:dev::app::pulumi:providers:aws::east::<id>
+// returns aws.east. Default providers return the provider name, e.g. aws.
+func providerConfigKey(ref string) (string, bool) {
+	parts := strings.Split(ref, "::")
+	if len(parts) < 4 || !strings.HasPrefix(parts[2], providerTypePrefix) {
+		return "", false
+	}
+
+	prefix, ok := packagePrefixes[strings.TrimPrefix(parts[2], providerTypePrefix)]
+	if !ok {
+		return "", false
+	}
+
+	if strings.HasPrefix(parts[3], "default") {
+		return prefix, true
+	}
+
+	return prefix + "." + parts[3], true
+}
+
+// convertProperties converts Pulumi resource properties to Terraform
+// attributes. Property names are converted to snake case, nested objects are
+// converted to single item lists to match Terraform blocks and pluralized list
+// properties are also set under their singular Terraform name.
+func convertProperties(props map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(props))
+
+	for k, v := range props {
+		if strings.HasPrefix(k, "__") {
+			continue
+		}
+
+		key := toSnakeCase(k)
+		val, ok := convertValue(key, v)
+		if !ok {
+			continue
+		}
+
+		out[key] = val
+	}
+
+	for key, val := range out {
+		if _, isList := val.([]interface{}); !isList {
+			continue
+		}
+
+		if s := singular(key); s != key {
+			if _, exists := out[s]; !exists {
+				out[s] = val
+			}
+		}
+	}
+
+	return out
+}
+
+// convertValue converts a single property value. The bool is false if the
+// value is unknown or is a secret that can't be read.
+func convertValue(key string, v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, val != unknownValue
+	case map[string]interface{}:
+		if _, ok := val[secretSig]; ok {
+			plaintext, ok := val["plaintext"].(string)
+			if !ok {
+				return nil, false
+			}
+
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(plaintext), &decoded); err != nil {
+				return nil, false
+			}
+
+			return convertValue(key, decoded)
+		}
+
+		if mapAttributes[key] {
+			m := make(map[string]interface{}, len(val))
+			for k, item := range val {
+				if conv, ok := convertValue("", item); ok {
+					m[k] = conv
+				}
+			}
+
+			return m, true
+		}
+
+		return []interface{}{convertProperties(val)}, true
+	case []interface{}:
+		items := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if m, ok := item.(map[string]interface{}); ok {
+				if _, isSecret := m[secretSig]; !isSecret {
+					items = append(items, convertProperties(m))
+					continue
+				}
+			}
+
+			if conv, ok := convertValue("", item); ok {
+				items = append(items, conv)
+			}
+		}
+
+		return items, true
+	}
+
+	return v, true
+}
@@ -0,0 +1,181 @@
+package pulumi
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newTestParser() *Parser {
+	return NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, map[string]interface{}{}), true)
+}
+
+func partialsByAddress(partials []*schema.PartialResource) map[string]*schema.PartialResource {
+	m := make(map[string]*schema.PartialResource, len(partials))
+	for _, p := range partials {
+		m[p.Address] = p
+	}
+
+	return m
+}
+
+func addresses(partials []*schema.PartialResource) []string {
+	var out []string
+	for _, p := range partials {
+		out = append(out, p.Address)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func TestParsePreviewJSON(t *testing.T) {
+	j, err := os.ReadFile("testdata/preview.json")
+	require.NoError(t, err)
+	require.True(t, IsPreviewJSON(j))
+	require.False(t, IsStateJSON(j))
+
+	parsed, err := newTestParser().parsePreviewJSON(j, schema.UsageMap{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws_db_instance.db", "aws_instance.web"}, addresses(parsed.CurrentResources))
+	assert.Equal(t, []string{"aws_eip.old", "aws_instance.web"}, addresses(parsed.PastResources))
+
+	current := partialsByAddress(parsed.CurrentResources)
+
+	web := current["aws_instance.web"]
+	assert.Equal(t, "aws_instance", web.Type)
+	assert.Equal(t, "m5.large", web.RawValues.Get("instance_type").String())
+	assert.Equal(t, int64(50), web.RawValues.Get("root_block_device.0.volume_size").Int())
+	assert.Equal(t, int64(100), web.RawValues.Get("ebs_block_device.0.volume_size").Int())
+	assert.Equal(t, "us-east-1", web.RawValues.Get("region").String())
+	assert.False(t, web.RawValues.Get("arn").Exists())
+	require.NotNil(t, web.Tags)
+	assert.Equal(t, "123", (*web.Tags)["cost-center"])
+
+	db := current["aws_db_instance.db"]
+	assert.Equal(t, "db.t3.medium", db.RawValues.Get("instance_class").String())
+	assert.Equal(t, "eu-west-1", db.RawValues.Get("region").String())
+	assert.False(t, db.RawValues.Get("password").Exists())
+
+	past := partialsByAddress(parsed.PastResources)
+	assert.Equal(t, "t3.micro", past["aws_instance.web"].RawValues.Get("instance_type").String())
+}
+
+func TestParseStateJSON(t *testing.T) {
+	j, err := os.ReadFile("testdata/stack_export.json")
+	require.NoError(t, err)
+	require.True(t, IsStateJSON(j))
+	require.False(t, IsPreviewJSON(j))
+
+	parsed, err := newTestParser().parseStateJSON(j, schema.UsageMap{})
+	require.NoError(t, err)
+
+	assert.Empty(t, parsed.PastResources)
+	assert.Equal(t, []string{"google_compute_instance.vm", "google_storage_bucket.assets"}, addresses(parsed.CurrentResources))
+
+	vm := partialsByAddress(parsed.CurrentResources)["google_compute_instance.vm"]
+	assert.Equal(t, "e2-standard-2", vm.RawValues.Get("machine_type").String())
+	assert.Equal(t, "pd-balanced", vm.RawValues.Get("boot_disk.0.initialize_params.0.type").String())
+	assert.Equal(t, "projects/my-project/zones/europe-west1-b/instances/vm", vm.RawValues.Get("id").String())
+	assert.Equal(t, "europe-west1", vm.RawValues.Get("region").String())
+}
+
+func TestParseStateJSONComponentsWithSameChildName(t *testing.T) {
+	j := []byte(`{
+  "version": 3,
+  "deployment": {
+    "resources": [
+      {"urn": "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev", "type": "pulumi:pulumi:Stack"},
+      {
+        "urn": "urn:pulumi:dev::app::my:web:Site::frontend",
+        "type": "my:web:Site",
+        "parent": "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev"
+      },
+      {
+        "urn": "urn:pulumi:dev::app::my:web:Site::docs",
+        "type": "my:web:Site",
+        "parent": "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev"
+      },
+      {
+        "urn": "urn:pulumi:dev::app::my:web:Site$aws:s3/bucket:Bucket::bucket",
+        "custom": true,
+        "type": "aws:s3/bucket:Bucket",
+        "inputs": {"bucket": "frontend"},
+        "parent": "urn:pulumi:dev::app::my:web:Site::frontend"
+      },
+      {
+        "urn": "urn:pulumi:dev::app::my:web:Site$aws:s3/bucket:Bucket::bucket",
+        "custom": true,
+        "type": "aws:s3/bucket:Bucket",
+        "inputs": {"bucket": "docs"},
+        "parent": "urn:pulumi:dev::app::my:web:Site::docs"
+      },
+      {
+        "urn": "urn:pulumi:dev::app::aws:s3/bucket:Bucket::bucket",
+        "custom": true,
+        "type": "aws:s3/bucket:Bucket",
+        "inputs": {"bucket": "root"},
+        "parent": "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev"
+      }
+    ]
+  }
+}`)
+
+	parsed, err := newTestParser().parseStateJSON(j, schema.UsageMap{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws_s3_bucket.bucket", "aws_s3_bucket.docs/bucket", "aws_s3_bucket.frontend/bucket"}, addresses(parsed.CurrentResources))
+
+	current := partialsByAddress(parsed.CurrentResources)
+	assert.Equal(t, "frontend", current["aws_s3_bucket.frontend/bucket"].RawValues.Get("bucket").String())
+	assert.Equal(t, "docs", current["aws_s3_bucket.docs/bucket"].RawValues.Get("bucket").String())
+	assert.Equal(t, "root", current["aws_s3_bucket.bucket"].RawValues.Get("bucket").String())
+}
+
+func TestTerraformType(t *testing.T) {
+	tests := []struct {
+		pulumiType string
+		expected   string
+	}{
+		{"aws:ec2/instance:Instance", "aws_instance"},
+		{"aws:ec2/natGateway:NatGateway", "aws_nat_gateway"},
+		{"aws:s3/bucket:Bucket", "aws_s3_bucket"},
+		{"aws:lambda/function:Function", "aws_lambda_function"},
+		{"aws:rds/instance:Instance", "aws_db_instance"},
+		{"aws:rds/cluster:Cluster", "aws_rds_cluster"},
+		{"aws:lb/loadBalancer:LoadBalancer", "aws_lb"},
+		{"gcp:compute/instance:Instance", "google_compute_instance"},
+		{"gcp:sql/databaseInstance:DatabaseInstance", "google_sql_database_instance"},
+		{"azure:compute/linuxVirtualMachine:LinuxVirtualMachine", "azurerm_linux_virtual_machine"},
+		{"azure:storage/account:Account", "azurerm_storage_account"},
+		{"aws:ec2/notARealThing:NotARealThing", "aws:ec2/notARealThing:NotARealThing"},
+		{"kubernetes:core/v1:Pod", "kubernetes:core/v1:Pod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pulumiType, func(t *testing.T) {
+			assert.Equal(t, tt.expected, terraformType(tt.pulumiType))
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"instanceType":     "instance_type",
+		"ipv6AddressCount": "ipv6_address_count",
+		"NatGateway":       "nat_gateway",
+		"VPCEndpoint":      "vpc_endpoint",
+		"id":               "id",
+	}
+
+	for in, expected := range tests {
+		assert.Equal(t, expected, toSnakeCase(in), in)
+	}
+}
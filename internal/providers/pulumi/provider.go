@@ -0,0 +1,135 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	typePreviewJSON = "pulumi_preview_json"
+	typeStateJSON   = "pulumi_state_json"
+)
+
+// JSONProvider loads resources from the output of `pulumi preview --json` or
+// `pulumi stack export`.
+type JSONProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+	projectType          string
+}
+
+// NewPreviewJSONProvider returns a provider for the output of `pulumi preview --json`.
+func NewPreviewJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &JSONProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+		projectType:          typePreviewJSON,
+	}
+}
+
+// NewStateJSONProvider returns a provider for the output of `pulumi stack export`.
+func NewStateJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &JSONProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+		projectType:          typeStateJSON,
+	}
+}
+
+func (p *JSONProvider) ProjectName() string {
+	return config.CleanProjectName(p.ctx.ProjectConfig.Path)
+}
+
+func (p *JSONProvider) VarFiles() []string {
+	return nil
+}
+
+func (p *JSONProvider) RelativePath() string {
+	return p.ctx.ProjectConfig.Path
+}
+
+func (p *JSONProvider) Context() *config.ProjectContext { return p.ctx }
+
+func (p *JSONProvider) Type() string {
+	return p.projectType
+}
+
+func (p *JSONProvider) DisplayType() string {
+	if p.projectType == typeStateJSON {
+		return "Pulumi stack export file"
+	}
+
+	return "Pulumi preview JSON file"
+}
+
+func (p *JSONProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *JSONProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	j, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrapf(err, "Error reading %s", p.DisplayType())
+	}
+
+	logging.Logger.Debug().Msg("Extracting only cost-related params from pulumi")
+
+	metadata := schema.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+	parser := NewParser(p.ctx, p.includePastResources)
+
+	var parsedConf *terraform.ParsedPlanConfiguration
+	if p.projectType == typeStateJSON {
+		parsedConf, err = parser.parseStateJSON(j, usage)
+	} else {
+		parsedConf, err = parser.parsePreviewJSON(j, usage)
+	}
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrapf(err, "Error parsing %s", p.DisplayType())
+	}
+
+	project.AddProviderMetadata(parsedConf.ProviderMetadata)
+
+	project.PartialPastResources = parsedConf.PastResources
+	project.PartialResources = parsedConf.CurrentResources
+
+	return []*schema.Project{project}, nil
+}
+
+// IsPreviewJSON returns true if the JSON is the output of `pulumi preview --json`.
+func IsPreviewJSON(j []byte) bool {
+	var preview previewJSON
+	if err := json.Unmarshal(j, &preview); err != nil || len(preview.Steps) == 0 {
+		return false
+	}
+
+	return strings.HasPrefix(preview.Steps[0].URN, "urn:pulumi:")
+}
+
+// IsStateJSON returns true if the JSON is the output of `pulumi stack export`.
+func IsStateJSON(j []byte) bool {
+	var state stateJSON
+	if err := json.Unmarshal(j, &state); err != nil || state.Version == 0 || len(state.Deployment.Resources) == 0 {
+		return false
+	}
+
+	return strings.HasPrefix(state.Deployment.Resources[0].URN, "urn:pulumi:")
+}
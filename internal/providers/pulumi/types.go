@@ -0,0 +1,107 @@
+package pulumi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// packagePrefixes maps the Pulumi package name to the prefix of the Terraform
+// provider that the Pulumi package bridges.
+var packagePrefixes = map[string]string{
+	"aws":   "aws",
+	"azure": "azurerm",
+	"gcp":   "google",
+}
+
+// typeOverrides are Pulumi types whose Terraform resource type can't be worked
+// out from the Pulumi module and type name.
+var typeOverrides = map[string]string{
+	"aws:alb/loadBalancer:LoadBalancer":                   "aws_alb",
+	"aws:apigateway/restApi:RestApi":                      "aws_api_gateway_rest_api",
+	"aws:apigateway/stage:Stage":                          "aws_api_gateway_stage",
+	"aws:ec2transitgateway/transitGateway:TransitGateway": "aws_ec2_transit_gateway",
+	"aws:ec2transitgateway/vpcAttachment:VpcAttachment":   "aws_ec2_transit_gateway_vpc_attachment",
+	"aws:elb/loadBalancer:LoadBalancer":                   "aws_elb",
+	"aws:lb/loadBalancer:LoadBalancer":                    "aws_lb",
+	"aws:rds/instance:Instance":                           "aws_db_instance",
+	"aws:s3/bucketV2:BucketV2":                            "aws_s3_bucket",
+}
+
+// terraformType returns the Terraform resource type for a Pulumi type token,
+// e.g. aws:ec2/instance:Instance returns aws_instance. The Terraform type is
+// found by checking the Terraform resource registry for the type with and
+// without the Pulumi module, e.g. aws:s3/bucket:Bucket maps to aws_s3_bucket.
+// If the type can't be mapped the Pulumi type is returned so that it is
+// shown as unsupported.
+func terraformType(pulumiType string) string {
+	if t, ok := typeOverrides[pulumiType]; ok {
+		return t
+	}
+
+	parts := strings.Split(pulumiType, ":")
+	if len(parts) != 3 {
+		return pulumiType
+	}
+
+	prefix, ok := packagePrefixes[parts[0]]
+	if !ok {
+		return pulumiType
+	}
+
+	module := strings.Split(parts[1], "/")[0]
+	name := toSnakeCase(parts[2])
+
+	candidates := []string{prefix + "_" + name}
+	if module != "" && module != "index" {
+		candidates = append([]string{prefix + "_" + strings.ToLower(module) + "_" + name}, candidates...)
+	}
+
+	for _, c := range candidates {
+		if _, ok := (*terraform.ResourceRegistryMap)[c]; ok {
+			return c
+		}
+	}
+
+	return pulumiType
+}
+
+// toSnakeCase converts a Pulumi camel case property or type name to the
+// Terraform snake case name, e.g. rootBlockDevice returns root_block_device.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// singular returns the Terraform name of a pluralized Pulumi list property,
+// e.g. ebs_block_devices returns ebs_block_device.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+
+	return s
+}
@@ -55,6 +55,11 @@ type HCLProviderConfig struct {
 	SuppressLogging     bool
 	CacheParsingModules bool
 	SkipAutoDetection   bool
+	// ProviderRegions are the regions of providers that are configured outside
+	// of the module, keyed by the provider name, e.g. the providers passed to
+	// a Terraform Stacks component. They are used for providers that aren't
+	// defined in the root module.
+	ProviderRegions map[string]string
 }
 
 type flagStringSlice []string
@@ -122,6 +127,14 @@ func NewHCLProvider(ctx *config.ProjectContext, rootPath hcl.RootPath, config *H
 		options = append(options, withInputVars)
 	}
 
+	dataSources, err := projectDataSources(ctx, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataSources) > 0 {
+		options = append(options, hcl.OptionWithDataSources(dataSources))
+	}
+
 	options = append(options, opts...)
 
 	credsSource, err := modules.NewTerraformCredentialsSource(modules.BaseCredentialSet{
@@ -163,27 +176,7 @@ func NewHCLProvider(ctx *config.ProjectContext, rootPath hcl.RootPath, config *H
 		}
 	}
 
-	var remoteCache modules.RemoteCache
-	if runCtx.Config.S3ModuleCacheRegion != "" && runCtx.Config.S3ModuleCacheBucket != "" {
-		s3ModuleCache, err := modules.NewS3Cache(runCtx.Config.S3ModuleCacheRegion, runCtx.Config.S3ModuleCacheBucket, runCtx.Config.S3ModuleCachePrefix, runCtx.Config.S3ModuleCachePrivate)
-		if err != nil {
-			logger.Warn().Msgf("failed to initialize S3 module cache: %s", err)
-		} else {
-			remoteCache = s3ModuleCache
-		}
-	}
-
-	loader := modules.NewModuleLoader(modules.ModuleLoaderOptions{
-		CachePath:           runCtx.Config.CachePath(),
-		HCLParser:           modules.NewSharedHCLParser(),
-		CredentialsSource:   credsSource,
-		SourceMap:           runCtx.Config.TerraformSourceMap,
-		SourceMapRegex:      runCtx.Config.TerraformSourceMapRegex,
-		Logger:              logger,
-		ModuleSync:          runCtx.ModuleMutex,
-		RemoteCache:         remoteCache,
-		PublicModuleChecker: modules.NewHttpPublicModuleChecker(),
-	})
+	loader := newModuleLoader(runCtx, credsSource, logger)
 	cachePath := ctx.RunContext.Config.CachePath()
 	initialPath := rootPath.DetectedPath
 	rootPath.DetectedPath = initialPath
@@ -212,6 +205,61 @@ func NewHCLProvider(ctx *config.ProjectContext, rootPath hcl.RootPath, config *H
 		logger:         logger,
 	}, nil
 }
+
+// newModuleLoader returns a module loader that downloads modules to the cache
+// path of the run, using the S3 module cache if one is configured.
+func newModuleLoader(runCtx *config.RunContext, credsSource *modules.CredentialsSource, logger zerolog.Logger) *modules.ModuleLoader {
+	var remoteCache modules.RemoteCache
+	if runCtx.Config.S3ModuleCacheRegion != "" && runCtx.Config.S3ModuleCacheBucket != "" {
+		s3ModuleCache, err := modules.NewS3Cache(runCtx.Config.S3ModuleCacheRegion, runCtx.Config.S3ModuleCacheBucket, runCtx.Config.S3ModuleCachePrefix, runCtx.Config.S3ModuleCachePrivate)
+		if err != nil {
+			logger.Warn().Msgf("failed to initialize S3 module cache: %s", err)
+		} else {
+			remoteCache = s3ModuleCache
+		}
+	}
+
+	return modules.NewModuleLoader(modules.ModuleLoaderOptions{
+		CachePath:           runCtx.Config.CachePath(),
+		HCLParser:           modules.NewSharedHCLParser(),
+		CredentialsSource:   credsSource,
+		SourceMap:           runCtx.Config.TerraformSourceMap,
+		SourceMapRegex:      runCtx.Config.TerraformSourceMapRegex,
+		Logger:              logger,
+		ModuleSync:          runCtx.ModuleMutex,
+		RemoteCache:         remoteCache,
+		PublicModuleChecker: modules.NewHttpPublicModuleChecker(),
+	})
+}
+
+// projectDataSources returns the data sources for the project in order of
+// precedence: the project data sources, the data sources file and then the
+// top level data sources in the config file.
+func projectDataSources(ctx *config.ProjectContext, rootPath hcl.RootPath) ([]config.DataSource, error) {
+	dataSources := append([]config.DataSource{}, ctx.ProjectConfig.DataSources...)
+
+	var path string
+	if ctx.ProjectConfig.DataSourcesFile != "" {
+		path = ctx.ProjectConfig.DataSourcesFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ctx.ProjectConfig.Path, path)
+		}
+	} else if p := filepath.Join(rootPath.DetectedPath, config.DataSourcesFileName); config.FileExists(p) {
+		path = p
+	}
+
+	if path != "" {
+		fileDataSources, err := config.LoadDataSourcesFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		dataSources = append(dataSources, fileDataSources...)
+	}
+
+	return append(dataSources, ctx.RunContext.Config.DataSources...), nil
+}
+
 func (p *HCLProvider) Context() *config.ProjectContext { return p.ctx }
 
 func (p *HCLProvider) ProjectName() string {
@@ -295,6 +343,10 @@ func (p *HCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, e
 	project.AddProviderMetadata(parsedConf.ProviderMetadata)
 	project.Metadata.RemoteModuleCalls = parsedConf.RemoteModuleCalls
 
+	if addressChanges := j.Module.AddressChanges(); !addressChanges.IsEmpty() {
+		project.Metadata.AddressChanges = addressChanges
+	}
+
 	project.PartialPastResources = parsedConf.PastResources
 	project.PartialResources = parsedConf.CurrentResources
 
@@ -421,6 +473,22 @@ func (p *HCLProvider) InvalidateCache() *HCLProvider {
 	return p
 }
 
+// Reload removes the module cache along with the files parsed by the Parser,
+// so that the next parse reads the files that have changed since.
+func (p *HCLProvider) Reload() *HCLProvider {
+	p.Parser.ClearParsedFiles()
+
+	return p.InvalidateCache()
+}
+
+// WithModuleCache caches the module parsed by Module so that it is reused by
+// LoadResources, until InvalidateCache or Reload is called.
+func (p *HCLProvider) WithModuleCache() *HCLProvider {
+	p.config.CacheParsingModules = true
+
+	return p
+}
+
 func (p *HCLProvider) newPlanSchema() {
 	p.schema = &PlanSchema{
 		FormatVersion:    "1.0",
@@ -460,6 +528,7 @@ func (p *HCLProvider) modulesToPlanJSON(rootModule *hcl.Module) ([]byte, error)
 	}
 
 	mo := p.marshalModule(rootModule)
+	p.addExternalProviders()
 	p.schema.Configuration.RootModule = mo.ModuleConfig
 	p.schema.PriorState.Values.RootModule = mo.PlanModule
 	p.schema.PlannedValues.RootModule = mo.PlanModule
@@ -663,6 +732,27 @@ func normalizeModuleURL(sshURL string) (string, error) {
 	return res.String(), nil
 }
 
+// addExternalProviders adds the providers from the ProviderRegions config
+// that aren't defined in the root module, so resources that use them get the
+// region of the provider.
+func (p *HCLProvider) addExternalProviders() {
+	for name, region := range p.config.ProviderRegions {
+		if _, ok := p.schema.Configuration.ProviderConfig[name]; ok {
+			continue
+		}
+
+		p.schema.Configuration.ProviderConfig[name] = ProviderConfig{
+			Name: name,
+			Expressions: map[string]interface{}{
+				"region": map[string]interface{}{
+					"constant_value": region,
+				},
+			},
+			InfracostMetadata: map[string]interface{}{},
+		}
+	}
+}
+
 func (p *HCLProvider) marshalProviderBlock(block *hcl.Block) string {
 	providerConfigKey := block.Values().GetAttr("config_key").AsString()
 
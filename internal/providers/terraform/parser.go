@@ -88,14 +88,25 @@ func (p *Parser) createParsedResource(d *schema.ResourceData, u *schema.UsageDat
 				}
 			}
 		} else {
-			res := registryItem.RFunc(d, u)
-			if res != nil {
-				if u != nil {
+			build := func(u *schema.UsageData) *schema.Resource {
+				res := registryItem.RFunc(d, u)
+				if res != nil && u != nil {
 					res.EstimationSummary = u.CalcEstimationSummary()
+					res.Usage = u
+				}
+
+				return res
+			}
+
+			res := build(u)
+			if res != nil {
+				partial := schema.NewPartialResource(d, res, nil, registryItem.CloudResourceIDFunc(d))
+				if p.ctx.RunContext.Config.ForecastMonths > 0 {
+					partial.BuildWithUsage = build
 				}
 
 				return parsedResource{
-					PartialResource: schema.NewPartialResource(d, res, nil, registryItem.CloudResourceIDFunc(d)),
+					PartialResource: partial,
 					ResourceData:    d,
 				}
 			}
@@ -209,6 +220,13 @@ func newParsedPlanConfiguration(pastResources, currentResources []parsedResource
 	return &ppc
 }
 
+// ParseJSON parses Terraform plan or state JSON into resources. It is used by
+// providers for other IaC tools that translate their resources into the
+// Terraform JSON format so that the Terraform resource registry can be reused.
+func (p *Parser) ParseJSON(j []byte, usage schema.UsageMap) (*ParsedPlanConfiguration, error) {
+	return p.parseJSON(j, usage)
+}
+
 func (p *Parser) parseJSON(j []byte, usage schema.UsageMap) (*ParsedPlanConfiguration, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
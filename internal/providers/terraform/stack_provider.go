@@ -0,0 +1,284 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/metrics"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// StackProvider loads a Terraform Stacks configuration. Each component of each
+// deployment in the stack is reported as its own project, which is loaded by
+// parsing the component's module with a HCLProvider using the inputs of the
+// component evaluated for the deployment.
+type StackProvider struct {
+	ctx    *config.ProjectContext
+	Path   hcl.RootPath
+	logger zerolog.Logger
+}
+
+// NewStackProvider returns a provider for the Terraform Stacks configuration
+// at the root path.
+func NewStackProvider(rootPath hcl.RootPath, ctx *config.ProjectContext) schema.Provider {
+	logger := ctx.Logger().With().Str(
+		"provider", "terraform_stack",
+	).Logger()
+
+	return &StackProvider{
+		ctx:    ctx,
+		Path:   rootPath,
+		logger: logger,
+	}
+}
+
+func (p *StackProvider) Context() *config.ProjectContext { return p.ctx }
+
+func (p *StackProvider) ProjectName() string {
+	if p.ctx.ProjectConfig.Name != "" {
+		return p.ctx.ProjectConfig.Name
+	}
+
+	return config.CleanProjectName(p.RelativePath())
+}
+
+func (p *StackProvider) EnvName() string {
+	return ""
+}
+
+func (p *StackProvider) RelativePath() string {
+	r, err := filepath.Rel(p.Path.StartingPath, p.Path.DetectedPath)
+	if err != nil {
+		return p.Path.DetectedPath
+	}
+
+	return r
+}
+
+func (p *StackProvider) VarFiles() []string {
+	return nil
+}
+
+func (p *StackProvider) DependencyPaths() []string {
+	return nil
+}
+
+func (p *StackProvider) YAML() string {
+	str := strings.Builder{}
+
+	str.WriteString(fmt.Sprintf("  - path: %s\n    name: %s\n", p.RelativePath(), p.ProjectName()))
+
+	return str.String()
+}
+
+func (p *StackProvider) Type() string {
+	return "terraform_stack"
+}
+
+func (p *StackProvider) DisplayType() string {
+	return "Terraform Stack"
+}
+
+func (p *StackProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+
+	modulePath := p.RelativePath()
+	if modulePath != "" && modulePath != "." {
+		metadata.TerraformModulePath = modulePath
+	}
+
+	metadata.TerraformWorkspace = p.ctx.ProjectConfig.TerraformWorkspace
+}
+
+// LoadResources loads the stack and returns a project for each component of
+// each of its deployments.
+func (p *StackProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	loadResourcesTimer := metrics.GetTimer("stack.LoadResources", false, p.ctx.ProjectConfig.Path).Start()
+	defer loadResourcesTimer.Stop()
+
+	stack, err := hcl.LoadStack(p.Path.DetectedPath, p.logger)
+	if err != nil {
+		return []*schema.Project{p.newErroredProject(hcl.StackComponentInstance{}, err)}, nil
+	}
+
+	instances := stack.Instances()
+	if len(instances) == 0 {
+		return []*schema.Project{}, nil
+	}
+
+	runCtx := p.ctx.RunContext
+	parallelism, _ := runCtx.GetParallelism()
+	if len(instances) < parallelism {
+		parallelism = len(instances)
+	}
+
+	ch := make(chan hcl.StackComponentInstance, len(instances))
+	for _, instance := range instances {
+		ch <- instance
+	}
+	close(ch)
+
+	var allProjects []*schema.Project
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	wg.Add(parallelism)
+
+	for i := 0; i < parallelism; i++ {
+		go func() {
+			defer wg.Done()
+
+			for instance := range ch {
+				projects := p.loadInstance(instance, usage)
+
+				mu.Lock()
+				allProjects = append(allProjects, projects...)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	sort.Slice(allProjects, func(i, j int) bool {
+		return allProjects[i].Name < allProjects[j].Name
+	})
+
+	return allProjects, nil
+}
+
+// loadInstance loads the source of the component and parses it with a
+// HCLProvider, passing the component inputs as input vars and the regions of
+// the providers passed to the component.
+func (p *StackProvider) loadInstance(instance hcl.StackComponentInstance, usage schema.UsageMap) []*schema.Project {
+	dir, err := p.componentPath(instance)
+	if err != nil {
+		return []*schema.Project{p.newErroredProject(instance, err)}
+	}
+
+	pconfig := *p.ctx.ProjectConfig // clone the projectConfig
+	pconfig.Path = dir
+	pconfig.TerraformVarFiles = nil
+
+	logCtx := p.logger.With().
+		Str("parent_provider", "terraform_stack").
+		Str("deployment", instance.Deployment).
+		Str("component", instance.Component).
+		Ctx(context.Background())
+
+	h, err := NewHCLProvider(
+		config.NewProjectContext(p.ctx.RunContext, &pconfig, logCtx),
+		hcl.RootPath{
+			StartingPath: dir,
+			DetectedPath: dir,
+		},
+		&HCLProviderConfig{CacheParsingModules: true, SkipAutoDetection: true, ProviderRegions: instance.ProviderRegions},
+		hcl.OptionWithRawCtyInput(instance.Inputs),
+	)
+	if err != nil {
+		return []*schema.Project{p.newErroredProject(instance, fmt.Errorf("failed to evaluate stack component %s: %w", instance.Component, err))}
+	}
+
+	// HCLProvider.LoadResources never returns an error.
+	projects, _ := h.LoadResources(usage)
+	for _, project := range projects {
+		metadata := p.newProjectMetadata(instance)
+		project.Metadata.Path = metadata.Path
+		project.Metadata.VCSSubPath = metadata.VCSSubPath
+		project.Metadata.Type = metadata.Type
+		project.Metadata.TerraformModulePath = metadata.TerraformModulePath
+		project.Metadata.StackDeployment = metadata.StackDeployment
+		project.Metadata.StackComponent = metadata.StackComponent
+
+		project.Name = p.generateProjectName(project.Metadata, instance)
+		project.DisplayName = p.ProjectName() + instanceNameSuffix(instance)
+	}
+
+	return projects
+}
+
+// componentPath returns the directory of the component's module, downloading
+// it with the module loader if it has a remote source.
+func (p *StackProvider) componentPath(instance hcl.StackComponentInstance) (string, error) {
+	if instance.Source == "" {
+		return "", fmt.Errorf("stack component %s has no source", instance.Component)
+	}
+
+	if modules.IsLocalModule(instance.Source) {
+		return filepath.Join(p.Path.DetectedPath, instance.Source), nil
+	}
+
+	credsSource, err := modules.NewTerraformCredentialsSource(modules.BaseCredentialSet{
+		Token: p.ctx.ProjectConfig.TerraformCloudToken,
+		Host:  p.ctx.ProjectConfig.TerraformCloudHost,
+	})
+	if err != nil {
+		p.logger.Debug().Err(err).Msg("could not load Terraform credentials for stack component source")
+	}
+
+	loader := newModuleLoader(p.ctx.RunContext, credsSource, p.logger)
+	dir, err := loader.LoadSource("stack."+instance.Component, instance.Source, instance.Version, p.Path.DetectedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load source %s of stack component %s: %w", instance.Source, instance.Component, err)
+	}
+
+	return dir, nil
+}
+
+func (p *StackProvider) newErroredProject(instance hcl.StackComponentInstance, err error) *schema.Project {
+	metadata := p.newProjectMetadata(instance)
+	metadata.AddError(schema.NewDiagModuleEvaluationFailure(err))
+
+	project := schema.NewProject(p.generateProjectName(metadata, instance), metadata)
+	project.DisplayName = p.ProjectName() + instanceNameSuffix(instance)
+
+	return project
+}
+
+func (p *StackProvider) newProjectMetadata(instance hcl.StackComponentInstance) *schema.ProjectMetadata {
+	projectPath := p.Path.DetectedPath
+	// attempt to convert project path to be relative to the top level provider path
+	if absPath, err := filepath.Abs(p.ctx.ProjectConfig.Path); err == nil {
+		if relProjectPath, err := filepath.Rel(absPath, projectPath); err == nil {
+			projectPath = filepath.Join(p.ctx.ProjectConfig.Path, relProjectPath)
+		}
+	}
+
+	metadata := schema.DetectProjectMetadata(projectPath)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	metadata.StackDeployment = instance.Deployment
+	metadata.StackComponent = instance.Component
+
+	return metadata
+}
+
+func (p *StackProvider) generateProjectName(metadata *schema.ProjectMetadata, instance hcl.StackComponentInstance) string {
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	return name + instanceNameSuffix(instance)
+}
+
+// instanceNameSuffix is appended to the project names of a stack so that each
+// deployment and component has a unique name, e.g. -production-vpc.
+func instanceNameSuffix(instance hcl.StackComponentInstance) string {
+	suffix := ""
+	if instance.Deployment != "" {
+		suffix += "-" + instance.Deployment
+	}
+	if instance.Component != "" {
+		suffix += "-" + instance.Component
+	}
+
+	return suffix
+}
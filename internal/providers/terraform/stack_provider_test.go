@@ -0,0 +1,80 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestStackProviderDeploymentRegions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"components.tfstack.hcl": `
+variable "region" {
+  type = string
+}
+
+provider "aws" "this" {
+  config {
+    region = var.region
+  }
+}
+
+component "web" {
+  source = "./modules/web"
+
+  providers = {
+    aws = provider.aws.this
+  }
+}
+`,
+		"deployments.tfdeploy.hcl": `
+deployment "development" {
+  inputs = {
+    region = "us-east-1"
+  }
+}
+
+deployment "production" {
+  inputs = {
+    region = "eu-west-1"
+  }
+}
+`,
+		"modules/web/main.tf": `
+resource "aws_instance" "web" {
+  ami           = "ami-674cbc1e"
+  instance_type = "m5.large"
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: dir}, logrus.Fields{})
+	p := NewStackProvider(hcl.RootPath{StartingPath: dir, DetectedPath: dir}, ctx)
+
+	projects, err := p.LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	regions := map[string]string{}
+	for _, project := range projects {
+		require.Len(t, project.PartialResources, 1)
+		r := schema.BuildResource(project.PartialResources[0], nil)
+		regions[project.Metadata.StackDeployment] = *r.CostComponents[0].ProductFilter.Region
+	}
+
+	assert.Equal(t, map[string]string{"development": "us-east-1", "production": "eu-west-1"}, regions)
+}
@@ -673,6 +673,7 @@ func (p *TerragruntHCLProvider) runTerragrunt(opts *tgoptions.TerragruntOptions)
 		config.NewProjectContext(p.ctx.RunContext, &pconfig, logCtx),
 		hcl.RootPath{
 			DetectedPath: pconfig.Path,
+			IsOpenTofu:   hcl.HasOpenTofuFiles(pconfig.Path),
 		},
 		&HCLProviderConfig{CacheParsingModules: true, SkipAutoDetection: true},
 		ops...,
@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"strings"
+)
+
+const (
+	// TrackingChangeMoved marks diff resources that were moved to a new address
+	// with a moved block, so their past and current costs are compared.
+	TrackingChangeMoved = "moved"
+	// TrackingChangeImported marks diff resources that already exist and are
+	// brought under management with an import block.
+	TrackingChangeImported = "imported"
+	// TrackingChangeRemoved marks diff resources that are removed from the
+	// state with a removed block but are not destroyed.
+	TrackingChangeRemoved = "removed"
+)
+
+// AddressChanges are the changes to resource addresses in a Terraform project
+// from its moved, import and removed blocks. Addresses can be resources or
+// modules and are relative to the root module.
+type AddressChanges struct {
+	Moved    []MovedAddress `json:"moved,omitempty"`
+	Imported []string       `json:"imported,omitempty"`
+	// Removed are the addresses of removed blocks that don't destroy the
+	// resources, i.e. they have lifecycle { destroy = false }.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// MovedAddress is a moved block.
+type MovedAddress struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IsEmpty returns true if there are no address changes.
+func (c *AddressChanges) IsEmpty() bool {
+	return c == nil || (len(c.Moved) == 0 && len(c.Imported) == 0 && len(c.Removed) == 0)
+}
+
+// previousAddresses returns the addresses the resource had before it was moved,
+// most recent first, by following the moved blocks backwards.
+func (c *AddressChanges) previousAddresses(address string) []string {
+	if c == nil {
+		return nil
+	}
+
+	var addresses []string
+	seen := map[string]bool{address: true}
+
+	for current := address; ; {
+		previous := ""
+		for _, m := range c.Moved {
+			if rest, ok := addressSuffix(current, m.To); ok {
+				previous = m.From + rest
+				break
+			}
+		}
+
+		if previous == "" || seen[previous] {
+			return addresses
+		}
+
+		seen[previous] = true
+		addresses = append(addresses, previous)
+		current = previous
+	}
+}
+
+// isImported returns true if the resource is the target of an import block.
+func (c *AddressChanges) isImported(address string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, a := range c.Imported {
+		if _, ok := addressSuffix(address, a); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRemoved returns true if the resource is removed from the state without
+// being destroyed.
+func (c *AddressChanges) isRemoved(address string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, a := range c.Removed {
+		if _, ok := addressSuffix(address, a); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addressSuffix returns the part of the address after prefix if the address
+// is the prefix, an instance of it or inside it, e.g. the address
+// module.web[0].aws_instance.this has the suffix [0].aws_instance.this for the
+// prefix module.web.
+func addressSuffix(address, prefix string) (string, bool) {
+	if address == prefix {
+		return "", true
+	}
+
+	if !strings.HasPrefix(address, prefix) {
+		return "", false
+	}
+
+	rest := address[len(prefix):]
+	if strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "[") {
+		return rest, true
+	}
+
+	return "", false
+}
+
+// applyMoves returns the past resources with the resources that were moved
+// renamed to their current address, and a map of the current addresses to
+// the past addresses of the resources that were renamed.
+func (c *AddressChanges) applyMoves(past []*Resource, current []*Resource) ([]*Resource, map[string]string) {
+	movedFrom := map[string]string{}
+	if c == nil || len(c.Moved) == 0 {
+		return past, movedFrom
+	}
+
+	pastByName := make(map[string]int, len(past))
+	for i, r := range past {
+		pastByName[r.Name] = i
+	}
+
+	currentNames := make(map[string]bool, len(current))
+	for _, r := range current {
+		currentNames[r.Name] = true
+	}
+
+	renamed := append([]*Resource{}, past...)
+	for _, r := range current {
+		if _, ok := pastByName[r.Name]; ok {
+			continue
+		}
+
+		for _, previous := range c.previousAddresses(r.Name) {
+			i, ok := pastByName[previous]
+			if !ok || currentNames[previous] {
+				continue
+			}
+
+			moved := *renamed[i]
+			moved.Name = r.Name
+			renamed[i] = &moved
+			movedFrom[r.Name] = previous
+			delete(pastByName, previous)
+			break
+		}
+	}
+
+	return renamed, movedFrom
+}
@@ -75,6 +75,16 @@ type PartialResource struct {
 	// CloudResourceIDs are collected during parsing in case they need to be uploaded to the
 	// Cloud Usage API to be used in the usage estimate calculations.
 	CloudResourceIDs []string
+
+	// RawValues are the attribute values of the resource. These are kept so that
+	// local policies can be evaluated once the resource costs are known.
+	RawValues gjson.Result
+
+	// BuildWithUsage builds the Resource again with different usage data, for
+	// provider resource builders that have not been converted to build
+	// CoreResource's. It is only set when costs are forecast, so that the
+	// ResourceData can be garbage collected otherwise.
+	BuildWithUsage func(u *UsageData) *Resource
 }
 
 func NewPartialResource(d *ResourceData, r *Resource, cr CoreResource, cloudResourceIds []string) *PartialResource {
@@ -88,6 +98,7 @@ func NewPartialResource(d *ResourceData, r *Resource, cr CoreResource, cloudReso
 		TagPropagation:                          d.TagPropagation,
 		UsageData:                               d.UsageData,
 		Metadata:                                d.Metadata,
+		RawValues:                               d.RawValues,
 		CoreResource:                            cr,
 		Resource:                                r,
 		CloudResourceIDs:                        cloudResourceIds,
@@ -106,6 +117,9 @@ func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource
 
 		partial.CoreResource.PopulateUsage(u)
 		res = partial.CoreResource.BuildResource()
+		if res != nil {
+			res.Usage = u
+		}
 	} else {
 		res = partial.Resource
 	}
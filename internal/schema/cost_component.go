@@ -21,10 +21,22 @@ type CostComponent struct {
 	price                decimal.Decimal
 	customPrice          *decimal.Decimal
 	priceHash            string
+	sku                  string
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
 	UsageBased           bool
 	PriceNotFound        bool
+	// Commitment is the name of the Reserved Instance, Savings Plan or Committed Use
+	// Discount that covers some or all of the usage of this cost component.
+	Commitment            string
+	commitmentCoverage    decimal.Decimal
+	commitmentPrice       decimal.Decimal
+	CommitmentHourlyCost  *decimal.Decimal
+	CommitmentMonthlyCost *decimal.Decimal
+	// PriceOverride is the name of the price override rule that changed the
+	// price of this cost component from the list price.
+	PriceOverride string
+	listPrice     *decimal.Decimal
 }
 
 func (c *CostComponent) CalculateCosts() {
@@ -36,6 +48,29 @@ func (c *CostComponent) CalculateCosts() {
 		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
 		c.MonthlyCost = decimalPtr(c.price.Mul(*c.MonthlyQuantity).Mul(discountMul))
 	}
+
+	if c.commitmentCoverage.IsPositive() {
+		c.calculateCommitmentCosts()
+	}
+}
+
+// calculateCommitmentCosts calculates the costs of the cost component when the
+// commitment coverage is priced at the committed price and the rest of the
+// usage at the normal price. Monthly discounts, e.g. GCP sustained use
+// discounts, only apply to the usage that is not covered by the commitment.
+func (c *CostComponent) calculateCommitmentCosts() {
+	uncovered := decimal.NewFromInt(1).Sub(c.commitmentCoverage)
+	coveredPrice := c.commitmentPrice.Mul(c.commitmentCoverage)
+
+	if c.HourlyQuantity != nil {
+		blendedPrice := c.price.Mul(uncovered).Add(coveredPrice)
+		c.CommitmentHourlyCost = decimalPtr(blendedPrice.Mul(*c.HourlyQuantity))
+	}
+	if c.MonthlyQuantity != nil {
+		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
+		blendedPrice := c.price.Mul(uncovered).Mul(discountMul).Add(coveredPrice)
+		c.CommitmentMonthlyCost = decimalPtr(blendedPrice.Mul(*c.MonthlyQuantity))
+	}
 }
 
 func (c *CostComponent) fillQuantities() {
@@ -69,6 +104,16 @@ func (c *CostComponent) PriceHash() string {
 	return c.priceHash
 }
 
+func (c *CostComponent) SetSKU(sku string) {
+	c.sku = sku
+}
+
+// SKU returns the SKU of the product the price was taken from. This is empty
+// if the pricing API did not return a SKU.
+func (c *CostComponent) SKU() string {
+	return c.sku
+}
+
 func (c *CostComponent) SetCustomPrice(price *decimal.Decimal) {
 	c.customPrice = price
 }
@@ -77,11 +122,54 @@ func (c *CostComponent) CustomPrice() *decimal.Decimal {
 	return c.customPrice
 }
 
+// SetPriceOverride replaces the price of the cost component with the price
+// from the named price override. The price it replaces is kept as the list price.
+func (c *CostComponent) SetPriceOverride(name string, price decimal.Decimal) {
+	if c.listPrice == nil {
+		c.listPrice = decimalPtr(c.price)
+	}
+	c.price = price
+	c.PriceOverride = name
+}
+
+// ListPrice returns the price of the cost component before any price override
+// was applied.
+func (c *CostComponent) ListPrice() decimal.Decimal {
+	if c.listPrice != nil {
+		return *c.listPrice
+	}
+
+	return c.price
+}
+
+// SetCommitment sets the commitment that covers the given fraction of the cost
+// component's usage at the committed price.
+func (c *CostComponent) SetCommitment(name string, coverage float64, price decimal.Decimal) {
+	c.Commitment = name
+	c.commitmentCoverage = decimal.NewFromFloat(coverage)
+	c.commitmentPrice = price
+}
+
+// CommitmentPrice returns the committed price of the cost component.
+func (c *CostComponent) CommitmentPrice() decimal.Decimal {
+	return c.commitmentPrice
+}
+
 func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	// Round the final number to 16 decimal places to avoid floating point issues.
 	return c.Price().Mul(c.UnitMultiplier)
 }
 
+// UnitMultiplierListPrice returns the list price per output unit, or nil if
+// the price of the cost component has not been overridden.
+func (c *CostComponent) UnitMultiplierListPrice() *decimal.Decimal {
+	if c.listPrice == nil {
+		return nil
+	}
+
+	return decimalPtr(c.listPrice.Mul(c.UnitMultiplier))
+}
+
 func (c *CostComponent) UnitMultiplierHourlyQuantity() *decimal.Decimal {
 	if c.HourlyQuantity == nil {
 		return nil
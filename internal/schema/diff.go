@@ -15,6 +15,18 @@ var nameBracketReg = regexp.MustCompile(`(.*?)\s*\((.*?)\)`)
 
 // CalculateDiff calculates the diff of past and current resources
 func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
+	return CalculateDiffWithAddressChanges(past, current, nil)
+}
+
+// CalculateDiffWithAddressChanges calculates the diff like CalculateDiff but
+// compares resources that were moved to their past address, and marks the
+// resources that were moved, imported or removed from the state without being
+// destroyed with their TrackingChange. Moved resources are always included
+// in the diff so the move is shown even if the cost didn't change. See
+// UntrackedDiffAddresses for the resources that are left out of the diff totals.
+func CalculateDiffWithAddressChanges(past []*Resource, current []*Resource, changes *AddressChanges) []*Resource {
+	past, movedFrom := changes.applyMoves(past, current)
+
 	// There are many ways to calculate a diff between two sets of
 	// nested objects. The method used here is to create a nested
 	// hashmap of each set of states for fast lookup so the structure
@@ -37,9 +49,31 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 
 	diff := make([]*Resource, 0)
 
+	pastNames := make(map[string]bool, len(past))
+	for _, resource := range past {
+		pastNames[resource.Name] = true
+	}
+
+	currentNames := make(map[string]bool, len(current))
+	for _, resource := range current {
+		currentNames[resource.Name] = true
+	}
+
 	for _, resource := range past {
 		resourceKey := resource.Name
 		changed, resources := diffResourcesByKey(resourceKey, pastRMap, currentRMap)
+		if resources == nil {
+			continue
+		}
+
+		if from, ok := movedFrom[resourceKey]; ok {
+			resources.TrackingChange = TrackingChangeMoved
+			resources.MovedFrom = from
+			changed = true
+		} else if !currentNames[resourceKey] && changes.isRemoved(resourceKey) {
+			resources.TrackingChange = TrackingChangeRemoved
+		}
+
 		if changed {
 			diff = append(diff, resources)
 		}
@@ -51,6 +85,10 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 			continue
 		}
 		changed, resources := diffResourcesByKey(resourceKey, pastRMap, currentRMap)
+		if changed && !pastNames[resourceKey] && changes.isImported(resourceKey) {
+			resources.TrackingChange = TrackingChangeImported
+		}
+
 		if changed {
 			diff = append(diff, resources)
 		}
@@ -59,6 +97,27 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	return diff
 }
 
+// UntrackedDiffAddresses returns the addresses of the diff resources that
+// are imported, and of the ones that are removed from the state without being
+// destroyed. These resources exist before and after the change, so their
+// costs should be left out of the diff totals, but still be counted in the
+// past and current totals.
+func UntrackedDiffAddresses(diff []*Resource) (imported map[string]bool, removed map[string]bool) {
+	imported = make(map[string]bool)
+	removed = make(map[string]bool)
+
+	for _, r := range diff {
+		switch r.TrackingChange {
+		case TrackingChangeImported:
+			imported[r.Name] = true
+		case TrackingChangeRemoved:
+			removed[r.Name] = true
+		}
+	}
+
+	return imported, removed
+}
+
 // diffResourcesByKey calculates the diff between two resources given their resourcesMap and
 // their key.
 func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string]*Resource) (bool, *Resource) {
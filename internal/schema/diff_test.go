@@ -414,3 +414,67 @@ func TestDiffName(t *testing.T) {
 		assert.Equal(t, test.expected, diffName(test.current, test.past))
 	}
 }
+
+func TestCalculateDiffWithAddressChanges(t *testing.T) {
+	resource := func(name string, cost int64) *Resource {
+		return &Resource{
+			Name:        name,
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+			CostComponents: []*CostComponent{
+				{
+					Name:        "cc",
+					MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+				},
+			},
+		}
+	}
+
+	past := []*Resource{
+		resource("aws_instance.old", 10),
+		resource("module.web[0].aws_instance.this", 20),
+		resource("aws_instance.kept", 30),
+		resource("aws_instance.deleted", 40),
+	}
+
+	current := []*Resource{
+		resource("aws_instance.new", 10),
+		resource("module.app[0].aws_instance.this", 25),
+		resource("aws_instance.existing", 50),
+		resource("aws_instance.created", 60),
+	}
+
+	changes := &AddressChanges{
+		Moved: []MovedAddress{
+			{From: "aws_instance.old", To: "aws_instance.new"},
+			{From: "module.web", To: "module.app"},
+		},
+		Imported: []string{"aws_instance.existing"},
+		Removed:  []string{"aws_instance.kept"},
+	}
+
+	diff := CalculateDiffWithAddressChanges(past, current, changes)
+
+	byName := make(map[string]*Resource, len(diff))
+	for _, r := range diff {
+		byName[r.Name] = r
+	}
+
+	assert.Len(t, diff, 6)
+
+	moved := byName["aws_instance.new"]
+	assert.Equal(t, TrackingChangeMoved, moved.TrackingChange)
+	assert.Equal(t, "aws_instance.old", moved.MovedFrom)
+	assert.Equal(t, "0", moved.MonthlyCost.String())
+
+	movedModule := byName["module.app[0].aws_instance.this"]
+	assert.Equal(t, TrackingChangeMoved, movedModule.TrackingChange)
+	assert.Equal(t, "module.web[0].aws_instance.this", movedModule.MovedFrom)
+	assert.Equal(t, "5", movedModule.MonthlyCost.String())
+
+	assert.Equal(t, TrackingChangeImported, byName["aws_instance.existing"].TrackingChange)
+	assert.Equal(t, TrackingChangeRemoved, byName["aws_instance.kept"].TrackingChange)
+	assert.Equal(t, "", byName["aws_instance.deleted"].TrackingChange)
+	assert.Equal(t, "", byName["aws_instance.created"].TrackingChange)
+
+	assert.Equal(t, "aws_instance.old", past[0].Name)
+}
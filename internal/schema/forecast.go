@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// UsageGrowth is the month over month growth of usage that is used to
+// forecast costs. Rates are percentages keyed by usage key, e.g. a rate of 5
+// for storage_gb grows the storage of a resource by 5% each month.
+type UsageGrowth struct {
+	// Rates are the growth rates that apply to all resources.
+	Rates map[string]float64 `yaml:"growth_rates,omitempty"`
+	// ResourceRates are the growth rates of individual resources keyed by
+	// address. These take precedence over Rates for the same usage key.
+	ResourceRates map[string]map[string]float64 `yaml:"resource_growth_rates,omitempty"`
+}
+
+// Validate checks that none of the growth rates would make usage negative.
+func (g *UsageGrowth) Validate() error {
+	if g == nil {
+		return nil
+	}
+
+	for k, v := range g.Rates {
+		if v <= -100 {
+			return fmt.Errorf("growth rate for %s must be greater than -100", k)
+		}
+	}
+
+	for address, rates := range g.ResourceRates {
+		for k, v := range rates {
+			if v <= -100 {
+				return fmt.Errorf("growth rate for %s %s must be greater than -100", address, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RatesFor returns the growth rates for the resource with the given address.
+// Resources with an index, e.g. aws_instance.web[0], also match rates
+// defined for the wildcard address aws_instance.web[*].
+func (g *UsageGrowth) RatesFor(address string) map[string]float64 {
+	if g == nil {
+		return nil
+	}
+
+	resourceRates, ok := g.ResourceRates[address]
+	if !ok && strings.HasSuffix(address, "]") {
+		resourceRates = g.ResourceRates[convertArrayKeyToWildcard(address)]
+	}
+
+	if len(resourceRates) == 0 {
+		return g.Rates
+	}
+
+	rates := make(map[string]float64, len(g.Rates)+len(resourceRates))
+	for k, v := range g.Rates {
+		rates[k] = v
+	}
+
+	for k, v := range resourceRates {
+		rates[k] = v
+	}
+
+	return rates
+}
+
+// BuildForecastProject returns a copy of the project with its resources built
+// from the usage for the given month of a forecast, where month 1 uses the
+// current usage. Only resources that can be built again from their usage are
+// included, since the costs of other resources do not change over time.
+// The returned project still needs to be priced.
+func (p *Project) BuildForecastProject(usageMap UsageMap, growth *UsageGrowth, month int) *Project {
+	forecast := &Project{
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+		Metadata:    p.Metadata,
+		HasDiff:     p.HasDiff,
+	}
+
+	seen := make(map[*PartialResource]*Resource)
+	build := func(partials []*PartialResource) []*Resource {
+		resources := make([]*Resource, 0, len(partials))
+
+		for _, partial := range partials {
+			if partial.CoreResource == nil && partial.BuildWithUsage == nil {
+				continue
+			}
+
+			r, ok := seen[partial]
+			if !ok {
+				r = buildForecastResource(partial, usageMap.Get(partial.Address), growth, month)
+				seen[partial] = r
+			}
+
+			resources = append(resources, r)
+		}
+
+		return resources
+	}
+
+	forecast.PastResources = build(p.PartialPastResources)
+	forecast.Resources = build(p.PartialResources)
+
+	return forecast
+}
+
+// buildForecastResource builds the resource with its usage grown to the given
+// month. PopulateUsage modifies the core resource, so the current usage is
+// populated again afterwards. Resources that aren't built from a core resource
+// are built again with BuildWithUsage, and don't use the fetched usage since
+// their current costs don't either.
+func buildForecastResource(partial *PartialResource, fetchedUsage *UsageData, growth *UsageGrowth, month int) *Resource {
+	if partial.CoreResource == nil {
+		grown := *partial
+		grown.Resource = partial.BuildWithUsage(partial.UsageData.Grow(growth.RatesFor(partial.Address), month-1))
+		return BuildResource(&grown, nil)
+	}
+
+	u := partial.UsageData.Merge(fetchedUsage)
+
+	grown := *partial
+	grown.UsageData = u.Grow(growth.RatesFor(partial.Address), month-1)
+	r := BuildResource(&grown, nil)
+
+	partial.CoreResource.PopulateUsage(u)
+
+	return r
+}
+
+// AddForecastMonth appends the monthly costs of the resources in the priced
+// forecast project to the ForecastMonthlyCosts of the matching resources in
+// the project. Resources that are not in the forecast project use their
+// current monthly cost. The forecast costs of the diff resources are the
+// difference between the past and current forecast costs.
+func (p *Project) AddForecastMonth(forecast *Project) {
+	added := make(map[*Resource]decimal.Decimal)
+
+	pastCosts := addForecastCosts(p.PastResources, forecastCostsByName(forecast.PastResources), added)
+	currentCosts := addForecastCosts(p.Resources, forecastCostsByName(forecast.Resources), added)
+
+	for _, r := range p.Diff {
+		r.ForecastMonthlyCosts = append(r.ForecastMonthlyCosts, currentCosts[r.Name].Sub(pastCosts[r.Name]))
+	}
+}
+
+func forecastCostsByName(resources []*Resource) map[string]decimal.Decimal {
+	costs := make(map[string]decimal.Decimal, len(resources))
+	for _, r := range resources {
+		if r.MonthlyCost != nil {
+			costs[r.Name] = *r.MonthlyCost
+		}
+	}
+
+	return costs
+}
+
+// addForecastCosts appends the forecast cost of each resource and returns the
+// costs keyed by resource name. Resources shared between the past and current
+// resources are only appended to once.
+func addForecastCosts(resources []*Resource, costs map[string]decimal.Decimal, added map[*Resource]decimal.Decimal) map[string]decimal.Decimal {
+	byName := make(map[string]decimal.Decimal, len(resources))
+
+	for _, r := range resources {
+		cost, ok := added[r]
+		if !ok {
+			cost, ok = costs[r.Name]
+			if !ok && r.MonthlyCost != nil {
+				cost = *r.MonthlyCost
+			}
+
+			r.ForecastMonthlyCosts = append(r.ForecastMonthlyCosts, cost)
+			added[r] = cost
+		}
+
+		byName[r.Name] = cost
+	}
+
+	return byName
+}
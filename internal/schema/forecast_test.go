@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageResource is a core resource that costs $0.10 per GB of storage.
+type storageResource struct {
+	Address   string
+	StorageGB *float64
+}
+
+func (s *storageResource) CoreType() string          { return "storage" }
+func (s *storageResource) UsageSchema() []*UsageItem { return nil }
+func (s *storageResource) PopulateUsage(u *UsageData) {
+	s.StorageGB = u.GetFloat("storage_gb")
+}
+func (s *storageResource) BuildResource() *Resource {
+	cost := decimal.Zero
+	if s.StorageGB != nil {
+		cost = decimal.NewFromFloat(*s.StorageGB).Mul(decimal.NewFromFloat(0.1))
+	}
+
+	return &Resource{
+		Name:           s.Address,
+		CostComponents: []*CostComponent{{Name: "Storage", MonthlyCost: &cost}},
+		MonthlyCost:    &cost,
+	}
+}
+
+func TestUsageGrowthRatesFor(t *testing.T) {
+	growth := &UsageGrowth{
+		Rates: map[string]float64{"storage_gb": 5, "monthly_requests": 10},
+		ResourceRates: map[string]map[string]float64{
+			"aws_s3_bucket.logs":   {"storage_gb": 20},
+			"aws_instance.web[*]":  {"monthly_data_processed_gb": 2},
+			"aws_instance.web[10]": {"monthly_data_processed_gb": 3},
+		},
+	}
+
+	assert.Equal(t, growth.Rates, growth.RatesFor("aws_s3_bucket.other"))
+	assert.Equal(t, map[string]float64{"storage_gb": 20, "monthly_requests": 10}, growth.RatesFor("aws_s3_bucket.logs"))
+	assert.Equal(t, 2.0, growth.RatesFor("aws_instance.web[0]")["monthly_data_processed_gb"])
+	assert.Equal(t, 3.0, growth.RatesFor("aws_instance.web[10]")["monthly_data_processed_gb"])
+	assert.Nil(t, (*UsageGrowth)(nil).RatesFor("aws_s3_bucket.logs"))
+
+	assert.NoError(t, growth.Validate())
+	assert.Error(t, (&UsageGrowth{Rates: map[string]float64{"storage_gb": -100}}).Validate())
+}
+
+func TestProjectForecast(t *testing.T) {
+	storage := 1000.0
+	bucket := &PartialResource{
+		Address:      "aws_s3_bucket.logs",
+		CoreResource: &storageResource{Address: "aws_s3_bucket.logs", StorageGB: &storage},
+		UsageData:    NewUsageData("aws_s3_bucket.logs", ParseAttributes(map[string]interface{}{"storage_gb": storage})),
+	}
+	fixedCost := decimal.NewFromInt(50)
+	legacy := &PartialResource{
+		Address:  "aws_instance.web",
+		Resource: &Resource{Name: "aws_instance.web", MonthlyCost: &fixedCost},
+	}
+
+	project := &Project{
+		HasDiff:              true,
+		PartialPastResources: []*PartialResource{legacy},
+		PartialResources:     []*PartialResource{bucket, legacy},
+	}
+	project.BuildResources(UsageMap{})
+	project.CalculateDiff()
+
+	growth := &UsageGrowth{Rates: map[string]float64{"storage_gb": 10}}
+
+	project.AddForecastMonth(project)
+	for month := 2; month <= 3; month++ {
+		forecast := project.BuildForecastProject(UsageMap{}, growth, month)
+		require.Len(t, forecast.Resources, 1, "only core resources should be rebuilt")
+		assert.Empty(t, forecast.PastResources)
+
+		project.AddForecastMonth(forecast)
+	}
+
+	assert.Equal(t, 1000.0, *bucket.CoreResource.(*storageResource).StorageGB, "the core resource should have its current usage")
+
+	assertCosts := func(t *testing.T, want []string, costs []decimal.Decimal) {
+		t.Helper()
+
+		got := make([]string, len(costs))
+		for i, c := range costs {
+			got[i] = c.StringFixed(2)
+		}
+
+		assert.Equal(t, want, got)
+	}
+
+	assertCosts(t, []string{"100.00", "110.00", "121.00"}, project.Resources[0].ForecastMonthlyCosts)
+	assertCosts(t, []string{"50.00", "50.00", "50.00"}, project.Resources[1].ForecastMonthlyCosts)
+	assert.Same(t, project.PastResources[0], project.Resources[1])
+
+	require.Len(t, project.Diff, 1)
+	assertCosts(t, []string{"100.00", "110.00", "121.00"}, project.Diff[0].ForecastMonthlyCosts)
+}
+
+func TestProjectForecastBuildWithUsage(t *testing.T) {
+	build := func(u *UsageData) *Resource {
+		cost := decimal.NewFromFloat(*u.GetFloat("monthly_requests")).Mul(decimal.NewFromFloat(0.001))
+		return &Resource{
+			Name:           "aws_lambda_function.worker",
+			CostComponents: []*CostComponent{{Name: "Requests", MonthlyCost: &cost}},
+			MonthlyCost:    &cost,
+		}
+	}
+
+	usage := NewUsageData("aws_lambda_function.worker", ParseAttributes(map[string]interface{}{"monthly_requests": 10000}))
+	lambda := &PartialResource{
+		Type:           "aws_lambda_function",
+		Address:        "aws_lambda_function.worker",
+		Resource:       build(usage),
+		UsageData:      usage,
+		BuildWithUsage: build,
+	}
+
+	project := &Project{PartialResources: []*PartialResource{lambda}}
+	project.BuildResources(UsageMap{})
+
+	growth := &UsageGrowth{Rates: map[string]float64{"monthly_requests": 50}}
+
+	project.AddForecastMonth(project)
+	for month := 2; month <= 3; month++ {
+		forecast := project.BuildForecastProject(UsageMap{}, growth, month)
+		require.Len(t, forecast.Resources, 1)
+		assert.Equal(t, "aws_lambda_function", forecast.Resources[0].ResourceType)
+
+		project.AddForecastMonth(forecast)
+	}
+
+	costs := make([]string, 0, 3)
+	for _, c := range project.Resources[0].ForecastMonthlyCosts {
+		costs = append(costs, c.StringFixed(2))
+	}
+
+	assert.Equal(t, []string{"10.00", "15.00", "22.50"}, costs)
+	assert.Equal(t, "10.00", project.Resources[0].MonthlyCost.StringFixed(2), "the current costs should not change")
+}
@@ -59,6 +59,12 @@ func IsEmptyPathTypeError(err error) bool {
 	return errors.As(err, &diag) && diag.Code == diagEmptyPathType
 }
 
+// IsMissingVarsDiag checks if the diag is for missing Terraform vars, see
+// NewDiagMissingVars.
+func IsMissingVarsDiag(diag *ProjectDiag) bool {
+	return diag != nil && diag.Code == diagMissingVars
+}
+
 // NewEmptyPathTypeError returns a project diag to indicate that a path type
 // cannot be detected.
 func NewEmptyPathTypeError(err error) *ProjectDiag {
@@ -202,6 +208,10 @@ type ProjectMetadata struct {
 	PastPolicySha       string             `json:"pastPolicySha,omitempty"`
 	TerraformModulePath string             `json:"terraformModulePath,omitempty"`
 	TerraformWorkspace  string             `json:"terraformWorkspace,omitempty"`
+	CDKStackName        string             `json:"cdkStackName,omitempty"`
+	CDKEnvironment      string             `json:"cdkEnvironment,omitempty"`
+	StackDeployment     string             `json:"stackDeployment,omitempty"`
+	StackComponent      string             `json:"stackComponent,omitempty"`
 	VCSSubPath          string             `json:"vcsSubPath,omitempty"`
 	VCSCodeChanged      *bool              `json:"vcsCodeChanged,omitempty"`
 	Errors              []*ProjectDiag     `json:"errors,omitempty"` // contains merged current and past errors
@@ -211,6 +221,7 @@ type ProjectMetadata struct {
 	Policies            Policies           `json:"policies,omitempty"`
 	Providers           []ProviderMetadata `json:"providers,omitempty"`
 	RemoteModuleCalls   []string           `json:"remoteModuleCalls,omitempty"`
+	AddressChanges      *AddressChanges    `json:"addressChanges,omitempty"`
 }
 
 // DetectProjectMetadata returns a new ProjectMetadata struct initialized
@@ -471,10 +482,16 @@ func (p *Project) BuildResources(usageMap UsageMap) {
 	p.Resources = resources
 }
 
-// CalculateDiff calculates the diff of past and current resources
+// CalculateDiff calculates the diff of past and current resources, taking
+// into account the address changes of the project.
 func (p *Project) CalculateDiff() {
 	if p.HasDiff {
-		p.Diff = CalculateDiff(p.PastResources, p.Resources)
+		var changes *AddressChanges
+		if p.Metadata != nil {
+			changes = p.Metadata.AddressChanges
+		}
+
+		p.Diff = CalculateDiffWithAddressChanges(p.PastResources, p.Resources, changes)
 	}
 }
 
@@ -26,6 +26,8 @@ type Resource struct {
 	HourlyCost                              *decimal.Decimal
 	MonthlyCost                             *decimal.Decimal
 	MonthlyUsageCost                        *decimal.Decimal
+	CommitmentHourlyCost                    *decimal.Decimal
+	CommitmentMonthlyCost                   *decimal.Decimal
 	IsSkipped                               bool
 	NoPrice                                 bool
 	SkipMessage                             string
@@ -42,6 +44,20 @@ type Resource struct {
 	MissingVarsCausingUnknownTagKeys        []string
 	MissingVarsCausingUnknownDefaultTagKeys []string
 
+	// ForecastMonthlyCosts is the monthly cost of the resource for each month
+	// of a forecast. It is only set when running with a forecast.
+	ForecastMonthlyCosts []decimal.Decimal
+
+	// Usage is the usage data the resource was built with, from the usage
+	// file or fetched from Infracost Cloud.
+	Usage *UsageData
+
+	// TrackingChange is set on diff resources that were moved, imported or
+	// removed from the state without being destroyed, see TrackingChangeMoved.
+	TrackingChange string
+	// MovedFrom is the past address of diff resources that were moved.
+	MovedFrom string
+
 	// parent is the parent resource of this resource, this is only
 	// applicable for sub resources. See FlattenedSubResources for more info
 	// on how this is built and used.
@@ -82,11 +98,22 @@ func (r *Resource) CalculateCosts() {
 	var monthlyUsageCost *decimal.Decimal
 	hasCost := false
 
+	// commitmentH and commitmentM are the costs with any commitments applied,
+	// they're only set on the resource if a commitment covers some of its usage.
+	commitmentH := decimal.Zero
+	commitmentM := decimal.Zero
+	hasCommitment := false
+
 	for _, c := range r.CostComponents {
 		c.CalculateCosts()
 		if c.HourlyCost != nil || c.MonthlyCost != nil {
 			hasCost = true
 		}
+		if c.CommitmentHourlyCost != nil || c.CommitmentMonthlyCost != nil {
+			hasCommitment = true
+		}
+		commitmentH = commitmentH.Add(firstNonNil(c.CommitmentHourlyCost, c.HourlyCost))
+		commitmentM = commitmentM.Add(firstNonNil(c.CommitmentMonthlyCost, c.MonthlyCost))
 		if c.HourlyCost != nil {
 			h = h.Add(*c.HourlyCost)
 		}
@@ -106,6 +133,11 @@ func (r *Resource) CalculateCosts() {
 		if s.HourlyCost != nil || s.MonthlyCost != nil || s.MonthlyUsageCost != nil {
 			hasCost = true
 		}
+		if s.CommitmentHourlyCost != nil || s.CommitmentMonthlyCost != nil {
+			hasCommitment = true
+		}
+		commitmentH = commitmentH.Add(firstNonNil(s.CommitmentHourlyCost, s.HourlyCost))
+		commitmentM = commitmentM.Add(firstNonNil(s.CommitmentMonthlyCost, s.MonthlyCost))
 		if s.HourlyCost != nil {
 			h = h.Add(*s.HourlyCost)
 		}
@@ -125,6 +157,10 @@ func (r *Resource) CalculateCosts() {
 		r.MonthlyCost = &m
 		r.MonthlyUsageCost = monthlyUsageCost
 	}
+	if hasCommitment {
+		r.CommitmentHourlyCost = &commitmentH
+		r.CommitmentMonthlyCost = &commitmentM
+	}
 	if r.NoPrice {
 		logging.Logger.Debug().Msgf("Skipping free resource %s", r.Name)
 	}
@@ -180,6 +216,17 @@ func MultiplyQuantities(resource *Resource, multiplier decimal.Decimal) {
 	}
 }
 
+// firstNonNil returns the value of the first non-nil decimal, or zero if they are all nil.
+func firstNonNil(values ...*decimal.Decimal) decimal.Decimal {
+	for _, v := range values {
+		if v != nil {
+			return *v
+		}
+	}
+
+	return decimal.Zero
+}
+
 func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
 }
@@ -411,3 +411,56 @@ func ParseAttributes(i interface{}) map[string]gjson.Result {
 
 	return a
 }
+
+// Grow returns a copy of the usage data with the numeric attributes that have
+// a growth rate compounded over the given number of months. Rates are month
+// over month percentages keyed by usage key. Nested attributes can be matched
+// by their full path, e.g. standard.storage_gb, or by their own key.
+func (u *UsageData) Grow(rates map[string]float64, months int) *UsageData {
+	if u == nil || len(rates) == 0 || months <= 0 {
+		return u.Copy()
+	}
+
+	c := &UsageData{
+		Address:    u.Address,
+		Attributes: make(map[string]gjson.Result, len(u.Attributes)),
+	}
+
+	for k, v := range u.Attributes {
+		if v.Type != gjson.Number && !v.IsObject() {
+			c.Attributes[k] = v
+			continue
+		}
+
+		grown := growValue(k, k, v.Value(), rates, months)
+		j, _ := jsoniter.Marshal(grown)
+		c.Attributes[k] = gjson.ParseBytes(j)
+	}
+
+	return c
+}
+
+func growValue(path, key string, v interface{}, rates map[string]float64, months int) interface{} {
+	switch val := v.(type) {
+	case float64:
+		rate, ok := rates[path]
+		if !ok {
+			rate, ok = rates[key]
+		}
+
+		if !ok {
+			return val
+		}
+
+		return val * math.Pow(1+rate/100, float64(months))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, nested := range val {
+			m[k] = growValue(path+"."+k, k, nested, rates, months)
+		}
+
+		return m
+	default:
+		return v
+	}
+}
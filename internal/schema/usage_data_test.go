@@ -213,3 +213,36 @@ func TestUsageMap_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageDataGrow(t *testing.T) {
+	t.Parallel()
+
+	u := NewUsageData("aws_s3_bucket.logs", ParseAttributes(map[string]interface{}{
+		"monthly_requests": 1000,
+		"name":             "logs",
+		"standard": map[string]interface{}{
+			"storage_gb":              100,
+			"monthly_tier_1_requests": 200,
+		},
+		"glacier": map[string]interface{}{
+			"storage_gb": 50,
+		},
+	}))
+
+	grown := u.Grow(map[string]float64{
+		"monthly_requests":         10,
+		"storage_gb":               5,
+		"glacier.storage_gb":       -50,
+		"monthly_tier_2_requests":  100,
+		"standard.unknown_usage_k": 100,
+	}, 2)
+
+	assert.InDelta(t, 1210, *grown.GetFloat("monthly_requests"), 0.0001)
+	assert.Equal(t, "logs", *grown.GetString("name"))
+	assert.InDelta(t, 110.25, grown.Get("standard").Get("storage_gb").Float(), 0.0001)
+	assert.InDelta(t, 200, grown.Get("standard").Get("monthly_tier_1_requests").Float(), 0.0001)
+	assert.InDelta(t, 12.5, grown.Get("glacier").Get("storage_gb").Float(), 0.0001)
+
+	assert.InDelta(t, 1000, *u.GetFloat("monthly_requests"), 0.0001, "the original usage should not change")
+	assert.Equal(t, u, u.Grow(map[string]float64{"monthly_requests": 10}, 0))
+}
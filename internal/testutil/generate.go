@@ -145,6 +145,20 @@ module "` + fmt.Sprintf("call_%d", i) + `" {
 		}
 	}
 
+	if strings.HasPrefix(filename, "component-call") {
+		pieces := strings.Split(strings.TrimSuffix(filename, ".tfstack.hcl"), "|")
+		calls := pieces[1:]
+		content = ""
+		for i, call := range calls {
+			call = strings.ReplaceAll(call, "-", "/")
+			content += `
+component "` + fmt.Sprintf("call_%d", i) + `" {
+  source = "` + call + `"
+}
+`
+		}
+	}
+
 	return os.WriteFile(filePath, []byte(content), 0600)
 }
 
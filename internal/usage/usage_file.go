@@ -12,6 +12,7 @@ import (
 	"golang.org/x/mod/semver"
 	yamlv3 "gopkg.in/yaml.v3"
 
+	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -29,6 +30,12 @@ type UsageFile struct { // nolint:revive
 	RawResourceUsage yamlv3.Node `yaml:"resource_usage"`
 	// The raw usage is then parsed into this struct
 	ResourceUsages []*ResourceUsage `yaml:"-"`
+	// Commitments are the Reserved Instances, Savings Plans and Committed Use Discounts
+	// that cover the usage of the project the usage file is used with.
+	Commitments []config.Commitment `yaml:"commitments,omitempty"`
+	// Forecast is the month over month growth of usage keys that is used
+	// to forecast costs when running with --forecast-months.
+	Forecast *schema.UsageGrowth `yaml:"forecast,omitempty"`
 }
 
 // CreateUsageFile creates a blank usage file if it does not exists
@@ -102,6 +109,16 @@ func LoadUsageFileFromString(s string) (*UsageFile, error) {
 		return usageFile, errors.Wrap(err, "Error loading YAML file")
 	}
 
+	err = config.ValidateCommitments(usageFile.Commitments)
+	if err != nil {
+		return usageFile, errors.Wrap(err, "Error loading usage file commitments")
+	}
+
+	err = usageFile.Forecast.Validate()
+	if err != nil {
+		return usageFile, errors.Wrap(err, "Error loading usage file forecast")
+	}
+
 	return usageFile, nil
 }
 
@@ -147,6 +164,38 @@ See https://infracost.io/usage-file/ for docs`,
 		&u.RawResourceUsage,
 	)
 
+	if len(u.Commitments) > 0 {
+		commitmentsNode := &yamlv3.Node{}
+		err := commitmentsNode.Encode(u.Commitments)
+		if err != nil {
+			return err
+		}
+
+		root.Content = append(root.Content,
+			&yamlv3.Node{
+				Kind:  yamlv3.ScalarNode,
+				Value: "commitments",
+			},
+			commitmentsNode,
+		)
+	}
+
+	if u.Forecast != nil {
+		forecastNode := &yamlv3.Node{}
+		err := forecastNode.Encode(u.Forecast)
+		if err != nil {
+			return err
+		}
+
+		root.Content = append(root.Content,
+			&yamlv3.Node{
+				Kind:  yamlv3.ScalarNode,
+				Value: "forecast",
+			},
+			forecastNode,
+		)
+	}
+
 	// Add a comment to the first commented-out resource
 	for _, node := range u.RawResourceTypeUsage.Content {
 		if isNodeMarkedAsCommented(node) {
@@ -1,6 +1,7 @@
 package usage_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,3 +102,35 @@ resource_usage:
 	}
 
 }
+
+func TestUsageFileCommitments(t *testing.T) {
+	usageFile, err := usage.LoadUsageFileFromString(`
+version: 0.1
+resource_usage: {}
+commitments:
+  - name: Prod savings plan
+    type: savings_plan
+    term: 3_year
+    coverage_percent: 60
+    discount_percent: 28
+    region: us-east-1
+`)
+	assert.NoError(t, err)
+	assert.Len(t, usageFile.Commitments, 1)
+	assert.Equal(t, "Prod savings plan", usageFile.Commitments[0].Name)
+	assert.Equal(t, 0.6, usageFile.Commitments[0].Coverage())
+
+	path := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	assert.NoError(t, usageFile.WriteToPath(path))
+
+	written, err := usage.LoadUsageFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, usageFile.Commitments, written.Commitments)
+
+	_, err = usage.LoadUsageFileFromString(`
+version: 0.1
+commitments:
+  - type: reserved_capacity
+`)
+	assert.ErrorContains(t, err, "commitment at index 0 is invalid")
+}